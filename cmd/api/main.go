@@ -12,6 +12,10 @@ import (
 	"time"
 
 	"github.com/PavelFesenkoFirst/task_tracker/internal/config"
+	"github.com/PavelFesenkoFirst/task_tracker/internal/job"
+	jobmysql "github.com/PavelFesenkoFirst/task_tracker/internal/job/repository/mysql"
+	"github.com/PavelFesenkoFirst/task_tracker/internal/notification"
+	"github.com/PavelFesenkoFirst/task_tracker/internal/platform/filestore"
 	platformlogger "github.com/PavelFesenkoFirst/task_tracker/internal/platform/logger"
 	mysqlplatform "github.com/PavelFesenkoFirst/task_tracker/internal/platform/mysql"
 	"github.com/PavelFesenkoFirst/task_tracker/internal/task"
@@ -39,8 +43,37 @@ func main() {
 	defer db.Close()
 
 	taskRepository := taskmysql.New(db)
-	taskService := task.NewService(taskRepository)
-	taskHandler := taskhttp.NewHandler(taskService)
+	notifier := notification.NewLogNotifier(logger)
+	taskService := task.NewService(taskRepository, task.WithNotifier(notifier))
+	jobService := job.NewService(jobmysql.New(db))
+	exportStorage, err := filestore.New(cfg.App.ExportDir)
+	if err != nil {
+		logger.Error("export storage init failed", "error", err)
+		os.Exit(1)
+	}
+	exportService := task.NewExportService(taskRepository, exportStorage, jobService, cfg.Worker.ExportRetention)
+	templateHandler := taskhttp.NewTemplateHandler(task.NewTemplateService(taskRepository, notifier))
+	taskHandler := taskhttp.NewHandler(
+		taskService,
+		taskhttp.WithAdminToken(cfg.App.AdminToken),
+		taskhttp.WithIdempotency(task.NewIdempotencyService(taskRepository, cfg.App.IdempotencyKeyTTL)),
+		taskhttp.WithExport(exportService),
+		taskhttp.WithCalendar(task.NewCalendarService(taskRepository)),
+		taskhttp.WithTemplates(templateHandler),
+	)
+	timeTrackingHandler := taskhttp.NewTimeTrackingHandler(task.NewTimeTrackingService(taskRepository))
+	projectHandler := taskhttp.NewProjectHandler(task.NewProjectService(taskRepository))
+	checklistHandler := taskhttp.NewChecklistHandler(task.NewChecklistService(taskRepository))
+	watcherHandler := taskhttp.NewWatcherHandler(task.NewWatcherService(taskRepository))
+	commentHandler := taskhttp.NewCommentHandler(task.NewCommentService(taskRepository, notifier))
+	mentionHandler := taskhttp.NewMentionHandler(task.NewMentionService(taskRepository))
+	boardHandler := taskhttp.NewBoardHandler(task.NewBoardService(taskRepository))
+	sprintHandler := taskhttp.NewSprintHandler(task.NewSprintService(taskRepository))
+	milestoneHandler := taskhttp.NewMilestoneHandler(task.NewMilestoneService(taskRepository))
+	bulkHandler := taskhttp.NewBulkHandler(task.NewBulkService(taskService, taskRepository, jobService, cfg.App.BulkAsyncThreshold))
+	// Imports do not notify anyone about the tasks they create.
+	importHandler := taskhttp.NewImportHandler(task.NewImportService(task.NewService(taskRepository), jobService, cfg.App.ImportAsyncThreshold))
+	jobHandler := taskhttp.NewJobHandler(jobService)
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -55,6 +88,19 @@ func main() {
 		})
 	})
 	taskHandler.Register(mux)
+	timeTrackingHandler.Register(mux)
+	projectHandler.Register(mux)
+	checklistHandler.Register(mux)
+	templateHandler.Register(mux)
+	watcherHandler.Register(mux)
+	commentHandler.Register(mux)
+	mentionHandler.Register(mux)
+	boardHandler.Register(mux)
+	sprintHandler.Register(mux)
+	milestoneHandler.Register(mux)
+	bulkHandler.Register(mux)
+	importHandler.Register(mux)
+	jobHandler.Register(mux)
 
 	server := &http.Server{
 		Addr:         ":" + cfg.App.Port,
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log/slog"
 	"os"
 	"os/signal"
@@ -8,8 +9,14 @@ import (
 	"time"
 
 	"github.com/PavelFesenkoFirst/task_tracker/internal/config"
+	"github.com/PavelFesenkoFirst/task_tracker/internal/job"
+	jobmysql "github.com/PavelFesenkoFirst/task_tracker/internal/job/repository/mysql"
+	"github.com/PavelFesenkoFirst/task_tracker/internal/notification"
+	"github.com/PavelFesenkoFirst/task_tracker/internal/platform/filestore"
 	platformlogger "github.com/PavelFesenkoFirst/task_tracker/internal/platform/logger"
 	mysqlplatform "github.com/PavelFesenkoFirst/task_tracker/internal/platform/mysql"
+	"github.com/PavelFesenkoFirst/task_tracker/internal/task"
+	taskmysql "github.com/PavelFesenkoFirst/task_tracker/internal/task/repository/mysql"
 	"github.com/joho/godotenv"
 )
 
@@ -31,21 +38,88 @@ func main() {
 	}
 	defer db.Close()
 
-	logger.Info("worker started", "concurrency", cfg.Worker.Concurrency, "queue", cfg.Worker.Queue)
+	taskRepository := taskmysql.New(db)
+	notifier := notification.NewLogNotifier(logger)
+	reminderService := task.NewReminderService(taskRepository, notifier, cfg.Worker.ReminderOffsets)
+	housekeepingService := task.NewHousekeepingService(taskRepository, task.HousekeepingConfig{
+		DeletedRetention: cfg.Worker.DeletedTaskRetention,
+		ArchiveAfter:     cfg.Worker.ArchiveDoneAfter,
+	})
 
-	ticker := time.NewTicker(5 * time.Second)
-	defer ticker.Stop()
+	taskService := task.NewService(taskRepository, task.WithNotifier(notifier))
+	jobRunner := job.NewRunner(jobmysql.New(db), cfg.Worker.JobTimeout)
+	jobRunner.Handle(task.BulkJobType, task.NewBulkJobHandler(task.NewBulkService(taskService, taskRepository, nil, 0)))
+	jobRunner.Handle(task.ImportJobType, task.NewImportJobHandler(task.NewImportService(task.NewService(taskRepository), nil, 0)))
+	exportStorage, err := filestore.New(cfg.App.ExportDir)
+	if err != nil {
+		logger.Error("export storage init failed", "error", err)
+		os.Exit(1)
+	}
+	exportService := task.NewExportService(taskRepository, exportStorage, nil, cfg.Worker.ExportRetention)
+	jobRunner.Handle(task.ExportJobType, task.NewExportJobHandler(exportService))
 
-	stop := make(chan os.Signal, 1)
-	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	scheduler := job.NewScheduler(logger)
+	scheduler.Every("heartbeat", 5*time.Second, func(ctx context.Context, now time.Time) error {
+		logger.Info("worker heartbeat")
+		return nil
+	})
+	scheduler.Every("task-reminders", cfg.Worker.ReminderInterval, func(ctx context.Context, now time.Time) error {
+		sent, err := reminderService.SendDueReminders(ctx, now)
+		if sent > 0 {
+			logger.Info("task reminders sent", "count", sent)
+		}
+		return err
+	})
+	scheduler.Every("task-purge", cfg.Worker.PurgeInterval, func(ctx context.Context, now time.Time) error {
+		purged, err := housekeepingService.PurgeDeleted(ctx, now)
+		if purged > 0 {
+			logger.Info("deleted tasks purged", "count", purged)
+		}
+		return err
+	})
+	scheduler.Every("idempotency-key-purge", cfg.Worker.PurgeInterval, func(ctx context.Context, now time.Time) error {
+		purged, err := housekeepingService.PurgeIdempotencyKeys(ctx, now)
+		if purged > 0 {
+			logger.Info("expired idempotency keys purged", "count", purged)
+		}
+		return err
+	})
+	scheduler.Every("export-purge", cfg.Worker.PurgeInterval, func(ctx context.Context, now time.Time) error {
+		removed, err := exportService.PurgeFiles(ctx, now)
+		if removed > 0 {
+			logger.Info("expired exports removed", "count", removed)
+		}
+		return err
+	})
+	scheduler.Every("task-archive", cfg.Worker.ArchiveInterval, func(ctx context.Context, now time.Time) error {
+		archived, err := housekeepingService.ArchiveCompleted(ctx, now)
+		if archived > 0 {
+			logger.Info("done tasks archived", "count", archived)
+		}
+		return err
+	})
 
-	for {
-		select {
-		case <-ticker.C:
-			logger.Info("worker heartbeat")
-		case <-stop:
-			logger.Info("worker stopped")
-			return
+	scheduler.Every("jobs", cfg.Worker.JobInterval, func(ctx context.Context, now time.Time) error {
+		executed, err := jobRunner.RunQueued(ctx)
+		if executed > 0 {
+			logger.Info("background jobs executed", "count", executed)
 		}
-	}
+		return err
+	})
+	scheduler.Every("job-timeouts", cfg.Worker.JobInterval, func(ctx context.Context, now time.Time) error {
+		failed, err := jobRunner.FailStale(ctx, now)
+		if failed > 0 {
+			logger.Warn("stale background jobs failed", "count", failed)
+		}
+		return err
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	logger.Info("worker started", "concurrency", cfg.Worker.Concurrency, "queue", cfg.Worker.Queue)
+
+	scheduler.Run(ctx)
+
+	logger.Info("worker stopped")
 }
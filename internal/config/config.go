@@ -3,13 +3,27 @@ package config
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 )
 
 type AppConfig struct {
-	Name string
-	Env  string
-	Port string
+	Name              string
+	Env               string
+	Port              string
+	AdminToken        string
+	IdempotencyKeyTTL time.Duration
+	// BulkAsyncThreshold is the number of bulk operations above which they
+	// run as a background job.
+	BulkAsyncThreshold int
+	// ImportAsyncThreshold is the number of imported rows above which an
+	// import runs as a background job.
+	ImportAsyncThreshold int
+	// ExportDir keeps the files of background exports; the api and the
+	// worker must share it.
+	ExportDir string
 }
 
 type MySQLConfig struct {
@@ -28,8 +42,17 @@ type RedisConfig struct {
 }
 
 type WorkerConfig struct {
-	Concurrency int
-	Queue       string
+	Concurrency          int
+	Queue                string
+	ReminderInterval     time.Duration
+	ReminderOffsets      []time.Duration
+	PurgeInterval        time.Duration
+	DeletedTaskRetention time.Duration
+	ArchiveInterval      time.Duration
+	ArchiveDoneAfter     time.Duration
+	JobInterval          time.Duration
+	JobTimeout           time.Duration
+	ExportRetention      time.Duration
 }
 
 type Config struct {
@@ -42,9 +65,14 @@ type Config struct {
 func Load() (Config, error) {
 	cfg := Config{
 		App: AppConfig{
-			Name: getEnv("APP_NAME", "TaskTracker"),
-			Env:  getEnv("APP_ENV", "local"),
-			Port: getEnv("APP_PORT", "8080"),
+			Name:                 getEnv("APP_NAME", "TaskTracker"),
+			Env:                  getEnv("APP_ENV", "local"),
+			Port:                 getEnv("APP_PORT", "8080"),
+			AdminToken:           getEnv("APP_ADMIN_TOKEN", ""),
+			IdempotencyKeyTTL:    getEnvAsDuration("APP_IDEMPOTENCY_KEY_TTL", 24*time.Hour),
+			BulkAsyncThreshold:   getEnvAsInt("APP_BULK_ASYNC_THRESHOLD", 100),
+			ImportAsyncThreshold: getEnvAsInt("APP_IMPORT_ASYNC_THRESHOLD", 100),
+			ExportDir:            getEnv("APP_EXPORT_DIR", filepath.Join(os.TempDir(), "task_tracker_exports")),
 		},
 		MySQL: MySQLConfig{
 			Host:     getEnv("DB_HOST", "127.0.0.1"),
@@ -60,8 +88,21 @@ func Load() (Config, error) {
 			DB:       getEnvAsInt("REDIS_DB", 0),
 		},
 		Worker: WorkerConfig{
-			Concurrency: getEnvAsInt("WORKER_CONCURRENCY", 10),
-			Queue:       getEnv("WORKER_QUEUE", "default"),
+			Concurrency:      getEnvAsInt("WORKER_CONCURRENCY", 10),
+			Queue:            getEnv("WORKER_QUEUE", "default"),
+			ReminderInterval: getEnvAsDuration("WORKER_REMINDER_INTERVAL", time.Minute),
+			ReminderOffsets: getEnvAsDurations("WORKER_REMINDER_OFFSETS", []time.Duration{
+				-24 * time.Hour,
+				0,
+				24 * time.Hour,
+			}),
+			PurgeInterval:        getEnvAsDuration("WORKER_PURGE_INTERVAL", time.Hour),
+			DeletedTaskRetention: getEnvAsDuration("WORKER_DELETED_TASK_RETENTION", 30*24*time.Hour),
+			ArchiveInterval:      getEnvAsDuration("WORKER_ARCHIVE_INTERVAL", time.Hour),
+			ArchiveDoneAfter:     getEnvAsDuration("WORKER_ARCHIVE_DONE_AFTER", 14*24*time.Hour),
+			JobInterval:          getEnvAsDuration("WORKER_JOB_INTERVAL", 5*time.Second),
+			JobTimeout:           getEnvAsDuration("WORKER_JOB_TIMEOUT", time.Hour),
+			ExportRetention:      getEnvAsDuration("WORKER_EXPORT_RETENTION", 7*24*time.Hour),
 		},
 	}
 
@@ -107,3 +148,34 @@ func getEnvAsInt(key string, fallback int) int {
 	}
 	return intValue
 }
+
+func getEnvAsDuration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		return fallback
+	}
+	return duration
+}
+
+func getEnvAsDurations(key string, fallback []time.Duration) []time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	parts := strings.Split(value, ",")
+	durations := make([]time.Duration, 0, len(parts))
+	for _, part := range parts {
+		duration, err := time.ParseDuration(strings.TrimSpace(part))
+		if err != nil {
+			return fallback
+		}
+		durations = append(durations, duration)
+	}
+	return durations
+}
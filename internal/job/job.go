@@ -0,0 +1,87 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrJobNotFound = errors.New("job not found")
+
+type Status string
+
+const (
+	StatusQueued  Status = "queued"
+	StatusRunning Status = "running"
+	StatusDone    Status = "done"
+	StatusFailed  Status = "failed"
+)
+
+// Job is a unit of background work stored in the jobs table. Payload is the
+// input of the job's Handler and Result its output once the job is done.
+type Job struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	Payload    json.RawMessage `json:"-"`
+	Status     Status          `json:"status"`
+	Result     json.RawMessage `json:"result,omitempty"`
+	Error      string          `json:"error,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+	StartedAt  *time.Time      `json:"started_at,omitempty"`
+	FinishedAt *time.Time      `json:"finished_at,omitempty"`
+}
+
+type Repository interface {
+	Enqueue(ctx context.Context, job Job) error
+	Get(ctx context.Context, id string) (Job, error)
+	// ClaimNext marks the oldest queued job as running and returns it, or
+	// returns ErrJobNotFound if no job is queued.
+	ClaimNext(ctx context.Context, startedAt time.Time) (Job, error)
+	Finish(ctx context.Context, job Job) error
+	// FailStale marks the jobs running since before startedBefore as failed
+	// with the reason and returns how many there were.
+	FailStale(ctx context.Context, startedBefore, finishedAt time.Time, reason string) (int, error)
+}
+
+type Service interface {
+	Enqueue(ctx context.Context, jobType string, payload any) (Job, error)
+	Get(ctx context.Context, id string) (Job, error)
+}
+
+type service struct {
+	repo Repository
+	now  func() time.Time
+}
+
+func NewService(repo Repository) Service {
+	return &service{repo: repo, now: time.Now}
+}
+
+func (s *service) Enqueue(ctx context.Context, jobType string, payload any) (Job, error) {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return Job{}, err
+	}
+
+	queued := Job{
+		ID:        uuid.NewString(),
+		Type:      jobType,
+		Payload:   encoded,
+		Status:    StatusQueued,
+		CreatedAt: s.now().UTC().Truncate(time.Second),
+	}
+	if err := s.repo.Enqueue(ctx, queued); err != nil {
+		return Job{}, err
+	}
+	return queued, nil
+}
+
+func (s *service) Get(ctx context.Context, id string) (Job, error) {
+	if uuid.Validate(id) != nil {
+		return Job{}, ErrJobNotFound
+	}
+	return s.repo.Get(ctx, id)
+}
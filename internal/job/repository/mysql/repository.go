@@ -0,0 +1,150 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/job"
+)
+
+type Repository struct {
+	db *sql.DB
+}
+
+var _ job.Repository = (*Repository)(nil)
+
+const jobColumns = `id, type, payload, status, result, error, created_at, started_at, finished_at`
+
+func New(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) Enqueue(ctx context.Context, queued job.Job) error {
+	const query = `
+		INSERT INTO jobs (id, type, payload, status, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+	_, err := r.db.ExecContext(ctx, query, queued.ID, queued.Type, []byte(queued.Payload), queued.Status, queued.CreatedAt.UTC())
+	return err
+}
+
+func (r *Repository) Get(ctx context.Context, id string) (job.Job, error) {
+	query := `SELECT ` + jobColumns + ` FROM jobs WHERE id = ?`
+
+	found, err := scanJob(r.db.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return job.Job{}, job.ErrJobNotFound
+	}
+	return found, err
+}
+
+// ClaimNext skips jobs locked by other workers, so several workers can claim
+// jobs concurrently without taking the same one.
+func (r *Repository) ClaimNext(ctx context.Context, startedAt time.Time) (job.Job, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return job.Job{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `
+		SELECT ` + jobColumns + `
+		FROM jobs
+		WHERE status = 'queued'
+		ORDER BY created_at, id
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED
+	`
+	claimed, err := scanJob(tx.QueryRowContext(ctx, query))
+	if errors.Is(err, sql.ErrNoRows) {
+		return job.Job{}, job.ErrJobNotFound
+	}
+	if err != nil {
+		return job.Job{}, err
+	}
+
+	startedAt = startedAt.UTC().Truncate(time.Second)
+	if _, err := tx.ExecContext(ctx, `UPDATE jobs SET status = 'running', started_at = ? WHERE id = ?`, startedAt, claimed.ID); err != nil {
+		return job.Job{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return job.Job{}, err
+	}
+
+	claimed.Status = job.StatusRunning
+	claimed.StartedAt = &startedAt
+	return claimed, nil
+}
+
+func (r *Repository) Finish(ctx context.Context, finished job.Job) error {
+	const query = `
+		UPDATE jobs
+		SET status = ?, result = ?, error = ?, finished_at = ?
+		WHERE id = ?
+	`
+	var result any
+	if len(finished.Result) > 0 {
+		result = []byte(finished.Result)
+	}
+	var errorMessage any
+	if finished.Error != "" {
+		errorMessage = finished.Error
+	}
+	var finishedAt any
+	if finished.FinishedAt != nil {
+		finishedAt = finished.FinishedAt.UTC()
+	}
+
+	_, err := r.db.ExecContext(ctx, query, finished.Status, result, errorMessage, finishedAt, finished.ID)
+	return err
+}
+
+func (r *Repository) FailStale(ctx context.Context, startedBefore, finishedAt time.Time, reason string) (int, error) {
+	const query = `
+		UPDATE jobs
+		SET status = 'failed', error = ?, finished_at = ?
+		WHERE status = 'running' AND started_at < ?
+	`
+	result, err := r.db.ExecContext(ctx, query, reason, finishedAt.UTC(), startedBefore.UTC())
+	if err != nil {
+		return 0, err
+	}
+
+	failed, err := result.RowsAffected()
+	return int(failed), err
+}
+
+type sqlScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanJob(scanner sqlScanner) (job.Job, error) {
+	var (
+		found        job.Job
+		payload      []byte
+		result       []byte
+		errorMessage sql.NullString
+		startedAt    sql.NullTime
+		finishedAt   sql.NullTime
+	)
+	err := scanner.Scan(&found.ID, &found.Type, &payload, &found.Status, &result, &errorMessage, &found.CreatedAt, &startedAt, &finishedAt)
+	if err != nil {
+		return job.Job{}, err
+	}
+
+	found.Payload = payload
+	found.Result = result
+	found.Error = errorMessage.String
+	found.CreatedAt = found.CreatedAt.UTC()
+	if startedAt.Valid {
+		value := startedAt.Time.UTC()
+		found.StartedAt = &value
+	}
+	if finishedAt.Valid {
+		value := finishedAt.Time.UTC()
+		found.FinishedAt = &value
+	}
+	return found, nil
+}
@@ -0,0 +1,93 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Handler executes a job of one type; the returned value is stored as the
+// job's result.
+type Handler func(ctx context.Context, payload json.RawMessage) (any, error)
+
+// Runner executes queued jobs with the handler registered for their type.
+// The context of a job is cancelled after the timeout, unless it is zero.
+type Runner struct {
+	repo     Repository
+	handlers map[string]Handler
+	timeout  time.Duration
+	now      func() time.Time
+}
+
+func NewRunner(repo Repository, timeout time.Duration) *Runner {
+	return &Runner{repo: repo, handlers: make(map[string]Handler), timeout: timeout, now: time.Now}
+}
+
+func (r *Runner) Handle(jobType string, handler Handler) {
+	r.handlers[jobType] = handler
+}
+
+// RunQueued executes queued jobs one at a time until none is left and
+// returns how many it executed. A failing job is recorded as failed and does
+// not stop the others. A job interrupted by cancelling ctx is still recorded,
+// so it does not stay running.
+func (r *Runner) RunQueued(ctx context.Context) (int, error) {
+	executed := 0
+	for ctx.Err() == nil {
+		claimed, err := r.repo.ClaimNext(ctx, r.now())
+		if errors.Is(err, ErrJobNotFound) {
+			return executed, nil
+		}
+		if err != nil {
+			return executed, err
+		}
+
+		r.execute(ctx, &claimed)
+		finishedAt := r.now().UTC().Truncate(time.Second)
+		claimed.FinishedAt = &finishedAt
+		if err := r.repo.Finish(context.WithoutCancel(ctx), claimed); err != nil {
+			return executed, err
+		}
+		executed++
+	}
+	return executed, ctx.Err()
+}
+
+// FailStale fails the jobs that have been running for longer than the
+// timeout. Such jobs were left behind by a worker that crashed, because a
+// job's context is cancelled after the timeout.
+func (r *Runner) FailStale(ctx context.Context, now time.Time) (int, error) {
+	if r.timeout <= 0 {
+		return 0, nil
+	}
+	now = now.UTC().Truncate(time.Second)
+	return r.repo.FailStale(ctx, now.Add(-r.timeout), now, fmt.Sprintf("job did not finish within %s", r.timeout))
+}
+
+func (r *Runner) execute(ctx context.Context, claimed *Job) {
+	handler, ok := r.handlers[claimed.Type]
+	if !ok {
+		claimed.Status = StatusFailed
+		claimed.Error = fmt.Sprintf("no handler for job type %q", claimed.Type)
+		return
+	}
+
+	if r.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+	}
+
+	result, err := handler(ctx, claimed.Payload)
+	if err == nil {
+		claimed.Result, err = json.Marshal(result)
+	}
+	if err != nil {
+		claimed.Status = StatusFailed
+		claimed.Error = err.Error()
+		return
+	}
+	claimed.Status = StatusDone
+}
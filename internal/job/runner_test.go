@@ -0,0 +1,160 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+type mockRepository struct {
+	queued   []Job
+	finished []Job
+
+	staleBefore time.Time
+	staleReason string
+}
+
+func (m *mockRepository) Enqueue(_ context.Context, job Job) error {
+	m.queued = append(m.queued, job)
+	return nil
+}
+
+func (m *mockRepository) Get(context.Context, string) (Job, error) {
+	return Job{}, ErrJobNotFound
+}
+
+func (m *mockRepository) ClaimNext(_ context.Context, startedAt time.Time) (Job, error) {
+	if len(m.queued) == 0 {
+		return Job{}, ErrJobNotFound
+	}
+	claimed := m.queued[0]
+	m.queued = m.queued[1:]
+	claimed.Status = StatusRunning
+	claimed.StartedAt = &startedAt
+	return claimed, nil
+}
+
+// Finish fails on a cancelled context like a database driver does.
+func (m *mockRepository) Finish(ctx context.Context, job Job) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m.finished = append(m.finished, job)
+	return nil
+}
+
+func (m *mockRepository) FailStale(_ context.Context, startedBefore, _ time.Time, reason string) (int, error) {
+	m.staleBefore, m.staleReason = startedBefore, reason
+	return 1, nil
+}
+
+func TestRunnerRunQueued(t *testing.T) {
+	repo := &mockRepository{queued: []Job{
+		{ID: "1", Type: "sum", Payload: json.RawMessage(`[1,2]`)},
+		{ID: "2", Type: "fail"},
+		{ID: "3", Type: "unknown"},
+	}}
+	runner := NewRunner(repo, 0)
+	runner.Handle("sum", func(_ context.Context, payload json.RawMessage) (any, error) {
+		var numbers []int
+		if err := json.Unmarshal(payload, &numbers); err != nil {
+			return nil, err
+		}
+		return map[string]int{"sum": numbers[0] + numbers[1]}, nil
+	})
+	runner.Handle("fail", func(context.Context, json.RawMessage) (any, error) {
+		return nil, errors.New("boom")
+	})
+
+	executed, err := runner.RunQueued(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if executed != 3 || len(repo.finished) != 3 {
+		t.Fatalf("expected all jobs to be executed, got %d", executed)
+	}
+
+	if done := repo.finished[0]; done.Status != StatusDone || string(done.Result) != `{"sum":3}` || done.FinishedAt == nil {
+		t.Fatalf("unexpected done job: %+v", done)
+	}
+	if failed := repo.finished[1]; failed.Status != StatusFailed || failed.Error != "boom" {
+		t.Fatalf("unexpected failed job: %+v", failed)
+	}
+	if unknown := repo.finished[2]; unknown.Status != StatusFailed || unknown.Error != `no handler for job type "unknown"` {
+		t.Fatalf("unexpected job without handler: %+v", unknown)
+	}
+}
+
+func TestRunnerRunQueued_Cancelled(t *testing.T) {
+	repo := &mockRepository{queued: []Job{{ID: "1", Type: "wait"}, {ID: "2", Type: "wait"}}}
+	ctx, cancel := context.WithCancel(context.Background())
+	runner := NewRunner(repo, time.Hour)
+	runner.Handle("wait", func(ctx context.Context, _ json.RawMessage) (any, error) {
+		cancel()
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	executed, err := runner.RunQueued(ctx)
+	if !errors.Is(err, context.Canceled) || executed != 1 {
+		t.Fatalf("expected to stop after the interrupted job, got %d, %v", executed, err)
+	}
+	if len(repo.finished) != 1 || repo.finished[0].Status != StatusFailed || repo.finished[0].Error != context.Canceled.Error() {
+		t.Fatalf("expected the interrupted job to be recorded as failed, got %+v", repo.finished)
+	}
+	if len(repo.queued) != 1 {
+		t.Fatalf("expected the next job to stay queued, got %+v", repo.queued)
+	}
+}
+
+func TestRunnerRunQueued_Timeout(t *testing.T) {
+	repo := &mockRepository{queued: []Job{{ID: "1", Type: "wait"}}}
+	runner := NewRunner(repo, time.Millisecond)
+	runner.Handle("wait", func(ctx context.Context, _ json.RawMessage) (any, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	if _, err := runner.RunQueued(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.finished) != 1 || repo.finished[0].Error != context.DeadlineExceeded.Error() {
+		t.Fatalf("expected the job to time out, got %+v", repo.finished)
+	}
+}
+
+func TestRunnerFailStale(t *testing.T) {
+	repo := &mockRepository{}
+	now := time.Date(2026, 3, 2, 9, 30, 0, 0, time.UTC)
+
+	if failed, err := NewRunner(repo, 0).FailStale(context.Background(), now); err != nil || failed != 0 || !repo.staleBefore.IsZero() {
+		t.Fatalf("expected no stale jobs without a timeout, got %d, %v", failed, err)
+	}
+
+	failed, err := NewRunner(repo, time.Hour).FailStale(context.Background(), now)
+	if err != nil || failed != 1 {
+		t.Fatalf("unexpected result: %d, %v", failed, err)
+	}
+	if !repo.staleBefore.Equal(now.Add(-time.Hour)) || repo.staleReason != "job did not finish within 1h0m0s" {
+		t.Fatalf("unexpected stale query: %v %q", repo.staleBefore, repo.staleReason)
+	}
+}
+
+func TestServiceEnqueue(t *testing.T) {
+	repo := &mockRepository{}
+	svc := NewService(repo)
+
+	queued, err := svc.Enqueue(context.Background(), "sum", []int{1, 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if queued.ID == "" || queued.Status != StatusQueued || string(queued.Payload) != `[1,2]` || len(repo.queued) != 1 {
+		t.Fatalf("unexpected job: %+v", queued)
+	}
+
+	if _, err := svc.Get(context.Background(), "not-a-job"); !errors.Is(err, ErrJobNotFound) {
+		t.Fatalf("expected ErrJobNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package job
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+type PeriodicFunc func(ctx context.Context, now time.Time) error
+
+type periodicTask struct {
+	name     string
+	interval time.Duration
+	run      PeriodicFunc
+}
+
+// Scheduler runs registered functions on fixed intervals until its context is
+// cancelled. Each function runs in its own goroutine, and a slow run delays
+// only its own next tick.
+type Scheduler struct {
+	logger *slog.Logger
+	tasks  []periodicTask
+}
+
+func NewScheduler(logger *slog.Logger) *Scheduler {
+	return &Scheduler{logger: logger}
+}
+
+func (s *Scheduler) Every(name string, interval time.Duration, run PeriodicFunc) {
+	s.tasks = append(s.tasks, periodicTask{name: name, interval: interval, run: run})
+}
+
+func (s *Scheduler) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, periodic := range s.tasks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.loop(ctx, periodic)
+		}()
+	}
+	wg.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context, periodic periodicTask) {
+	ticker := time.NewTicker(periodic.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := periodic.run(ctx, now.UTC()); err != nil && ctx.Err() == nil {
+				s.logger.Error("periodic task failed", "task", periodic.name, "error", err)
+			}
+		}
+	}
+}
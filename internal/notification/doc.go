@@ -0,0 +1,3 @@
+package notification
+
+// Package notification contains notification types and delivery channels.
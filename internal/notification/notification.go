@@ -0,0 +1,52 @@
+package notification
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+type Type string
+
+const (
+	TypeTaskReminder  Type = "task.reminder"
+	TypeTaskUpdated   Type = "task.updated"
+	TypeTaskCommented Type = "task.commented"
+	TypeTaskMentioned Type = "task.mentioned"
+)
+
+type Notification struct {
+	Type       Type
+	TaskID     uint64
+	Recipients []string
+	Subject    string
+	Message    string
+	CreatedAt  time.Time
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+type LogNotifier struct {
+	logger *slog.Logger
+}
+
+var _ Notifier = (*LogNotifier)(nil)
+
+func NewLogNotifier(logger *slog.Logger) *LogNotifier {
+	return &LogNotifier{logger: logger}
+}
+
+func (n *LogNotifier) Notify(ctx context.Context, notification Notification) error {
+	n.logger.InfoContext(
+		ctx,
+		"notification",
+		"type", notification.Type,
+		"task_id", notification.TaskID,
+		"recipients", notification.Recipients,
+		"subject", notification.Subject,
+		"message", notification.Message,
+	)
+	return nil
+}
@@ -0,0 +1,81 @@
+package filestore
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Dir stores files flat in a local directory, e.g. a volume shared by the
+// api and the worker.
+type Dir struct {
+	path string
+}
+
+func New(path string) (*Dir, error) {
+	if err := os.MkdirAll(path, 0o750); err != nil {
+		return nil, err
+	}
+	return &Dir{path: path}, nil
+}
+
+// Save writes to a temporary file that is renamed to name once write
+// succeeds, so readers never see a partial file.
+func (d *Dir) Save(name string, write func(w io.Writer) error) error {
+	file, err := os.CreateTemp(d.path, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(file.Name()) }()
+
+	err = write(file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), d.resolve(name))
+}
+
+func (d *Dir) Open(name string) (io.ReadCloser, error) {
+	return os.Open(d.resolve(name))
+}
+
+// RemoveOlderThan removes the files last modified before cutoff, including
+// temporary files left behind by a crash.
+func (d *Dir) RemoveOlderThan(cutoff time.Time) (int, error) {
+	entries, err := os.ReadDir(d.path)
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return removed, err
+		}
+		if !info.ModTime().Before(cutoff) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(d.path, entry.Name())); err != nil && !os.IsNotExist(err) {
+			return removed, err
+		}
+		if !strings.HasPrefix(entry.Name(), ".tmp-") {
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// resolve keeps names from pointing outside the directory.
+func (d *Dir) resolve(name string) string {
+	return filepath.Join(d.path, filepath.Base(name))
+}
@@ -0,0 +1,77 @@
+package filestore
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDirSave(t *testing.T) {
+	dir, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := dir.Save("export.csv", func(w io.Writer) error {
+		_, err := io.WriteString(w, "id\n1\n")
+		return err
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	file, err := dir.Open("../export.csv")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	content, _ := io.ReadAll(file)
+	_ = file.Close()
+	if string(content) != "id\n1\n" {
+		t.Fatalf("unexpected content: %q", content)
+	}
+
+	failed := errors.New("export failed")
+	if err := dir.Save("failed.csv", func(w io.Writer) error { return failed }); !errors.Is(err, failed) {
+		t.Fatalf("expected the write error, got %v", err)
+	}
+	if _, err := dir.Open("failed.csv"); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected a failed save to leave no file, got %v", err)
+	}
+	entries, _ := os.ReadDir(dir.path)
+	if len(entries) != 1 {
+		t.Fatalf("expected temporary files to be removed, got %d entries", len(entries))
+	}
+}
+
+func TestDirRemoveOlderThan(t *testing.T) {
+	dir, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	now := time.Now()
+	for name, age := range map[string]time.Duration{"old.csv": 48 * time.Hour, ".tmp-123": 48 * time.Hour, "new.csv": time.Hour} {
+		path := filepath.Join(dir.path, name)
+		if err := os.WriteFile(path, []byte("x"), 0o600); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+		if err := os.Chtimes(path, now.Add(-age), now.Add(-age)); err != nil {
+			t.Fatalf("chtimes %s: %v", name, err)
+		}
+	}
+
+	removed, err := dir.RemoveOlderThan(now.Add(-24 * time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if removed != 1 {
+		t.Fatalf("expected 1 export to be removed, got %d", removed)
+	}
+	entries, _ := os.ReadDir(dir.path)
+	if len(entries) != 1 || entries[0].Name() != "new.csv" {
+		t.Fatalf("unexpected remaining files: %v", entries)
+	}
+}
@@ -0,0 +1,131 @@
+package task
+
+import (
+	"context"
+	"sort"
+	"strings"
+)
+
+const maxWIPLimit = 1000
+
+// boardColumns are the status columns of a board in display order.
+var boardColumns = []Status{StatusNew, StatusInProgress, StatusDone}
+
+// WIPLimitCheck makes a status change fail with ErrWIPLimitExceeded when
+// the project already has Limit unarchived tasks in the target status.
+type WIPLimitCheck struct {
+	ProjectID uint64
+	Limit     int
+}
+
+type BoardColumn struct {
+	Status   Status `json:"status"`
+	Count    int64  `json:"count"`
+	WIPLimit *int   `json:"wip_limit,omitempty"`
+	Tasks    []Task `json:"tasks"`
+}
+
+type Board struct {
+	ProjectID uint64        `json:"project_id"`
+	Columns   []BoardColumn `json:"columns"`
+}
+
+// BoardInput.Limit caps the number of tasks returned per column; counts
+// always include every task of the column.
+type BoardInput struct {
+	Limit int
+}
+
+type BoardRepository interface {
+	GetProject(ctx context.Context, id uint64) (Project, error)
+	List(ctx context.Context, filter ListFilter) ([]Task, error)
+	Summarize(ctx context.Context, filter ListFilter) (ListSummary, error)
+}
+
+type BoardService interface {
+	Get(ctx context.Context, projectID uint64, input BoardInput) (Board, error)
+}
+
+type boardService struct {
+	repo BoardRepository
+}
+
+func NewBoardService(repo BoardRepository) BoardService {
+	return &boardService{repo: repo}
+}
+
+// Get returns the unarchived tasks of a project grouped by status and ordered
+// by rank.
+func (s *boardService) Get(ctx context.Context, projectID uint64, input BoardInput) (Board, error) {
+	if projectID == 0 {
+		return Board{}, ValidationError{Field: "id", Message: "must be greater than 0"}
+	}
+
+	limit := input.Limit
+	if limit == 0 {
+		limit = defaultLimit
+	}
+	if limit < 1 || limit > maxLimit {
+		return Board{}, ValidationError{Field: "limit", Message: "must be between 1 and 100"}
+	}
+
+	project, err := s.repo.GetProject(ctx, projectID)
+	if err != nil {
+		return Board{}, err
+	}
+
+	board := Board{ProjectID: project.ID, Columns: make([]BoardColumn, 0, len(boardColumns))}
+	for _, status := range boardColumns {
+		filter := ListFilter{
+			ProjectID: &project.ID,
+			Statuses:  []Status{status},
+			Archived:  ArchivedExclude,
+			Sort:      []Sort{{Field: SortByRank}},
+			Limit:     limit,
+		}
+
+		tasks, err := s.repo.List(ctx, filter)
+		if err != nil {
+			return Board{}, err
+		}
+		summary, err := s.repo.Summarize(ctx, filter)
+		if err != nil {
+			return Board{}, err
+		}
+
+		column := BoardColumn{Status: status, Count: summary.Count, Tasks: tasks}
+		if wipLimit, ok := project.WIPLimits[status]; ok {
+			column.WIPLimit = &wipLimit
+		}
+		board.Columns = append(board.Columns, column)
+	}
+
+	return board, nil
+}
+
+// normalizeWIPLimits validates WIP limits keyed by status. A nil limit
+// removes the limit of the column.
+func normalizeWIPLimits(raw map[string]*int) (map[Status]int, error) {
+	keys := make([]string, 0, len(raw))
+	for key := range raw {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	limits := make(map[Status]int, len(raw))
+	for _, key := range keys {
+		limit := raw[key]
+		status := Status(strings.ToLower(strings.TrimSpace(key)))
+		if !status.IsValid() {
+			return nil, ValidationError{Field: "wip_limits." + key, Message: "is not a status column"}
+		}
+		if limit == nil {
+			continue
+		}
+		if *limit < 1 || *limit > maxWIPLimit {
+			return nil, ValidationError{Field: "wip_limits." + key, Message: "must be between 1 and 1000"}
+		}
+		limits[status] = *limit
+	}
+	return limits, nil
+}
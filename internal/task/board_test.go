@@ -0,0 +1,146 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type mockBoardRepository struct {
+	project Project
+	filters []ListFilter
+}
+
+func (m *mockBoardRepository) GetProject(_ context.Context, id uint64) (Project, error) {
+	if id != m.project.ID {
+		return Project{}, ErrProjectNotFound
+	}
+	return m.project, nil
+}
+
+func (m *mockBoardRepository) List(_ context.Context, filter ListFilter) ([]Task, error) {
+	m.filters = append(m.filters, filter)
+	return []Task{{ID: 1, Status: filter.Statuses[0]}}, nil
+}
+
+func (m *mockBoardRepository) Summarize(_ context.Context, filter ListFilter) (ListSummary, error) {
+	counts := map[Status]int64{StatusNew: 4, StatusInProgress: 2, StatusDone: 9}
+	return ListSummary{Count: counts[filter.Statuses[0]]}, nil
+}
+
+func TestBoardServiceGet(t *testing.T) {
+	repo := &mockBoardRepository{project: Project{ID: 2, WIPLimits: map[Status]int{StatusInProgress: 3}}}
+
+	board, err := NewBoardService(repo).Get(context.Background(), 2, BoardInput{Limit: 10})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(board.Columns) != 3 {
+		t.Fatalf("expected 3 columns, got %d", len(board.Columns))
+	}
+	inProgress := board.Columns[1]
+	if inProgress.Status != StatusInProgress || inProgress.Count != 2 || inProgress.WIPLimit == nil || *inProgress.WIPLimit != 3 {
+		t.Fatalf("unexpected in_progress column: %+v", inProgress)
+	}
+	if board.Columns[0].WIPLimit != nil {
+		t.Fatalf("expected no limit for the new column, got %v", *board.Columns[0].WIPLimit)
+	}
+
+	filter := repo.filters[0]
+	if *filter.ProjectID != 2 || filter.Archived != ArchivedExclude || filter.Limit != 10 || filter.Sort[0].Field != SortByRank {
+		t.Fatalf("unexpected filter: %+v", filter)
+	}
+}
+
+func TestBoardServiceGet_Errors(t *testing.T) {
+	repo := &mockBoardRepository{project: Project{ID: 2}}
+	svc := NewBoardService(repo)
+
+	if _, err := svc.Get(context.Background(), 9, BoardInput{}); !errors.Is(err, ErrProjectNotFound) {
+		t.Fatalf("expected ErrProjectNotFound, got %v", err)
+	}
+
+	_, err := svc.Get(context.Background(), 2, BoardInput{Limit: 101})
+	var validationErr ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Field != "limit" {
+		t.Fatalf("expected limit validation error, got %v", err)
+	}
+}
+
+func TestProjectServiceSetWIPLimits(t *testing.T) {
+	repo := &mockProjectRepository{}
+	svc := NewProjectService(repo)
+
+	three := 3
+	_, err := svc.SetWIPLimits(context.Background(), 1, map[string]*int{"In_Progress": &three, "done": nil})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.wipLimits) != 1 || repo.wipLimits[StatusInProgress] != 3 {
+		t.Fatalf("unexpected limits: %v", repo.wipLimits)
+	}
+
+	zero := 0
+	for key, limit := range map[string]*int{"blocked": &three, "new": &zero} {
+		_, err := svc.SetWIPLimits(context.Background(), 1, map[string]*int{key: limit})
+		var validationErr ValidationError
+		if !errors.As(err, &validationErr) || validationErr.Field != "wip_limits."+key {
+			t.Fatalf("expected validation error for %s, got %v", key, err)
+		}
+	}
+}
+
+func TestServiceUpdate_WIPLimit(t *testing.T) {
+	projectID := uint64(2)
+	project := Project{ID: 2, WIPLimits: map[Status]int{StatusInProgress: 3}}
+	status := string(StatusInProgress)
+
+	tests := []struct {
+		name    string
+		current Status
+		force   bool
+		want    bool
+	}{
+		{name: "status change into limited column", current: StatusNew, want: true},
+		{name: "forced", current: StatusNew, force: true, want: false},
+		{name: "status unchanged", current: StatusInProgress, want: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockRepository{
+				project:   project,
+				getResult: Task{ID: 5, ProjectID: &projectID, Status: tc.current},
+			}
+
+			_, err := NewService(repo).Update(context.Background(), 5, UpdateTaskInput{Status: &status, Force: tc.force})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			check := repo.updateParams.WIPLimit
+			if (check != nil) != tc.want {
+				t.Fatalf("expected WIP limit check %v, got %+v", tc.want, check)
+			}
+			if check != nil && (check.ProjectID != 2 || check.Limit != 3) {
+				t.Fatalf("unexpected check: %+v", check)
+			}
+		})
+	}
+}
+
+func TestServiceUpdate_WIPLimitExceeded(t *testing.T) {
+	projectID := uint64(2)
+	repo := &mockRepository{
+		project:   Project{ID: 2, WIPLimits: map[Status]int{StatusInProgress: 1}},
+		getResult: Task{ID: 5, ProjectID: &projectID, Status: StatusNew},
+		updateErr: ErrWIPLimitExceeded,
+	}
+	status := string(StatusInProgress)
+
+	_, err := NewService(repo).Update(context.Background(), 5, UpdateTaskInput{Status: &status})
+	if !errors.Is(err, ErrWIPLimitExceeded) {
+		t.Fatalf("expected ErrWIPLimitExceeded, got %v", err)
+	}
+}
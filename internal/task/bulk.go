@@ -0,0 +1,330 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/job"
+)
+
+const (
+	maxBulkOperations = 1000
+	// BulkJobType is the job type of bulk operations run in the background.
+	BulkJobType = "task.bulk"
+)
+
+type BulkAction string
+
+const (
+	BulkCreate BulkAction = "create"
+	BulkUpdate BulkAction = "update"
+	BulkDelete BulkAction = "delete"
+)
+
+// BulkOperation uses Create, Update or Delete depending on Action; ID is the
+// task to update or delete.
+type BulkOperation struct {
+	Action BulkAction
+	ID     uint64
+	Create CreateTaskInput
+	Update UpdateTaskInput
+	Delete DeleteTaskInput
+}
+
+// BulkInput either lists Operations or applies Update to every task matching
+// the Filter expression. Atomic runs all operations in one transaction that
+// is rolled back if any of them fails; otherwise every operation succeeds or
+// fails on its own.
+type BulkInput struct {
+	Operations []BulkOperation
+	Filter     string
+	Update     UpdateTaskInput
+	Atomic     bool
+}
+
+type BulkItemStatus string
+
+const (
+	BulkItemSucceeded  BulkItemStatus = "succeeded"
+	BulkItemFailed     BulkItemStatus = "failed"
+	BulkItemRolledBack BulkItemStatus = "rolled_back"
+	BulkItemSkipped    BulkItemStatus = "skipped"
+)
+
+type BulkItemResult struct {
+	Index  int            `json:"index"`
+	Action BulkAction     `json:"action"`
+	ID     uint64         `json:"id,omitempty"`
+	Status BulkItemStatus `json:"status"`
+	Task   *Task          `json:"task,omitempty"`
+	Error  string         `json:"error,omitempty"`
+	Field  string         `json:"field,omitempty"`
+}
+
+// BulkResult.Committed is false if an atomic run was rolled back.
+type BulkResult struct {
+	Atomic    bool             `json:"atomic"`
+	Committed bool             `json:"committed"`
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+	Items     []BulkItemResult `json:"items"`
+}
+
+// BulkSubmission holds the result of a bulk run, or the queued job if the
+// run was moved to the background.
+type BulkSubmission struct {
+	Result *BulkResult
+	Job    *job.Job
+}
+
+type BulkRepository interface {
+	RunInTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type BulkService interface {
+	// Submit runs the operations, or queues them as a job if there are more
+	// than the async threshold.
+	Submit(ctx context.Context, input BulkInput) (BulkSubmission, error)
+	Run(ctx context.Context, input BulkInput) (BulkResult, error)
+}
+
+type bulkService struct {
+	tasks          Service
+	repo           BulkRepository
+	jobs           job.Service
+	asyncThreshold int
+}
+
+// NewBulkService applies every operation through the task service, so bulk
+// operations are validated like single ones. The notifications of an atomic
+// run are sent once it is committed, and not at all if it is rolled back.
+func NewBulkService(tasks Service, repo BulkRepository, jobs job.Service, asyncThreshold int) BulkService {
+	return &bulkService{tasks: tasks, repo: repo, jobs: jobs, asyncThreshold: asyncThreshold}
+}
+
+// NewBulkJobHandler runs the bulk operations queued by Submit.
+func NewBulkJobHandler(service BulkService) job.Handler {
+	return func(ctx context.Context, payload json.RawMessage) (any, error) {
+		var input BulkInput
+		if err := json.Unmarshal(payload, &input); err != nil {
+			return nil, err
+		}
+		return service.Run(ctx, input)
+	}
+}
+
+func (s *bulkService) Submit(ctx context.Context, input BulkInput) (BulkSubmission, error) {
+	if err := validateBulkInput(input); err != nil {
+		return BulkSubmission{}, err
+	}
+
+	size := len(input.Operations)
+	if input.Filter != "" {
+		summary, err := s.tasks.Summarize(ctx, ListTasksInput{Filter: input.Filter})
+		if err != nil {
+			return BulkSubmission{}, err
+		}
+		if err := checkBulkFilterSize(summary.Count); err != nil {
+			return BulkSubmission{}, err
+		}
+		size = int(summary.Count)
+	}
+
+	if s.jobs != nil && s.asyncThreshold > 0 && size > s.asyncThreshold {
+		queued, err := s.jobs.Enqueue(ctx, BulkJobType, input)
+		if err != nil {
+			return BulkSubmission{}, err
+		}
+		return BulkSubmission{Job: &queued}, nil
+	}
+
+	result, err := s.Run(ctx, input)
+	if err != nil {
+		return BulkSubmission{}, err
+	}
+	return BulkSubmission{Result: &result}, nil
+}
+
+func (s *bulkService) Run(ctx context.Context, input BulkInput) (BulkResult, error) {
+	if err := validateBulkInput(input); err != nil {
+		return BulkResult{}, err
+	}
+
+	operations := input.Operations
+	if input.Filter != "" {
+		var err error
+		operations, err = s.filterOperations(ctx, input.Filter, input.Update)
+		if err != nil {
+			return BulkResult{}, err
+		}
+	}
+
+	result := BulkResult{Atomic: input.Atomic, Items: make([]BulkItemResult, len(operations))}
+	for i, operation := range operations {
+		result.Items[i] = BulkItemResult{Index: i, Action: operation.Action, ID: operation.ID, Status: BulkItemSkipped}
+	}
+
+	if !input.Atomic {
+		for i, operation := range operations {
+			s.apply(ctx, operation, &result.Items[i])
+		}
+		result.Committed = true
+		result.count()
+		return result, nil
+	}
+
+	errRolledBack := errors.New("bulk operation failed")
+	pending := &pendingNotifications{}
+	err := s.repo.RunInTx(withPendingNotifications(ctx, pending), func(ctx context.Context) error {
+		for i, operation := range operations {
+			if !s.apply(ctx, operation, &result.Items[i]) {
+				return errRolledBack
+			}
+		}
+		return nil
+	})
+	switch {
+	case err == nil:
+		result.Committed = true
+		pending.send(ctx)
+	case errors.Is(err, errRolledBack):
+		for i := range result.Items {
+			if result.Items[i].Status == BulkItemSucceeded {
+				result.Items[i].Status = BulkItemRolledBack
+			}
+		}
+	default:
+		return BulkResult{}, err
+	}
+
+	result.count()
+	return result, nil
+}
+
+// apply runs one operation and records its outcome in item; it reports
+// whether the operation succeeded.
+func (s *bulkService) apply(ctx context.Context, operation BulkOperation, item *BulkItemResult) bool {
+	var (
+		changed Task
+		err     error
+	)
+	switch operation.Action {
+	case BulkCreate:
+		changed, err = s.tasks.Create(ctx, operation.Create)
+	case BulkUpdate:
+		changed, err = s.tasks.Update(ctx, operation.ID, operation.Update)
+	case BulkDelete:
+		err = s.tasks.Delete(ctx, operation.ID, operation.Delete)
+	}
+
+	if err != nil {
+		item.Status = BulkItemFailed
+		item.Error, item.Field = bulkItemError(err)
+		return false
+	}
+
+	item.Status = BulkItemSucceeded
+	if operation.Action != BulkDelete {
+		item.ID = changed.ID
+		item.Task = &changed
+	}
+	return true
+}
+
+// filterOperations resolves the tasks matching the filter before any of them
+// is updated, so an update that changes whether a task matches does not
+// affect which tasks are updated.
+func (s *bulkService) filterOperations(ctx context.Context, filter string, update UpdateTaskInput) ([]BulkOperation, error) {
+	operations := make([]BulkOperation, 0)
+	cursor := ""
+	for {
+		page, err := s.tasks.ListPage(ctx, ListTasksInput{Filter: filter, Cursor: cursor, Limit: maxLimit})
+		if err != nil {
+			return nil, err
+		}
+		for _, matched := range page.Items {
+			operations = append(operations, BulkOperation{Action: BulkUpdate, ID: matched.ID, Update: update})
+		}
+		if err := checkBulkFilterSize(int64(len(operations))); err != nil {
+			return nil, err
+		}
+		if page.NextCursor == "" {
+			return operations, nil
+		}
+		cursor = page.NextCursor
+	}
+}
+
+func (r *BulkResult) count() {
+	for _, item := range r.Items {
+		switch item.Status {
+		case BulkItemSucceeded:
+			r.Succeeded++
+		case BulkItemFailed:
+			r.Failed++
+		}
+	}
+}
+
+func validateBulkInput(input BulkInput) error {
+	if input.Filter != "" {
+		if len(input.Operations) > 0 {
+			return ValidationError{Field: "operations", Message: "cannot be provided together with filter"}
+		}
+		return nil
+	}
+
+	if len(input.Operations) == 0 {
+		return ValidationError{Field: "operations", Message: "must contain at least one operation or a filter must be provided"}
+	}
+	if len(input.Operations) > maxBulkOperations {
+		return ValidationError{Field: "operations", Message: fmt.Sprintf("must contain at most %d operations", maxBulkOperations)}
+	}
+
+	for i, operation := range input.Operations {
+		field := "operations[" + strconv.Itoa(i) + "]"
+		switch operation.Action {
+		case BulkCreate:
+		case BulkUpdate, BulkDelete:
+			if operation.ID == 0 {
+				return ValidationError{Field: field + ".id", Message: "must be greater than 0"}
+			}
+		default:
+			return ValidationError{Field: field + ".action", Message: "must be one of: create, update, delete"}
+		}
+	}
+	return nil
+}
+
+func checkBulkFilterSize(count int64) error {
+	if count > maxBulkOperations {
+		return ValidationError{Field: "filter", Message: fmt.Sprintf("matches more than %d tasks", maxBulkOperations)}
+	}
+	return nil
+}
+
+// bulkItemError describes a failed operation without exposing internal
+// errors.
+func bulkItemError(err error) (message, field string) {
+	var validationErr ValidationError
+	if errors.As(err, &validationErr) {
+		return validationErr.Message, validationErr.Field
+	}
+	for _, known := range []error{
+		ErrTaskNotFound,
+		ErrVersionConflict,
+		ErrWIPLimitExceeded,
+		ErrProjectNotFound,
+		ErrSprintNotFound,
+		ErrMilestoneNotFound,
+		ErrCustomFieldNotFound,
+	} {
+		if errors.Is(err, known) {
+			return err.Error(), ""
+		}
+	}
+	return "internal error", ""
+}
@@ -0,0 +1,310 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/job"
+	"github.com/PavelFesenkoFirst/task_tracker/internal/notification"
+)
+
+type mockBulkTaskService struct {
+	createErrs map[string]error
+	updateErrs map[uint64]error
+	deleteErrs map[uint64]error
+	pages      map[string]TaskPage
+	count      int64
+	// notifier is notified about every update, like watchers are.
+	notifier notification.Notifier
+
+	created   []string
+	validated []string
+	updated   []uint64
+	deleted   []uint64
+	listed    []ListTasksInput
+}
+
+func (m *mockBulkTaskService) Create(_ context.Context, input CreateTaskInput) (Task, error) {
+	if err := m.createErrs[input.Title]; err != nil {
+		return Task{}, err
+	}
+	m.created = append(m.created, input.Title)
+	return Task{ID: uint64(100 + len(m.created)), Title: input.Title}, nil
+}
+
+func (m *mockBulkTaskService) ValidateCreate(_ context.Context, input CreateTaskInput) error {
+	m.validated = append(m.validated, input.Title)
+	return m.createErrs[input.Title]
+}
+
+func (m *mockBulkTaskService) GetByID(context.Context, uint64) (Task, error) {
+	return Task{}, nil
+}
+
+func (m *mockBulkTaskService) List(context.Context, ListTasksInput) ([]Task, error) {
+	return nil, nil
+}
+
+func (m *mockBulkTaskService) ListPage(_ context.Context, input ListTasksInput) (TaskPage, error) {
+	m.listed = append(m.listed, input)
+	return m.pages[input.Cursor], nil
+}
+
+func (m *mockBulkTaskService) Summarize(context.Context, ListTasksInput) (ListSummary, error) {
+	return ListSummary{Count: m.count}, nil
+}
+
+func (m *mockBulkTaskService) Update(ctx context.Context, id uint64, _ UpdateTaskInput) (Task, error) {
+	if err := m.updateErrs[id]; err != nil {
+		return Task{}, err
+	}
+	m.updated = append(m.updated, id)
+	if m.notifier != nil {
+		notify(ctx, m.notifier, notification.Notification{TaskID: id})
+	}
+	return Task{ID: id}, nil
+}
+
+func (m *mockBulkTaskService) Delete(_ context.Context, id uint64, _ DeleteTaskInput) error {
+	if err := m.deleteErrs[id]; err != nil {
+		return err
+	}
+	m.deleted = append(m.deleted, id)
+	return nil
+}
+
+func (m *mockBulkTaskService) Restore(context.Context, uint64) (Task, error) {
+	return Task{}, nil
+}
+
+func (m *mockBulkTaskService) Archive(context.Context, uint64) (Task, error) {
+	return Task{}, nil
+}
+
+func (m *mockBulkTaskService) Unarchive(context.Context, uint64) (Task, error) {
+	return Task{}, nil
+}
+
+func (m *mockBulkTaskService) Move(context.Context, uint64, MoveTaskInput) (Task, error) {
+	return Task{}, nil
+}
+
+type mockBulkRepository struct {
+	txCalls int
+	txErr   error
+}
+
+func (m *mockBulkRepository) RunInTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.txCalls++
+	if err := fn(ctx); err != nil {
+		return err
+	}
+	return m.txErr
+}
+
+type mockJobService struct {
+	jobType string
+	payload any
+}
+
+func (m *mockJobService) Enqueue(_ context.Context, jobType string, payload any) (job.Job, error) {
+	m.jobType = jobType
+	m.payload = payload
+	return job.Job{ID: "job-1", Type: jobType, Status: job.StatusQueued}, nil
+}
+
+func (m *mockJobService) Get(context.Context, string) (job.Job, error) {
+	return job.Job{}, job.ErrJobNotFound
+}
+
+func bulkStatuses(result BulkResult) []BulkItemStatus {
+	statuses := make([]BulkItemStatus, 0, len(result.Items))
+	for _, item := range result.Items {
+		statuses = append(statuses, item.Status)
+	}
+	return statuses
+}
+
+func TestBulkServiceRun_PerItem(t *testing.T) {
+	tasks := &mockBulkTaskService{
+		createErrs: map[string]error{"": ValidationError{Field: "title", Message: "must not be empty"}},
+		deleteErrs: map[uint64]error{9: ErrTaskNotFound},
+		updateErrs: map[uint64]error{4: errors.New("connection reset")},
+	}
+	repo := &mockBulkRepository{}
+	svc := NewBulkService(tasks, repo, nil, 0)
+
+	result, err := svc.Run(context.Background(), BulkInput{Operations: []BulkOperation{
+		{Action: BulkCreate, Create: CreateTaskInput{Title: "Write docs"}},
+		{Action: BulkCreate},
+		{Action: BulkUpdate, ID: 3},
+		{Action: BulkUpdate, ID: 4},
+		{Action: BulkDelete, ID: 9},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []BulkItemStatus{BulkItemSucceeded, BulkItemFailed, BulkItemSucceeded, BulkItemFailed, BulkItemFailed}
+	if !reflect.DeepEqual(bulkStatuses(result), expected) {
+		t.Fatalf("unexpected statuses: %v", bulkStatuses(result))
+	}
+	if !result.Committed || result.Succeeded != 2 || result.Failed != 3 || repo.txCalls != 0 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if result.Items[0].ID != 101 || result.Items[0].Task == nil {
+		t.Fatalf("expected the created task, got %+v", result.Items[0])
+	}
+	if result.Items[1].Field != "title" || result.Items[3].Error != "internal error" || result.Items[4].Error != ErrTaskNotFound.Error() {
+		t.Fatalf("unexpected errors: %+v", result.Items)
+	}
+}
+
+func TestBulkServiceRun_AtomicRollsBack(t *testing.T) {
+	tasks := &mockBulkTaskService{updateErrs: map[uint64]error{4: ErrVersionConflict}}
+	repo := &mockBulkRepository{}
+	svc := NewBulkService(tasks, repo, nil, 0)
+
+	result, err := svc.Run(context.Background(), BulkInput{Atomic: true, Operations: []BulkOperation{
+		{Action: BulkUpdate, ID: 3},
+		{Action: BulkUpdate, ID: 4},
+		{Action: BulkDelete, ID: 5},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []BulkItemStatus{BulkItemRolledBack, BulkItemFailed, BulkItemSkipped}
+	if !reflect.DeepEqual(bulkStatuses(result), expected) {
+		t.Fatalf("unexpected statuses: %v", bulkStatuses(result))
+	}
+	if result.Committed || result.Succeeded != 0 || result.Failed != 1 || repo.txCalls != 1 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if len(tasks.deleted) != 0 {
+		t.Fatal("expected operations after the failure not to run")
+	}
+
+	repo.txErr = errors.New("commit failed")
+	if _, err := svc.Run(context.Background(), BulkInput{Atomic: true, Operations: []BulkOperation{{Action: BulkDelete, ID: 5}}}); err == nil {
+		t.Fatal("expected the commit error")
+	}
+}
+
+func TestBulkServiceRun_AtomicNotifiesAfterCommit(t *testing.T) {
+	notifier := &mockNotifier{}
+	tasks := &mockBulkTaskService{notifier: notifier, updateErrs: map[uint64]error{4: ErrVersionConflict}}
+	repo := &mockBulkRepository{}
+	svc := NewBulkService(tasks, repo, nil, 0)
+
+	_, err := svc.Run(context.Background(), BulkInput{Atomic: true, Operations: []BulkOperation{
+		{Action: BulkUpdate, ID: 3},
+		{Action: BulkUpdate, ID: 4},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(notifier.notifications) != 0 {
+		t.Fatalf("expected no notifications for a rolled back run, got %+v", notifier.notifications)
+	}
+
+	repo.txErr = errors.New("commit failed")
+	if _, err := svc.Run(context.Background(), BulkInput{Atomic: true, Operations: []BulkOperation{{Action: BulkUpdate, ID: 3}}}); err == nil {
+		t.Fatal("expected the commit error")
+	}
+	if len(notifier.notifications) != 0 {
+		t.Fatalf("expected no notifications for a failed commit, got %+v", notifier.notifications)
+	}
+
+	repo.txErr = nil
+	_, err = svc.Run(context.Background(), BulkInput{Atomic: true, Operations: []BulkOperation{
+		{Action: BulkUpdate, ID: 3},
+		{Action: BulkUpdate, ID: 5},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(notifier.notifications) != 2 || notifier.notifications[0].TaskID != 3 || notifier.notifications[1].TaskID != 5 {
+		t.Fatalf("expected the notifications after the commit, got %+v", notifier.notifications)
+	}
+}
+
+func TestBulkServiceRun_Filter(t *testing.T) {
+	tasks := &mockBulkTaskService{pages: map[string]TaskPage{
+		"":     {Items: []Task{{ID: 8}, {ID: 7}}, NextCursor: "next"},
+		"next": {Items: []Task{{ID: 3}}},
+	}}
+	svc := NewBulkService(tasks, &mockBulkRepository{}, nil, 0)
+
+	status := "done"
+	result, err := svc.Run(context.Background(), BulkInput{Filter: "status = new", Update: UpdateTaskInput{Status: &status}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(tasks.updated, []uint64{8, 7, 3}) || result.Succeeded != 3 {
+		t.Fatalf("unexpected updates: %v", tasks.updated)
+	}
+	if len(tasks.listed) != 2 || tasks.listed[0].Filter != "status = new" || tasks.listed[0].Limit != maxLimit {
+		t.Fatalf("unexpected listing: %+v", tasks.listed)
+	}
+}
+
+func TestBulkServiceSubmit_Async(t *testing.T) {
+	tasks := &mockBulkTaskService{count: 3}
+	jobs := &mockJobService{}
+	svc := NewBulkService(tasks, &mockBulkRepository{}, jobs, 2)
+
+	input := BulkInput{Filter: "priority >= 4", Update: UpdateTaskInput{Force: true}}
+	submission, err := svc.Submit(context.Background(), input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if submission.Job == nil || submission.Result != nil || jobs.jobType != BulkJobType {
+		t.Fatalf("expected a queued job, got %+v", submission)
+	}
+	if !reflect.DeepEqual(jobs.payload, input) || len(tasks.listed) != 0 {
+		t.Fatal("expected the input to be queued without running it")
+	}
+
+	submission, err = svc.Submit(context.Background(), BulkInput{Operations: []BulkOperation{{Action: BulkDelete, ID: 1}, {Action: BulkDelete, ID: 2}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if submission.Result == nil || submission.Result.Succeeded != 2 {
+		t.Fatalf("expected operations up to the threshold to run directly, got %+v", submission)
+	}
+
+	tasks.count = maxBulkOperations + 1
+	_, err = svc.Submit(context.Background(), input)
+	var validationErr ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Field != "filter" {
+		t.Fatalf("expected filter validation error, got %v", err)
+	}
+}
+
+func TestBulkServiceRun_InvalidInput(t *testing.T) {
+	tests := []struct {
+		name  string
+		input BulkInput
+		field string
+	}{
+		{name: "empty", input: BulkInput{}, field: "operations"},
+		{name: "filter and operations", input: BulkInput{Filter: "status = new", Operations: []BulkOperation{{Action: BulkCreate}}}, field: "operations"},
+		{name: "too many", input: BulkInput{Operations: make([]BulkOperation, maxBulkOperations+1)}, field: "operations"},
+		{name: "unknown action", input: BulkInput{Operations: []BulkOperation{{Action: BulkCreate}, {Action: "archive", ID: 1}}}, field: "operations[1].action"},
+		{name: "missing id", input: BulkInput{Operations: []BulkOperation{{Action: BulkDelete}}}, field: "operations[0].id"},
+	}
+
+	svc := NewBulkService(&mockBulkTaskService{}, &mockBulkRepository{}, nil, 0)
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := svc.Run(context.Background(), tc.input)
+			var validationErr ValidationError
+			if !errors.As(err, &validationErr) || validationErr.Field != tc.field {
+				t.Fatalf("expected %s validation error, got %v", tc.field, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,333 @@
+package task
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	calendarTokenBytes = 32
+	// calendarUIDDomain makes the UID of a task's calendar entry globally
+	// unique; it must never change, or subscribed calendars duplicate
+	// every entry.
+	calendarUIDDomain = "task-tracker"
+	icsTimeLayout     = "20060102T150405Z"
+	icsMaxLineOctets  = 75
+)
+
+// CalendarFeed grants access to an iCalendar feed to anyone who knows its
+// token, so calendar apps can subscribe without credentials. A feed without
+// ProjectID lists the tasks assigned to its owner, a feed with ProjectID the
+// tasks of the project. Token and URL are only set when the feed is created.
+type CalendarFeed struct {
+	ID        uint64    `json:"id"`
+	OwnerID   string    `json:"owner_id"`
+	ProjectID *uint64   `json:"project_id,omitempty"`
+	Token     string    `json:"token,omitempty"`
+	URL       string    `json:"url,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type CreateCalendarFeedInput struct {
+	OwnerID   string
+	ProjectID *uint64
+}
+
+// CalendarFeedParams.TokenHash is the SHA-256 of the token; the token itself
+// is not stored.
+type CalendarFeedParams struct {
+	OwnerID   string
+	ProjectID *uint64
+	TokenHash string
+}
+
+// CalendarComponent is how tasks appear in a calendar: as events at their
+// due time or as to-dos due then.
+type CalendarComponent string
+
+const (
+	CalendarEvent CalendarComponent = "vevent"
+	CalendarTodo  CalendarComponent = "vtodo"
+)
+
+// ParseCalendarComponent defaults to events, which more calendar apps show.
+func ParseCalendarComponent(raw string) (CalendarComponent, error) {
+	switch component := CalendarComponent(strings.ToLower(strings.TrimSpace(raw))); component {
+	case "":
+		return CalendarEvent, nil
+	case CalendarEvent, CalendarTodo:
+		return component, nil
+	default:
+		return "", ValidationError{Field: "component", Message: "must be one of: vevent, vtodo"}
+	}
+}
+
+// CalendarInput narrows the tasks of a feed with the filters of a task list;
+// tasks without a due date and the pagination of List are ignored.
+type CalendarInput struct {
+	Token     string
+	Component string
+	List      ListTasksInput
+}
+
+type CalendarRepository interface {
+	CreateCalendarFeed(ctx context.Context, params CalendarFeedParams) (CalendarFeed, error)
+	FindCalendarFeed(ctx context.Context, tokenHash string) (CalendarFeed, error)
+	ListCalendarFeeds(ctx context.Context, ownerID string) ([]CalendarFeed, error)
+	DeleteCalendarFeed(ctx context.Context, id uint64, ownerID string) error
+	GetProject(ctx context.Context, id uint64) (Project, error)
+	StreamTasks(ctx context.Context, filter ListFilter, fn func(Task) error) error
+}
+
+type CalendarService interface {
+	CreateFeed(ctx context.Context, input CreateCalendarFeedInput) (CalendarFeed, error)
+	ListFeeds(ctx context.Context, ownerID string) ([]CalendarFeed, error)
+	// DeleteFeed revokes a feed of the owner.
+	DeleteFeed(ctx context.Context, id uint64, ownerID string) error
+	// Calendar writes the feed with the token to w. An unknown token and
+	// invalid input are reported before anything is written.
+	Calendar(ctx context.Context, input CalendarInput, w io.Writer) error
+}
+
+type calendarService struct {
+	repo CalendarRepository
+	now  func() time.Time
+}
+
+func NewCalendarService(repo CalendarRepository) CalendarService {
+	return &calendarService{repo: repo, now: time.Now}
+}
+
+func (s *calendarService) CreateFeed(ctx context.Context, input CreateCalendarFeedInput) (CalendarFeed, error) {
+	ownerID, err := normalizeUserID(input.OwnerID)
+	if err != nil {
+		return CalendarFeed{}, err
+	}
+	if input.ProjectID != nil {
+		if _, err := s.project(ctx, *input.ProjectID); err != nil {
+			return CalendarFeed{}, err
+		}
+	}
+
+	secret := make([]byte, calendarTokenBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return CalendarFeed{}, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(secret)
+
+	feed, err := s.repo.CreateCalendarFeed(ctx, CalendarFeedParams{OwnerID: ownerID, ProjectID: input.ProjectID, TokenHash: hashCalendarToken(token)})
+	if err != nil {
+		return CalendarFeed{}, err
+	}
+	feed.Token = token
+	feed.URL = "/calendar.ics?token=" + token
+	return feed, nil
+}
+
+func (s *calendarService) ListFeeds(ctx context.Context, ownerID string) ([]CalendarFeed, error) {
+	ownerID, err := normalizeUserID(ownerID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.ListCalendarFeeds(ctx, ownerID)
+}
+
+func (s *calendarService) DeleteFeed(ctx context.Context, id uint64, ownerID string) error {
+	ownerID, err := normalizeUserID(ownerID)
+	if err != nil {
+		return err
+	}
+	return s.repo.DeleteCalendarFeed(ctx, id, ownerID)
+}
+
+func (s *calendarService) Calendar(ctx context.Context, input CalendarInput, w io.Writer) error {
+	if strings.TrimSpace(input.Token) == "" {
+		return ErrCalendarFeedNotFound
+	}
+	feed, err := s.repo.FindCalendarFeed(ctx, hashCalendarToken(input.Token))
+	if err != nil {
+		return err
+	}
+
+	component, err := ParseCalendarComponent(input.Component)
+	if err != nil {
+		return err
+	}
+
+	list := input.List
+	list.Cursor, list.Limit, list.Offset = "", 0, 0
+	filter, err := buildListFilter(list, s.now())
+	if err != nil {
+		return err
+	}
+
+	hasDueDate := true
+	filter.HasDueDate = &hasDueDate
+	name := "My tasks"
+	if feed.ProjectID != nil {
+		project, err := s.project(ctx, *feed.ProjectID)
+		if err != nil {
+			return err
+		}
+		filter.ProjectID = feed.ProjectID
+		name = project.Name
+	} else {
+		assigned := FilterComparison{Field: FilterAssignee, Operator: FilterEq, Value: feed.OwnerID}
+		if filter.Expr == nil {
+			filter.Expr = assigned
+		} else {
+			filter.Expr = FilterAnd{Operands: []FilterExpr{assigned, filter.Expr}}
+		}
+	}
+
+	calendar := &icsWriter{w: w}
+	calendar.line("BEGIN", "VCALENDAR")
+	calendar.line("VERSION", "2.0")
+	calendar.line("PRODID", "-//task_tracker//Tasks//EN")
+	calendar.line("CALSCALE", "GREGORIAN")
+	calendar.line("METHOD", "PUBLISH")
+	calendar.text("X-WR-CALNAME", name)
+	calendar.line("REFRESH-INTERVAL;VALUE=DURATION", "PT15M")
+	calendar.line("X-PUBLISHED-TTL", "PT15M")
+	if calendar.err != nil {
+		return calendar.err
+	}
+
+	err = s.repo.StreamTasks(ctx, filter, func(due Task) error {
+		calendar.task(component, due)
+		return calendar.err
+	})
+	if err != nil {
+		return err
+	}
+
+	calendar.line("END", "VCALENDAR")
+	return calendar.err
+}
+
+func (s *calendarService) project(ctx context.Context, id uint64) (Project, error) {
+	if id == 0 {
+		return Project{}, ValidationError{Field: "project_id", Message: "must be greater than 0"}
+	}
+	project, err := s.repo.GetProject(ctx, id)
+	if errors.Is(err, ErrProjectNotFound) {
+		return Project{}, ValidationError{Field: "project_id", Message: "project does not exist"}
+	}
+	return project, err
+}
+
+func hashCalendarToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// icsWriter writes iCalendar content lines (RFC 5545); the first error is
+// kept and later writes are skipped.
+type icsWriter struct {
+	w   io.Writer
+	err error
+}
+
+// task writes the entry of a task. The UID only depends on the task and the
+// SEQUENCE grows with every change, so calendar apps replace the entry when
+// the task changes.
+func (c *icsWriter) task(component CalendarComponent, due Task) {
+	name := strings.ToUpper(string(component))
+	c.line("BEGIN", name)
+	c.line("UID", "task-"+strconv.FormatUint(due.ID, 10)+"@"+calendarUIDDomain)
+	c.line("DTSTAMP", due.UpdatedAt.UTC().Format(icsTimeLayout))
+	c.line("CREATED", due.CreatedAt.UTC().Format(icsTimeLayout))
+	c.line("LAST-MODIFIED", due.UpdatedAt.UTC().Format(icsTimeLayout))
+	c.line("SEQUENCE", strconv.FormatUint(due.Version, 10))
+	c.text("SUMMARY", due.Title)
+	if due.Description != "" {
+		c.text("DESCRIPTION", due.Description)
+	}
+	if len(due.Labels) > 0 {
+		escaped := make([]string, 0, len(due.Labels))
+		for _, label := range due.Labels {
+			escaped = append(escaped, escapeICSText(label))
+		}
+		c.line("CATEGORIES", strings.Join(escaped, ","))
+	}
+	if due.Priority != 0 {
+		// iCalendar priorities run from 1 (highest) to 9 (lowest).
+		c.line("PRIORITY", strconv.Itoa(11-2*int(due.Priority)))
+	}
+
+	dueAt := due.DueAt.UTC().Format(icsTimeLayout)
+	if component == CalendarTodo {
+		c.line("DUE", dueAt)
+		c.line("STATUS", icsTodoStatus(due.Status))
+		if due.CompletedAt != nil {
+			c.line("COMPLETED", due.CompletedAt.UTC().Format(icsTimeLayout))
+		}
+	} else {
+		c.line("DTSTART", dueAt)
+		// Due dates do not make anyone busy.
+		c.line("TRANSP", "TRANSPARENT")
+	}
+	c.line("END", name)
+}
+
+func (c *icsWriter) text(name, value string) {
+	c.line(name, escapeICSText(value))
+}
+
+// line writes a content line folded after at most 75 octets, without
+// splitting a UTF-8 sequence.
+func (c *icsWriter) line(name, value string) {
+	if c.err != nil {
+		return
+	}
+
+	content := name + ":" + value
+	var folded strings.Builder
+	limit := icsMaxLineOctets
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		folded.WriteString(content[:cut])
+		folded.WriteString("\r\n ")
+		content = content[cut:]
+		// The leading space of a continuation line counts towards its length.
+		limit = icsMaxLineOctets - 1
+	}
+	folded.WriteString(content)
+	folded.WriteString("\r\n")
+
+	_, c.err = io.WriteString(c.w, folded.String())
+}
+
+func escapeICSText(value string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	).Replace(value)
+}
+
+func icsTodoStatus(status Status) string {
+	switch status {
+	case StatusDone:
+		return "COMPLETED"
+	case StatusInProgress:
+		return "IN-PROCESS"
+	default:
+		return "NEEDS-ACTION"
+	}
+}
@@ -0,0 +1,241 @@
+package task
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const calendarOwnerID = "3b241101-e2bb-4255-8caf-4136c566a962"
+
+type mockCalendarRepository struct {
+	feeds   map[string]CalendarFeed
+	created CalendarFeedParams
+	tasks   []Task
+	filter  ListFilter
+}
+
+func (m *mockCalendarRepository) CreateCalendarFeed(_ context.Context, params CalendarFeedParams) (CalendarFeed, error) {
+	m.created = params
+	return CalendarFeed{ID: 1, OwnerID: params.OwnerID, ProjectID: params.ProjectID}, nil
+}
+
+func (m *mockCalendarRepository) FindCalendarFeed(_ context.Context, tokenHash string) (CalendarFeed, error) {
+	feed, ok := m.feeds[tokenHash]
+	if !ok {
+		return CalendarFeed{}, ErrCalendarFeedNotFound
+	}
+	return feed, nil
+}
+
+func (m *mockCalendarRepository) ListCalendarFeeds(context.Context, string) ([]CalendarFeed, error) {
+	return nil, nil
+}
+
+func (m *mockCalendarRepository) DeleteCalendarFeed(context.Context, uint64, string) error {
+	return nil
+}
+
+func (m *mockCalendarRepository) GetProject(_ context.Context, id uint64) (Project, error) {
+	if id != 2 {
+		return Project{}, ErrProjectNotFound
+	}
+	return Project{ID: 2, Name: "Launch, phase 1"}, nil
+}
+
+func (m *mockCalendarRepository) StreamTasks(_ context.Context, filter ListFilter, fn func(Task) error) error {
+	m.filter = filter
+	for _, streamed := range m.tasks {
+		if err := fn(streamed); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func calendarTasks() []Task {
+	createdAt := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	dueAt := time.Date(2026, 3, 10, 12, 30, 0, 0, time.FixedZone("CET", 3600))
+	completedAt := time.Date(2026, 3, 9, 8, 0, 0, 0, time.UTC)
+	return []Task{
+		{ID: 7, Title: "Ship; then, celebrate", Description: "Line one\nLine two", Status: StatusInProgress, Priority: 5, Labels: []string{"release", "a,b"}, DueAt: &dueAt, CreatedAt: createdAt, UpdatedAt: createdAt, Version: 3},
+		{ID: 8, Title: "Write notes", Status: StatusDone, Priority: 3, DueAt: &dueAt, CreatedAt: createdAt, UpdatedAt: completedAt, CompletedAt: &completedAt, Version: 2},
+	}
+}
+
+func TestCalendarServiceCreateFeed(t *testing.T) {
+	repo := &mockCalendarRepository{}
+	svc := NewCalendarService(repo)
+
+	feed, err := svc.CreateFeed(context.Background(), CreateCalendarFeedInput{OwnerID: strings.ToUpper(calendarOwnerID)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(feed.Token) < 40 || feed.URL != "/calendar.ics?token="+feed.Token {
+		t.Fatalf("unexpected feed: %+v", feed)
+	}
+	if repo.created.OwnerID != calendarOwnerID || repo.created.TokenHash != hashCalendarToken(feed.Token) || strings.Contains(repo.created.TokenHash, feed.Token) {
+		t.Fatalf("expected only the token hash to be stored, got %+v", repo.created)
+	}
+
+	other, _ := svc.CreateFeed(context.Background(), CreateCalendarFeedInput{OwnerID: calendarOwnerID})
+	if other.Token == feed.Token {
+		t.Fatal("expected every feed to get its own token")
+	}
+
+	missing := uint64(9)
+	var validationErr ValidationError
+	if _, err := svc.CreateFeed(context.Background(), CreateCalendarFeedInput{OwnerID: calendarOwnerID, ProjectID: &missing}); !errors.As(err, &validationErr) || validationErr.Field != "project_id" {
+		t.Fatalf("expected project_id validation error, got %v", err)
+	}
+	if _, err := svc.CreateFeed(context.Background(), CreateCalendarFeedInput{OwnerID: "nobody"}); !errors.As(err, &validationErr) {
+		t.Fatalf("expected validation error for the owner, got %v", err)
+	}
+}
+
+func TestCalendarServiceCalendar_Events(t *testing.T) {
+	repo := &mockCalendarRepository{
+		feeds: map[string]CalendarFeed{hashCalendarToken("secret"): {ID: 1, OwnerID: calendarOwnerID}},
+		tasks: calendarTasks(),
+	}
+	svc := NewCalendarService(repo)
+
+	var output bytes.Buffer
+	err := svc.Calendar(context.Background(), CalendarInput{Token: "secret", List: ListTasksInput{Status: "new,in_progress", Filter: "priority >= 3", Limit: 5}}, &output)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//task_tracker//Tasks//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:My tasks",
+		"REFRESH-INTERVAL;VALUE=DURATION:PT15M",
+		"X-PUBLISHED-TTL:PT15M",
+		"BEGIN:VEVENT",
+		"UID:task-7@task-tracker",
+		"DTSTAMP:20260301T090000Z",
+		"CREATED:20260301T090000Z",
+		"LAST-MODIFIED:20260301T090000Z",
+		"SEQUENCE:3",
+		`SUMMARY:Ship\; then\, celebrate`,
+		`DESCRIPTION:Line one\nLine two`,
+		`CATEGORIES:release,a\,b`,
+		"PRIORITY:1",
+		"DTSTART:20260310T113000Z",
+		"TRANSP:TRANSPARENT",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:task-8@task-tracker",
+		"DTSTAMP:20260309T080000Z",
+		"CREATED:20260301T090000Z",
+		"LAST-MODIFIED:20260309T080000Z",
+		"SEQUENCE:2",
+		"SUMMARY:Write notes",
+		"PRIORITY:5",
+		"DTSTART:20260310T113000Z",
+		"TRANSP:TRANSPARENT",
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n")
+	if output.String() != expected {
+		t.Fatalf("unexpected calendar:\n%s", output.String())
+	}
+
+	filter := repo.filter
+	if filter.HasDueDate == nil || !*filter.HasDueDate || len(filter.Statuses) != 2 {
+		t.Fatalf("expected the list filters for tasks with a due date, got %+v", filter)
+	}
+	and, ok := filter.Expr.(FilterAnd)
+	if !ok || len(and.Operands) != 2 || !reflect.DeepEqual(and.Operands[0], FilterComparison{Field: FilterAssignee, Operator: FilterEq, Value: calendarOwnerID}) {
+		t.Fatalf("expected the feed to be limited to the owner's tasks, got %#v", filter.Expr)
+	}
+}
+
+func TestCalendarServiceCalendar_ProjectTodos(t *testing.T) {
+	projectID := uint64(2)
+	repo := &mockCalendarRepository{
+		feeds: map[string]CalendarFeed{hashCalendarToken("secret"): {ID: 1, OwnerID: calendarOwnerID, ProjectID: &projectID}},
+		tasks: calendarTasks()[1:],
+	}
+	svc := NewCalendarService(repo)
+
+	otherProject := uint64(5)
+	var output bytes.Buffer
+	if err := svc.Calendar(context.Background(), CalendarInput{Token: "secret", Component: "VTODO", List: ListTasksInput{ProjectID: &otherProject}}, &output); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, line := range []string{`X-WR-CALNAME:Launch\, phase 1`, "BEGIN:VTODO", "DUE:20260310T113000Z", "STATUS:COMPLETED", "COMPLETED:20260309T080000Z", "END:VTODO"} {
+		if !strings.Contains(output.String(), line+"\r\n") {
+			t.Fatalf("expected %q in:\n%s", line, output.String())
+		}
+	}
+	if repo.filter.ProjectID == nil || *repo.filter.ProjectID != projectID || repo.filter.Expr != nil {
+		t.Fatalf("expected the feed to be limited to its project, got %+v", repo.filter)
+	}
+}
+
+func TestCalendarServiceCalendar_Errors(t *testing.T) {
+	repo := &mockCalendarRepository{
+		feeds: map[string]CalendarFeed{hashCalendarToken("secret"): {ID: 1, OwnerID: calendarOwnerID}},
+		tasks: calendarTasks(),
+	}
+	svc := NewCalendarService(repo)
+
+	tests := []struct {
+		name  string
+		input CalendarInput
+		check func(err error) bool
+	}{
+		{name: "missing token", input: CalendarInput{}, check: func(err error) bool { return errors.Is(err, ErrCalendarFeedNotFound) }},
+		{name: "unknown token", input: CalendarInput{Token: "guess"}, check: func(err error) bool { return errors.Is(err, ErrCalendarFeedNotFound) }},
+		{name: "component", input: CalendarInput{Token: "secret", Component: "vjournal"}, check: func(err error) bool {
+			var validationErr ValidationError
+			return errors.As(err, &validationErr) && validationErr.Field == "component"
+		}},
+		{name: "filter", input: CalendarInput{Token: "secret", List: ListTasksInput{Filter: "status ="}}, check: func(err error) bool {
+			var validationErr ValidationError
+			return errors.As(err, &validationErr) && validationErr.Field == "filter"
+		}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var output bytes.Buffer
+			if err := svc.Calendar(context.Background(), tc.input, &output); !tc.check(err) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if output.Len() != 0 {
+				t.Fatalf("expected nothing to be written, got %q", output.String())
+			}
+		})
+	}
+}
+
+func TestICSWriterFoldsLongLines(t *testing.T) {
+	var output bytes.Buffer
+	writer := &icsWriter{w: &output}
+	writer.text("SUMMARY", strings.Repeat("ä", 60))
+
+	lines := strings.Split(strings.TrimSuffix(output.String(), "\r\n"), "\r\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[1], " ") {
+		t.Fatalf("expected the line to be folded once, got %q", lines)
+	}
+	for _, line := range lines {
+		if len(line) > icsMaxLineOctets {
+			t.Fatalf("line of %d octets: %q", len(line), line)
+		}
+	}
+	if unfolded := lines[0] + strings.TrimPrefix(lines[1], " "); unfolded != "SUMMARY:"+strings.Repeat("ä", 60) {
+		t.Fatalf("unexpected unfolded line: %q", unfolded)
+	}
+}
@@ -0,0 +1,204 @@
+package task
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+const (
+	maxChecklistItems      = 100
+	maxChecklistItemLength = 500
+)
+
+type ChecklistItem struct {
+	ID        uint64     `json:"id"`
+	TaskID    uint64     `json:"task_id"`
+	Position  int        `json:"position"`
+	Text      string     `json:"text"`
+	Checked   bool       `json:"checked"`
+	CheckedAt *time.Time `json:"checked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// ChecklistProgress is the completion count of a task checklist included in
+// the Task JSON.
+type ChecklistProgress struct {
+	Total     int `json:"total"`
+	Completed int `json:"completed"`
+}
+
+type Checklist struct {
+	Items []ChecklistItem `json:"items"`
+	ChecklistProgress
+}
+
+func NewChecklist(items []ChecklistItem) Checklist {
+	checklist := Checklist{Items: items}
+	if checklist.Items == nil {
+		checklist.Items = []ChecklistItem{}
+	}
+	for _, item := range checklist.Items {
+		checklist.Total++
+		if item.Checked {
+			checklist.Completed++
+		}
+	}
+	return checklist
+}
+
+type AddChecklistItemInput struct {
+	Text     string
+	Position *int
+}
+
+type UpdateChecklistItemInput struct {
+	Text     *string
+	Checked  *bool
+	Position *int
+}
+
+// AddChecklistItemParams.Position is nil to append the item; positions past
+// the end of the list append as well.
+type AddChecklistItemParams struct {
+	Text     string
+	Position *int
+	MaxItems int
+}
+
+type UpdateChecklistItemParams struct {
+	Text     *string
+	Checked  *bool
+	Position *int
+}
+
+type ChecklistRepository interface {
+	ListChecklistItems(ctx context.Context, taskID uint64) ([]ChecklistItem, error)
+	AddChecklistItem(ctx context.Context, taskID uint64, params AddChecklistItemParams) error
+	UpdateChecklistItem(ctx context.Context, taskID, itemID uint64, params UpdateChecklistItemParams) error
+	DeleteChecklistItem(ctx context.Context, taskID, itemID uint64) error
+}
+
+// ChecklistService manages the ordered checklist of a task. Every change is
+// applied atomically and returns the resulting checklist.
+type ChecklistService interface {
+	Get(ctx context.Context, taskID uint64) (Checklist, error)
+	AddItem(ctx context.Context, taskID uint64, input AddChecklistItemInput) (Checklist, error)
+	UpdateItem(ctx context.Context, taskID, itemID uint64, input UpdateChecklistItemInput) (Checklist, error)
+	DeleteItem(ctx context.Context, taskID, itemID uint64) (Checklist, error)
+}
+
+type checklistService struct {
+	repo ChecklistRepository
+}
+
+func NewChecklistService(repo ChecklistRepository) ChecklistService {
+	return &checklistService{repo: repo}
+}
+
+func (s *checklistService) Get(ctx context.Context, taskID uint64) (Checklist, error) {
+	if err := validateTaskID(taskID); err != nil {
+		return Checklist{}, err
+	}
+	return s.load(ctx, taskID)
+}
+
+func (s *checklistService) AddItem(ctx context.Context, taskID uint64, input AddChecklistItemInput) (Checklist, error) {
+	if err := validateTaskID(taskID); err != nil {
+		return Checklist{}, err
+	}
+	text, err := normalizeChecklistText(input.Text)
+	if err != nil {
+		return Checklist{}, err
+	}
+	if err := validateChecklistPosition(input.Position); err != nil {
+		return Checklist{}, err
+	}
+
+	err = s.repo.AddChecklistItem(ctx, taskID, AddChecklistItemParams{
+		Text:     text,
+		Position: input.Position,
+		MaxItems: maxChecklistItems,
+	})
+	if err != nil {
+		return Checklist{}, err
+	}
+	return s.load(ctx, taskID)
+}
+
+func (s *checklistService) UpdateItem(ctx context.Context, taskID, itemID uint64, input UpdateChecklistItemInput) (Checklist, error) {
+	if err := validateTaskID(taskID); err != nil {
+		return Checklist{}, err
+	}
+	if err := validateChecklistItemID(itemID); err != nil {
+		return Checklist{}, err
+	}
+	if input.Text == nil && input.Checked == nil && input.Position == nil {
+		return Checklist{}, ValidationError{Field: "body", Message: "at least one field must be provided for update"}
+	}
+
+	params := UpdateChecklistItemParams{Checked: input.Checked, Position: input.Position}
+	if input.Text != nil {
+		text, err := normalizeChecklistText(*input.Text)
+		if err != nil {
+			return Checklist{}, err
+		}
+		params.Text = &text
+	}
+	if err := validateChecklistPosition(input.Position); err != nil {
+		return Checklist{}, err
+	}
+
+	if err := s.repo.UpdateChecklistItem(ctx, taskID, itemID, params); err != nil {
+		return Checklist{}, err
+	}
+	return s.load(ctx, taskID)
+}
+
+func (s *checklistService) DeleteItem(ctx context.Context, taskID, itemID uint64) (Checklist, error) {
+	if err := validateTaskID(taskID); err != nil {
+		return Checklist{}, err
+	}
+	if err := validateChecklistItemID(itemID); err != nil {
+		return Checklist{}, err
+	}
+
+	if err := s.repo.DeleteChecklistItem(ctx, taskID, itemID); err != nil {
+		return Checklist{}, err
+	}
+	return s.load(ctx, taskID)
+}
+
+func (s *checklistService) load(ctx context.Context, taskID uint64) (Checklist, error) {
+	items, err := s.repo.ListChecklistItems(ctx, taskID)
+	if err != nil {
+		return Checklist{}, err
+	}
+	return NewChecklist(items), nil
+}
+
+func validateChecklistItemID(id uint64) error {
+	if id == 0 {
+		return ValidationError{Field: "item_id", Message: "must be greater than 0"}
+	}
+	return nil
+}
+
+func validateChecklistPosition(position *int) error {
+	if position != nil && *position < 0 {
+		return ValidationError{Field: "position", Message: "must be greater or equal to 0"}
+	}
+	return nil
+}
+
+func normalizeChecklistText(raw string) (string, error) {
+	text := strings.TrimSpace(raw)
+	if text == "" {
+		return "", ValidationError{Field: "text", Message: "must not be empty"}
+	}
+	if len(text) > maxChecklistItemLength {
+		return "", ValidationError{Field: "text", Message: "must be at most 500 characters"}
+	}
+	return text, nil
+}
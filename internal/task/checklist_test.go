@@ -0,0 +1,145 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type mockChecklistRepository struct {
+	items []ChecklistItem
+
+	addTaskID    uint64
+	addParams    AddChecklistItemParams
+	updateItemID uint64
+	updateParams UpdateChecklistItemParams
+	calls        int
+}
+
+func (m *mockChecklistRepository) ListChecklistItems(_ context.Context, _ uint64) ([]ChecklistItem, error) {
+	return m.items, nil
+}
+
+func (m *mockChecklistRepository) AddChecklistItem(_ context.Context, taskID uint64, params AddChecklistItemParams) error {
+	m.calls++
+	m.addTaskID = taskID
+	m.addParams = params
+	return nil
+}
+
+func (m *mockChecklistRepository) UpdateChecklistItem(_ context.Context, _, itemID uint64, params UpdateChecklistItemParams) error {
+	m.calls++
+	m.updateItemID = itemID
+	m.updateParams = params
+	return nil
+}
+
+func (m *mockChecklistRepository) DeleteChecklistItem(_ context.Context, _, _ uint64) error {
+	m.calls++
+	return nil
+}
+
+func TestChecklistServiceAddItem(t *testing.T) {
+	repo := &mockChecklistRepository{
+		items: []ChecklistItem{{ID: 1, Text: "write tests", Checked: true}, {ID: 2, Text: "deploy"}},
+	}
+	svc := NewChecklistService(repo)
+
+	position := 0
+	checklist, err := svc.AddItem(context.Background(), 4, AddChecklistItemInput{Text: "  review  ", Position: &position})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.addTaskID != 4 || repo.addParams.Text != "review" || repo.addParams.MaxItems != maxChecklistItems {
+		t.Fatalf("unexpected add call: task=%d params=%+v", repo.addTaskID, repo.addParams)
+	}
+	if checklist.Total != 2 || checklist.Completed != 1 {
+		t.Fatalf("unexpected progress: %+v", checklist.ChecklistProgress)
+	}
+}
+
+func TestChecklistServiceUpdateItem(t *testing.T) {
+	repo := &mockChecklistRepository{}
+	svc := NewChecklistService(repo)
+
+	checked := true
+	checklist, err := svc.UpdateItem(context.Background(), 4, 9, UpdateChecklistItemInput{Checked: &checked})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.updateItemID != 9 || repo.updateParams.Checked == nil || !*repo.updateParams.Checked {
+		t.Fatalf("unexpected update call: item=%d params=%+v", repo.updateItemID, repo.updateParams)
+	}
+	if checklist.Items == nil {
+		t.Fatal("expected empty items list, got nil")
+	}
+}
+
+func TestChecklistServiceValidation(t *testing.T) {
+	negative := -1
+	empty := " "
+	tests := []struct {
+		name  string
+		run   func(svc ChecklistService) error
+		field string
+	}{
+		{
+			name: "empty text",
+			run: func(svc ChecklistService) error {
+				_, err := svc.AddItem(context.Background(), 1, AddChecklistItemInput{Text: " "})
+				return err
+			},
+			field: "text",
+		},
+		{
+			name: "negative position",
+			run: func(svc ChecklistService) error {
+				_, err := svc.AddItem(context.Background(), 1, AddChecklistItemInput{Text: "a", Position: &negative})
+				return err
+			},
+			field: "position",
+		},
+		{
+			name: "empty update",
+			run: func(svc ChecklistService) error {
+				_, err := svc.UpdateItem(context.Background(), 1, 2, UpdateChecklistItemInput{})
+				return err
+			},
+			field: "body",
+		},
+		{
+			name: "clearing text",
+			run: func(svc ChecklistService) error {
+				_, err := svc.UpdateItem(context.Background(), 1, 2, UpdateChecklistItemInput{Text: &empty})
+				return err
+			},
+			field: "text",
+		},
+		{
+			name: "missing item id",
+			run: func(svc ChecklistService) error {
+				_, err := svc.DeleteItem(context.Background(), 1, 0)
+				return err
+			},
+			field: "item_id",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockChecklistRepository{}
+			err := tc.run(NewChecklistService(repo))
+
+			var validationErr ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("expected validation error, got %v", err)
+			}
+			if validationErr.Field != tc.field {
+				t.Fatalf("expected field %q, got %q", tc.field, validationErr.Field)
+			}
+			if repo.calls != 0 {
+				t.Fatal("repository must not be called")
+			}
+		})
+	}
+}
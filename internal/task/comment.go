@@ -0,0 +1,109 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/notification"
+)
+
+const maxCommentLength = 10000
+
+type Comment struct {
+	ID        uint64    `json:"id"`
+	TaskID    uint64    `json:"task_id"`
+	AuthorID  string    `json:"author_id"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type AddCommentInput struct {
+	AuthorID string
+	Body     string
+}
+
+type CommentParams struct {
+	TaskID   uint64
+	AuthorID string
+	Body     string
+	Mentions []string
+}
+
+// CommentRepository.CreateComment also makes the author and the mentioned
+// users watchers of the task.
+type CommentRepository interface {
+	CreateComment(ctx context.Context, params CommentParams) (Comment, error)
+	ListComments(ctx context.Context, taskID uint64) ([]Comment, error)
+	ListWatchers(ctx context.Context, taskID uint64) ([]Watcher, error)
+	FindUsersByUsername(ctx context.Context, usernames []string) ([]User, error)
+}
+
+type CommentService interface {
+	Add(ctx context.Context, taskID uint64, input AddCommentInput) (Comment, error)
+	List(ctx context.Context, taskID uint64) ([]Comment, error)
+}
+
+type commentService struct {
+	repo     CommentRepository
+	notifier notification.Notifier
+}
+
+func NewCommentService(repo CommentRepository, notifier notification.Notifier) CommentService {
+	return &commentService{repo: repo, notifier: notifier}
+}
+
+func (s *commentService) Add(ctx context.Context, taskID uint64, input AddCommentInput) (Comment, error) {
+	if err := validateTaskID(taskID); err != nil {
+		return Comment{}, err
+	}
+	authorID, err := normalizeUserID(input.AuthorID)
+	if err != nil {
+		return Comment{}, err
+	}
+	body := strings.TrimSpace(input.Body)
+	if body == "" {
+		return Comment{}, ValidationError{Field: "body", Message: "must not be empty"}
+	}
+	if len(body) > maxCommentLength {
+		return Comment{}, ValidationError{Field: "body", Message: "must be at most 10000 characters"}
+	}
+
+	mentioned, err := resolveMentions(ctx, s.repo, body)
+	if err != nil {
+		return Comment{}, err
+	}
+
+	comment, err := s.repo.CreateComment(ctx, CommentParams{
+		TaskID:   taskID,
+		AuthorID: authorID,
+		Body:     body,
+		Mentions: mentionedUserIDs(mentioned),
+	})
+	if err != nil {
+		return Comment{}, err
+	}
+
+	notifyMentioned(ctx, s.notifier, mentioned, authorID, notification.Notification{
+		TaskID:  taskID,
+		Subject: fmt.Sprintf("You were mentioned in a comment on task #%d", taskID),
+		Message: comment.Body,
+	})
+
+	notifyWatchers(ctx, s.repo, s.notifier, authorID, notification.Notification{
+		Type:    notification.TypeTaskCommented,
+		TaskID:  taskID,
+		Subject: fmt.Sprintf("New comment on task #%d", taskID),
+		Message: comment.Body,
+	})
+
+	return comment, nil
+}
+
+func (s *commentService) List(ctx context.Context, taskID uint64) ([]Comment, error) {
+	if err := validateTaskID(taskID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListComments(ctx, taskID)
+}
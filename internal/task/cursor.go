@@ -0,0 +1,54 @@
+package task
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cursor is a keyset position in the created_at order of tasks; ties are
+// broken by id.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        uint64
+}
+
+// TaskPage is a page of tasks; NextCursor is empty on the last page.
+// NextOffset is set instead when the order does not support cursors.
+type TaskPage struct {
+	Items      []Task
+	NextCursor string
+	NextOffset int
+}
+
+// EncodeCursor returns the opaque cursor of the page that starts right after
+// the given task.
+func EncodeCursor(after Task) string {
+	raw := strconv.FormatInt(after.CreatedAt.UTC().UnixNano(), 10) + ":" + strconv.FormatUint(after.ID, 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeCursor(encoded string) (Cursor, error) {
+	invalid := ValidationError{Field: "cursor", Message: "is invalid"}
+
+	raw, err := base64.RawURLEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return Cursor{}, invalid
+	}
+
+	rawCreatedAt, rawID, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return Cursor{}, invalid
+	}
+	createdAt, err := strconv.ParseInt(rawCreatedAt, 10, 64)
+	if err != nil {
+		return Cursor{}, invalid
+	}
+	id, err := strconv.ParseUint(rawID, 10, 64)
+	if err != nil || id == 0 {
+		return Cursor{}, invalid
+	}
+
+	return Cursor{CreatedAt: time.Unix(0, createdAt).UTC(), ID: id}, nil
+}
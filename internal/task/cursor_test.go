@@ -0,0 +1,86 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	createdAt := time.Date(2026, 3, 4, 10, 30, 0, 0, time.UTC)
+
+	cursor, err := DecodeCursor(EncodeCursor(Task{ID: 42, CreatedAt: createdAt}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cursor.ID != 42 || !cursor.CreatedAt.Equal(createdAt) {
+		t.Fatalf("unexpected cursor: %+v", cursor)
+	}
+
+	for _, raw := range []string{"not base64!", "MTIz", "YWJjOjE", "MTIzOjA"} {
+		_, err := DecodeCursor(raw)
+		var validationErr ValidationError
+		if !errors.As(err, &validationErr) || validationErr.Field != "cursor" {
+			t.Fatalf("expected cursor validation error for %q, got %v", raw, err)
+		}
+	}
+}
+
+func TestBuildListFilter_Cursor(t *testing.T) {
+	cursor := EncodeCursor(Task{ID: 7, CreatedAt: time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)})
+
+	filter, err := buildListFilter(ListTasksInput{Cursor: cursor, Sort: "created_at"}, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if filter.After == nil || filter.After.ID != 7 {
+		t.Fatalf("unexpected cursor filter: %+v", filter.After)
+	}
+
+	tests := []struct {
+		input ListTasksInput
+		field string
+	}{
+		{input: ListTasksInput{Cursor: cursor, Offset: 20}, field: "offset"},
+		{input: ListTasksInput{Cursor: cursor, Sort: "-story_points"}, field: "cursor"},
+		{input: ListTasksInput{Cursor: cursor, Sort: "created_at,rank"}, field: "cursor"},
+	}
+	for _, tc := range tests {
+		_, err := buildListFilter(tc.input, time.Now())
+		var validationErr ValidationError
+		if !errors.As(err, &validationErr) || validationErr.Field != tc.field {
+			t.Fatalf("expected %s validation error for %+v, got %v", tc.field, tc.input, err)
+		}
+	}
+}
+
+func TestServiceListPage(t *testing.T) {
+	createdAt := time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)
+	repo := &mockRepository{listResult: []Task{
+		{ID: 3, CreatedAt: createdAt},
+		{ID: 2, CreatedAt: createdAt},
+		{ID: 1, CreatedAt: createdAt},
+	}}
+	svc := NewService(repo)
+
+	page, err := svc.ListPage(context.Background(), ListTasksInput{Limit: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.listFilter.Limit != 3 {
+		t.Fatalf("expected one extra task to be requested, got limit %d", repo.listFilter.Limit)
+	}
+	if len(page.Items) != 2 || page.NextCursor != EncodeCursor(page.Items[1]) {
+		t.Fatalf("unexpected page: %+v", page)
+	}
+
+	repo.listResult = repo.listResult[:2]
+	page, err = svc.ListPage(context.Background(), ListTasksInput{Limit: 2, Cursor: page.NextCursor})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.Items) != 2 || page.NextCursor != "" {
+		t.Fatalf("expected the last page, got %+v", page)
+	}
+}
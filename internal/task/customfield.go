@@ -0,0 +1,242 @@
+package task
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	customFieldDateLayout      = "2006-01-02"
+	maxCustomFieldFilterValues = 20
+)
+
+// CustomFieldValue is the storage form of a custom field on a task: the
+// canonical string representation of every value. A value without Values
+// removes the field from the task.
+type CustomFieldValue struct {
+	FieldID uint64
+	Values  []string
+}
+
+type CustomFieldFilter struct {
+	Name   string
+	Values []string
+}
+
+// Decode converts stored values back to their JSON representation: numbers
+// become float64, multi-select fields a list of options and everything else a
+// string.
+func (d CustomFieldDefinition) Decode(values []string) any {
+	if len(values) == 0 {
+		return nil
+	}
+
+	switch d.Type {
+	case CustomFieldNumber:
+		number, err := strconv.ParseFloat(values[0], 64)
+		if err != nil {
+			return values[0]
+		}
+		return number
+	case CustomFieldMultiSelect:
+		return append([]string(nil), values...)
+	default:
+		return values[0]
+	}
+}
+
+// normalizeCustomFields validates raw JSON values against the definitions of
+// a project. A nil value clears the field.
+func normalizeCustomFields(project Project, raw map[string]any) ([]CustomFieldValue, error) {
+	names := make([]string, 0, len(raw))
+	for name := range raw {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	values := make([]CustomFieldValue, 0, len(raw))
+	for _, name := range names {
+		field := "custom_fields." + name
+		definition, ok := project.CustomField(name)
+		if !ok {
+			return nil, ValidationError{Field: field, Message: "is not defined for the project"}
+		}
+
+		normalized, err := definition.normalize(raw[name])
+		if err != nil {
+			return nil, withField(err, field)
+		}
+		values = append(values, CustomFieldValue{FieldID: definition.ID, Values: normalized})
+	}
+
+	return values, nil
+}
+
+// checkRequiredCustomFields reports the first required field of the project
+// that has no value. When all is false only the fields present in values are
+// checked, i.e. a required field may be left untouched but not cleared.
+func checkRequiredCustomFields(project Project, values []CustomFieldValue, all bool) error {
+	provided := make(map[uint64]bool, len(values))
+	for _, value := range values {
+		provided[value.FieldID] = len(value.Values) > 0
+	}
+
+	for _, definition := range project.CustomFields {
+		if !definition.Required {
+			continue
+		}
+		hasValue, present := provided[definition.ID]
+		if (all || present) && !hasValue {
+			return ValidationError{Field: "custom_fields." + definition.Name, Message: "is required"}
+		}
+	}
+	return nil
+}
+
+func (d CustomFieldDefinition) normalize(raw any) ([]string, error) {
+	if raw == nil {
+		return nil, nil
+	}
+
+	switch d.Type {
+	case CustomFieldText:
+		text, ok := raw.(string)
+		if !ok {
+			return nil, ValidationError{Message: "must be a string"}
+		}
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return nil, nil
+		}
+		if len(text) > maxCustomFieldValueLen {
+			return nil, ValidationError{Message: "must be at most 255 characters"}
+		}
+		return []string{text}, nil
+	case CustomFieldNumber:
+		number, ok := asFloat(raw)
+		if !ok {
+			return nil, ValidationError{Message: "must be a number"}
+		}
+		return []string{strconv.FormatFloat(number, 'f', -1, 64)}, nil
+	case CustomFieldDate:
+		text, _ := raw.(string)
+		date, err := time.Parse(customFieldDateLayout, strings.TrimSpace(text))
+		if err != nil {
+			return nil, ValidationError{Message: "must be a date in YYYY-MM-DD format"}
+		}
+		return []string{date.Format(customFieldDateLayout)}, nil
+	case CustomFieldSingleSelect:
+		option, ok := raw.(string)
+		if !ok || !d.hasOption(option) {
+			return nil, ValidationError{Message: "must be one of: " + strings.Join(d.Options, ", ")}
+		}
+		return []string{option}, nil
+	case CustomFieldMultiSelect:
+		items, ok := asStrings(raw)
+		if !ok {
+			return nil, ValidationError{Message: "must be a list of strings"}
+		}
+		options := make([]string, 0, len(items))
+		seen := make(map[string]bool, len(items))
+		for _, item := range items {
+			if !d.hasOption(item) {
+				return nil, ValidationError{Message: "must only contain: " + strings.Join(d.Options, ", ")}
+			}
+			if seen[item] {
+				continue
+			}
+			seen[item] = true
+			options = append(options, item)
+		}
+		return options, nil
+	case CustomFieldUser:
+		text, _ := raw.(string)
+		userID, err := normalizeUserID(text)
+		if err != nil {
+			return nil, ValidationError{Message: "must be a valid user UUID"}
+		}
+		return []string{userID}, nil
+	default:
+		return nil, ValidationError{Message: "has an unsupported type"}
+	}
+}
+
+func (d CustomFieldDefinition) hasOption(value string) bool {
+	for _, option := range d.Options {
+		if option == value {
+			return true
+		}
+	}
+	return false
+}
+
+func asFloat(raw any) (float64, bool) {
+	switch value := raw.(type) {
+	case float64:
+		return value, true
+	case int:
+		return float64(value), true
+	case json.Number:
+		number, err := value.Float64()
+		return number, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func asStrings(raw any) ([]string, bool) {
+	switch value := raw.(type) {
+	case []string:
+		return value, true
+	case []any:
+		items := make([]string, 0, len(value))
+		for _, item := range value {
+			text, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			items = append(items, text)
+		}
+		return items, true
+	default:
+		return nil, false
+	}
+}
+
+// parseCustomFieldFilters validates cf.<name> list filters. Several values for
+// the same field match any of them.
+func parseCustomFieldFilters(raw map[string][]string) ([]CustomFieldFilter, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	filters := make([]CustomFieldFilter, 0, len(raw))
+	for name, rawValues := range raw {
+		field := "cf." + name
+		if err := validateCustomFieldName(field, name); err != nil {
+			return nil, err
+		}
+
+		values := make([]string, 0, len(rawValues))
+		for _, value := range rawValues {
+			value = strings.TrimSpace(value)
+			if value == "" {
+				return nil, ValidationError{Field: field, Message: "must not be empty"}
+			}
+			values = append(values, value)
+		}
+		if len(values) == 0 {
+			return nil, ValidationError{Field: field, Message: "must not be empty"}
+		}
+		if len(values) > maxCustomFieldFilterValues {
+			return nil, ValidationError{Field: field, Message: "must contain at most 20 values"}
+		}
+		filters = append(filters, CustomFieldFilter{Name: name, Values: values})
+	}
+
+	sort.Slice(filters, func(i, j int) bool { return filters[i].Name < filters[j].Name })
+	return filters, nil
+}
@@ -1,16 +1,50 @@
 package task
 
-import "errors"
+import (
+	"errors"
+	"strconv"
+)
 
 var (
-	ErrTaskNotFound = errors.New("task not found")
+	ErrTaskNotFound             = errors.New("task not found")
+	ErrNextOccurrenceExists     = errors.New("next occurrence already exists")
+	ErrTimeEntryNotFound        = errors.New("time entry not found")
+	ErrTimerAlreadyRunning      = errors.New("user already has a running timer")
+	ErrTimerNotRunning          = errors.New("no running timer for this task")
+	ErrProjectNotFound          = errors.New("project not found")
+	ErrProjectExists            = errors.New("project with this name already exists")
+	ErrCustomFieldNotFound      = errors.New("custom field not found")
+	ErrCustomFieldExists        = errors.New("custom field with this name already exists in the project")
+	ErrChecklistItemNotFound    = errors.New("checklist item not found")
+	ErrTemplateNotFound         = errors.New("template not found")
+	ErrTemplateExists           = errors.New("template with this name already exists")
+	ErrChecklistFull            = errors.New("checklist has reached the maximum number of items")
+	ErrWIPLimitExceeded         = errors.New("status column has reached its WIP limit")
+	ErrSprintNotFound           = errors.New("sprint not found")
+	ErrSprintExists             = errors.New("sprint with this name already exists in the project")
+	ErrActiveSprintExists       = errors.New("project already has an active sprint")
+	ErrInvalidSprintTransition  = errors.New("sprint cannot change to this state")
+	ErrMilestoneNotFound        = errors.New("milestone not found")
+	ErrMilestoneExists          = errors.New("milestone with this name already exists")
+	ErrVersionConflict          = errors.New("task was modified since the given version")
+	ErrIdempotencyKeyReused     = errors.New("idempotency key was already used for a different request")
+	ErrIdempotencyKeyInProgress = errors.New("a request with this idempotency key is still in progress")
+	ErrIdempotencyKeyLost       = errors.New("idempotency key expired before the request completed")
+	ErrExportNotFound           = errors.New("export not found")
+	ErrCalendarFeedNotFound     = errors.New("calendar feed not found")
 )
 
+// ValidationError.Position is the 1-based position of the offending token
+// in a field holding an expression, e.g. a filter; it is 0 otherwise.
 type ValidationError struct {
-	Field   string
-	Message string
+	Field    string
+	Message  string
+	Position int
 }
 
 func (e ValidationError) Error() string {
+	if e.Position > 0 {
+		return "invalid " + e.Field + " at position " + strconv.Itoa(e.Position) + ": " + e.Message
+	}
 	return "invalid " + e.Field + ": " + e.Message
 }
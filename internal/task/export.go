@@ -0,0 +1,375 @@
+package task
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/job"
+	"github.com/google/uuid"
+)
+
+// ExportJobType is the job type of exports run in the background.
+const ExportJobType = "task.export"
+
+type ExportFormat string
+
+const (
+	ExportCSV    ExportFormat = "csv"
+	ExportJSON   ExportFormat = "json"
+	ExportNDJSON ExportFormat = "ndjson"
+)
+
+// ParseExportFormat defaults to CSV.
+func ParseExportFormat(raw string) (ExportFormat, error) {
+	switch format := ExportFormat(strings.ToLower(strings.TrimSpace(raw))); format {
+	case "":
+		return ExportCSV, nil
+	case ExportCSV, ExportJSON, ExportNDJSON:
+		return format, nil
+	default:
+		return "", ValidationError{Field: "format", Message: "must be one of: csv, json, ndjson"}
+	}
+}
+
+func (f ExportFormat) ContentType() string {
+	switch f {
+	case ExportJSON:
+		return "application/json"
+	case ExportNDJSON:
+		return "application/x-ndjson"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+// ExportInput selects the tasks to export with the filters and sort of a
+// task list; its pagination is ignored.
+type ExportInput struct {
+	Format string
+	List   ListTasksInput
+}
+
+// ExportResult is the result of an export job.
+type ExportResult struct {
+	Format      ExportFormat `json:"format"`
+	Rows        int          `json:"rows"`
+	DownloadURL string       `json:"download_url"`
+}
+
+// ExportFile is a finished export ready to be downloaded.
+type ExportFile struct {
+	Name    string
+	Format  ExportFormat
+	Content io.ReadCloser
+}
+
+// ExportJob is the payload of an export job; File is the name the export is
+// saved under.
+type ExportJob struct {
+	Input ExportInput
+	File  string
+}
+
+type ExportRepository interface {
+	// StreamTasks calls fn for every task matching the filter, in the order
+	// of the filter, without loading them all into memory. Limit and Offset
+	// are ignored.
+	StreamTasks(ctx context.Context, filter ListFilter, fn func(Task) error) error
+}
+
+// ExportStorage keeps the files written by export jobs until they are
+// downloaded or expire.
+type ExportStorage interface {
+	// Save makes a file available under name only if write succeeds.
+	Save(name string, write func(w io.Writer) error) error
+	Open(name string) (io.ReadCloser, error)
+	RemoveOlderThan(cutoff time.Time) (int, error)
+}
+
+type ExportService interface {
+	// Export writes the tasks to w. Invalid input is reported before
+	// anything is written.
+	Export(ctx context.Context, input ExportInput, w io.Writer) (int, error)
+	// Submit queues the export as a job whose result links to the file.
+	Submit(ctx context.Context, input ExportInput) (job.Job, error)
+	Run(ctx context.Context, queued ExportJob) (ExportResult, error)
+	Download(ctx context.Context, file string) (ExportFile, error)
+	// PurgeFiles removes export files older than the retention.
+	PurgeFiles(ctx context.Context, now time.Time) (int, error)
+}
+
+type exportService struct {
+	repo      ExportRepository
+	storage   ExportStorage
+	jobs      job.Service
+	retention time.Duration
+	now       func() time.Time
+}
+
+func NewExportService(repo ExportRepository, storage ExportStorage, jobs job.Service, retention time.Duration) ExportService {
+	return &exportService{repo: repo, storage: storage, jobs: jobs, retention: retention, now: time.Now}
+}
+
+// NewExportJobHandler runs the exports queued by Submit.
+func NewExportJobHandler(service ExportService) job.Handler {
+	return func(ctx context.Context, payload json.RawMessage) (any, error) {
+		var queued ExportJob
+		if err := json.Unmarshal(payload, &queued); err != nil {
+			return nil, err
+		}
+		return service.Run(ctx, queued)
+	}
+}
+
+func (s *exportService) Export(ctx context.Context, input ExportInput, w io.Writer) (int, error) {
+	format, filter, err := s.prepare(input)
+	if err != nil {
+		return 0, err
+	}
+
+	writer := newTaskWriter(format, w)
+	rows := 0
+	err = s.repo.StreamTasks(ctx, filter, func(exported Task) error {
+		rows++
+		return writer.Write(exported)
+	})
+	if err != nil {
+		return rows, err
+	}
+	return rows, writer.Close()
+}
+
+func (s *exportService) Submit(ctx context.Context, input ExportInput) (job.Job, error) {
+	format, _, err := s.prepare(input)
+	if err != nil {
+		return job.Job{}, err
+	}
+	return s.jobs.Enqueue(ctx, ExportJobType, ExportJob{Input: input, File: uuid.NewString() + "." + string(format)})
+}
+
+func (s *exportService) Run(ctx context.Context, queued ExportJob) (ExportResult, error) {
+	format, _, err := s.prepare(queued.Input)
+	if err != nil {
+		return ExportResult{}, err
+	}
+
+	rows := 0
+	err = s.storage.Save(queued.File, func(w io.Writer) error {
+		buffered := bufio.NewWriter(w)
+		var err error
+		if rows, err = s.Export(ctx, queued.Input, buffered); err != nil {
+			return err
+		}
+		return buffered.Flush()
+	})
+	if err != nil {
+		return ExportResult{}, err
+	}
+
+	return ExportResult{Format: format, Rows: rows, DownloadURL: "/exports/" + queued.File}, nil
+}
+
+// Download opens an export saved by Run; file names are random, so knowing
+// one is what grants access to the export.
+func (s *exportService) Download(_ context.Context, file string) (ExportFile, error) {
+	id, extension, _ := strings.Cut(file, ".")
+	format, err := ParseExportFormat(extension)
+	if err != nil || extension == "" || uuid.Validate(id) != nil {
+		return ExportFile{}, ErrExportNotFound
+	}
+
+	content, err := s.storage.Open(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return ExportFile{}, ErrExportNotFound
+	}
+	if err != nil {
+		return ExportFile{}, err
+	}
+	return ExportFile{Name: "tasks." + string(format), Format: format, Content: content}, nil
+}
+
+func (s *exportService) PurgeFiles(_ context.Context, now time.Time) (int, error) {
+	return s.storage.RemoveOlderThan(now.Add(-s.retention))
+}
+
+func (s *exportService) prepare(input ExportInput) (ExportFormat, ListFilter, error) {
+	format, err := ParseExportFormat(input.Format)
+	if err != nil {
+		return "", ListFilter{}, err
+	}
+
+	list := input.List
+	list.Cursor, list.Limit, list.Offset = "", 0, 0
+	filter, err := buildListFilter(list, s.now())
+	if err != nil {
+		return "", ListFilter{}, err
+	}
+	return format, filter, nil
+}
+
+// taskWriter encodes exported tasks one at a time.
+type taskWriter interface {
+	Write(exported Task) error
+	Close() error
+}
+
+func newTaskWriter(format ExportFormat, w io.Writer) taskWriter {
+	switch format {
+	case ExportJSON:
+		return &jsonTaskWriter{w: w}
+	case ExportNDJSON:
+		return &ndjsonTaskWriter{encoder: json.NewEncoder(w)}
+	default:
+		return &csvTaskWriter{w: csv.NewWriter(w)}
+	}
+}
+
+// exportColumns are the columns of a CSV export, named like the JSON fields
+// of a task.
+var exportColumns = []string{
+	"id", "title", "description", "status", "priority", "project_id", "sprint_id", "milestone_id",
+	"reporter_id", "assignee_id", "labels", "estimate_minutes", "story_points", "time_spent_seconds",
+	"due_at", "recurrence_rule", "custom_fields", "created_at", "updated_at", "completed_at", "archived_at",
+}
+
+type csvTaskWriter struct {
+	w             *csv.Writer
+	headerWritten bool
+}
+
+func (c *csvTaskWriter) Write(exported Task) error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+
+	customFields := ""
+	if len(exported.CustomFields) > 0 {
+		encoded, err := json.Marshal(exported.CustomFields)
+		if err != nil {
+			return err
+		}
+		customFields = string(encoded)
+	}
+
+	return c.w.Write([]string{
+		strconv.FormatUint(exported.ID, 10),
+		spreadsheetSafe(exported.Title),
+		spreadsheetSafe(exported.Description),
+		string(exported.Status),
+		strconv.Itoa(int(exported.Priority)),
+		formatOptionalUint(exported.ProjectID),
+		formatOptionalUint(exported.SprintID),
+		formatOptionalUint(exported.MilestoneID),
+		formatOptionalString(exported.ReporterID),
+		formatOptionalString(exported.AssigneeID),
+		spreadsheetSafe(strings.Join(exported.Labels, ";")),
+		formatOptionalUint(exported.EstimateMinutes),
+		formatOptionalUint(exported.StoryPoints),
+		strconv.FormatInt(exported.TimeSpentSeconds, 10),
+		formatOptionalTime(exported.DueAt),
+		exported.RecurrenceRule,
+		customFields,
+		exported.CreatedAt.UTC().Format(time.RFC3339),
+		exported.UpdatedAt.UTC().Format(time.RFC3339),
+		formatOptionalTime(exported.CompletedAt),
+		formatOptionalTime(exported.ArchivedAt),
+	})
+}
+
+func (c *csvTaskWriter) Close() error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvTaskWriter) writeHeader() error {
+	if c.headerWritten {
+		return nil
+	}
+	c.headerWritten = true
+	return c.w.Write(exportColumns)
+}
+
+type jsonTaskWriter struct {
+	w       io.Writer
+	started bool
+}
+
+func (j *jsonTaskWriter) Write(exported Task) error {
+	encoded, err := json.Marshal(exported)
+	if err != nil {
+		return err
+	}
+
+	separator := ",\n"
+	if !j.started {
+		separator = "[\n"
+		j.started = true
+	}
+	if _, err := io.WriteString(j.w, separator); err != nil {
+		return err
+	}
+	_, err = j.w.Write(encoded)
+	return err
+}
+
+func (j *jsonTaskWriter) Close() error {
+	closing := "\n]\n"
+	if !j.started {
+		closing = "[]\n"
+	}
+	_, err := io.WriteString(j.w, closing)
+	return err
+}
+
+type ndjsonTaskWriter struct {
+	encoder *json.Encoder
+}
+
+func (n *ndjsonTaskWriter) Write(exported Task) error {
+	return n.encoder.Encode(exported)
+}
+
+func (n *ndjsonTaskWriter) Close() error {
+	return nil
+}
+
+// spreadsheetSafe keeps spreadsheet applications from evaluating text that
+// starts like a formula.
+func spreadsheetSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func formatOptionalUint[T uint64 | uint32 | uint16](value *T) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatUint(uint64(*value), 10)
+}
+
+func formatOptionalString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func formatOptionalTime(value *time.Time) string {
+	if value == nil {
+		return ""
+	}
+	return value.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,205 @@
+package task
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"strings"
+	"testing"
+	"time"
+)
+
+type mockExportRepository struct {
+	tasks  []Task
+	err    error
+	filter ListFilter
+}
+
+func (m *mockExportRepository) StreamTasks(_ context.Context, filter ListFilter, fn func(Task) error) error {
+	m.filter = filter
+	for _, streamed := range m.tasks {
+		if err := fn(streamed); err != nil {
+			return err
+		}
+	}
+	return m.err
+}
+
+type mockExportStorage struct {
+	files map[string][]byte
+}
+
+func (m *mockExportStorage) Save(name string, write func(w io.Writer) error) error {
+	var buffer bytes.Buffer
+	if err := write(&buffer); err != nil {
+		return err
+	}
+	m.files[name] = buffer.Bytes()
+	return nil
+}
+
+func (m *mockExportStorage) Open(name string) (io.ReadCloser, error) {
+	content, ok := m.files[name]
+	if !ok {
+		return nil, fs.ErrNotExist
+	}
+	return io.NopCloser(bytes.NewReader(content)), nil
+}
+
+func (m *mockExportStorage) RemoveOlderThan(time.Time) (int, error) {
+	return 0, nil
+}
+
+func exportedTasks() []Task {
+	createdAt := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	dueAt := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	projectID := uint64(2)
+	points := uint16(5)
+	return []Task{
+		{ID: 1, Title: "Ship, then \"celebrate\"", Status: StatusNew, Priority: 3, ProjectID: &projectID, StoryPoints: &points, Labels: []string{"backend", "release"}, DueAt: &dueAt, CreatedAt: createdAt, UpdatedAt: createdAt},
+		{ID: 2, Title: "=HYPERLINK(\"http://evil\")", Description: "multi\nline", Status: StatusDone, Priority: 1, Labels: []string{}, CustomFields: map[string]any{"team": "core"}, CreatedAt: createdAt, UpdatedAt: createdAt},
+	}
+}
+
+func TestExportServiceExport_CSV(t *testing.T) {
+	repo := &mockExportRepository{tasks: exportedTasks()}
+	svc := NewExportService(repo, nil, nil, 0)
+
+	var output bytes.Buffer
+	rows, err := svc.Export(context.Background(), ExportInput{List: ListTasksInput{Status: "new,done", Sort: "-priority", Limit: 5, Offset: 10}}, &output)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rows != 2 {
+		t.Fatalf("expected 2 rows, got %d", rows)
+	}
+	if repo.filter.Offset != 0 || len(repo.filter.Statuses) != 2 || len(repo.filter.Sort) != 1 {
+		t.Fatalf("expected the list filters without pagination, got %+v", repo.filter)
+	}
+
+	expected := strings.Join([]string{
+		"id,title,description,status,priority,project_id,sprint_id,milestone_id,reporter_id,assignee_id,labels,estimate_minutes,story_points,time_spent_seconds,due_at,recurrence_rule,custom_fields,created_at,updated_at,completed_at,archived_at",
+		`1,"Ship, then ""celebrate""",,new,3,2,,,,,backend;release,,5,0,2026-03-10T12:00:00Z,,,2026-03-01T09:00:00Z,2026-03-01T09:00:00Z,,`,
+		`2,"'=HYPERLINK(""http://evil"")","multi` + "\n" + `line",done,1,,,,,,,,,0,,,"{""team"":""core""}",2026-03-01T09:00:00Z,2026-03-01T09:00:00Z,,`,
+		"",
+	}, "\n")
+	if output.String() != expected {
+		t.Fatalf("unexpected csv:\n%s", output.String())
+	}
+}
+
+func TestExportServiceExport_JSONFormats(t *testing.T) {
+	tests := []struct {
+		format string
+		tasks  []Task
+		check  func(t *testing.T, output string)
+	}{
+		{format: "json", tasks: exportedTasks(), check: func(t *testing.T, output string) {
+			var decoded []Task
+			if err := json.Unmarshal([]byte(output), &decoded); err != nil || len(decoded) != 2 || decoded[1].ID != 2 {
+				t.Fatalf("expected a JSON array of 2 tasks, got %q (%v)", output, err)
+			}
+		}},
+		{format: "json", check: func(t *testing.T, output string) {
+			if output != "[]\n" {
+				t.Fatalf("expected an empty array, got %q", output)
+			}
+		}},
+		{format: "NDJSON", tasks: exportedTasks(), check: func(t *testing.T, output string) {
+			lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+			if len(lines) != 2 {
+				t.Fatalf("expected one line per task, got %q", output)
+			}
+			var decoded Task
+			if err := json.Unmarshal([]byte(lines[0]), &decoded); err != nil || decoded.ID != 1 {
+				t.Fatalf("unexpected line %q (%v)", lines[0], err)
+			}
+		}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.format, func(t *testing.T) {
+			svc := NewExportService(&mockExportRepository{tasks: tc.tasks}, nil, nil, 0)
+			var output bytes.Buffer
+			if _, err := svc.Export(context.Background(), ExportInput{Format: tc.format}, &output); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			tc.check(t, output.String())
+		})
+	}
+}
+
+func TestExportServiceExport_InvalidInput(t *testing.T) {
+	tests := []struct {
+		name  string
+		input ExportInput
+		field string
+	}{
+		{name: "format", input: ExportInput{Format: "xlsx"}, field: "format"},
+		{name: "filter", input: ExportInput{List: ListTasksInput{Filter: "status ="}}, field: "filter"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var output bytes.Buffer
+			_, err := NewExportService(&mockExportRepository{tasks: exportedTasks()}, nil, nil, 0).Export(context.Background(), tc.input, &output)
+			var validationErr ValidationError
+			if !errors.As(err, &validationErr) || validationErr.Field != tc.field {
+				t.Fatalf("expected %s validation error, got %v", tc.field, err)
+			}
+			if output.Len() != 0 {
+				t.Fatalf("expected nothing to be written, got %q", output.String())
+			}
+		})
+	}
+}
+
+func TestExportServiceAsync(t *testing.T) {
+	storage := &mockExportStorage{files: map[string][]byte{}}
+	jobs := &mockJobService{}
+	svc := NewExportService(&mockExportRepository{tasks: exportedTasks()}, storage, jobs, 0)
+
+	queued, err := svc.Submit(context.Background(), ExportInput{Format: "ndjson"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if queued.Type != ExportJobType {
+		t.Fatalf("unexpected job: %+v", queued)
+	}
+	payload, ok := jobs.payload.(ExportJob)
+	if !ok || !strings.HasSuffix(payload.File, ".ndjson") {
+		t.Fatalf("unexpected payload: %+v", jobs.payload)
+	}
+
+	encoded, _ := json.Marshal(payload)
+	result, err := NewExportJobHandler(svc)(context.Background(), encoded)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	exported := result.(ExportResult)
+	if exported.Rows != 2 || exported.DownloadURL != "/exports/"+payload.File {
+		t.Fatalf("unexpected result: %+v", exported)
+	}
+
+	file, err := svc.Download(context.Background(), payload.File)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	content, _ := io.ReadAll(file.Content)
+	if file.Format != ExportNDJSON || file.Name != "tasks.ndjson" || !bytes.Equal(content, storage.files[payload.File]) {
+		t.Fatalf("unexpected download: %+v", file)
+	}
+
+	for _, name := range []string{"../secret.csv", "3b241101-e2bb-4255-8caf-4136c566a962.xlsx", "3b241101-e2bb-4255-8caf-4136c566a962.csv"} {
+		if _, err := svc.Download(context.Background(), name); !errors.Is(err, ErrExportNotFound) {
+			t.Fatalf("expected ErrExportNotFound for %q, got %v", name, err)
+		}
+	}
+
+	if _, err := svc.Submit(context.Background(), ExportInput{Format: "xml"}); err == nil {
+		t.Fatal("expected invalid input to be rejected before queueing")
+	}
+}
@@ -0,0 +1,513 @@
+package task
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	maxFilterLength      = 2000
+	maxFilterComparisons = 50
+	maxFilterDepth       = 16
+)
+
+// FilterField is a task attribute that can be compared in a filter
+// expression.
+type FilterField string
+
+const (
+	FilterStatus      FilterField = "status"
+	FilterPriority    FilterField = "priority"
+	FilterLabel       FilterField = "label"
+	FilterTitle       FilterField = "title"
+	FilterDescription FilterField = "description"
+	FilterAssignee    FilterField = "assignee"
+	FilterReporter    FilterField = "reporter"
+	FilterProject     FilterField = "project"
+	FilterSprint      FilterField = "sprint"
+	FilterMilestone   FilterField = "milestone"
+	FilterEstimate    FilterField = "estimate"
+	FilterPoints      FilterField = "points"
+	FilterDue         FilterField = "due"
+	FilterCreated     FilterField = "created"
+	FilterUpdated     FilterField = "updated"
+)
+
+// FilterOperator is a comparison operator; ":" and "=" are both parsed as
+// FilterEq and "~" matches a substring.
+type FilterOperator string
+
+const (
+	FilterEq       FilterOperator = "="
+	FilterNe       FilterOperator = "!="
+	FilterGt       FilterOperator = ">"
+	FilterGte      FilterOperator = ">="
+	FilterLt       FilterOperator = "<"
+	FilterLte      FilterOperator = "<="
+	FilterContains FilterOperator = "~"
+)
+
+// FilterExpr is a node of a parsed filter expression: FilterAnd, FilterOr,
+// FilterNot or FilterComparison.
+type FilterExpr interface {
+	filterExpr()
+}
+
+type FilterAnd struct {
+	Operands []FilterExpr
+}
+
+type FilterOr struct {
+	Operands []FilterExpr
+}
+
+type FilterNot struct {
+	Operand FilterExpr
+}
+
+// FilterComparison.Value is a Status, an int64, a string, a time.Time or nil
+// for null; relative times such as now+7d are resolved when parsing.
+// Position is the 1-based offset of the field in the expression.
+type FilterComparison struct {
+	Field    FilterField
+	Operator FilterOperator
+	Value    any
+	Position int
+}
+
+func (FilterAnd) filterExpr()        {}
+func (FilterOr) filterExpr()         {}
+func (FilterNot) filterExpr()        {}
+func (FilterComparison) filterExpr() {}
+
+type filterKind int
+
+const (
+	filterKindStatus filterKind = iota
+	filterKindNumber
+	filterKindID
+	filterKindText
+	filterKindLabel
+	filterKindUser
+	filterKindTime
+)
+
+type filterFieldSpec struct {
+	kind     filterKind
+	nullable bool
+	validate func(int) error
+}
+
+var filterFields = map[FilterField]filterFieldSpec{
+	FilterStatus:      {kind: filterKindStatus},
+	FilterPriority:    {kind: filterKindNumber, validate: discardValue(validatePriority)},
+	FilterLabel:       {kind: filterKindLabel},
+	FilterTitle:       {kind: filterKindText},
+	FilterDescription: {kind: filterKindText},
+	FilterAssignee:    {kind: filterKindUser, nullable: true},
+	FilterReporter:    {kind: filterKindUser, nullable: true},
+	FilterProject:     {kind: filterKindID, nullable: true},
+	FilterSprint:      {kind: filterKindID, nullable: true},
+	FilterMilestone:   {kind: filterKindID, nullable: true},
+	FilterEstimate:    {kind: filterKindNumber, nullable: true, validate: discardValue(validateEstimateMinutes)},
+	FilterPoints:      {kind: filterKindNumber, nullable: true, validate: discardValue(validateStoryPoints)},
+	FilterDue:         {kind: filterKindTime, nullable: true},
+	FilterCreated:     {kind: filterKindTime},
+	FilterUpdated:     {kind: filterKindTime},
+}
+
+func discardValue[T any](validate func(int) (T, error)) func(int) error {
+	return func(raw int) error {
+		_, err := validate(raw)
+		return err
+	}
+}
+
+func (kind filterKind) supports(operator FilterOperator) bool {
+	switch kind {
+	case filterKindNumber, filterKindTime:
+		return operator != FilterContains
+	case filterKindText:
+		return operator == FilterEq || operator == FilterNe || operator == FilterContains
+	default:
+		return operator == FilterEq || operator == FilterNe
+	}
+}
+
+// ParseFilter parses a filter expression such as
+//
+//	status:in_progress AND priority>=4 AND (label:bug OR title~"deploy") AND due<now+7d
+//
+// NOT binds tighter than AND, which binds tighter than OR. Relative times are
+// resolved against now. Errors are ValidationErrors on the "filter" field
+// carrying the position of the offending token.
+func ParseFilter(raw string, now time.Time) (FilterExpr, error) {
+	if len(raw) > maxFilterLength {
+		return nil, ValidationError{Field: "filter", Message: "must be at most 2000 characters"}
+	}
+
+	parser := &filterParser{input: raw, now: now.UTC()}
+	expr, err := parser.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	parser.skipSpaces()
+	if parser.pos < len(parser.input) {
+		if parser.input[parser.pos] == ')' {
+			return nil, parser.errorAt(parser.pos, "unexpected )")
+		}
+		return nil, parser.errorAt(parser.pos, "expected AND or OR")
+	}
+	return expr, nil
+}
+
+type filterParser struct {
+	input       string
+	pos         int
+	depth       int
+	comparisons int
+	now         time.Time
+}
+
+func (p *filterParser) parseOr() (FilterExpr, error) {
+	operands, err := p.parseOperands("OR", p.parseAnd)
+	if err != nil {
+		return nil, err
+	}
+	if len(operands) == 1 {
+		return operands[0], nil
+	}
+	return FilterOr{Operands: operands}, nil
+}
+
+func (p *filterParser) parseAnd() (FilterExpr, error) {
+	operands, err := p.parseOperands("AND", p.parseUnary)
+	if err != nil {
+		return nil, err
+	}
+	if len(operands) == 1 {
+		return operands[0], nil
+	}
+	return FilterAnd{Operands: operands}, nil
+}
+
+func (p *filterParser) parseOperands(keyword string, parse func() (FilterExpr, error)) ([]FilterExpr, error) {
+	first, err := parse()
+	if err != nil {
+		return nil, err
+	}
+
+	operands := []FilterExpr{first}
+	for p.consumeKeyword(keyword) {
+		next, err := parse()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, next)
+	}
+	return operands, nil
+}
+
+func (p *filterParser) parseUnary() (FilterExpr, error) {
+	p.skipSpaces()
+	start := p.pos
+
+	if p.consumeKeyword("NOT") {
+		if err := p.enter(start); err != nil {
+			return nil, err
+		}
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		p.depth--
+		return FilterNot{Operand: operand}, nil
+	}
+
+	if p.pos < len(p.input) && p.input[p.pos] == '(' {
+		if err := p.enter(start); err != nil {
+			return nil, err
+		}
+		p.pos++
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpaces()
+		if p.pos >= len(p.input) || p.input[p.pos] != ')' {
+			return nil, p.errorAt(p.pos, "expected )")
+		}
+		p.pos++
+		p.depth--
+		return expr, nil
+	}
+
+	return p.parseComparison()
+}
+
+func (p *filterParser) enter(start int) error {
+	p.depth++
+	if p.depth > maxFilterDepth {
+		return p.errorAt(start, "is nested too deeply")
+	}
+	return nil
+}
+
+func (p *filterParser) parseComparison() (FilterExpr, error) {
+	start := p.pos
+	name := p.readWhile(isFilterIdentifier)
+	if name == "" {
+		if p.pos >= len(p.input) {
+			return nil, p.errorAt(p.pos, "unexpected end of filter, expected a field")
+		}
+		return nil, p.errorAt(p.pos, "expected a field")
+	}
+
+	field := FilterField(strings.ToLower(name))
+	spec, ok := filterFields[field]
+	if !ok {
+		return nil, p.errorAt(start, "unknown field "+name)
+	}
+
+	p.comparisons++
+	if p.comparisons > maxFilterComparisons {
+		return nil, p.errorAt(start, "must contain at most 50 comparisons")
+	}
+
+	p.skipSpaces()
+	operatorPos := p.pos
+	operator, ok := p.readOperator()
+	if !ok {
+		return nil, p.errorAt(operatorPos, "expected an operator after "+name)
+	}
+	if !spec.kind.supports(operator) {
+		return nil, p.errorAt(operatorPos, fmt.Sprintf("operator %s is not supported for %s", operator, field))
+	}
+
+	p.skipSpaces()
+	valuePos := p.pos
+	rawValue, quoted, err := p.readValue()
+	if err != nil {
+		return nil, err
+	}
+
+	comparison := FilterComparison{Field: field, Operator: operator, Position: p.position(start)}
+	if !quoted && strings.EqualFold(rawValue, "null") {
+		if !spec.nullable {
+			return nil, p.errorAt(valuePos, string(field)+" cannot be null")
+		}
+		if operator != FilterEq && operator != FilterNe {
+			return nil, p.errorAt(valuePos, "null can only be compared with = or !=")
+		}
+		return comparison, nil
+	}
+
+	comparison.Value, err = p.convertValue(spec, operator, rawValue)
+	if err != nil {
+		return nil, p.errorAt(valuePos, err.Error())
+	}
+	return comparison, nil
+}
+
+func (p *filterParser) convertValue(spec filterFieldSpec, operator FilterOperator, raw string) (any, error) {
+	switch spec.kind {
+	case filterKindStatus:
+		status := Status(strings.ToLower(raw))
+		if !status.IsValid() {
+			return nil, fmt.Errorf("status must be one of: new, in_progress, done")
+		}
+		return status, nil
+	case filterKindNumber:
+		value, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an integer", raw)
+		}
+		if err := spec.validate(value); err != nil {
+			return nil, fmt.Errorf("%s", validationMessage(err))
+		}
+		return int64(value), nil
+	case filterKindID:
+		value, err := strconv.ParseUint(raw, 10, 63)
+		if err != nil || value == 0 {
+			return nil, fmt.Errorf("%q is not a positive integer", raw)
+		}
+		return int64(value), nil
+	case filterKindText:
+		if raw == "" {
+			return nil, fmt.Errorf("must not be empty")
+		}
+		if operator == FilterContains && len(raw) > maxTitleLength {
+			return nil, fmt.Errorf("must be at most %d characters", maxTitleLength)
+		}
+		return raw, nil
+	case filterKindLabel:
+		labels, err := normalizeLabels([]string{raw})
+		if err != nil {
+			return nil, fmt.Errorf("%s", validationMessage(err))
+		}
+		return labels[0], nil
+	case filterKindUser:
+		userID, err := normalizeUserID(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s", validationMessage(err))
+		}
+		return userID, nil
+	case filterKindTime:
+		return parseFilterTime(raw, p.now)
+	}
+	return nil, fmt.Errorf("unsupported value")
+}
+
+// parseFilterTime accepts an RFC 3339 timestamp, a date, now or a time
+// relative to now such as now+7d or now-12h (units: m, h, d, w).
+func parseFilterTime(raw string, now time.Time) (time.Time, error) {
+	if parsed, err := time.Parse(time.RFC3339, raw); err == nil {
+		return parsed.UTC(), nil
+	}
+	if parsed, err := time.Parse(time.DateOnly, raw); err == nil {
+		return parsed, nil
+	}
+
+	lowered := strings.ToLower(raw)
+	if lowered == "now" {
+		return now, nil
+	}
+	offset, ok := strings.CutPrefix(lowered, "now")
+	if !ok || len(offset) < 3 || (offset[0] != '+' && offset[0] != '-') {
+		return time.Time{}, fmt.Errorf("%q is not a timestamp, a date or a time relative to now", raw)
+	}
+
+	digits := offset[1 : len(offset)-1]
+	amount, err := strconv.Atoi(digits)
+	if err != nil || strings.Trim(digits, "0123456789") != "" || amount > 3650*24*60 {
+		return time.Time{}, fmt.Errorf("%q has an invalid offset", raw)
+	}
+	var unit time.Duration
+	switch offset[len(offset)-1] {
+	case 'm':
+		unit = time.Minute
+	case 'h':
+		unit = time.Hour
+	case 'd':
+		unit = 24 * time.Hour
+	case 'w':
+		unit = 7 * 24 * time.Hour
+	default:
+		return time.Time{}, fmt.Errorf("%q has an unknown unit, use m, h, d or w", raw)
+	}
+
+	shift := time.Duration(amount) * unit
+	if offset[0] == '-' {
+		shift = -shift
+	}
+	return now.Add(shift), nil
+}
+
+// consumeKeyword consumes a case-insensitive keyword that is not the prefix
+// of a longer word.
+func (p *filterParser) consumeKeyword(keyword string) bool {
+	p.skipSpaces()
+	end := p.pos + len(keyword)
+	if end > len(p.input) || !strings.EqualFold(p.input[p.pos:end], keyword) {
+		return false
+	}
+	if end < len(p.input) && isFilterIdentifier(p.input[end]) {
+		return false
+	}
+	p.pos = end
+	return true
+}
+
+func (p *filterParser) readOperator() (FilterOperator, bool) {
+	for _, candidate := range []struct {
+		token    string
+		operator FilterOperator
+	}{
+		{token: ">=", operator: FilterGte},
+		{token: "<=", operator: FilterLte},
+		{token: "!=", operator: FilterNe},
+		{token: ":", operator: FilterEq},
+		{token: "=", operator: FilterEq},
+		{token: ">", operator: FilterGt},
+		{token: "<", operator: FilterLt},
+		{token: "~", operator: FilterContains},
+	} {
+		if strings.HasPrefix(p.input[p.pos:], candidate.token) {
+			p.pos += len(candidate.token)
+			return candidate.operator, true
+		}
+	}
+	return "", false
+}
+
+// readValue reads a double quoted string, in which \" and \\ are escapes, or
+// a bare word that ends at a space or a parenthesis.
+func (p *filterParser) readValue() (string, bool, error) {
+	start := p.pos
+	if p.pos >= len(p.input) {
+		return "", false, p.errorAt(start, "unexpected end of filter, expected a value")
+	}
+
+	if p.input[p.pos] != '"' {
+		value := p.readWhile(func(c byte) bool {
+			return c != ' ' && c != '\t' && c != '(' && c != ')' && c != '"'
+		})
+		if value == "" {
+			return "", false, p.errorAt(start, "expected a value")
+		}
+		return value, false, nil
+	}
+
+	var value strings.Builder
+	for p.pos++; p.pos < len(p.input); p.pos++ {
+		c := p.input[p.pos]
+		switch {
+		case c == '"':
+			p.pos++
+			return value.String(), true, nil
+		case c == '\\' && p.pos+1 < len(p.input) && (p.input[p.pos+1] == '"' || p.input[p.pos+1] == '\\'):
+			p.pos++
+			value.WriteByte(p.input[p.pos])
+		default:
+			value.WriteByte(c)
+		}
+	}
+	return "", false, p.errorAt(start, "unterminated string")
+}
+
+func (p *filterParser) readWhile(accept func(byte) bool) string {
+	start := p.pos
+	for p.pos < len(p.input) && accept(p.input[p.pos]) {
+		p.pos++
+	}
+	return p.input[start:p.pos]
+}
+
+func (p *filterParser) skipSpaces() {
+	p.readWhile(func(c byte) bool { return c == ' ' || c == '\t' || c == '\n' || c == '\r' })
+}
+
+// position converts a byte offset into the 1-based character position
+// reported to clients.
+func (p *filterParser) position(offset int) int {
+	return utf8.RuneCountInString(p.input[:offset]) + 1
+}
+
+func (p *filterParser) errorAt(offset int, message string) error {
+	return ValidationError{Field: "filter", Message: message, Position: p.position(offset)}
+}
+
+func isFilterIdentifier(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func validationMessage(err error) string {
+	if validationErr, ok := err.(ValidationError); ok {
+		return validationErr.Message
+	}
+	return err.Error()
+}
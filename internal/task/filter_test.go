@@ -0,0 +1,127 @@
+package task
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseFilter(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	expr, err := ParseFilter(`status:in_progress AND priority>=4 AND (label:Bug OR title~"deploy \"blue\"") AND due<now+7d`, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := FilterAnd{Operands: []FilterExpr{
+		FilterComparison{Field: FilterStatus, Operator: FilterEq, Value: StatusInProgress, Position: 1},
+		FilterComparison{Field: FilterPriority, Operator: FilterGte, Value: int64(4), Position: 24},
+		FilterOr{Operands: []FilterExpr{
+			FilterComparison{Field: FilterLabel, Operator: FilterEq, Value: "bug", Position: 41},
+			FilterComparison{Field: FilterTitle, Operator: FilterContains, Value: `deploy "blue"`, Position: 54},
+		}},
+		FilterComparison{Field: FilterDue, Operator: FilterLt, Value: now.AddDate(0, 0, 7), Position: 83},
+	}}
+	if !reflect.DeepEqual(expr, expected) {
+		t.Fatalf("unexpected expression:\n got %#v\nwant %#v", expr, expected)
+	}
+}
+
+func TestParseFilter_Precedence(t *testing.T) {
+	expr, err := ParseFilter("not status=done or priority<2 and assignee=null", time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := FilterOr{Operands: []FilterExpr{
+		FilterNot{Operand: FilterComparison{Field: FilterStatus, Operator: FilterEq, Value: StatusDone, Position: 5}},
+		FilterAnd{Operands: []FilterExpr{
+			FilterComparison{Field: FilterPriority, Operator: FilterLt, Value: int64(2), Position: 20},
+			FilterComparison{Field: FilterAssignee, Operator: FilterEq, Position: 35},
+		}},
+	}}
+	if !reflect.DeepEqual(expr, expected) {
+		t.Fatalf("unexpected expression:\n got %#v\nwant %#v", expr, expected)
+	}
+}
+
+func TestParseFilter_Times(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := map[string]time.Time{
+		"now":                  now,
+		"now-12h":              now.Add(-12 * time.Hour),
+		"now+2w":               now.AddDate(0, 0, 14),
+		"2026-04-01":           time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC),
+		"2026-04-01T10:00:00Z": time.Date(2026, 4, 1, 10, 0, 0, 0, time.UTC),
+	}
+	for raw, want := range tests {
+		expr, err := ParseFilter("created>="+raw, now)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", raw, err)
+		}
+		if got := expr.(FilterComparison).Value; got != want {
+			t.Fatalf("%s: expected %v, got %v", raw, want, got)
+		}
+	}
+}
+
+func TestParseFilter_Errors(t *testing.T) {
+	tests := []struct {
+		raw      string
+		position int
+		message  string
+	}{
+		{raw: "status:done AND", position: 16, message: "unexpected end of filter, expected a field"},
+		{raw: "colour:red", position: 1, message: "unknown field colour"},
+		{raw: "priority>=9", position: 11, message: "must be between 1 and 5"},
+		{raw: "status>done", position: 7, message: "operator > is not supported for status"},
+		{raw: "title~\"deploy", position: 7, message: "unterminated string"},
+		{raw: "(status:done", position: 13, message: "expected )"},
+		{raw: "status:done)", position: 12, message: "unexpected )"},
+		{raw: "status:done priority:1", position: 13, message: "expected AND or OR"},
+		{raw: "title:null", position: 7, message: "title cannot be null"},
+		{raw: "due>null", position: 5, message: "null can only be compared with = or !="},
+		{raw: "due<tomorrow", position: 5, message: `"tomorrow" is not a timestamp, a date or a time relative to now`},
+		{raw: "assignee", position: 9, message: "expected an operator after assignee"},
+		{raw: "due>now+-7d", position: 5, message: `"now+-7d" has an invalid offset`},
+		{raw: "due>now--3h", position: 5, message: `"now--3h" has an invalid offset`},
+		{raw: "due>now-+9999999999999w", position: 5, message: `"now-+9999999999999w" has an invalid offset`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.raw, func(t *testing.T) {
+			_, err := ParseFilter(tc.raw, time.Now())
+			var validationErr ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("expected validation error, got %v", err)
+			}
+			expected := ValidationError{Field: "filter", Message: tc.message, Position: tc.position}
+			if validationErr != expected {
+				t.Fatalf("expected %+v, got %+v", expected, validationErr)
+			}
+		})
+	}
+}
+
+func TestBuildListFilter_Expression(t *testing.T) {
+	filter, err := buildListFilter(ListTasksInput{Filter: " label:ops "}, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := FilterComparison{Field: FilterLabel, Operator: FilterEq, Value: "ops", Position: 2}
+	if filter.Expr != expected {
+		t.Fatalf("unexpected expression: %#v", filter.Expr)
+	}
+
+	_, err = buildListFilter(ListTasksInput{Filter: "label:ops OR"}, time.Now())
+	expectedErr := ValidationError{Field: "filter", Message: "unexpected end of filter, expected a field", Position: 13}
+	if err != expectedErr {
+		t.Fatalf("expected %v, got %v", expectedErr, err)
+	}
+	if err.Error() != "invalid filter at position 13: unexpected end of filter, expected a field" {
+		t.Fatalf("unexpected message: %s", err.Error())
+	}
+}
@@ -0,0 +1,73 @@
+package task
+
+import (
+	"context"
+	"time"
+)
+
+const housekeepingBatchSize = 500
+
+type HousekeepingRepository interface {
+	PurgeDeleted(ctx context.Context, deletedBefore time.Time, limit int) (int, error)
+	ArchiveCompleted(ctx context.Context, completedBefore time.Time, limit int) (int, error)
+	PurgeIdempotencyKeys(ctx context.Context, expiredBefore time.Time, limit int) (int, error)
+}
+
+// HousekeepingService contains maintenance use-cases that are run by the
+// worker rather than requested through the API.
+type HousekeepingService interface {
+	PurgeDeleted(ctx context.Context, now time.Time) (int, error)
+	ArchiveCompleted(ctx context.Context, now time.Time) (int, error)
+	PurgeIdempotencyKeys(ctx context.Context, now time.Time) (int, error)
+}
+
+type HousekeepingConfig struct {
+	DeletedRetention time.Duration
+	ArchiveAfter     time.Duration
+}
+
+type housekeepingService struct {
+	repo HousekeepingRepository
+	cfg  HousekeepingConfig
+}
+
+func NewHousekeepingService(repo HousekeepingRepository, cfg HousekeepingConfig) HousekeepingService {
+	return &housekeepingService{repo: repo, cfg: cfg}
+}
+
+// PurgeDeleted permanently removes tasks that were soft deleted more than the
+// configured retention period ago. Rows are removed in batches to keep
+// transactions short.
+func (s *housekeepingService) PurgeDeleted(ctx context.Context, now time.Time) (int, error) {
+	deletedBefore := now.UTC().Add(-s.cfg.DeletedRetention)
+	return inBatches(func() (int, error) {
+		return s.repo.PurgeDeleted(ctx, deletedBefore, housekeepingBatchSize)
+	})
+}
+
+// ArchiveCompleted archives tasks that have been done for longer than the
+// configured period.
+func (s *housekeepingService) ArchiveCompleted(ctx context.Context, now time.Time) (int, error) {
+	completedBefore := now.UTC().Add(-s.cfg.ArchiveAfter)
+	return inBatches(func() (int, error) {
+		return s.repo.ArchiveCompleted(ctx, completedBefore, housekeepingBatchSize)
+	})
+}
+
+// PurgeIdempotencyKeys removes idempotency keys whose TTL has passed.
+func (s *housekeepingService) PurgeIdempotencyKeys(ctx context.Context, now time.Time) (int, error) {
+	return inBatches(func() (int, error) {
+		return s.repo.PurgeIdempotencyKeys(ctx, now.UTC(), housekeepingBatchSize)
+	})
+}
+
+func inBatches(run func() (int, error)) (int, error) {
+	total := 0
+	for {
+		affected, err := run()
+		total += affected
+		if err != nil || affected < housekeepingBatchSize {
+			return total, err
+		}
+	}
+}
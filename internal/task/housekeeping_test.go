@@ -0,0 +1,94 @@
+package task
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type mockHousekeepingRepository struct {
+	purgeBatches  []int
+	purgeCalls    int
+	deletedBefore time.Time
+
+	archiveBatches  []int
+	archiveCalls    int
+	completedBefore time.Time
+
+	idempotencyCalls int
+	expiredBefore    time.Time
+}
+
+func (m *mockHousekeepingRepository) PurgeDeleted(_ context.Context, deletedBefore time.Time, _ int) (int, error) {
+	m.deletedBefore = deletedBefore
+	batch := m.purgeBatches[m.purgeCalls]
+	m.purgeCalls++
+	return batch, nil
+}
+
+func (m *mockHousekeepingRepository) ArchiveCompleted(_ context.Context, completedBefore time.Time, _ int) (int, error) {
+	m.completedBefore = completedBefore
+	batch := m.archiveBatches[m.archiveCalls]
+	m.archiveCalls++
+	return batch, nil
+}
+
+func (m *mockHousekeepingRepository) PurgeIdempotencyKeys(_ context.Context, expiredBefore time.Time, _ int) (int, error) {
+	m.expiredBefore = expiredBefore
+	m.idempotencyCalls++
+	return 2, nil
+}
+
+func TestHousekeepingServicePurgeDeleted(t *testing.T) {
+	repo := &mockHousekeepingRepository{purgeBatches: []int{housekeepingBatchSize, 7}}
+	svc := NewHousekeepingService(repo, HousekeepingConfig{DeletedRetention: 30 * 24 * time.Hour})
+
+	now := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+	purged, err := svc.PurgeDeleted(context.Background(), now)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if purged != housekeepingBatchSize+7 {
+		t.Fatalf("unexpected purged count: %d", purged)
+	}
+	if repo.purgeCalls != 2 {
+		t.Fatalf("expected purge to continue until a partial batch, got %d calls", repo.purgeCalls)
+	}
+	if !repo.deletedBefore.Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected retention cutoff: %v", repo.deletedBefore)
+	}
+}
+
+func TestHousekeepingServiceArchiveCompleted(t *testing.T) {
+	repo := &mockHousekeepingRepository{archiveBatches: []int{3}}
+	svc := NewHousekeepingService(repo, HousekeepingConfig{ArchiveAfter: 14 * 24 * time.Hour})
+
+	now := time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)
+	archived, err := svc.ArchiveCompleted(context.Background(), now)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if archived != 3 || repo.archiveCalls != 1 {
+		t.Fatalf("unexpected archive result: archived=%d calls=%d", archived, repo.archiveCalls)
+	}
+	if !repo.completedBefore.Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected archive cutoff: %v", repo.completedBefore)
+	}
+}
+
+func TestHousekeepingServicePurgeIdempotencyKeys(t *testing.T) {
+	repo := &mockHousekeepingRepository{}
+	svc := NewHousekeepingService(repo, HousekeepingConfig{})
+
+	now := time.Date(2026, 3, 15, 12, 0, 0, 0, time.FixedZone("CET", 3600))
+	purged, err := svc.PurgeIdempotencyKeys(context.Background(), now)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if purged != 2 || repo.idempotencyCalls != 1 {
+		t.Fatalf("unexpected purge result: purged=%d calls=%d", purged, repo.idempotencyCalls)
+	}
+	if !repo.expiredBefore.Equal(now) || repo.expiredBefore.Location() != time.UTC {
+		t.Fatalf("unexpected expiry cutoff: %v", repo.expiredBefore)
+	}
+}
@@ -0,0 +1,40 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/task"
+)
+
+type BoardHandler struct {
+	service task.BoardService
+}
+
+func NewBoardHandler(service task.BoardService) *BoardHandler {
+	return &BoardHandler{service: service}
+}
+
+func (h *BoardHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /projects/{id}/board", h.getBoard)
+}
+
+func (h *BoardHandler) getBoard(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+
+	limit, err := parseQueryInt(r.URL.Query().Get("limit"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errorResponse{Error: "limit must be an integer", Field: "limit"})
+		return
+	}
+
+	board, err := h.service.Get(r.Context(), id, task.BoardInput{Limit: limit})
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, board)
+}
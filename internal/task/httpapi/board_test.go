@@ -0,0 +1,81 @@
+package httpapi
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/task"
+)
+
+type mockBoardService struct {
+	projectID uint64
+	input     task.BoardInput
+}
+
+func (m *mockBoardService) Get(_ context.Context, projectID uint64, input task.BoardInput) (task.Board, error) {
+	m.projectID = projectID
+	m.input = input
+	return task.Board{ProjectID: projectID, Columns: []task.BoardColumn{}}, nil
+}
+
+func TestBoardHandlerGetBoard(t *testing.T) {
+	svc := &mockBoardService{}
+	mux := http.NewServeMux()
+	NewBoardHandler(svc).Register(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/projects/3/board?limit=50", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	if svc.projectID != 3 || svc.input.Limit != 50 {
+		t.Fatalf("unexpected call: project=%d input=%+v", svc.projectID, svc.input)
+	}
+}
+
+func TestProjectHandlerSetWIPLimits(t *testing.T) {
+	svc := &mockProjectService{}
+	mux := http.NewServeMux()
+	NewProjectHandler(svc).Register(mux)
+
+	req := httptest.NewRequest(http.MethodPut, "/projects/3/wip-limits", bytes.NewBufferString(`{"wip_limits":{"in_progress":3,"done":null}}`))
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	limit, ok := svc.wipLimits["in_progress"]
+	if !ok || limit == nil || *limit != 3 {
+		t.Fatalf("unexpected limits: %v", svc.wipLimits)
+	}
+	if limit, ok := svc.wipLimits["done"]; !ok || limit != nil {
+		t.Fatalf("expected done to clear the limit, got %v", limit)
+	}
+}
+
+func TestHandlerUpdateTask_WIPLimitExceeded(t *testing.T) {
+	svc := &mockService{updateErr: task.ErrWIPLimitExceeded}
+	mux := http.NewServeMux()
+	NewHandler(svc).Register(mux)
+
+	req := httptest.NewRequest(http.MethodPatch, "/tasks/3", bytes.NewBufferString(`{"status":"in_progress"}`))
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected status %d, got %d", http.StatusConflict, rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPatch, "/tasks/3", bytes.NewBufferString(`{"status":"in_progress","force":true}`))
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if !svc.updateInput.Force {
+		t.Fatal("expected force to be passed to the service")
+	}
+}
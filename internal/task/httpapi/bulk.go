@@ -0,0 +1,134 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/task"
+)
+
+type BulkHandler struct {
+	service task.BulkService
+}
+
+// bulkRequest either lists operations or applies changes to every task
+// matching the filter expression.
+type bulkRequest struct {
+	Atomic     bool                   `json:"atomic"`
+	Operations []bulkOperationRequest `json:"operations"`
+	Filter     string                 `json:"filter"`
+	Changes    *updateTaskRequest     `json:"changes"`
+}
+
+// bulkOperationRequest.Task is the task to create and Changes the update to
+// apply; Version is the expected version of the task to update or delete.
+type bulkOperationRequest struct {
+	Action  string             `json:"action"`
+	ID      uint64             `json:"id"`
+	Version *uint64            `json:"version"`
+	Task    *createTaskRequest `json:"task"`
+	Changes *updateTaskRequest `json:"changes"`
+}
+
+func NewBulkHandler(service task.BulkService) *BulkHandler {
+	return &BulkHandler{service: service}
+}
+
+func (h *BulkHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("POST /tasks/bulk", h.bulk)
+}
+
+func (h *BulkHandler) bulk(w http.ResponseWriter, r *http.Request) {
+	var request bulkRequest
+	if err := decodeJSON(w, r, &request); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	input, err := request.input(optionalUserID(r))
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	submission, err := h.service.Submit(r.Context(), input)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	if submission.Job != nil {
+		w.Header().Set("Location", "/jobs/"+submission.Job.ID)
+		writeJSON(w, http.StatusAccepted, submission.Job)
+		return
+	}
+	writeJSON(w, http.StatusOK, submission.Result)
+}
+
+func (request bulkRequest) input(userID string) (task.BulkInput, error) {
+	input := task.BulkInput{Atomic: request.Atomic, Filter: request.Filter}
+
+	if request.Filter != "" {
+		if request.Changes == nil {
+			return task.BulkInput{}, task.ValidationError{Field: "changes", Message: "must be provided with filter"}
+		}
+		changes, err := request.Changes.input(userID)
+		if err != nil {
+			return task.BulkInput{}, prefixField(err, "changes.")
+		}
+		input.Update = changes
+	} else if request.Changes != nil {
+		return task.BulkInput{}, task.ValidationError{Field: "changes", Message: "can only be provided with filter"}
+	}
+
+	input.Operations = make([]task.BulkOperation, 0, len(request.Operations))
+	for i, item := range request.Operations {
+		operation, err := item.operation(userID)
+		if err != nil {
+			return task.BulkInput{}, prefixField(err, "operations["+strconv.Itoa(i)+"].")
+		}
+		input.Operations = append(input.Operations, operation)
+	}
+
+	return input, nil
+}
+
+func (item bulkOperationRequest) operation(userID string) (task.BulkOperation, error) {
+	operation := task.BulkOperation{Action: task.BulkAction(item.Action), ID: item.ID}
+
+	switch operation.Action {
+	case task.BulkCreate:
+		if item.Task == nil {
+			return task.BulkOperation{}, task.ValidationError{Field: "task", Message: "must be provided for create"}
+		}
+		input, err := item.Task.input(userID)
+		if err != nil {
+			return task.BulkOperation{}, prefixField(err, "task.")
+		}
+		operation.Create = input
+	case task.BulkUpdate:
+		if item.Changes == nil {
+			return task.BulkOperation{}, task.ValidationError{Field: "changes", Message: "must be provided for update"}
+		}
+		input, err := item.Changes.input(userID)
+		if err != nil {
+			return task.BulkOperation{}, prefixField(err, "changes.")
+		}
+		input.ExpectedVersion = item.Version
+		operation.Update = input
+	case task.BulkDelete:
+		operation.Delete = task.DeleteTaskInput{ExpectedVersion: item.Version}
+	}
+
+	return operation, nil
+}
+
+func prefixField(err error, prefix string) error {
+	var validationErr task.ValidationError
+	if errors.As(err, &validationErr) {
+		validationErr.Field = prefix + validationErr.Field
+		return validationErr
+	}
+	return err
+}
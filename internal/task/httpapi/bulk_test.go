@@ -0,0 +1,168 @@
+package httpapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/job"
+	"github.com/PavelFesenkoFirst/task_tracker/internal/task"
+)
+
+type mockBulkService struct {
+	input      task.BulkInput
+	submission task.BulkSubmission
+	err        error
+}
+
+func (m *mockBulkService) Submit(_ context.Context, input task.BulkInput) (task.BulkSubmission, error) {
+	m.input = input
+	return m.submission, m.err
+}
+
+func (m *mockBulkService) Run(context.Context, task.BulkInput) (task.BulkResult, error) {
+	return task.BulkResult{}, nil
+}
+
+type mockJobService struct {
+	result job.Job
+	err    error
+}
+
+func (m *mockJobService) Enqueue(context.Context, string, any) (job.Job, error) {
+	return job.Job{}, nil
+}
+
+func (m *mockJobService) Get(context.Context, string) (job.Job, error) {
+	return m.result, m.err
+}
+
+func TestBulkHandler_Operations(t *testing.T) {
+	svc := &mockBulkService{submission: task.BulkSubmission{Result: &task.BulkResult{Atomic: true, Committed: true, Succeeded: 3}}}
+	mux := http.NewServeMux()
+	NewBulkHandler(svc).Register(mux)
+
+	req := httptest.NewRequest(http.MethodPost, "/tasks/bulk", bytes.NewBufferString(`{
+		"atomic": true,
+		"operations": [
+			{"action": "create", "task": {"title": "Write docs", "due_at": "2026-03-04T10:00:00Z"}},
+			{"action": "update", "id": 3, "version": 2, "changes": {"status": "done"}},
+			{"action": "delete", "id": 4}
+		]
+	}`))
+	req.Header.Set(userIDHeader, testUserID)
+	rec := httptest.NewRecorder()
+
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	input := svc.input
+	if !input.Atomic || len(input.Operations) != 3 {
+		t.Fatalf("unexpected input: %+v", input)
+	}
+	if create := input.Operations[0]; create.Action != task.BulkCreate || create.Create.Title != "Write docs" || create.Create.DueAt == nil || create.Create.ReporterID != testUserID {
+		t.Fatalf("unexpected create: %+v", create)
+	}
+	update := input.Operations[1]
+	if update.ID != 3 || update.Update.Status == nil || *update.Update.Status != "done" || update.Update.ActorID != testUserID {
+		t.Fatalf("unexpected update: %+v", update)
+	}
+	if update.Update.ExpectedVersion == nil || *update.Update.ExpectedVersion != 2 {
+		t.Fatalf("unexpected expected version: %v", update.Update.ExpectedVersion)
+	}
+	if remove := input.Operations[2]; remove.Action != task.BulkDelete || remove.ID != 4 || remove.Delete.ExpectedVersion != nil {
+		t.Fatalf("unexpected delete: %+v", remove)
+	}
+
+	var response task.BulkResult
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if !response.Committed || response.Succeeded != 3 {
+		t.Fatalf("unexpected response: %+v", response)
+	}
+}
+
+func TestBulkHandler_FilterQueuedAsJob(t *testing.T) {
+	svc := &mockBulkService{submission: task.BulkSubmission{Job: &job.Job{ID: "4c1f", Status: job.StatusQueued}}}
+	mux := http.NewServeMux()
+	NewBulkHandler(svc).Register(mux)
+
+	req := httptest.NewRequest(http.MethodPost, "/tasks/bulk", bytes.NewBufferString(`{"filter": "status = new", "changes": {"priority": 5}}`))
+	rec := httptest.NewRecorder()
+
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusAccepted || rec.Header().Get("Location") != "/jobs/4c1f" {
+		t.Fatalf("expected a queued job, got %d %q", rec.Code, rec.Header().Get("Location"))
+	}
+	if svc.input.Filter != "status = new" || svc.input.Update.Priority == nil || *svc.input.Update.Priority != 5 {
+		t.Fatalf("unexpected input: %+v", svc.input)
+	}
+}
+
+func TestBulkHandler_InvalidRequest(t *testing.T) {
+	tests := []struct {
+		name  string
+		body  string
+		field string
+	}{
+		{name: "create without task", body: `{"operations": [{"action": "create"}]}`, field: "operations[0].task"},
+		{name: "invalid due_at", body: `{"operations": [{"action": "delete", "id": 1}, {"action": "update", "id": 2, "changes": {"due_at": "tomorrow"}}]}`, field: "operations[1].changes.due_at"},
+		{name: "filter without changes", body: `{"filter": "status = new"}`, field: "changes"},
+		{name: "changes without filter", body: `{"changes": {"priority": 1}}`, field: "changes"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			NewBulkHandler(&mockBulkService{}).Register(mux)
+
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/tasks/bulk", bytes.NewBufferString(tc.body)))
+
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
+			}
+			var response errorResponse
+			if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if response.Field != tc.field {
+				t.Fatalf("expected field %q, got %q", tc.field, response.Field)
+			}
+		})
+	}
+}
+
+func TestJobHandler(t *testing.T) {
+	svc := &mockJobService{result: job.Job{ID: "4c1f", Type: task.BulkJobType, Status: job.StatusDone, Result: json.RawMessage(`{"succeeded":2}`)}}
+	mux := http.NewServeMux()
+	NewJobHandler(svc).Register(mux)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/jobs/4c1f", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	var response map[string]any
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if response["status"] != "done" || response["result"].(map[string]any)["succeeded"] != float64(2) {
+		t.Fatalf("unexpected response: %v", response)
+	}
+
+	svc.err = job.ErrJobNotFound
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/jobs/missing", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d, got %d", http.StatusNotFound, rec.Code)
+	}
+}
@@ -0,0 +1,94 @@
+package httpapi
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/task"
+)
+
+// createCalendarFeedRequest.ProjectID selects a project feed; without it the
+// feed lists the tasks assigned to the user.
+type createCalendarFeedRequest struct {
+	ProjectID *uint64 `json:"project_id"`
+}
+
+// WithCalendar enables the iCalendar feeds of tasks with a due date.
+func WithCalendar(service task.CalendarService) Option {
+	return func(h *Handler) {
+		h.calendar = service
+	}
+}
+
+// calendarFeed serves a feed to calendar apps, which cannot send headers:
+// the token in the URL is the only credential.
+func (h *Handler) calendarFeed(w http.ResponseWriter, r *http.Request) {
+	list, ok := h.parseListInput(w, r)
+	if !ok {
+		return
+	}
+
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	response := &streamedResponse{w: w, contentType: "text/calendar; charset=utf-8"}
+	err := h.calendar.Calendar(r.Context(), task.CalendarInput{
+		Token:     r.URL.Query().Get("token"),
+		Component: r.URL.Query().Get("component"),
+		List:      list,
+	}, response)
+	response.finish(err)
+}
+
+func (h *Handler) createCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var request createCalendarFeedRequest
+	if err := decodeOptionalJSON(w, r, &request); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	feed, err := h.calendar.CreateFeed(r.Context(), task.CreateCalendarFeedInput{OwnerID: userID, ProjectID: request.ProjectID})
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, feed)
+}
+
+func (h *Handler) listCalendarFeeds(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireUserID(w, r)
+	if !ok {
+		return
+	}
+
+	feeds, err := h.calendar.ListFeeds(r.Context(), userID)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, feeds)
+}
+
+func (h *Handler) deleteCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+	userID, ok := requireUserID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.calendar.DeleteFeed(r.Context(), id, userID); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
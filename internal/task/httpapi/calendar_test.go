@@ -0,0 +1,120 @@
+package httpapi
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/task"
+)
+
+type mockCalendarService struct {
+	input     task.CalendarInput
+	create    task.CreateCalendarFeedInput
+	deletedID uint64
+	ownerID   string
+	err       error
+}
+
+func (m *mockCalendarService) CreateFeed(_ context.Context, input task.CreateCalendarFeedInput) (task.CalendarFeed, error) {
+	m.create = input
+	return task.CalendarFeed{ID: 1, OwnerID: input.OwnerID, ProjectID: input.ProjectID, Token: "secret", URL: "/calendar.ics?token=secret"}, m.err
+}
+
+func (m *mockCalendarService) ListFeeds(_ context.Context, ownerID string) ([]task.CalendarFeed, error) {
+	m.ownerID = ownerID
+	return []task.CalendarFeed{{ID: 1, OwnerID: ownerID}}, m.err
+}
+
+func (m *mockCalendarService) DeleteFeed(_ context.Context, id uint64, ownerID string) error {
+	m.deletedID, m.ownerID = id, ownerID
+	return m.err
+}
+
+func (m *mockCalendarService) Calendar(_ context.Context, input task.CalendarInput, w io.Writer) error {
+	m.input = input
+	if m.err != nil {
+		return m.err
+	}
+	_, err := io.WriteString(w, "BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n")
+	return err
+}
+
+func newCalendarMux(calendar *mockCalendarService) *http.ServeMux {
+	mux := http.NewServeMux()
+	NewHandler(&mockService{}, WithCalendar(calendar)).Register(mux)
+	return mux
+}
+
+func TestHandlerCalendarFeed(t *testing.T) {
+	calendar := &mockCalendarService{}
+	rec := httptest.NewRecorder()
+
+	newCalendarMux(calendar).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/calendar.ics?token=secret&component=vtodo&status=new,in_progress&priority_min=3", nil))
+
+	if rec.Code != http.StatusOK || rec.Body.String() != "BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n" {
+		t.Fatalf("unexpected response: %d %q", rec.Code, rec.Body.String())
+	}
+	if rec.Header().Get("Content-Type") != "text/calendar; charset=utf-8" || rec.Header().Get("Content-Disposition") != "" {
+		t.Fatalf("unexpected headers: %v", rec.Header())
+	}
+	input := calendar.input
+	if input.Token != "secret" || input.Component != "vtodo" || input.List.Status != "new,in_progress" || input.List.PriorityMin == nil || *input.List.PriorityMin != 3 {
+		t.Fatalf("unexpected input: %+v", input)
+	}
+
+	rec = httptest.NewRecorder()
+	newCalendarMux(&mockCalendarService{err: task.ErrCalendarFeedNotFound}).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/calendar.ics?token=guess", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d, got %d", http.StatusNotFound, rec.Code)
+	}
+}
+
+func TestHandlerCalendarFeeds(t *testing.T) {
+	calendar := &mockCalendarService{}
+	mux := newCalendarMux(calendar)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/calendar-feeds", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status %d without a user, got %d", http.StatusUnauthorized, rec.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/calendar-feeds", bytes.NewBufferString(`{"project_id": 2}`))
+	req.Header.Set(userIDHeader, testUserID)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusCreated || !strings.Contains(rec.Body.String(), `"url":"/calendar.ics?token=secret"`) {
+		t.Fatalf("unexpected response: %d %s", rec.Code, rec.Body.String())
+	}
+	if calendar.create.OwnerID != testUserID || calendar.create.ProjectID == nil || *calendar.create.ProjectID != 2 {
+		t.Fatalf("unexpected input: %+v", calendar.create)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/calendar-feeds", nil)
+	req.Header.Set(userIDHeader, testUserID)
+	mux.ServeHTTP(httptest.NewRecorder(), req)
+	if calendar.create.ProjectID != nil {
+		t.Fatalf("expected a user feed without a body, got %+v", calendar.create)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/calendar-feeds", nil)
+	req.Header.Set(userIDHeader, testUserID)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || calendar.ownerID != testUserID {
+		t.Fatalf("unexpected response: %d %s", rec.Code, rec.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodDelete, "/calendar-feeds/4", nil)
+	req.Header.Set(userIDHeader, testUserID)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNoContent || calendar.deletedID != 4 || calendar.ownerID != testUserID {
+		t.Fatalf("unexpected response: %d (deleted %d)", rec.Code, calendar.deletedID)
+	}
+}
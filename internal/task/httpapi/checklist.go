@@ -0,0 +1,145 @@
+package httpapi
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/task"
+)
+
+type ChecklistHandler struct {
+	service task.ChecklistService
+}
+
+type addChecklistItemRequest struct {
+	Text     string `json:"text"`
+	Position *int   `json:"position"`
+}
+
+type updateChecklistItemRequest struct {
+	Text     *string `json:"text"`
+	Checked  *bool   `json:"checked"`
+	Position *int    `json:"position"`
+}
+
+func NewChecklistHandler(service task.ChecklistService) *ChecklistHandler {
+	return &ChecklistHandler{service: service}
+}
+
+func (h *ChecklistHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /tasks/{id}/checklist", h.getChecklist)
+	mux.HandleFunc("POST /tasks/{id}/checklist", h.addItem)
+	mux.HandleFunc("PATCH /tasks/{id}/checklist/{item_id}", h.updateItem)
+	mux.HandleFunc("POST /tasks/{id}/checklist/{item_id}/check", h.checkItem(true))
+	mux.HandleFunc("POST /tasks/{id}/checklist/{item_id}/uncheck", h.checkItem(false))
+	mux.HandleFunc("DELETE /tasks/{id}/checklist/{item_id}", h.deleteItem)
+}
+
+func (h *ChecklistHandler) getChecklist(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+
+	checklist, err := h.service.Get(r.Context(), id)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, checklist)
+}
+
+func (h *ChecklistHandler) addItem(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+
+	var request addChecklistItemRequest
+	if err := decodeJSON(w, r, &request); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	checklist, err := h.service.AddItem(r.Context(), id, task.AddChecklistItemInput{
+		Text:     request.Text,
+		Position: request.Position,
+	})
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, checklist)
+}
+
+func (h *ChecklistHandler) updateItem(w http.ResponseWriter, r *http.Request) {
+	id, itemID, ok := parseChecklistItemID(w, r)
+	if !ok {
+		return
+	}
+
+	var request updateChecklistItemRequest
+	if err := decodeJSON(w, r, &request); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	checklist, err := h.service.UpdateItem(r.Context(), id, itemID, task.UpdateChecklistItemInput{
+		Text:     request.Text,
+		Checked:  request.Checked,
+		Position: request.Position,
+	})
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, checklist)
+}
+
+func (h *ChecklistHandler) checkItem(checked bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, itemID, ok := parseChecklistItemID(w, r)
+		if !ok {
+			return
+		}
+
+		checklist, err := h.service.UpdateItem(r.Context(), id, itemID, task.UpdateChecklistItemInput{Checked: &checked})
+		if err != nil {
+			writeDomainError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, checklist)
+	}
+}
+
+func (h *ChecklistHandler) deleteItem(w http.ResponseWriter, r *http.Request) {
+	id, itemID, ok := parseChecklistItemID(w, r)
+	if !ok {
+		return
+	}
+
+	checklist, err := h.service.DeleteItem(r.Context(), id, itemID)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, checklist)
+}
+
+func parseChecklistItemID(w http.ResponseWriter, r *http.Request) (uint64, uint64, bool) {
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return 0, 0, false
+	}
+	itemID, err := strconv.ParseUint(r.PathValue("item_id"), 10, 64)
+	if err != nil || itemID == 0 {
+		writeError(w, http.StatusBadRequest, errorResponse{Error: "item_id must be a positive integer", Field: "item_id"})
+		return 0, 0, false
+	}
+	return id, itemID, true
+}
@@ -0,0 +1,107 @@
+package httpapi
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/task"
+)
+
+type mockChecklistService struct {
+	taskID      uint64
+	itemID      uint64
+	addInput    task.AddChecklistItemInput
+	updateInput task.UpdateChecklistItemInput
+	err         error
+}
+
+func (m *mockChecklistService) Get(_ context.Context, taskID uint64) (task.Checklist, error) {
+	m.taskID = taskID
+	return task.NewChecklist(nil), m.err
+}
+
+func (m *mockChecklistService) AddItem(_ context.Context, taskID uint64, input task.AddChecklistItemInput) (task.Checklist, error) {
+	m.taskID = taskID
+	m.addInput = input
+	return task.NewChecklist(nil), m.err
+}
+
+func (m *mockChecklistService) UpdateItem(_ context.Context, taskID, itemID uint64, input task.UpdateChecklistItemInput) (task.Checklist, error) {
+	m.taskID = taskID
+	m.itemID = itemID
+	m.updateInput = input
+	return task.NewChecklist(nil), m.err
+}
+
+func (m *mockChecklistService) DeleteItem(_ context.Context, taskID, itemID uint64) (task.Checklist, error) {
+	m.taskID = taskID
+	m.itemID = itemID
+	return task.NewChecklist(nil), m.err
+}
+
+func TestChecklistHandlerAddItem(t *testing.T) {
+	svc := &mockChecklistService{}
+	mux := http.NewServeMux()
+	NewChecklistHandler(svc).Register(mux)
+
+	req := httptest.NewRequest(http.MethodPost, "/tasks/3/checklist", bytes.NewBufferString(`{"text":"write tests","position":0}`))
+	rec := httptest.NewRecorder()
+
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, rec.Code)
+	}
+	if svc.taskID != 3 || svc.addInput.Text != "write tests" || svc.addInput.Position == nil || *svc.addInput.Position != 0 {
+		t.Fatalf("unexpected add call: task=%d input=%+v", svc.taskID, svc.addInput)
+	}
+}
+
+func TestChecklistHandlerCheckItem(t *testing.T) {
+	svc := &mockChecklistService{}
+	mux := http.NewServeMux()
+	NewChecklistHandler(svc).Register(mux)
+
+	req := httptest.NewRequest(http.MethodPost, "/tasks/3/checklist/8/uncheck", nil)
+	rec := httptest.NewRecorder()
+
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	if svc.itemID != 8 || svc.updateInput.Checked == nil || *svc.updateInput.Checked {
+		t.Fatalf("unexpected update call: item=%d input=%+v", svc.itemID, svc.updateInput)
+	}
+}
+
+func TestChecklistHandlerErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		target string
+		status int
+	}{
+		{name: "item not found", err: task.ErrChecklistItemNotFound, target: "/tasks/3/checklist/8", status: http.StatusNotFound},
+		{name: "invalid item id", target: "/tasks/3/checklist/abc", status: http.StatusBadRequest},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			NewChecklistHandler(&mockChecklistService{err: tc.err}).Register(mux)
+
+			req := httptest.NewRequest(http.MethodDelete, tc.target, nil)
+			rec := httptest.NewRecorder()
+
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tc.status {
+				t.Fatalf("expected status %d, got %d", tc.status, rec.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/task"
+)
+
+type CommentHandler struct {
+	service task.CommentService
+}
+
+type addCommentRequest struct {
+	Body string `json:"body"`
+}
+
+func NewCommentHandler(service task.CommentService) *CommentHandler {
+	return &CommentHandler{service: service}
+}
+
+func (h *CommentHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /tasks/{id}/comments", h.listComments)
+	mux.HandleFunc("POST /tasks/{id}/comments", h.addComment)
+}
+
+func (h *CommentHandler) listComments(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+
+	comments, err := h.service.List(r.Context(), id)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, comments)
+}
+
+func (h *CommentHandler) addComment(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+	userID, ok := requireUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var request addCommentRequest
+	if err := decodeJSON(w, r, &request); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	comment, err := h.service.Add(r.Context(), id, task.AddCommentInput{AuthorID: userID, Body: request.Body})
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, comment)
+}
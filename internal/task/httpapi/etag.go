@@ -0,0 +1,55 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+var errMultipleEntityTags = errors.New("If-Match must contain a single entity tag or *")
+
+// taskETag is the strong entity tag of a task version.
+func taskETag(version uint64) string {
+	return `"` + strconv.FormatUint(version, 10) + `"`
+}
+
+// parseIfMatch returns the task version required by the If-Match header, or
+// nil if there is no header or it is "*". A weak or foreign tag is returned
+// as version 0, which never matches.
+func parseIfMatch(r *http.Request) (*uint64, error) {
+	raw := strings.TrimSpace(r.Header.Get("If-Match"))
+	if raw == "" || raw == "*" {
+		return nil, nil
+	}
+	if strings.Contains(raw, ",") {
+		return nil, errMultipleEntityTags
+	}
+
+	var version uint64
+	if unquoted, ok := strings.CutPrefix(raw, `"`); ok {
+		if parsed, err := strconv.ParseUint(strings.TrimSuffix(unquoted, `"`), 10, 64); err == nil && strings.HasSuffix(unquoted, `"`) {
+			version = parsed
+		}
+	}
+	return &version, nil
+}
+
+// noneMatch reports whether the If-None-Match header lists the entity tag,
+// using the weak comparison.
+func noneMatch(r *http.Request, etag string) bool {
+	raw := strings.TrimSpace(r.Header.Get("If-None-Match"))
+	if raw == "" {
+		return false
+	}
+	if raw == "*" {
+		return true
+	}
+
+	for _, candidate := range strings.Split(raw, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
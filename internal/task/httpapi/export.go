@@ -0,0 +1,106 @@
+package httpapi
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/task"
+)
+
+// WithExport enables the task export endpoints.
+func WithExport(service task.ExportService) Option {
+	return func(h *Handler) {
+		h.export = service
+	}
+}
+
+// streamedResponse sends its headers with the first bytes of the body, so an
+// error found before anything was written can still be reported. A filename
+// makes the body an attachment.
+type streamedResponse struct {
+	w           http.ResponseWriter
+	contentType string
+	filename    string
+	started     bool
+}
+
+func (s *streamedResponse) Write(p []byte) (int, error) {
+	if !s.started {
+		s.started = true
+		s.w.Header().Set("Content-Type", s.contentType)
+		if s.filename != "" {
+			s.w.Header().Set("Content-Disposition", `attachment; filename="`+s.filename+`"`)
+		}
+		s.w.WriteHeader(http.StatusOK)
+	}
+	return s.w.Write(p)
+}
+
+// finish completes the response after the body was streamed with the given
+// outcome.
+func (s *streamedResponse) finish(err error) {
+	if err == nil {
+		if !s.started {
+			_, _ = s.Write(nil)
+		}
+		return
+	}
+	if !s.started {
+		writeDomainError(s.w, err)
+		return
+	}
+	// Aborting the response tells the client the body is incomplete.
+	panic(http.ErrAbortHandler)
+}
+
+func (h *Handler) exportTasks(w http.ResponseWriter, r *http.Request) {
+	list, ok := h.parseListInput(w, r)
+	if !ok {
+		return
+	}
+
+	format, err := task.ParseExportFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	// Large exports take longer than the server's write timeout.
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	response := &streamedResponse{w: w, contentType: format.ContentType(), filename: "tasks." + string(format)}
+	_, err = h.export.Export(r.Context(), task.ExportInput{Format: string(format), List: list}, response)
+	response.finish(err)
+}
+
+func (h *Handler) submitExport(w http.ResponseWriter, r *http.Request) {
+	list, ok := h.parseListInput(w, r)
+	if !ok {
+		return
+	}
+
+	queued, err := h.export.Submit(r.Context(), task.ExportInput{Format: r.URL.Query().Get("format"), List: list})
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	w.Header().Set("Location", "/jobs/"+queued.ID)
+	writeJSON(w, http.StatusAccepted, queued)
+}
+
+func (h *Handler) downloadExport(w http.ResponseWriter, r *http.Request) {
+	file, err := h.export.Download(r.Context(), r.PathValue("file"))
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	defer file.Content.Close()
+
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+	w.Header().Set("Content-Type", file.Format.ContentType())
+	w.Header().Set("Content-Disposition", `attachment; filename="`+file.Name+`"`)
+	w.WriteHeader(http.StatusOK)
+	_, _ = io.Copy(w, file.Content)
+}
@@ -0,0 +1,131 @@
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/job"
+	"github.com/PavelFesenkoFirst/task_tracker/internal/task"
+)
+
+type mockExportService struct {
+	input    task.ExportInput
+	output   string
+	err      error
+	download task.ExportFile
+}
+
+func (m *mockExportService) Export(_ context.Context, input task.ExportInput, w io.Writer) (int, error) {
+	m.input = input
+	if m.output != "" {
+		if _, err := io.WriteString(w, m.output); err != nil {
+			return 0, err
+		}
+	}
+	return 1, m.err
+}
+
+func (m *mockExportService) Submit(_ context.Context, input task.ExportInput) (job.Job, error) {
+	m.input = input
+	return job.Job{ID: "9d2e", Type: task.ExportJobType, Status: job.StatusQueued}, m.err
+}
+
+func (m *mockExportService) Run(context.Context, task.ExportJob) (task.ExportResult, error) {
+	return task.ExportResult{}, nil
+}
+
+func (m *mockExportService) Download(context.Context, string) (task.ExportFile, error) {
+	return m.download, m.err
+}
+
+func (m *mockExportService) PurgeFiles(context.Context, time.Time) (int, error) {
+	return 0, nil
+}
+
+func newExportMux(export *mockExportService) *http.ServeMux {
+	mux := http.NewServeMux()
+	NewHandler(&mockService{}, WithExport(export)).Register(mux)
+	return mux
+}
+
+func TestHandlerExportTasks(t *testing.T) {
+	export := &mockExportService{output: "id,title\n1,Ship\n"}
+	rec := httptest.NewRecorder()
+
+	newExportMux(export).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tasks/export?format=csv&status=new&priority_min=3&sort=-priority", nil))
+
+	if rec.Code != http.StatusOK || rec.Body.String() != export.output {
+		t.Fatalf("unexpected response: %d %q", rec.Code, rec.Body.String())
+	}
+	if rec.Header().Get("Content-Type") != "text/csv; charset=utf-8" || rec.Header().Get("Content-Disposition") != `attachment; filename="tasks.csv"` {
+		t.Fatalf("unexpected headers: %v", rec.Header())
+	}
+	list := export.input.List
+	if list.Status != "new" || list.PriorityMin == nil || *list.PriorityMin != 3 || list.Sort != "-priority" {
+		t.Fatalf("expected the list filters, got %+v", list)
+	}
+}
+
+func TestHandlerExportTasks_Errors(t *testing.T) {
+	rec := httptest.NewRecorder()
+	newExportMux(&mockExportService{}).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tasks/export?format=xlsx", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d for an unknown format, got %d", http.StatusBadRequest, rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	export := &mockExportService{err: task.ValidationError{Field: "filter", Message: "unexpected end"}}
+	newExportMux(export).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tasks/export?format=json&filter=status", nil))
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), `"field":"filter"`) {
+		t.Fatalf("expected the validation error, got %d %s", rec.Code, rec.Body.String())
+	}
+
+	export = &mockExportService{output: "[\n{\"id\":1}", err: errors.New("connection lost")}
+	defer func() {
+		if recovered := recover(); recovered != http.ErrAbortHandler {
+			t.Fatalf("expected the response to be aborted, got %v", recovered)
+		}
+	}()
+	newExportMux(export).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/tasks/export?format=json", nil))
+}
+
+func TestHandlerSubmitExport(t *testing.T) {
+	export := &mockExportService{}
+	rec := httptest.NewRecorder()
+
+	newExportMux(export).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/tasks/export?format=ndjson&q=deploy", nil))
+
+	if rec.Code != http.StatusAccepted || rec.Header().Get("Location") != "/jobs/9d2e" {
+		t.Fatalf("unexpected response: %d %q", rec.Code, rec.Header().Get("Location"))
+	}
+	if export.input.Format != "ndjson" || export.input.List.Query != "deploy" {
+		t.Fatalf("unexpected input: %+v", export.input)
+	}
+}
+
+func TestHandlerDownloadExport(t *testing.T) {
+	export := &mockExportService{download: task.ExportFile{
+		Name:    "tasks.json",
+		Format:  task.ExportJSON,
+		Content: io.NopCloser(strings.NewReader("[]\n")),
+	}}
+	rec := httptest.NewRecorder()
+
+	newExportMux(export).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/exports/3b241101-e2bb-4255-8caf-4136c566a962.json", nil))
+
+	if rec.Code != http.StatusOK || rec.Body.String() != "[]\n" || rec.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("unexpected response: %d %q %v", rec.Code, rec.Body.String(), rec.Header())
+	}
+
+	rec = httptest.NewRecorder()
+	newExportMux(&mockExportService{err: task.ErrExportNotFound}).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/exports/missing.csv", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d, got %d", http.StatusNotFound, rec.Code)
+	}
+}
@@ -1,49 +1,118 @@
 package httpapi
 
 import (
+	"context"
+	"crypto/subtle"
 	"encoding/json"
 	"errors"
 	"io"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/PavelFesenkoFirst/task_tracker/internal/job"
 	"github.com/PavelFesenkoFirst/task_tracker/internal/task"
 )
 
 type Handler struct {
-	service task.Service
+	service     task.Service
+	adminToken  string
+	idempotency task.IdempotencyService
+	export      task.ExportService
+	calendar    task.CalendarService
+	templates   *TemplateHandler
 }
 
-const maxRequestBodyBytes int64 = 1 << 20
+type Option func(*Handler)
+
+// WithAdminToken enables admin-only features for requests that carry the
+// given token in the X-Admin-Token header. Admin features are disabled when
+// the token is empty.
+func WithAdminToken(token string) Option {
+	return func(h *Handler) {
+		h.adminToken = token
+	}
+}
+
+const (
+	maxRequestBodyBytes    int64 = 1 << 20
+	adminTokenHeader             = "X-Admin-Token"
+	customFieldQueryPrefix       = "cf."
+)
 
 type createTaskRequest struct {
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	Status      string    `json:"status"`
-	Priority    int       `json:"priority"`
-	DueAt       *taskTime `json:"due_at"`
+	ProjectID       *uint64        `json:"project_id"`
+	SprintID        *uint64        `json:"sprint_id"`
+	MilestoneID     *uint64        `json:"milestone_id"`
+	AssigneeID      *string        `json:"assignee_id"`
+	Title           string         `json:"title"`
+	Description     string         `json:"description"`
+	Status          string         `json:"status"`
+	Priority        int            `json:"priority"`
+	EstimateMinutes *int           `json:"estimate_minutes"`
+	StoryPoints     *int           `json:"story_points"`
+	Labels          []string       `json:"labels"`
+	CustomFields    map[string]any `json:"custom_fields"`
+	DueAt           *taskTime      `json:"due_at"`
+	RecurrenceRule  string         `json:"recurrence_rule"`
 }
 
 type updateTaskRequest struct {
-	Title       *string   `json:"title"`
-	Description *string   `json:"description"`
-	Status      *string   `json:"status"`
-	Priority    *int      `json:"priority"`
-	DueAt       *taskTime `json:"due_at"`
-	ClearDueAt  bool      `json:"clear_due_at"`
+	ProjectID            *uint64        `json:"project_id"`
+	ClearProjectID       bool           `json:"clear_project_id"`
+	SprintID             *uint64        `json:"sprint_id"`
+	ClearSprintID        bool           `json:"clear_sprint_id"`
+	MilestoneID          *uint64        `json:"milestone_id"`
+	ClearMilestoneID     bool           `json:"clear_milestone_id"`
+	AssigneeID           *string        `json:"assignee_id"`
+	ClearAssigneeID      bool           `json:"clear_assignee_id"`
+	Title                *string        `json:"title"`
+	Description          *string        `json:"description"`
+	Status               *string        `json:"status"`
+	Priority             *int           `json:"priority"`
+	EstimateMinutes      *int           `json:"estimate_minutes"`
+	ClearEstimateMinutes bool           `json:"clear_estimate_minutes"`
+	StoryPoints          *int           `json:"story_points"`
+	ClearStoryPoints     bool           `json:"clear_story_points"`
+	Labels               *[]string      `json:"labels"`
+	CustomFields         map[string]any `json:"custom_fields"`
+	DueAt                *taskTime      `json:"due_at"`
+	ClearDueAt           bool           `json:"clear_due_at"`
+	RecurrenceRule       *string        `json:"recurrence_rule"`
+	ClearRecurrenceRule  bool           `json:"clear_recurrence_rule"`
+	Force                bool           `json:"force"`
+}
+
+type moveTaskRequest struct {
+	BeforeID *uint64 `json:"before_id"`
+	AfterID  *uint64 `json:"after_id"`
+}
+
+// taskPageResponse is the envelope of GET /tasks; NextCursor is null on the
+// last page and Total is only set when requested.
+type taskPageResponse struct {
+	Items      []task.Task `json:"items"`
+	NextCursor *string     `json:"next_cursor"`
+	NextOffset *int        `json:"next_offset,omitempty"`
+	Total      *int64      `json:"total,omitempty"`
 }
 
 type errorResponse struct {
-	Error string `json:"error"`
-	Field string `json:"field,omitempty"`
+	Error    string `json:"error"`
+	Field    string `json:"field,omitempty"`
+	Position int    `json:"position,omitempty"`
 }
 
 type taskTime struct {
 	value string
 }
 
+func (t taskTime) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.value)
+}
+
 func (t *taskTime) UnmarshalJSON(data []byte) error {
 	var raw string
 	if err := json.Unmarshal(data, &raw); err != nil {
@@ -53,8 +122,12 @@ func (t *taskTime) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
-func NewHandler(service task.Service) *Handler {
-	return &Handler{service: service}
+func NewHandler(service task.Service, opts ...Option) *Handler {
+	h := &Handler{service: service}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
 func (h *Handler) Register(mux *http.ServeMux) {
@@ -63,6 +136,24 @@ func (h *Handler) Register(mux *http.ServeMux) {
 	mux.HandleFunc("GET /tasks/{id}", h.getTask)
 	mux.HandleFunc("PATCH /tasks/{id}", h.updateTask)
 	mux.HandleFunc("DELETE /tasks/{id}", h.deleteTask)
+	mux.HandleFunc("POST /tasks/{id}/restore", h.restoreTask)
+	mux.HandleFunc("POST /tasks/{id}/archive", h.archiveTask)
+	mux.HandleFunc("POST /tasks/{id}/unarchive", h.unarchiveTask)
+	mux.HandleFunc("POST /tasks/{id}/move", h.moveTask)
+	if h.templates != nil {
+		mux.HandleFunc("POST /tasks/{id}/{action}", h.taskAction)
+	}
+	if h.export != nil {
+		mux.HandleFunc("GET /tasks/export", h.exportTasks)
+		mux.HandleFunc("POST /tasks/export", h.submitExport)
+		mux.HandleFunc("GET /exports/{file}", h.downloadExport)
+	}
+	if h.calendar != nil {
+		mux.HandleFunc("GET /calendar.ics", h.calendarFeed)
+		mux.HandleFunc("POST /calendar-feeds", h.createCalendarFeed)
+		mux.HandleFunc("GET /calendar-feeds", h.listCalendarFeeds)
+		mux.HandleFunc("DELETE /calendar-feeds/{id}", h.deleteCalendarFeed)
+	}
 }
 
 func (h *Handler) createTask(w http.ResponseWriter, r *http.Request) {
@@ -72,19 +163,14 @@ func (h *Handler) createTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	dueAt, err := parseOptionalTime(request.DueAt)
-	if err != nil {
-		writeError(w, http.StatusBadRequest, errorResponse{Error: err.Error(), Field: "due_at"})
+	if h.idempotency != nil && r.Header.Get(idempotencyKeyHeader) != "" {
+		h.serveIdempotently(w, r, request, http.StatusCreated, func(ctx context.Context) (any, error) {
+			return h.createTaskFromRequest(ctx, r, request)
+		})
 		return
 	}
 
-	createdTask, err := h.service.Create(r.Context(), task.CreateTaskInput{
-		Title:       request.Title,
-		Description: request.Description,
-		Status:      request.Status,
-		Priority:    request.Priority,
-		DueAt:       dueAt,
-	})
+	createdTask, err := h.createTaskFromRequest(r.Context(), r, request)
 	if err != nil {
 		writeDomainError(w, err)
 		return
@@ -93,31 +179,240 @@ func (h *Handler) createTask(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusCreated, createdTask)
 }
 
+func (h *Handler) createTaskFromRequest(ctx context.Context, r *http.Request, request createTaskRequest) (task.Task, error) {
+	input, err := request.input(optionalUserID(r))
+	if err != nil {
+		return task.Task{}, err
+	}
+	return h.service.Create(ctx, input)
+}
+
+func (request createTaskRequest) input(reporterID string) (task.CreateTaskInput, error) {
+	dueAt, err := parseOptionalTime(request.DueAt)
+	if err != nil {
+		return task.CreateTaskInput{}, task.ValidationError{Field: "due_at", Message: err.Error()}
+	}
+
+	return task.CreateTaskInput{
+		ProjectID:       request.ProjectID,
+		SprintID:        request.SprintID,
+		MilestoneID:     request.MilestoneID,
+		ReporterID:      reporterID,
+		AssigneeID:      request.AssigneeID,
+		Title:           request.Title,
+		Description:     request.Description,
+		Status:          request.Status,
+		Priority:        request.Priority,
+		EstimateMinutes: request.EstimateMinutes,
+		StoryPoints:     request.StoryPoints,
+		Labels:          request.Labels,
+		CustomFields:    request.CustomFields,
+		DueAt:           dueAt,
+		RecurrenceRule:  request.RecurrenceRule,
+	}, nil
+}
+
 func (h *Handler) listTasks(w http.ResponseWriter, r *http.Request) {
+	input, ok := h.parseListInput(w, r)
+	if !ok {
+		return
+	}
+
+	flags := make(map[string]bool, 2)
+	for _, name := range []string{"envelope", "include_total"} {
+		value, err := parseQueryBool(r.URL.Query().Get(name))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, errorResponse{Error: name + " must be a boolean", Field: name})
+			return
+		}
+		flags[name] = value
+	}
+
+	// The bare array stays the default; the envelope is opted into explicitly
+	// or by using a cursor or asking for the total.
+	if flags["envelope"] || flags["include_total"] || r.URL.Query().Has("cursor") {
+		h.listTaskPage(w, r, input, flags["include_total"])
+		return
+	}
+
+	page, err := h.service.ListPage(r.Context(), input)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	summary, err := h.service.Summarize(r.Context(), input)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	// The bare array is always paged by offset, whichever way the service
+	// would continue the page.
+	links := []string{pageLink(r, "first", nil)}
+	if page.NextCursor != "" || page.NextOffset > 0 {
+		nextOffset := input.Offset + len(page.Items)
+		links = append(links, pageLink(r, "next", map[string]string{"offset": strconv.Itoa(nextOffset)}))
+	}
+
+	writeSummaryHeaders(w, summary)
+	w.Header().Set("Link", strings.Join(links, ", "))
+	writeJSON(w, http.StatusOK, page.Items)
+}
+
+// parseListInput reads the filters, sort and pagination of a task list from
+// the query string, writing the error response if they are malformed.
+func (h *Handler) parseListInput(w http.ResponseWriter, r *http.Request) (task.ListTasksInput, bool) {
 	limit, err := parseQueryInt(r.URL.Query().Get("limit"))
 	if err != nil {
 		writeError(w, http.StatusBadRequest, errorResponse{Error: "limit must be an integer", Field: "limit"})
-		return
+		return task.ListTasksInput{}, false
 	}
 
 	offset, err := parseQueryInt(r.URL.Query().Get("offset"))
 	if err != nil {
 		writeError(w, http.StatusBadRequest, errorResponse{Error: "offset must be an integer", Field: "offset"})
-		return
+		return task.ListTasksInput{}, false
 	}
 
-	tasks, err := h.service.List(r.Context(), task.ListTasksInput{
-		Status: r.URL.Query().Get("status"),
-		Query:  r.URL.Query().Get("q"),
-		Limit:  limit,
-		Offset: offset,
-	})
+	includeDeleted, err := parseQueryBool(r.URL.Query().Get("include_deleted"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errorResponse{Error: "include_deleted must be a boolean", Field: "include_deleted"})
+		return task.ListTasksInput{}, false
+	}
+	if includeDeleted && !h.isAdmin(r) {
+		writeError(w, http.StatusForbidden, errorResponse{Error: "include_deleted requires admin privileges", Field: "include_deleted"})
+		return task.ListTasksInput{}, false
+	}
+
+	rangeValues := make(map[string]*int, 6)
+	for _, name := range []string{"priority_min", "priority_max", "estimate_minutes_min", "estimate_minutes_max", "story_points_min", "story_points_max"} {
+		value, err := parseQueryOptionalInt(r.URL.Query().Get(name))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, errorResponse{Error: name + " must be an integer", Field: name})
+			return task.ListTasksInput{}, false
+		}
+		rangeValues[name] = value
+	}
+
+	timeValues := make(map[string]*time.Time, 4)
+	for _, name := range []string{"due_before", "due_after", "created_after", "updated_after"} {
+		value, err := parseQueryTime(name, r.URL.Query().Get(name))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, errorResponse{Error: err.Error(), Field: name})
+			return task.ListTasksInput{}, false
+		}
+		timeValues[name] = value
+	}
+
+	optionalFlags := make(map[string]*bool, 2)
+	for _, name := range []string{"has_due_date", "overdue"} {
+		value, err := parseQueryOptionalBool(r.URL.Query().Get(name))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, errorResponse{Error: name + " must be a boolean", Field: name})
+			return task.ListTasksInput{}, false
+		}
+		optionalFlags[name] = value
+	}
+
+	ids := make(map[string]*uint64, 3)
+	for _, name := range []string{"project_id", "sprint_id", "milestone_id"} {
+		raw := r.URL.Query().Get(name)
+		if strings.TrimSpace(raw) == "" {
+			continue
+		}
+		parsed, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, errorResponse{Error: name + " must be a positive integer", Field: name})
+			return task.ListTasksInput{}, false
+		}
+		ids[name] = &parsed
+	}
+
+	return task.ListTasksInput{
+		ProjectID:          ids["project_id"],
+		SprintID:           ids["sprint_id"],
+		MilestoneID:        ids["milestone_id"],
+		Status:             r.URL.Query().Get("status"),
+		Query:              r.URL.Query().Get("q"),
+		SearchMode:         r.URL.Query().Get("search_mode"),
+		Archived:           r.URL.Query().Get("archived"),
+		IncludeDeleted:     includeDeleted,
+		PriorityMin:        rangeValues["priority_min"],
+		PriorityMax:        rangeValues["priority_max"],
+		EstimateMinutesMin: rangeValues["estimate_minutes_min"],
+		EstimateMinutesMax: rangeValues["estimate_minutes_max"],
+		StoryPointsMin:     rangeValues["story_points_min"],
+		StoryPointsMax:     rangeValues["story_points_max"],
+		DueBefore:          timeValues["due_before"],
+		DueAfter:           timeValues["due_after"],
+		HasDueDate:         optionalFlags["has_due_date"],
+		Overdue:            optionalFlags["overdue"],
+		CreatedAfter:       timeValues["created_after"],
+		UpdatedAfter:       timeValues["updated_after"],
+		CustomFields:       customFieldFilters(r.URL.Query()),
+		Filter:             r.URL.Query().Get("filter"),
+		Sort:               r.URL.Query().Get("sort"),
+		Cursor:             r.URL.Query().Get("cursor"),
+		Limit:              limit,
+		Offset:             offset,
+	}, true
+}
+
+func (h *Handler) listTaskPage(w http.ResponseWriter, r *http.Request, input task.ListTasksInput, includeTotal bool) {
+	page, err := h.service.ListPage(r.Context(), input)
 	if err != nil {
 		writeDomainError(w, err)
 		return
 	}
 
-	writeJSON(w, http.StatusOK, tasks)
+	// The total and the sums need an aggregate over the whole filtered set,
+	// so the envelope only runs it when the total is asked for.
+	response := taskPageResponse{Items: page.Items}
+	if includeTotal {
+		summary, err := h.service.Summarize(r.Context(), input)
+		if err != nil {
+			writeDomainError(w, err)
+			return
+		}
+		response.Total = &summary.Count
+		writeSummaryHeaders(w, summary)
+	}
+
+	links := []string{pageLink(r, "first", map[string]string{"envelope": "true"})}
+	switch {
+	case page.NextCursor != "":
+		response.NextCursor = &page.NextCursor
+		links = append(links, pageLink(r, "next", map[string]string{"cursor": page.NextCursor}))
+	case page.NextOffset > 0:
+		response.NextOffset = &page.NextOffset
+		links = append(links, pageLink(r, "next", map[string]string{
+			"envelope": "true",
+			"offset":   strconv.Itoa(page.NextOffset),
+		}))
+	}
+
+	w.Header().Set("Link", strings.Join(links, ", "))
+	writeJSON(w, http.StatusOK, response)
+}
+
+func writeSummaryHeaders(w http.ResponseWriter, summary task.ListSummary) {
+	w.Header().Set("X-Total-Estimate-Minutes", strconv.FormatInt(summary.EstimateMinutes, 10))
+	w.Header().Set("X-Total-Story-Points", strconv.FormatInt(summary.StoryPoints, 10))
+}
+
+// pageLink formats a Link header entry pointing to the current request with
+// the pagination parameters replaced by the given ones.
+func pageLink(r *http.Request, rel string, params map[string]string) string {
+	query := r.URL.Query()
+	query.Del("cursor")
+	query.Del("offset")
+	for key, value := range params {
+		query.Set(key, value)
+	}
+
+	target := url.URL{Path: r.URL.Path, RawQuery: query.Encode()}
+	return "<" + target.String() + `>; rel="` + rel + `"`
 }
 
 func (h *Handler) getTask(w http.ResponseWriter, r *http.Request) {
@@ -132,6 +427,13 @@ func (h *Handler) getTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	etag := taskETag(foundTask.Version)
+	w.Header().Set("ETag", etag)
+	if noneMatch(r, etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
 	writeJSON(w, http.StatusOK, foundTask)
 }
 
@@ -141,41 +443,82 @@ func (h *Handler) updateTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	expectedVersion, err := parseIfMatch(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+
 	var request updateTaskRequest
 	if err := decodeJSON(w, r, &request); err != nil {
 		writeDecodeError(w, err)
 		return
 	}
 
-	dueAt, err := parseOptionalTime(request.DueAt)
+	input, err := request.input(optionalUserID(r))
 	if err != nil {
-		writeError(w, http.StatusBadRequest, errorResponse{Error: err.Error(), Field: "due_at"})
+		writeDomainError(w, err)
 		return
 	}
+	input.ExpectedVersion = expectedVersion
 
-	updatedTask, err := h.service.Update(r.Context(), id, task.UpdateTaskInput{
-		Title:       request.Title,
-		Description: request.Description,
-		Status:      request.Status,
-		Priority:    request.Priority,
-		DueAt:       dueAt,
-		ClearDueAt:  request.ClearDueAt,
-	})
+	updatedTask, err := h.service.Update(r.Context(), id, input)
 	if err != nil {
 		writeDomainError(w, err)
 		return
 	}
 
+	w.Header().Set("ETag", taskETag(updatedTask.Version))
 	writeJSON(w, http.StatusOK, updatedTask)
 }
 
+func (request updateTaskRequest) input(actorID string) (task.UpdateTaskInput, error) {
+	dueAt, err := parseOptionalTime(request.DueAt)
+	if err != nil {
+		return task.UpdateTaskInput{}, task.ValidationError{Field: "due_at", Message: err.Error()}
+	}
+
+	return task.UpdateTaskInput{
+		ActorID:              actorID,
+		ProjectID:            request.ProjectID,
+		ClearProjectID:       request.ClearProjectID,
+		SprintID:             request.SprintID,
+		ClearSprintID:        request.ClearSprintID,
+		MilestoneID:          request.MilestoneID,
+		ClearMilestoneID:     request.ClearMilestoneID,
+		AssigneeID:           request.AssigneeID,
+		ClearAssigneeID:      request.ClearAssigneeID,
+		Title:                request.Title,
+		Description:          request.Description,
+		Status:               request.Status,
+		Priority:             request.Priority,
+		EstimateMinutes:      request.EstimateMinutes,
+		ClearEstimateMinutes: request.ClearEstimateMinutes,
+		StoryPoints:          request.StoryPoints,
+		ClearStoryPoints:     request.ClearStoryPoints,
+		Labels:               request.Labels,
+		CustomFields:         request.CustomFields,
+		DueAt:                dueAt,
+		ClearDueAt:           request.ClearDueAt,
+		RecurrenceRule:       request.RecurrenceRule,
+		ClearRecurrenceRule:  request.ClearRecurrenceRule,
+		Force:                request.Force,
+	}, nil
+}
+
 func (h *Handler) deleteTask(w http.ResponseWriter, r *http.Request) {
 	id, ok := parseTaskID(w, r)
 	if !ok {
 		return
 	}
 
-	if err := h.service.Delete(r.Context(), id); err != nil {
+	expectedVersion, err := parseIfMatch(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), id, task.DeleteTaskInput{ExpectedVersion: expectedVersion}); err != nil {
 		writeDomainError(w, err)
 		return
 	}
@@ -183,6 +526,97 @@ func (h *Handler) deleteTask(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+func (h *Handler) restoreTask(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+
+	restoredTask, err := h.service.Restore(r.Context(), id)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, restoredTask)
+}
+
+func (h *Handler) archiveTask(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+
+	archivedTask, err := h.service.Archive(r.Context(), id)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, archivedTask)
+}
+
+func (h *Handler) unarchiveTask(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+
+	unarchivedTask, err := h.service.Unarchive(r.Context(), id)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, unarchivedTask)
+}
+
+func (h *Handler) moveTask(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+
+	var request moveTaskRequest
+	if err := decodeJSON(w, r, &request); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	movedTask, err := h.service.Move(r.Context(), id, task.MoveTaskInput{
+		BeforeID: request.BeforeID,
+		AfterID:  request.AfterID,
+	})
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, movedTask)
+}
+
+func (h *Handler) isAdmin(r *http.Request) bool {
+	if h.adminToken == "" {
+		return false
+	}
+	provided := r.Header.Get(adminTokenHeader)
+	return subtle.ConstantTimeCompare([]byte(provided), []byte(h.adminToken)) == 1
+}
+
+// taskAction serves the POST /tasks/{id}/{action} requests no more specific
+// route matches. POST /tasks/from-template/{id} is served here because neither
+// it nor the POST /tasks/{id}/... routes would be more specific than the other
+// on the same mux.
+func (h *Handler) taskAction(w http.ResponseWriter, r *http.Request) {
+	if r.PathValue("id") != "from-template" {
+		http.NotFound(w, r)
+		return
+	}
+
+	r.SetPathValue("id", r.PathValue("action"))
+	h.templates.instantiateTemplate(w, r)
+}
+
 func parseTaskID(w http.ResponseWriter, r *http.Request) (uint64, bool) {
 	rawID := r.PathValue("id")
 	id, err := strconv.ParseUint(rawID, 10, 64)
@@ -193,6 +627,17 @@ func parseTaskID(w http.ResponseWriter, r *http.Request) (uint64, bool) {
 	return id, true
 }
 
+// customFieldFilters collects cf.<name> query parameters.
+func customFieldFilters(query url.Values) map[string][]string {
+	filters := make(map[string][]string)
+	for key, values := range query {
+		if name, ok := strings.CutPrefix(key, customFieldQueryPrefix); ok {
+			filters[name] = values
+		}
+	}
+	return filters
+}
+
 func parseQueryInt(raw string) (int, error) {
 	if strings.TrimSpace(raw) == "" {
 		return 0, nil
@@ -200,19 +645,59 @@ func parseQueryInt(raw string) (int, error) {
 	return strconv.Atoi(raw)
 }
 
+func parseQueryOptionalInt(raw string) (*int, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
+
+func parseQueryBool(raw string) (bool, error) {
+	if strings.TrimSpace(raw) == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(raw)
+}
+
+func parseQueryOptionalBool(raw string) (*bool, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
+
 func parseOptionalTime(value *taskTime) (*time.Time, error) {
+	return parseOptionalFieldTime("due_at", value)
+}
+
+func parseOptionalFieldTime(field string, value *taskTime) (*time.Time, error) {
 	if value == nil {
 		return nil, nil
 	}
 
 	parsed, err := time.Parse(time.RFC3339, value.value)
 	if err != nil {
-		return nil, errors.New("due_at must be a valid RFC3339 timestamp")
+		return nil, errors.New(field + " must be a valid RFC3339 timestamp")
 	}
 
 	return &parsed, nil
 }
 
+func parseQueryTime(field, raw string) (*time.Time, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+	return parseOptionalFieldTime(field, &taskTime{value: raw})
+}
+
 var errRequestBodyTooLarge = errors.New("request body exceeds maximum size")
 
 func decodeJSON(w http.ResponseWriter, r *http.Request, target any) error {
@@ -239,16 +724,53 @@ func decodeJSON(w http.ResponseWriter, r *http.Request, target any) error {
 	return nil
 }
 
+// decodeOptionalJSON is decodeJSON for endpoints whose body may be omitted.
+func decodeOptionalJSON(w http.ResponseWriter, r *http.Request, target any) error {
+	if err := decodeJSON(w, r, target); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
+
 func writeDomainError(w http.ResponseWriter, err error) {
 	var validationErr task.ValidationError
 	switch {
 	case errors.As(err, &validationErr):
 		writeError(w, http.StatusBadRequest, errorResponse{
-			Error: validationErr.Message,
-			Field: validationErr.Field,
+			Error:    validationErr.Message,
+			Field:    validationErr.Field,
+			Position: validationErr.Position,
 		})
-	case errors.Is(err, task.ErrTaskNotFound):
-		writeError(w, http.StatusNotFound, errorResponse{Error: task.ErrTaskNotFound.Error()})
+	case errors.Is(err, task.ErrTaskNotFound),
+		errors.Is(err, task.ErrTimeEntryNotFound),
+		errors.Is(err, task.ErrProjectNotFound),
+		errors.Is(err, task.ErrCustomFieldNotFound),
+		errors.Is(err, task.ErrChecklistItemNotFound),
+		errors.Is(err, task.ErrTemplateNotFound),
+		errors.Is(err, task.ErrSprintNotFound),
+		errors.Is(err, task.ErrMilestoneNotFound),
+		errors.Is(err, job.ErrJobNotFound),
+		errors.Is(err, task.ErrExportNotFound),
+		errors.Is(err, task.ErrCalendarFeedNotFound):
+		writeError(w, http.StatusNotFound, errorResponse{Error: err.Error()})
+	case errors.Is(err, task.ErrTimerAlreadyRunning),
+		errors.Is(err, task.ErrTimerNotRunning),
+		errors.Is(err, task.ErrProjectExists),
+		errors.Is(err, task.ErrCustomFieldExists),
+		errors.Is(err, task.ErrChecklistFull),
+		errors.Is(err, task.ErrWIPLimitExceeded),
+		errors.Is(err, task.ErrSprintExists),
+		errors.Is(err, task.ErrMilestoneExists),
+		errors.Is(err, task.ErrActiveSprintExists),
+		errors.Is(err, task.ErrInvalidSprintTransition),
+		errors.Is(err, task.ErrTemplateExists),
+		errors.Is(err, task.ErrIdempotencyKeyInProgress),
+		errors.Is(err, task.ErrIdempotencyKeyLost):
+		writeError(w, http.StatusConflict, errorResponse{Error: err.Error()})
+	case errors.Is(err, task.ErrVersionConflict):
+		writeError(w, http.StatusPreconditionFailed, errorResponse{Error: err.Error()})
+	case errors.Is(err, task.ErrIdempotencyKeyReused):
+		writeError(w, http.StatusUnprocessableEntity, errorResponse{Error: err.Error()})
 	default:
 		writeError(w, http.StatusInternalServerError, errorResponse{Error: "internal server error"})
 	}
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 	"time"
@@ -21,6 +22,7 @@ type mockService struct {
 	getID       uint64
 	updateID    uint64
 	deleteID    uint64
+	deleteInput task.DeleteTaskInput
 
 	createCalled bool
 	updateCalled bool
@@ -38,6 +40,24 @@ type mockService struct {
 	listErr   error
 	getErr    error
 	deleteErr error
+
+	restoreID     uint64
+	restoreCalled bool
+	restoreResult task.Task
+	restoreErr    error
+
+	archiveID       uint64
+	archiveCalled   bool
+	unarchiveCalled bool
+
+	summaryResult   task.ListSummary
+	summarizeCalled bool
+
+	moveID    uint64
+	moveInput task.MoveTaskInput
+
+	nextCursor string
+	nextOffset int
 }
 
 func (m *mockService) Create(_ context.Context, input task.CreateTaskInput) (task.Task, error) {
@@ -49,6 +69,11 @@ func (m *mockService) Create(_ context.Context, input task.CreateTaskInput) (tas
 	return m.createResult, nil
 }
 
+func (m *mockService) ValidateCreate(_ context.Context, input task.CreateTaskInput) error {
+	m.createInput = input
+	return m.createErr
+}
+
 func (m *mockService) GetByID(_ context.Context, id uint64) (task.Task, error) {
 	m.getCalled = true
 	m.getID = id
@@ -67,6 +92,20 @@ func (m *mockService) List(_ context.Context, input task.ListTasksInput) ([]task
 	return m.listResult, nil
 }
 
+func (m *mockService) ListPage(_ context.Context, input task.ListTasksInput) (task.TaskPage, error) {
+	m.listCalled = true
+	m.listInput = input
+	if m.listErr != nil {
+		return task.TaskPage{}, m.listErr
+	}
+	return task.TaskPage{Items: m.listResult, NextCursor: m.nextCursor, NextOffset: m.nextOffset}, nil
+}
+
+func (m *mockService) Summarize(_ context.Context, _ task.ListTasksInput) (task.ListSummary, error) {
+	m.summarizeCalled = true
+	return m.summaryResult, nil
+}
+
 func (m *mockService) Update(_ context.Context, id uint64, input task.UpdateTaskInput) (task.Task, error) {
 	m.updateCalled = true
 	m.updateID = id
@@ -77,12 +116,39 @@ func (m *mockService) Update(_ context.Context, id uint64, input task.UpdateTask
 	return m.updateResult, nil
 }
 
-func (m *mockService) Delete(_ context.Context, id uint64) error {
+func (m *mockService) Delete(_ context.Context, id uint64, input task.DeleteTaskInput) error {
 	m.deleteCalled = true
 	m.deleteID = id
+	m.deleteInput = input
 	return m.deleteErr
 }
 
+func (m *mockService) Restore(_ context.Context, id uint64) (task.Task, error) {
+	m.restoreCalled = true
+	m.restoreID = id
+	if m.restoreErr != nil {
+		return task.Task{}, m.restoreErr
+	}
+	return m.restoreResult, nil
+}
+
+func (m *mockService) Archive(_ context.Context, id uint64) (task.Task, error) {
+	m.archiveCalled = true
+	m.archiveID = id
+	return task.Task{ID: id}, nil
+}
+
+func (m *mockService) Unarchive(_ context.Context, id uint64) (task.Task, error) {
+	m.unarchiveCalled = true
+	return task.Task{ID: id}, nil
+}
+
+func (m *mockService) Move(_ context.Context, id uint64, input task.MoveTaskInput) (task.Task, error) {
+	m.moveID = id
+	m.moveInput = input
+	return task.Task{ID: id}, nil
+}
+
 func TestHandlerCreateTask(t *testing.T) {
 	svc := &mockService{
 		createResult: task.Task{ID: 1, Title: "Write tests"},
@@ -118,6 +184,28 @@ func TestHandlerCreateTask(t *testing.T) {
 	}
 }
 
+func TestHandlerCreateTask_ReporterFromHeader(t *testing.T) {
+	svc := &mockService{createResult: task.Task{ID: 1}}
+	mux := http.NewServeMux()
+	NewHandler(svc).Register(mux)
+
+	req := httptest.NewRequest(http.MethodPost, "/tasks", bytes.NewBufferString(`{"title":"Review","assignee_id":"`+testUserID+`"}`))
+	req.Header.Set(userIDHeader, testUserID)
+	rec := httptest.NewRecorder()
+
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, rec.Code)
+	}
+	if svc.createInput.ReporterID != testUserID {
+		t.Fatalf("unexpected reporter: %q", svc.createInput.ReporterID)
+	}
+	if svc.createInput.AssigneeID == nil || *svc.createInput.AssigneeID != testUserID {
+		t.Fatalf("unexpected assignee: %v", svc.createInput.AssigneeID)
+	}
+}
+
 func TestHandlerListTasks(t *testing.T) {
 	svc := &mockService{
 		listResult: []task.Task{{ID: 1}},
@@ -125,7 +213,7 @@ func TestHandlerListTasks(t *testing.T) {
 	mux := http.NewServeMux()
 	NewHandler(svc).Register(mux)
 
-	req := httptest.NewRequest(http.MethodGet, "/tasks?status=done&q=report&limit=15&offset=5", nil)
+	req := httptest.NewRequest(http.MethodGet, "/tasks?status=done&q=report&archived=any&limit=15&offset=5", nil)
 	rec := httptest.NewRecorder()
 
 	mux.ServeHTTP(rec, req)
@@ -136,7 +224,7 @@ func TestHandlerListTasks(t *testing.T) {
 	if !svc.listCalled {
 		t.Fatal("expected list to be called")
 	}
-	if svc.listInput.Status != "done" || svc.listInput.Query != "report" {
+	if svc.listInput.Status != "done" || svc.listInput.Query != "report" || svc.listInput.Archived != "any" {
 		t.Fatalf("unexpected list input: %+v", svc.listInput)
 	}
 	if svc.listInput.Limit != 15 || svc.listInput.Offset != 5 {
@@ -243,6 +331,89 @@ func TestHandlerDeleteTask(t *testing.T) {
 	}
 }
 
+func TestHandlerRestoreTask(t *testing.T) {
+	svc := &mockService{restoreResult: task.Task{ID: 9}}
+	mux := http.NewServeMux()
+	NewHandler(svc).Register(mux)
+
+	req := httptest.NewRequest(http.MethodPost, "/tasks/9/restore", nil)
+	rec := httptest.NewRecorder()
+
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	if !svc.restoreCalled || svc.restoreID != 9 {
+		t.Fatalf("unexpected restore call: called=%v id=%d", svc.restoreCalled, svc.restoreID)
+	}
+}
+
+func TestHandlerArchiveTask(t *testing.T) {
+	svc := &mockService{}
+	mux := http.NewServeMux()
+	NewHandler(svc).Register(mux)
+
+	req := httptest.NewRequest(http.MethodPost, "/tasks/4/archive", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	if !svc.archiveCalled || svc.archiveID != 4 {
+		t.Fatalf("unexpected archive call: called=%v id=%d", svc.archiveCalled, svc.archiveID)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/tasks/4/unarchive", nil)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK || !svc.unarchiveCalled {
+		t.Fatalf("unexpected unarchive result: status=%d called=%v", rec.Code, svc.unarchiveCalled)
+	}
+}
+
+func TestHandlerListTasks_IncludeDeletedRequiresAdmin(t *testing.T) {
+	tests := []struct {
+		name       string
+		token      string
+		header     string
+		wantStatus int
+	}{
+		{name: "admin features disabled", token: "", header: "", wantStatus: http.StatusForbidden},
+		{name: "missing token", token: "secret", header: "", wantStatus: http.StatusForbidden},
+		{name: "wrong token", token: "secret", header: "nope", wantStatus: http.StatusForbidden},
+		{name: "admin token", token: "secret", header: "secret", wantStatus: http.StatusOK},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &mockService{}
+			mux := http.NewServeMux()
+			NewHandler(svc, WithAdminToken(tc.token)).Register(mux)
+
+			req := httptest.NewRequest(http.MethodGet, "/tasks?include_deleted=true", nil)
+			if tc.header != "" {
+				req.Header.Set(adminTokenHeader, tc.header)
+			}
+			rec := httptest.NewRecorder()
+
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tc.wantStatus {
+				t.Fatalf("expected status %d, got %d", tc.wantStatus, rec.Code)
+			}
+			if tc.wantStatus == http.StatusOK && !svc.listInput.IncludeDeleted {
+				t.Fatal("expected include_deleted to be passed to the service")
+			}
+			if tc.wantStatus != http.StatusOK && svc.listCalled {
+				t.Fatal("service should not be called without admin privileges")
+			}
+		})
+	}
+}
+
 func TestHandlerErrors(t *testing.T) {
 	mux := http.NewServeMux()
 	NewHandler(&mockService{}).Register(mux)
@@ -342,3 +513,415 @@ func TestParseOptionalTime(t *testing.T) {
 		t.Fatalf("unexpected time: %v", *got)
 	}
 }
+
+func TestHandlerListTasks_EstimateFiltersAndSums(t *testing.T) {
+	svc := &mockService{
+		listResult:    []task.Task{{ID: 1}},
+		summaryResult: task.ListSummary{Count: 1, EstimateMinutes: 120, StoryPoints: 5},
+	}
+	mux := http.NewServeMux()
+	NewHandler(svc).Register(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/tasks?story_points_min=3&estimate_minutes_max=240&sort=-story_points", nil)
+	rec := httptest.NewRecorder()
+
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	if svc.listInput.StoryPointsMin == nil || *svc.listInput.StoryPointsMin != 3 {
+		t.Fatalf("unexpected story_points_min: %v", svc.listInput.StoryPointsMin)
+	}
+	if svc.listInput.EstimateMinutesMax == nil || *svc.listInput.EstimateMinutesMax != 240 {
+		t.Fatalf("unexpected estimate_minutes_max: %v", svc.listInput.EstimateMinutesMax)
+	}
+	if svc.listInput.Sort != "-story_points" {
+		t.Fatalf("unexpected sort: %q", svc.listInput.Sort)
+	}
+	if got := rec.Header().Get("X-Total-Estimate-Minutes"); got != "120" {
+		t.Fatalf("unexpected estimate sum header: %q", got)
+	}
+	if got := rec.Header().Get("X-Total-Story-Points"); got != "5" {
+		t.Fatalf("unexpected story points sum header: %q", got)
+	}
+}
+
+func TestHandlerListTasks_InvalidRangeParam(t *testing.T) {
+	svc := &mockService{}
+	mux := http.NewServeMux()
+	NewHandler(svc).Register(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/tasks?story_points_min=abc", nil)
+	rec := httptest.NewRecorder()
+
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+	if svc.listCalled {
+		t.Fatal("list must not be called")
+	}
+}
+
+func TestHandlerCreateTask_CustomFields(t *testing.T) {
+	svc := &mockService{createResult: task.Task{ID: 1}}
+	mux := http.NewServeMux()
+	NewHandler(svc).Register(mux)
+
+	req := httptest.NewRequest(http.MethodPost, "/tasks", bytes.NewBufferString(`{
+		"title":"Checkout fails",
+		"project_id":7,
+		"custom_fields":{"severity":"high","impact":3,"environments":["production"]}
+	}`))
+	rec := httptest.NewRecorder()
+
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, rec.Code)
+	}
+	if svc.createInput.ProjectID == nil || *svc.createInput.ProjectID != 7 {
+		t.Fatalf("unexpected project id: %v", svc.createInput.ProjectID)
+	}
+	if svc.createInput.CustomFields["severity"] != "high" || svc.createInput.CustomFields["impact"] != float64(3) {
+		t.Fatalf("unexpected custom fields: %v", svc.createInput.CustomFields)
+	}
+}
+
+func TestHandlerListTasks_CustomFieldFilters(t *testing.T) {
+	svc := &mockService{}
+	mux := http.NewServeMux()
+	NewHandler(svc).Register(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/tasks?project_id=7&cf.severity=high&cf.severity=critical&cf.customer=ACME", nil)
+	rec := httptest.NewRecorder()
+
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	if svc.listInput.ProjectID == nil || *svc.listInput.ProjectID != 7 {
+		t.Fatalf("unexpected project id: %v", svc.listInput.ProjectID)
+	}
+	if got := svc.listInput.CustomFields["severity"]; len(got) != 2 || got[1] != "critical" {
+		t.Fatalf("unexpected severity filter: %v", got)
+	}
+	if got := svc.listInput.CustomFields["customer"]; len(got) != 1 || got[0] != "ACME" {
+		t.Fatalf("unexpected customer filter: %v", got)
+	}
+}
+
+func TestHandlerMoveTask(t *testing.T) {
+	svc := &mockService{}
+	mux := http.NewServeMux()
+	NewHandler(svc).Register(mux)
+
+	req := httptest.NewRequest(http.MethodPost, "/tasks/4/move", bytes.NewBufferString(`{"after_id":2,"before_id":9}`))
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	if svc.moveID != 4 || svc.moveInput.AfterID == nil || *svc.moveInput.AfterID != 2 || *svc.moveInput.BeforeID != 9 {
+		t.Fatalf("unexpected move call: id=%d input=%+v", svc.moveID, svc.moveInput)
+	}
+}
+
+func TestHandlerListTasks_Envelope(t *testing.T) {
+	svc := &mockService{
+		listResult:    []task.Task{{ID: 3}, {ID: 2}},
+		nextCursor:    "abc",
+		summaryResult: task.ListSummary{Count: 7},
+	}
+	mux := http.NewServeMux()
+	NewHandler(svc).Register(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/tasks?status=new&limit=2&include_total=true", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	var response struct {
+		Items      []task.Task `json:"items"`
+		NextCursor *string     `json:"next_cursor"`
+		Total      *int64      `json:"total"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(response.Items) != 2 || response.NextCursor == nil || *response.NextCursor != "abc" || response.Total == nil || *response.Total != 7 {
+		t.Fatalf("unexpected envelope: %+v", response)
+	}
+
+	link := rec.Header().Get("Link")
+	if !strings.Contains(link, `</tasks?cursor=abc&include_total=true&limit=2&status=new>; rel="next"`) {
+		t.Fatalf("unexpected Link header: %s", link)
+	}
+
+	svc.nextCursor = ""
+	svc.summarizeCalled = false
+	req = httptest.NewRequest(http.MethodGet, "/tasks?cursor=abc", nil)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if svc.summarizeCalled {
+		t.Fatal("expected the envelope without include_total not to summarize")
+	}
+	if strings.Contains(rec.Body.String(), `"next_cursor":null,"total"`) {
+		t.Fatalf("expected no total without include_total: %s", rec.Body.String())
+	}
+
+	if !strings.HasSuffix(strings.TrimSpace(rec.Body.String()), `"next_cursor":null}`) {
+		t.Fatalf("unexpected last page: %s", rec.Body.String())
+	}
+	if svc.listInput.Cursor != "abc" {
+		t.Fatalf("expected cursor to be passed, got %q", svc.listInput.Cursor)
+	}
+	if strings.Contains(rec.Header().Get("Link"), `rel="next"`) {
+		t.Fatalf("last page must not link to a next page: %s", rec.Header().Get("Link"))
+	}
+}
+
+func TestHandlerListTasks_ArrayLinks(t *testing.T) {
+	svc := &mockService{
+		listResult:    []task.Task{{ID: 3}, {ID: 2}},
+		nextCursor:    "abc",
+		summaryResult: task.ListSummary{Count: 2},
+	}
+	mux := http.NewServeMux()
+	NewHandler(svc).Register(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/tasks?limit=2&offset=2", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	var tasks []task.Task
+	if err := json.NewDecoder(rec.Body).Decode(&tasks); err != nil {
+		t.Fatalf("expected a bare array: %v", err)
+	}
+
+	link := rec.Header().Get("Link")
+	if !strings.Contains(link, `</tasks?limit=2&offset=4>; rel="next"`) || !strings.Contains(link, `</tasks?limit=2>; rel="first"`) {
+		t.Fatalf("unexpected Link header: %s", link)
+	}
+
+	svc.nextCursor = ""
+	svc.summaryResult = task.ListSummary{Count: 9}
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tasks?limit=2&offset=2", nil))
+
+	if strings.Contains(rec.Header().Get("Link"), `rel="next"`) {
+		t.Fatalf("last page must not link to a next page: %s", rec.Header().Get("Link"))
+	}
+}
+
+func TestHandlerListTasks_RichFilters(t *testing.T) {
+	svc := &mockService{}
+	mux := http.NewServeMux()
+	NewHandler(svc).Register(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/tasks?status=new,in_progress&priority_min=2&priority_max=4&due_before=2026-04-01T00:00:00Z&overdue=true&has_due_date=true&updated_after=2026-03-01T00:00:00Z&sort=priority,-due_at", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	input := svc.listInput
+	if input.Status != "new,in_progress" || input.Sort != "priority,-due_at" {
+		t.Fatalf("unexpected input: %+v", input)
+	}
+	if *input.PriorityMin != 2 || *input.PriorityMax != 4 {
+		t.Fatalf("unexpected priority range: %v-%v", *input.PriorityMin, *input.PriorityMax)
+	}
+	if input.DueBefore == nil || input.UpdatedAfter == nil || input.DueAfter != nil || input.CreatedAfter != nil {
+		t.Fatalf("unexpected time filters: %+v", input)
+	}
+	if input.Overdue == nil || !*input.Overdue || input.HasDueDate == nil || !*input.HasDueDate {
+		t.Fatalf("unexpected flags: overdue=%v has_due_date=%v", input.Overdue, input.HasDueDate)
+	}
+}
+
+func TestHandlerListTasks_InvalidRichFilters(t *testing.T) {
+	for _, query := range []string{"due_after=yesterday", "overdue=maybe", "priority_max=high"} {
+		mux := http.NewServeMux()
+		NewHandler(&mockService{}).Register(mux)
+
+		req := httptest.NewRequest(http.MethodGet, "/tasks?"+query, nil)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected status %d, got %d", query, http.StatusBadRequest, rec.Code)
+		}
+	}
+}
+
+func TestHandlerListTasks_FilterExpression(t *testing.T) {
+	svc := &mockService{
+		listErr: task.ValidationError{Field: "filter", Message: "unknown field colour", Position: 17},
+	}
+	mux := http.NewServeMux()
+	NewHandler(svc).Register(mux)
+
+	query := url.Values{"filter": {"status:done AND colour:red"}}
+	req := httptest.NewRequest(http.MethodGet, "/tasks?"+query.Encode(), nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if svc.listInput.Filter != "status:done AND colour:red" {
+		t.Fatalf("unexpected filter: %q", svc.listInput.Filter)
+	}
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+
+	expected := `{"error":"unknown field colour","field":"filter","position":17}`
+	if body := strings.TrimSpace(rec.Body.String()); body != expected {
+		t.Fatalf("expected body %s, got %s", expected, body)
+	}
+}
+
+func TestHandlerListTasks_SearchOffsetPage(t *testing.T) {
+	svc := &mockService{
+		listResult: []task.Task{{ID: 3, Highlight: &task.SearchHighlight{Title: "<mark>Deploy</mark> api"}}},
+		nextOffset: 1,
+	}
+	mux := http.NewServeMux()
+	NewHandler(svc).Register(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/tasks?q=deploy&search_mode=boolean&limit=1&envelope=true", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	if svc.listInput.Query != "deploy" || svc.listInput.SearchMode != "boolean" {
+		t.Fatalf("unexpected input: %+v", svc.listInput)
+	}
+
+	body := rec.Body.String()
+	if !strings.Contains(body, `"highlight":{"title":"\u003cmark\u003eDeploy\u003c/mark\u003e api"}`) {
+		t.Fatalf("expected highlight in body, got %s", body)
+	}
+	if !strings.Contains(body, `"next_cursor":null,"next_offset":1`) {
+		t.Fatalf("expected next offset in body, got %s", body)
+	}
+
+	link := rec.Header().Get("Link")
+	if !strings.Contains(link, `</tasks?envelope=true&limit=1&offset=1&q=deploy&search_mode=boolean>; rel="next"`) {
+		t.Fatalf("unexpected Link header: %s", link)
+	}
+}
+
+func TestHandlerGetTask_ETag(t *testing.T) {
+	svc := &mockService{getResult: task.Task{ID: 4, Title: "Ship", Version: 7}}
+	mux := http.NewServeMux()
+	NewHandler(svc).Register(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/tasks/4", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") != `"7"` {
+		t.Fatalf("unexpected response: status=%d etag=%s", rec.Code, rec.Header().Get("ETag"))
+	}
+
+	for _, header := range []string{`"7"`, `"6", W/"7"`, "*"} {
+		req = httptest.NewRequest(http.MethodGet, "/tasks/4", nil)
+		req.Header.Set("If-None-Match", header)
+		rec = httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusNotModified || rec.Body.Len() != 0 || rec.Header().Get("ETag") != `"7"` {
+			t.Fatalf("%s: expected empty 304, got status=%d body=%s", header, rec.Code, rec.Body.String())
+		}
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/tasks/4", nil)
+	req.Header.Set("If-None-Match", `"6"`)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d for a stale tag, got %d", http.StatusOK, rec.Code)
+	}
+}
+
+func TestHandlerUpdateTask_IfMatch(t *testing.T) {
+	svc := &mockService{updateResult: task.Task{ID: 4, Version: 8}}
+	mux := http.NewServeMux()
+	NewHandler(svc).Register(mux)
+
+	req := httptest.NewRequest(http.MethodPatch, "/tasks/4", strings.NewReader(`{"title":"Ship"}`))
+	req.Header.Set("If-Match", `"7"`)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") != `"8"` {
+		t.Fatalf("unexpected response: status=%d etag=%s", rec.Code, rec.Header().Get("ETag"))
+	}
+	if svc.updateInput.ExpectedVersion == nil || *svc.updateInput.ExpectedVersion != 7 {
+		t.Fatalf("expected version 7, got %v", svc.updateInput.ExpectedVersion)
+	}
+
+	svc.updateErr = task.ErrVersionConflict
+	req = httptest.NewRequest(http.MethodPatch, "/tasks/4", strings.NewReader(`{"title":"Ship"}`))
+	req.Header.Set("If-Match", `W/"7"`)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusPreconditionFailed {
+		t.Fatalf("expected status %d, got %d", http.StatusPreconditionFailed, rec.Code)
+	}
+	if *svc.updateInput.ExpectedVersion != 0 {
+		t.Fatalf("expected a weak tag never to match, got version %d", *svc.updateInput.ExpectedVersion)
+	}
+
+	svc.updateErr = nil
+	req = httptest.NewRequest(http.MethodPatch, "/tasks/4", strings.NewReader(`{"title":"Ship"}`))
+	req.Header.Set("If-Match", "*")
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK || svc.updateInput.ExpectedVersion != nil {
+		t.Fatalf("expected * to update unconditionally, got status=%d version=%v", rec.Code, svc.updateInput.ExpectedVersion)
+	}
+}
+
+func TestHandlerDeleteTask_IfMatch(t *testing.T) {
+	svc := &mockService{}
+	mux := http.NewServeMux()
+	NewHandler(svc).Register(mux)
+
+	req := httptest.NewRequest(http.MethodDelete, "/tasks/4", nil)
+	req.Header.Set("If-Match", `"3"`)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected status %d, got %d", http.StatusNoContent, rec.Code)
+	}
+	if svc.deleteInput.ExpectedVersion == nil || *svc.deleteInput.ExpectedVersion != 3 {
+		t.Fatalf("expected version 3, got %v", svc.deleteInput.ExpectedVersion)
+	}
+
+	svc.deleteCalled = false
+	req = httptest.NewRequest(http.MethodDelete, "/tasks/4", nil)
+	req.Header.Set("If-Match", `"3", "4"`)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest || svc.deleteCalled {
+		t.Fatalf("expected status %d without deleting, got %d", http.StatusBadRequest, rec.Code)
+	}
+}
@@ -0,0 +1,101 @@
+package httpapi
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/task"
+)
+
+const (
+	idempotencyKeyHeader      = "Idempotency-Key"
+	idempotentReplayedHeader  = "Idempotent-Replayed"
+	idempotencyRetryAfterSecs = 1
+)
+
+// WithIdempotency lets clients retry POST /tasks safely by sending an
+// Idempotency-Key header. Without it the header is ignored.
+func WithIdempotency(service task.IdempotencyService) Option {
+	return func(h *Handler) {
+		h.idempotency = service
+	}
+}
+
+// requestFingerprint identifies a request body independently of its
+// formatting, so a retry with the same fields matches the original.
+func requestFingerprint(request any) (string, error) {
+	canonical, err := json.Marshal(request)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// serveIdempotently runs create once per idempotency key and replays the
+// stored response for retries. create runs in the transaction that stores its
+// response, so it must do its writes with the context it is given. Failed
+// requests release the key so they can be retried.
+func (h *Handler) serveIdempotently(w http.ResponseWriter, r *http.Request, request any, status int, create func(ctx context.Context) (any, error)) {
+	userID := optionalUserID(r)
+	key := r.Header.Get(idempotencyKeyHeader)
+
+	fingerprint, err := requestFingerprint(request)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, errorResponse{Error: "internal server error"})
+		return
+	}
+
+	reservation, stored, err := h.idempotency.Begin(r.Context(), userID, key, fingerprint)
+	if err != nil {
+		if errors.Is(err, task.ErrIdempotencyKeyInProgress) {
+			w.Header().Set("Retry-After", strconv.Itoa(idempotencyRetryAfterSecs))
+		}
+		writeDomainError(w, err)
+		return
+	}
+	if stored != nil {
+		w.Header().Set(idempotentReplayedHeader, "true")
+		writeRawJSON(w, stored.Status, stored.Body)
+		return
+	}
+
+	// The key must be completed or released even if the client goes away.
+	ctx := context.WithoutCancel(r.Context())
+
+	response, err := h.idempotency.Complete(ctx, reservation, func(ctx context.Context) (task.IdempotentResponse, error) {
+		result, err := create(ctx)
+		if err != nil {
+			return task.IdempotentResponse{}, err
+		}
+
+		body, err := json.Marshal(result)
+		if err != nil {
+			return task.IdempotentResponse{}, err
+		}
+		return task.IdempotentResponse{Status: status, Body: append(body, '\n')}, nil
+	})
+	if err != nil {
+		// A key that is still held would block retries until it expires, so
+		// failing to release it fails the request. A lost key is not ours to
+		// release.
+		if releaseErr := h.idempotency.Release(ctx, reservation); releaseErr != nil && !errors.Is(releaseErr, task.ErrIdempotencyKeyLost) {
+			err = releaseErr
+		}
+		writeDomainError(w, err)
+		return
+	}
+
+	writeRawJSON(w, response.Status, response.Body)
+}
+
+func writeRawJSON(w http.ResponseWriter, status int, body []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
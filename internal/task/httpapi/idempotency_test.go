@@ -0,0 +1,160 @@
+package httpapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/task"
+)
+
+type mockIdempotencyService struct {
+	beginUserID      string
+	beginKey         string
+	beginFingerprint string
+	beginResult      *task.IdempotentResponse
+	beginErr         error
+
+	completeErr error
+	releaseErr  error
+
+	completed *task.IdempotentResponse
+	released  bool
+}
+
+func (m *mockIdempotencyService) Begin(_ context.Context, userID, key, fingerprint string) (task.IdempotencyReservation, *task.IdempotentResponse, error) {
+	m.beginUserID = userID
+	m.beginKey = key
+	m.beginFingerprint = fingerprint
+	reservation := task.IdempotencyReservation{UserID: userID, Key: key, Fingerprint: fingerprint}
+	return reservation, m.beginResult, m.beginErr
+}
+
+func (m *mockIdempotencyService) Complete(ctx context.Context, _ task.IdempotencyReservation, execute func(ctx context.Context) (task.IdempotentResponse, error)) (task.IdempotentResponse, error) {
+	response, err := execute(ctx)
+	if err != nil {
+		return task.IdempotentResponse{}, err
+	}
+	if m.completeErr != nil {
+		return task.IdempotentResponse{}, m.completeErr
+	}
+	m.completed = &response
+	return response, nil
+}
+
+func (m *mockIdempotencyService) Release(context.Context, task.IdempotencyReservation) error {
+	m.released = true
+	return m.releaseErr
+}
+
+func newIdempotentCreateRequest(body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/tasks", bytes.NewBufferString(body))
+	req.Header.Set(idempotencyKeyHeader, "create-1")
+	req.Header.Set(userIDHeader, testUserID)
+	return req
+}
+
+func TestHandlerCreateTask_IdempotencyKey(t *testing.T) {
+	svc := &mockService{createResult: task.Task{ID: 7, Title: "Write tests"}}
+	idempotency := &mockIdempotencyService{}
+	mux := http.NewServeMux()
+	NewHandler(svc, WithIdempotency(idempotency)).Register(mux)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, newIdempotentCreateRequest(`{"title":"Write tests"}`))
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, rec.Code)
+	}
+	if idempotency.beginKey != "create-1" || idempotency.beginUserID != testUserID || idempotency.beginFingerprint == "" {
+		t.Fatalf("unexpected begin: %+v", idempotency)
+	}
+	if idempotency.completed == nil || idempotency.completed.Status != http.StatusCreated || !bytes.Equal(idempotency.completed.Body, rec.Body.Bytes()) {
+		t.Fatalf("expected the response to be stored, got %+v", idempotency.completed)
+	}
+	if rec.Header().Get(idempotentReplayedHeader) != "" {
+		t.Fatal("expected a fresh response not to be marked as replayed")
+	}
+
+	// The fingerprint ignores formatting but not content.
+	fingerprint := idempotency.beginFingerprint
+	mux.ServeHTTP(httptest.NewRecorder(), newIdempotentCreateRequest(`{ "title" : "Write tests" }`))
+	if idempotency.beginFingerprint != fingerprint {
+		t.Fatal("expected the same fingerprint for a reformatted body")
+	}
+	mux.ServeHTTP(httptest.NewRecorder(), newIdempotentCreateRequest(`{"title":"Write tests","due_at":"2026-03-04T10:00:00Z"}`))
+	if idempotency.beginFingerprint == fingerprint {
+		t.Fatal("expected a different fingerprint for a different body")
+	}
+}
+
+func TestHandlerCreateTask_IdempotentReplay(t *testing.T) {
+	svc := &mockService{}
+	idempotency := &mockIdempotencyService{
+		beginResult: &task.IdempotentResponse{Status: http.StatusCreated, Body: []byte(`{"id":7}` + "\n")},
+	}
+	mux := http.NewServeMux()
+	NewHandler(svc, WithIdempotency(idempotency)).Register(mux)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, newIdempotentCreateRequest(`{"title":"Write tests"}`))
+
+	if rec.Code != http.StatusCreated || rec.Body.String() != `{"id":7}`+"\n" {
+		t.Fatalf("expected the stored response, got %d %s", rec.Code, rec.Body.String())
+	}
+	if rec.Header().Get(idempotentReplayedHeader) != "true" {
+		t.Fatal("expected the replay to be marked")
+	}
+	if svc.createCalled {
+		t.Fatal("expected create not to be called on replay")
+	}
+}
+
+func TestHandlerCreateTask_IdempotencyErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		beginErr    error
+		createErr   error
+		completeErr error
+		releaseErr  error
+		status      int
+		retryAfter  string
+		released    bool
+	}{
+		{name: "reused key", beginErr: task.ErrIdempotencyKeyReused, status: http.StatusUnprocessableEntity},
+		{name: "in progress", beginErr: task.ErrIdempotencyKeyInProgress, status: http.StatusConflict, retryAfter: "1"},
+		{name: "invalid key", beginErr: task.ValidationError{Field: idempotencyKeyHeader, Message: "must not be empty"}, status: http.StatusBadRequest},
+		{name: "create fails", createErr: task.ValidationError{Field: "title", Message: "is required"}, status: http.StatusBadRequest, released: true},
+		{name: "internal error", createErr: errors.New("db down"), status: http.StatusInternalServerError, released: true},
+		{name: "key lost", completeErr: task.ErrIdempotencyKeyLost, releaseErr: task.ErrIdempotencyKeyLost, status: http.StatusConflict, released: true},
+		{name: "release fails", createErr: task.ValidationError{Field: "title", Message: "is required"}, releaseErr: errors.New("db down"), status: http.StatusInternalServerError, released: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &mockService{createErr: tc.createErr}
+			idempotency := &mockIdempotencyService{beginErr: tc.beginErr, completeErr: tc.completeErr, releaseErr: tc.releaseErr}
+			mux := http.NewServeMux()
+			NewHandler(svc, WithIdempotency(idempotency)).Register(mux)
+
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, newIdempotentCreateRequest(`{"title":""}`))
+
+			if rec.Code != tc.status {
+				t.Fatalf("expected status %d, got %d", tc.status, rec.Code)
+			}
+			if rec.Header().Get("Retry-After") != tc.retryAfter {
+				t.Fatalf("unexpected Retry-After: %q", rec.Header().Get("Retry-After"))
+			}
+			if idempotency.released != tc.released {
+				t.Fatalf("expected released=%v", tc.released)
+			}
+			if idempotency.completed != nil {
+				t.Fatal("expected a failed request not to be stored")
+			}
+		})
+	}
+}
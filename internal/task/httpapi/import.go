@@ -0,0 +1,84 @@
+package httpapi
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/task"
+)
+
+const (
+	maxImportBodyBytes  int64 = 10 << 20
+	importMappingPrefix       = "map."
+)
+
+// ImportHandler imports tasks from a CSV or JSON file sent as the request
+// body. Columns are mapped to task fields with map.<column>=<field> query
+// parameters.
+type ImportHandler struct {
+	service task.ImportService
+}
+
+func NewImportHandler(service task.ImportService) *ImportHandler {
+	return &ImportHandler{service: service}
+}
+
+func (h *ImportHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("POST /tasks/import", h.importTasks)
+}
+
+func (h *ImportHandler) importTasks(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	dryRun, err := parseQueryBool(query.Get("dry_run"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errorResponse{Error: "dry_run must be a boolean", Field: "dry_run"})
+		return
+	}
+
+	format := query.Get("format")
+	if format == "" {
+		if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err == nil && mediaType == "application/json" {
+			format = string(task.ImportJSON)
+		}
+	}
+
+	mapping := make(map[string]string)
+	for key, values := range query {
+		if column, ok := strings.CutPrefix(key, importMappingPrefix); ok {
+			mapping[column] = values[len(values)-1]
+		}
+	}
+
+	defer r.Body.Close()
+	content, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxImportBodyBytes))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			err = errRequestBodyTooLarge
+		}
+		writeDecodeError(w, err)
+		return
+	}
+
+	submission, err := h.service.Submit(r.Context(), task.ImportInput{
+		Format:     format,
+		Content:    string(content),
+		Mapping:    mapping,
+		DryRun:     dryRun,
+		ReporterID: optionalUserID(r),
+	})
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	if submission.Job != nil {
+		w.Header().Set("Location", "/jobs/"+submission.Job.ID)
+		writeJSON(w, http.StatusAccepted, submission.Job)
+		return
+	}
+	writeJSON(w, http.StatusOK, submission.Result)
+}
@@ -0,0 +1,103 @@
+package httpapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/job"
+	"github.com/PavelFesenkoFirst/task_tracker/internal/task"
+)
+
+type mockImportService struct {
+	input      task.ImportInput
+	submission task.ImportSubmission
+	err        error
+}
+
+func (m *mockImportService) Submit(_ context.Context, input task.ImportInput) (task.ImportSubmission, error) {
+	m.input = input
+	return m.submission, m.err
+}
+
+func (m *mockImportService) Run(context.Context, task.ImportInput) (task.ImportResult, error) {
+	return task.ImportResult{}, nil
+}
+
+func serveImport(svc *mockImportService, target, contentType, body string) *httptest.ResponseRecorder {
+	mux := http.NewServeMux()
+	NewImportHandler(svc).Register(mux)
+
+	req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set(userIDHeader, testUserID)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestImportHandler_CSV(t *testing.T) {
+	svc := &mockImportService{submission: task.ImportSubmission{Result: &task.ImportResult{
+		DryRun:  true,
+		Created: 1,
+		Rows:    []task.ImportRowResult{{Row: 1, Line: 2, Status: task.ImportRowCreated, Title: "Ship"}},
+	}}}
+
+	rec := serveImport(svc, "/tasks/import?dry_run=true&map.Summary=title&map.Legacy%20ID=", "text/csv", "Summary,Legacy ID\nShip,OLD-1\n")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	if !strings.Contains(rec.Body.String(), `"dry_run":true`) || !strings.Contains(rec.Body.String(), `"status":"created"`) {
+		t.Fatalf("unexpected body: %s", rec.Body.String())
+	}
+
+	expected := task.ImportInput{
+		Content:    "Summary,Legacy ID\nShip,OLD-1\n",
+		Mapping:    map[string]string{"Summary": "title", "Legacy ID": ""},
+		DryRun:     true,
+		ReporterID: testUserID,
+	}
+	if !reflect.DeepEqual(svc.input, expected) {
+		t.Fatalf("unexpected input: %+v", svc.input)
+	}
+}
+
+func TestImportHandler_JSONQueued(t *testing.T) {
+	svc := &mockImportService{submission: task.ImportSubmission{Job: &job.Job{ID: "job-1", Type: task.ImportJobType, Status: job.StatusQueued}}}
+
+	rec := serveImport(svc, "/tasks/import", "application/json; charset=utf-8", `[{"title": "Ship"}]`)
+
+	if rec.Code != http.StatusAccepted || rec.Header().Get("Location") != "/jobs/job-1" {
+		t.Fatalf("unexpected response: %d %q", rec.Code, rec.Header().Get("Location"))
+	}
+	if svc.input.Format != "json" || svc.input.DryRun {
+		t.Fatalf("expected a JSON import, got %+v", svc.input)
+	}
+
+	serveImport(svc, "/tasks/import?format=csv", "application/json", "title\nShip\n")
+	if svc.input.Format != "csv" {
+		t.Fatalf("expected the format parameter to win, got %q", svc.input.Format)
+	}
+}
+
+func TestImportHandler_Errors(t *testing.T) {
+	rec := serveImport(&mockImportService{}, "/tasks/import?dry_run=maybe", "text/csv", "title\nShip\n")
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), `"field":"dry_run"`) {
+		t.Fatalf("unexpected response: %d %s", rec.Code, rec.Body.String())
+	}
+
+	svc := &mockImportService{err: task.ValidationError{Field: "mapping.Summary", Message: "is not a column of the file"}}
+	rec = serveImport(svc, "/tasks/import?map.Summary=title", "text/csv", "title\nShip\n")
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), `"field":"mapping.Summary"`) {
+		t.Fatalf("unexpected response: %d %s", rec.Code, rec.Body.String())
+	}
+
+	rec = serveImport(&mockImportService{}, "/tasks/import", "text/csv", "title\n"+strings.Repeat("x", int(maxImportBodyBytes)))
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected status %d, got %d", http.StatusRequestEntityTooLarge, rec.Code)
+	}
+}
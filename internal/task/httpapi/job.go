@@ -0,0 +1,31 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/job"
+)
+
+// JobHandler reports the status and result of background jobs, e.g. bulk
+// operations queued by POST /tasks/bulk.
+type JobHandler struct {
+	service job.Service
+}
+
+func NewJobHandler(service job.Service) *JobHandler {
+	return &JobHandler{service: service}
+}
+
+func (h *JobHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /jobs/{id}", h.getJob)
+}
+
+func (h *JobHandler) getJob(w http.ResponseWriter, r *http.Request) {
+	found, err := h.service.Get(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, found)
+}
@@ -0,0 +1,50 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/task"
+)
+
+type MentionHandler struct {
+	service task.MentionService
+}
+
+func NewMentionHandler(service task.MentionService) *MentionHandler {
+	return &MentionHandler{service: service}
+}
+
+func (h *MentionHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /users/me/mentions", h.listMyMentions)
+}
+
+func (h *MentionHandler) listMyMentions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireUserID(w, r)
+	if !ok {
+		return
+	}
+
+	limit, err := parseQueryInt(r.URL.Query().Get("limit"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errorResponse{Error: "limit must be an integer", Field: "limit"})
+		return
+	}
+
+	offset, err := parseQueryInt(r.URL.Query().Get("offset"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errorResponse{Error: "offset must be an integer", Field: "offset"})
+		return
+	}
+
+	mentions, err := h.service.ListForUser(r.Context(), task.ListMentionsInput{
+		UserID: userID,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, mentions)
+}
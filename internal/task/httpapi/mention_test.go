@@ -0,0 +1,44 @@
+package httpapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/task"
+)
+
+type mockMentionService struct {
+	input task.ListMentionsInput
+}
+
+func (m *mockMentionService) ListForUser(_ context.Context, input task.ListMentionsInput) ([]task.Mention, error) {
+	m.input = input
+	return []task.Mention{{ID: 1, TaskID: 2, UserID: input.UserID}}, nil
+}
+
+func TestMentionHandlerListMyMentions(t *testing.T) {
+	svc := &mockMentionService{}
+	mux := http.NewServeMux()
+	NewMentionHandler(svc).Register(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/users/me/mentions?limit=5&offset=10", nil)
+	req.Header.Set(userIDHeader, testUserID)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	if svc.input.UserID != testUserID || svc.input.Limit != 5 || svc.input.Offset != 10 {
+		t.Fatalf("unexpected input: %+v", svc.input)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/users/me/mentions", nil)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status %d, got %d", http.StatusUnauthorized, rec.Code)
+	}
+}
@@ -0,0 +1,119 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/task"
+)
+
+type MilestoneHandler struct {
+	service task.MilestoneService
+}
+
+type createMilestoneRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	TargetDate  string `json:"target_date"`
+}
+
+type updateMilestoneRequest struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+	TargetDate  *string `json:"target_date"`
+}
+
+func NewMilestoneHandler(service task.MilestoneService) *MilestoneHandler {
+	return &MilestoneHandler{service: service}
+}
+
+func (h *MilestoneHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("POST /milestones", h.createMilestone)
+	mux.HandleFunc("GET /milestones", h.listMilestones)
+	mux.HandleFunc("GET /milestones/{id}", h.getMilestone)
+	mux.HandleFunc("PATCH /milestones/{id}", h.updateMilestone)
+	mux.HandleFunc("DELETE /milestones/{id}", h.deleteMilestone)
+}
+
+func (h *MilestoneHandler) createMilestone(w http.ResponseWriter, r *http.Request) {
+	var request createMilestoneRequest
+	if err := decodeJSON(w, r, &request); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	milestone, err := h.service.Create(r.Context(), task.CreateMilestoneInput{
+		Name:        request.Name,
+		Description: request.Description,
+		TargetDate:  request.TargetDate,
+	})
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, milestone)
+}
+
+func (h *MilestoneHandler) listMilestones(w http.ResponseWriter, r *http.Request) {
+	milestones, err := h.service.List(r.Context())
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, milestones)
+}
+
+func (h *MilestoneHandler) getMilestone(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+
+	milestone, err := h.service.GetByID(r.Context(), id)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, milestone)
+}
+
+func (h *MilestoneHandler) updateMilestone(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+
+	var request updateMilestoneRequest
+	if err := decodeJSON(w, r, &request); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	milestone, err := h.service.Update(r.Context(), id, task.UpdateMilestoneInput{
+		Name:        request.Name,
+		Description: request.Description,
+		TargetDate:  request.TargetDate,
+	})
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, milestone)
+}
+
+func (h *MilestoneHandler) deleteMilestone(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), id); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,96 @@
+package httpapi
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/task"
+)
+
+type mockMilestoneService struct {
+	createInput task.CreateMilestoneInput
+	updateInput task.UpdateMilestoneInput
+	deletedID   uint64
+	err         error
+}
+
+func (m *mockMilestoneService) Create(_ context.Context, input task.CreateMilestoneInput) (task.Milestone, error) {
+	m.createInput = input
+	return task.Milestone{ID: 1, Name: input.Name}, m.err
+}
+
+func (m *mockMilestoneService) GetByID(_ context.Context, id uint64) (task.Milestone, error) {
+	return task.Milestone{ID: id}, m.err
+}
+
+func (m *mockMilestoneService) List(_ context.Context) ([]task.Milestone, error) {
+	return []task.Milestone{}, m.err
+}
+
+func (m *mockMilestoneService) Update(_ context.Context, id uint64, input task.UpdateMilestoneInput) (task.Milestone, error) {
+	m.updateInput = input
+	return task.Milestone{ID: id}, m.err
+}
+
+func (m *mockMilestoneService) Delete(_ context.Context, id uint64) error {
+	m.deletedID = id
+	return m.err
+}
+
+func TestMilestoneHandlerCreateMilestone(t *testing.T) {
+	svc := &mockMilestoneService{}
+	mux := http.NewServeMux()
+	NewMilestoneHandler(svc).Register(mux)
+
+	req := httptest.NewRequest(http.MethodPost, "/milestones", bytes.NewBufferString(`{"name": "Beta", "target_date": "2026-06-01"}`))
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, rec.Code)
+	}
+	if svc.createInput.Name != "Beta" || svc.createInput.TargetDate != "2026-06-01" {
+		t.Fatalf("unexpected input: %+v", svc.createInput)
+	}
+}
+
+func TestMilestoneHandlerDeleteMilestone(t *testing.T) {
+	svc := &mockMilestoneService{}
+	mux := http.NewServeMux()
+	NewMilestoneHandler(svc).Register(mux)
+
+	req := httptest.NewRequest(http.MethodDelete, "/milestones/3", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNoContent || svc.deletedID != 3 {
+		t.Fatalf("unexpected response: status=%d deleted=%d", rec.Code, svc.deletedID)
+	}
+
+	svc.err = task.ErrMilestoneNotFound
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/milestones/3", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d, got %d", http.StatusNotFound, rec.Code)
+	}
+}
+
+func TestHandlerUpdateTask_Milestone(t *testing.T) {
+	svc := &mockService{}
+	mux := http.NewServeMux()
+	NewHandler(svc).Register(mux)
+
+	req := httptest.NewRequest(http.MethodPatch, "/tasks/1", bytes.NewBufferString(`{"milestone_id": 4}`))
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	if svc.updateInput.MilestoneID == nil || *svc.updateInput.MilestoneID != 4 {
+		t.Fatalf("unexpected milestone id: %v", svc.updateInput.MilestoneID)
+	}
+}
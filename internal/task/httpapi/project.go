@@ -0,0 +1,147 @@
+package httpapi
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/task"
+)
+
+type ProjectHandler struct {
+	service task.ProjectService
+}
+
+type createProjectRequest struct {
+	Name string `json:"name"`
+}
+
+type createCustomFieldRequest struct {
+	Name     string   `json:"name"`
+	Type     string   `json:"type"`
+	Required bool     `json:"required"`
+	Options  []string `json:"options"`
+}
+
+type setWIPLimitsRequest struct {
+	WIPLimits map[string]*int `json:"wip_limits"`
+}
+
+func NewProjectHandler(service task.ProjectService) *ProjectHandler {
+	return &ProjectHandler{service: service}
+}
+
+func (h *ProjectHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("POST /projects", h.createProject)
+	mux.HandleFunc("GET /projects", h.listProjects)
+	mux.HandleFunc("GET /projects/{id}", h.getProject)
+	mux.HandleFunc("POST /projects/{id}/custom-fields", h.createCustomField)
+	mux.HandleFunc("DELETE /projects/{id}/custom-fields/{field_id}", h.deleteCustomField)
+	mux.HandleFunc("PUT /projects/{id}/wip-limits", h.setWIPLimits)
+}
+
+func (h *ProjectHandler) createProject(w http.ResponseWriter, r *http.Request) {
+	var request createProjectRequest
+	if err := decodeJSON(w, r, &request); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	project, err := h.service.CreateProject(r.Context(), task.CreateProjectInput{Name: request.Name})
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, project)
+}
+
+func (h *ProjectHandler) listProjects(w http.ResponseWriter, r *http.Request) {
+	projects, err := h.service.ListProjects(r.Context())
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, projects)
+}
+
+func (h *ProjectHandler) getProject(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+
+	project, err := h.service.GetProject(r.Context(), id)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, project)
+}
+
+func (h *ProjectHandler) createCustomField(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+
+	var request createCustomFieldRequest
+	if err := decodeJSON(w, r, &request); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	definition, err := h.service.CreateCustomField(r.Context(), id, task.CreateCustomFieldInput{
+		Name:     request.Name,
+		Type:     request.Type,
+		Required: request.Required,
+		Options:  request.Options,
+	})
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, definition)
+}
+
+func (h *ProjectHandler) deleteCustomField(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+	fieldID, err := strconv.ParseUint(r.PathValue("field_id"), 10, 64)
+	if err != nil || fieldID == 0 {
+		writeError(w, http.StatusBadRequest, errorResponse{Error: "field_id must be a positive integer", Field: "field_id"})
+		return
+	}
+
+	if err := h.service.DeleteCustomField(r.Context(), id, fieldID); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *ProjectHandler) setWIPLimits(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+
+	var request setWIPLimitsRequest
+	if err := decodeJSON(w, r, &request); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	project, err := h.service.SetWIPLimits(r.Context(), id, request.WIPLimits)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, project)
+}
@@ -0,0 +1,119 @@
+package httpapi
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/task"
+)
+
+type mockProjectService struct {
+	customFieldProjectID uint64
+	customFieldInput     task.CreateCustomFieldInput
+	customFieldErr       error
+
+	deletedFieldID uint64
+
+	wipLimits map[string]*int
+}
+
+func (m *mockProjectService) SetWIPLimits(_ context.Context, id uint64, limits map[string]*int) (task.Project, error) {
+	m.wipLimits = limits
+	return task.Project{ID: id}, nil
+}
+
+func (m *mockProjectService) CreateProject(_ context.Context, input task.CreateProjectInput) (task.Project, error) {
+	return task.Project{ID: 1, Name: input.Name}, nil
+}
+
+func (m *mockProjectService) GetProject(_ context.Context, id uint64) (task.Project, error) {
+	if id != 1 {
+		return task.Project{}, task.ErrProjectNotFound
+	}
+	return task.Project{ID: id}, nil
+}
+
+func (m *mockProjectService) ListProjects(_ context.Context) ([]task.Project, error) {
+	return []task.Project{}, nil
+}
+
+func (m *mockProjectService) CreateCustomField(_ context.Context, projectID uint64, input task.CreateCustomFieldInput) (task.CustomFieldDefinition, error) {
+	m.customFieldProjectID = projectID
+	m.customFieldInput = input
+	if m.customFieldErr != nil {
+		return task.CustomFieldDefinition{}, m.customFieldErr
+	}
+	return task.CustomFieldDefinition{ID: 1, ProjectID: projectID, Name: input.Name}, nil
+}
+
+func (m *mockProjectService) DeleteCustomField(_ context.Context, _, fieldID uint64) error {
+	m.deletedFieldID = fieldID
+	return nil
+}
+
+func TestProjectHandlerCreateCustomField(t *testing.T) {
+	svc := &mockProjectService{}
+	mux := http.NewServeMux()
+	NewProjectHandler(svc).Register(mux)
+
+	req := httptest.NewRequest(http.MethodPost, "/projects/1/custom-fields", bytes.NewBufferString(`{
+		"name":"severity",
+		"type":"single_select",
+		"required":true,
+		"options":["low","high"]
+	}`))
+	rec := httptest.NewRecorder()
+
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, rec.Code)
+	}
+	if svc.customFieldProjectID != 1 || svc.customFieldInput.Type != "single_select" || !svc.customFieldInput.Required {
+		t.Fatalf("unexpected call: project=%d input=%+v", svc.customFieldProjectID, svc.customFieldInput)
+	}
+	if len(svc.customFieldInput.Options) != 2 {
+		t.Fatalf("unexpected options: %v", svc.customFieldInput.Options)
+	}
+}
+
+func TestProjectHandlerErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		svc    *mockProjectService
+		method string
+		target string
+		body   string
+		status int
+	}{
+		{name: "project not found", svc: &mockProjectService{}, method: http.MethodGet, target: "/projects/2", status: http.StatusNotFound},
+		{
+			name:   "duplicate custom field",
+			svc:    &mockProjectService{customFieldErr: task.ErrCustomFieldExists},
+			method: http.MethodPost,
+			target: "/projects/1/custom-fields",
+			body:   `{"name":"severity","type":"text"}`,
+			status: http.StatusConflict,
+		},
+		{name: "invalid field id", svc: &mockProjectService{}, method: http.MethodDelete, target: "/projects/1/custom-fields/x", status: http.StatusBadRequest},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			NewProjectHandler(tc.svc).Register(mux)
+
+			req := httptest.NewRequest(tc.method, tc.target, bytes.NewBufferString(tc.body))
+			rec := httptest.NewRecorder()
+
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tc.status {
+				t.Fatalf("expected status %d, got %d", tc.status, rec.Code)
+			}
+		})
+	}
+}
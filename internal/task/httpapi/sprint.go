@@ -0,0 +1,203 @@
+package httpapi
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/task"
+)
+
+type SprintHandler struct {
+	service task.SprintService
+}
+
+type createSprintRequest struct {
+	Name     string    `json:"name"`
+	Goal     string    `json:"goal"`
+	StartsAt *taskTime `json:"starts_at"`
+	EndsAt   *taskTime `json:"ends_at"`
+}
+
+type updateSprintRequest struct {
+	Name     *string   `json:"name"`
+	Goal     *string   `json:"goal"`
+	StartsAt *taskTime `json:"starts_at"`
+	EndsAt   *taskTime `json:"ends_at"`
+}
+
+type closeSprintRequest struct {
+	NextSprintID *uint64 `json:"next_sprint_id"`
+}
+
+func NewSprintHandler(service task.SprintService) *SprintHandler {
+	return &SprintHandler{service: service}
+}
+
+func (h *SprintHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("POST /projects/{id}/sprints", h.createSprint)
+	mux.HandleFunc("GET /projects/{id}/sprints", h.listSprints)
+	mux.HandleFunc("GET /sprints/{id}", h.getSprint)
+	mux.HandleFunc("PATCH /sprints/{id}", h.updateSprint)
+	mux.HandleFunc("POST /sprints/{id}/start", h.startSprint)
+	mux.HandleFunc("POST /sprints/{id}/close", h.closeSprint)
+	mux.HandleFunc("GET /sprints/{id}/report", h.sprintReport)
+}
+
+func (h *SprintHandler) createSprint(w http.ResponseWriter, r *http.Request) {
+	projectID, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+
+	var request createSprintRequest
+	if err := decodeJSON(w, r, &request); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	startsAt, endsAt, ok := parseSprintDates(w, request.StartsAt, request.EndsAt)
+	if !ok {
+		return
+	}
+
+	sprint, err := h.service.Create(r.Context(), projectID, task.CreateSprintInput{
+		Name:     request.Name,
+		Goal:     request.Goal,
+		StartsAt: startsAt,
+		EndsAt:   endsAt,
+	})
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, sprint)
+}
+
+func (h *SprintHandler) listSprints(w http.ResponseWriter, r *http.Request) {
+	projectID, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+
+	sprints, err := h.service.List(r.Context(), projectID)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, sprints)
+}
+
+func (h *SprintHandler) getSprint(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+
+	sprint, err := h.service.GetByID(r.Context(), id)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, sprint)
+}
+
+func (h *SprintHandler) updateSprint(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+
+	var request updateSprintRequest
+	if err := decodeJSON(w, r, &request); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	startsAt, endsAt, ok := parseSprintDates(w, request.StartsAt, request.EndsAt)
+	if !ok {
+		return
+	}
+
+	sprint, err := h.service.Update(r.Context(), id, task.UpdateSprintInput{
+		Name:     request.Name,
+		Goal:     request.Goal,
+		StartsAt: startsAt,
+		EndsAt:   endsAt,
+	})
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, sprint)
+}
+
+func (h *SprintHandler) startSprint(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+
+	sprint, err := h.service.Start(r.Context(), id)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, sprint)
+}
+
+func (h *SprintHandler) closeSprint(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+
+	var request closeSprintRequest
+	if err := decodeOptionalJSON(w, r, &request); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	sprint, err := h.service.Close(r.Context(), id, task.CloseSprintInput{NextSprintID: request.NextSprintID})
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, sprint)
+}
+
+func (h *SprintHandler) sprintReport(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.service.Report(r.Context(), id)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, report)
+}
+
+func parseSprintDates(w http.ResponseWriter, rawStartsAt, rawEndsAt *taskTime) (*time.Time, *time.Time, bool) {
+	startsAt, err := parseOptionalFieldTime("starts_at", rawStartsAt)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errorResponse{Error: err.Error(), Field: "starts_at"})
+		return nil, nil, false
+	}
+
+	endsAt, err := parseOptionalFieldTime("ends_at", rawEndsAt)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errorResponse{Error: err.Error(), Field: "ends_at"})
+		return nil, nil, false
+	}
+
+	return startsAt, endsAt, true
+}
@@ -0,0 +1,144 @@
+package httpapi
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/task"
+)
+
+type mockSprintService struct {
+	projectID   uint64
+	createInput task.CreateSprintInput
+	closeID     uint64
+	closeInput  task.CloseSprintInput
+	reportID    uint64
+	err         error
+}
+
+func (m *mockSprintService) Create(_ context.Context, projectID uint64, input task.CreateSprintInput) (task.Sprint, error) {
+	m.projectID = projectID
+	m.createInput = input
+	return task.Sprint{ID: 1, ProjectID: projectID}, m.err
+}
+
+func (m *mockSprintService) GetByID(_ context.Context, id uint64) (task.Sprint, error) {
+	return task.Sprint{ID: id}, m.err
+}
+
+func (m *mockSprintService) List(_ context.Context, projectID uint64) ([]task.Sprint, error) {
+	m.projectID = projectID
+	return []task.Sprint{}, m.err
+}
+
+func (m *mockSprintService) Update(_ context.Context, id uint64, _ task.UpdateSprintInput) (task.Sprint, error) {
+	return task.Sprint{ID: id}, m.err
+}
+
+func (m *mockSprintService) Start(_ context.Context, id uint64) (task.Sprint, error) {
+	return task.Sprint{ID: id}, m.err
+}
+
+func (m *mockSprintService) Close(_ context.Context, id uint64, input task.CloseSprintInput) (task.Sprint, error) {
+	m.closeID = id
+	m.closeInput = input
+	return task.Sprint{ID: id}, m.err
+}
+
+func (m *mockSprintService) Report(_ context.Context, id uint64) (task.SprintReport, error) {
+	m.reportID = id
+	return task.SprintReport{SprintID: id}, m.err
+}
+
+func TestSprintHandlerCreateSprint(t *testing.T) {
+	svc := &mockSprintService{}
+	mux := http.NewServeMux()
+	NewSprintHandler(svc).Register(mux)
+
+	body := `{"name": "Sprint 1", "goal": "Ship", "starts_at": "2026-03-02T09:00:00Z", "ends_at": "2026-03-16T09:00:00Z"}`
+	req := httptest.NewRequest(http.MethodPost, "/projects/2/sprints", bytes.NewBufferString(body))
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, rec.Code)
+	}
+	if svc.projectID != 2 || svc.createInput.Name != "Sprint 1" || svc.createInput.StartsAt == nil || svc.createInput.EndsAt == nil {
+		t.Fatalf("unexpected input: project=%d %+v", svc.projectID, svc.createInput)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/projects/2/sprints", bytes.NewBufferString(`{"name": "Sprint 2", "starts_at": "monday"}`))
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+}
+
+func TestSprintHandlerCloseSprint(t *testing.T) {
+	svc := &mockSprintService{}
+	mux := http.NewServeMux()
+	NewSprintHandler(svc).Register(mux)
+
+	req := httptest.NewRequest(http.MethodPost, "/sprints/4/close", bytes.NewBufferString(`{"next_sprint_id": 5}`))
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	if svc.closeID != 4 || svc.closeInput.NextSprintID == nil || *svc.closeInput.NextSprintID != 5 {
+		t.Fatalf("unexpected close call: id=%d input=%+v", svc.closeID, svc.closeInput)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/sprints/4/close", nil)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || svc.closeInput.NextSprintID != nil {
+		t.Fatalf("expected close without body to succeed, got %d %+v", rec.Code, svc.closeInput)
+	}
+}
+
+func TestSprintHandlerErrors(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+	}{
+		{err: task.ErrSprintNotFound, status: http.StatusNotFound},
+		{err: task.ErrActiveSprintExists, status: http.StatusConflict},
+		{err: task.ErrInvalidSprintTransition, status: http.StatusConflict},
+	}
+
+	for _, tc := range tests {
+		mux := http.NewServeMux()
+		NewSprintHandler(&mockSprintService{err: tc.err}).Register(mux)
+
+		req := httptest.NewRequest(http.MethodPost, "/sprints/4/start", nil)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+
+		if rec.Code != tc.status {
+			t.Fatalf("%v: expected status %d, got %d", tc.err, tc.status, rec.Code)
+		}
+	}
+}
+
+func TestHandlerListTasks_SprintFilter(t *testing.T) {
+	svc := &mockService{}
+	mux := http.NewServeMux()
+	NewHandler(svc).Register(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/tasks?sprint_id=5", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	if svc.listInput.SprintID == nil || *svc.listInput.SprintID != 5 {
+		t.Fatalf("unexpected sprint filter: %v", svc.listInput.SprintID)
+	}
+}
@@ -0,0 +1,126 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/task"
+)
+
+type TemplateHandler struct {
+	service task.TemplateService
+}
+
+type createTemplateRequest struct {
+	Name string            `json:"name"`
+	Task task.TemplateTask `json:"task"`
+}
+
+type instantiateTemplateRequest struct {
+	Variables map[string]string `json:"variables"`
+}
+
+type instantiateTemplateResponse struct {
+	Tasks []task.Task `json:"tasks"`
+}
+
+// WithTemplates serves POST /tasks/from-template/{id} with the given template
+// handler. The route goes through the task handler because on its own it
+// would conflict with the POST /tasks/{id}/... routes.
+func WithTemplates(templates *TemplateHandler) Option {
+	return func(h *Handler) {
+		h.templates = templates
+	}
+}
+
+func NewTemplateHandler(service task.TemplateService) *TemplateHandler {
+	return &TemplateHandler{service: service}
+}
+
+func (h *TemplateHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("POST /templates", h.createTemplate)
+	mux.HandleFunc("GET /templates", h.listTemplates)
+	mux.HandleFunc("GET /templates/{id}", h.getTemplate)
+	mux.HandleFunc("DELETE /templates/{id}", h.deleteTemplate)
+}
+
+func (h *TemplateHandler) createTemplate(w http.ResponseWriter, r *http.Request) {
+	var request createTemplateRequest
+	if err := decodeJSON(w, r, &request); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	created, err := h.service.Create(r.Context(), task.CreateTemplateInput{
+		Name: request.Name,
+		Task: request.Task,
+	})
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, created)
+}
+
+func (h *TemplateHandler) listTemplates(w http.ResponseWriter, r *http.Request) {
+	templates, err := h.service.List(r.Context())
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, templates)
+}
+
+func (h *TemplateHandler) getTemplate(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+
+	found, err := h.service.GetByID(r.Context(), id)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, found)
+}
+
+func (h *TemplateHandler) deleteTemplate(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), id); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *TemplateHandler) instantiateTemplate(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+
+	var request instantiateTemplateRequest
+	if err := decodeOptionalJSON(w, r, &request); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	tasks, err := h.service.Instantiate(r.Context(), id, task.InstantiateTemplateInput{
+		Variables:  request.Variables,
+		ReporterID: optionalUserID(r),
+	})
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, instantiateTemplateResponse{Tasks: tasks})
+}
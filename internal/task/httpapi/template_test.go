@@ -0,0 +1,85 @@
+package httpapi
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/task"
+)
+
+type mockTemplateService struct {
+	instantiateID    uint64
+	instantiateInput task.InstantiateTemplateInput
+}
+
+func (m *mockTemplateService) Create(_ context.Context, input task.CreateTemplateInput) (task.Template, error) {
+	return task.Template{ID: 1, Name: input.Name, Task: input.Task}, nil
+}
+
+func (m *mockTemplateService) GetByID(_ context.Context, id uint64) (task.Template, error) {
+	return task.Template{ID: id}, nil
+}
+
+func (m *mockTemplateService) List(_ context.Context) ([]task.Template, error) {
+	return []task.Template{}, nil
+}
+
+func (m *mockTemplateService) Delete(_ context.Context, _ uint64) error {
+	return nil
+}
+
+func (m *mockTemplateService) Instantiate(_ context.Context, id uint64, input task.InstantiateTemplateInput) ([]task.Task, error) {
+	m.instantiateID = id
+	m.instantiateInput = input
+	return []task.Task{{ID: 10}, {ID: 11}}, nil
+}
+
+func TestTemplateHandlerInstantiate(t *testing.T) {
+	svc := &mockTemplateService{}
+	templateHandler := NewTemplateHandler(svc)
+
+	handler := http.NewServeMux()
+	NewHandler(&mockService{restoreResult: task.Task{ID: 1}}, WithTemplates(templateHandler)).Register(handler)
+	templateHandler.Register(handler)
+	NewWatcherHandler(&mockWatcherService{}).Register(handler)
+
+	req := httptest.NewRequest(http.MethodPost, "/tasks/from-template/3", bytes.NewBufferString(`{"variables":{"name":"Alice"}}`))
+	req.Header.Set(userIDHeader, testUserID)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, rec.Code)
+	}
+	if svc.instantiateID != 3 || svc.instantiateInput.Variables["name"] != "Alice" || svc.instantiateInput.ReporterID != testUserID {
+		t.Fatalf("unexpected instantiate call: id=%d input=%+v", svc.instantiateID, svc.instantiateInput)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/tasks/1/restore", nil)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected task routes to be served, got status %d", rec.Code)
+	}
+
+	svc.instantiateID = 0
+	req = httptest.NewRequest(http.MethodGet, "/tasks/from-template/3", nil)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if svc.instantiateID != 0 || rec.Code == http.StatusCreated {
+		t.Fatalf("expected other methods to be served by the task routes, got status %d", rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/tasks/1/unknown", nil)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if svc.instantiateID != 0 || rec.Code != http.StatusNotFound {
+		t.Fatalf("expected unknown task actions to be not found, got status %d", rec.Code)
+	}
+}
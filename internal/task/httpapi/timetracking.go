@@ -0,0 +1,188 @@
+package httpapi
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/task"
+)
+
+type TimeTrackingHandler struct {
+	service task.TimeTrackingService
+}
+
+type startTimerRequest struct {
+	Note string `json:"note"`
+}
+
+type logTimeRequest struct {
+	StartedAt       *taskTime `json:"started_at"`
+	EndedAt         *taskTime `json:"ended_at"`
+	DurationMinutes int       `json:"duration_minutes"`
+	Note            string    `json:"note"`
+}
+
+func NewTimeTrackingHandler(service task.TimeTrackingService) *TimeTrackingHandler {
+	return &TimeTrackingHandler{service: service}
+}
+
+func (h *TimeTrackingHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("POST /tasks/{id}/timer/start", h.startTimer)
+	mux.HandleFunc("POST /tasks/{id}/timer/stop", h.stopTimer)
+	mux.HandleFunc("GET /tasks/{id}/time-entries", h.listTimeEntries)
+	mux.HandleFunc("POST /tasks/{id}/time-entries", h.logTime)
+	mux.HandleFunc("DELETE /tasks/{id}/time-entries/{entry_id}", h.deleteTimeEntry)
+	mux.HandleFunc("GET /reports/time", h.timeReport)
+}
+
+func (h *TimeTrackingHandler) startTimer(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+	userID, ok := requireUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var request startTimerRequest
+	if err := decodeOptionalJSON(w, r, &request); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	entry, err := h.service.StartTimer(r.Context(), id, task.StartTimerInput{
+		UserID: userID,
+		Note:   request.Note,
+	})
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, entry)
+}
+
+func (h *TimeTrackingHandler) stopTimer(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+	userID, ok := requireUserID(w, r)
+	if !ok {
+		return
+	}
+
+	entry, err := h.service.StopTimer(r.Context(), id, userID)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, entry)
+}
+
+func (h *TimeTrackingHandler) listTimeEntries(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+
+	entries, err := h.service.ListTimeEntries(r.Context(), id)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, entries)
+}
+
+func (h *TimeTrackingHandler) logTime(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+	userID, ok := requireUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var request logTimeRequest
+	if err := decodeJSON(w, r, &request); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	startedAt, err := parseOptionalFieldTime("started_at", request.StartedAt)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errorResponse{Error: err.Error(), Field: "started_at"})
+		return
+	}
+	endedAt, err := parseOptionalFieldTime("ended_at", request.EndedAt)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errorResponse{Error: err.Error(), Field: "ended_at"})
+		return
+	}
+
+	entry, err := h.service.LogTime(r.Context(), id, task.LogTimeInput{
+		UserID:          userID,
+		StartedAt:       startedAt,
+		EndedAt:         endedAt,
+		DurationMinutes: request.DurationMinutes,
+		Note:            request.Note,
+	})
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, entry)
+}
+
+func (h *TimeTrackingHandler) deleteTimeEntry(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+	entryID, err := strconv.ParseUint(r.PathValue("entry_id"), 10, 64)
+	if err != nil || entryID == 0 {
+		writeError(w, http.StatusBadRequest, errorResponse{Error: "entry_id must be a positive integer", Field: "entry_id"})
+		return
+	}
+	userID, ok := requireUserID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteTimeEntry(r.Context(), id, entryID, userID); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *TimeTrackingHandler) timeReport(w http.ResponseWriter, r *http.Request) {
+	from, err := parseQueryTime("from", r.URL.Query().Get("from"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errorResponse{Error: err.Error(), Field: "from"})
+		return
+	}
+	to, err := parseQueryTime("to", r.URL.Query().Get("to"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errorResponse{Error: err.Error(), Field: "to"})
+		return
+	}
+
+	report, err := h.service.Report(r.Context(), task.TimeReportInput{
+		UserID: r.URL.Query().Get("user_id"),
+		From:   from,
+		To:     to,
+	})
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, report)
+}
@@ -0,0 +1,173 @@
+package httpapi
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/task"
+)
+
+const testUserID = "7d3f9a52-7c1e-4a59-9b7e-0f6f3c2a1b10"
+
+type mockTimeTrackingService struct {
+	startTaskID uint64
+	startInput  task.StartTimerInput
+	startErr    error
+
+	logInput    task.LogTimeInput
+	reportInput task.TimeReportInput
+
+	// loggedTask models the repository: logging time on it adds to its time
+	// spent and bumps its version.
+	loggedTask *task.Task
+}
+
+func (m *mockTimeTrackingService) StartTimer(_ context.Context, taskID uint64, input task.StartTimerInput) (task.TimeEntry, error) {
+	m.startTaskID = taskID
+	m.startInput = input
+	if m.startErr != nil {
+		return task.TimeEntry{}, m.startErr
+	}
+	return task.TimeEntry{ID: 1, TaskID: taskID, UserID: input.UserID}, nil
+}
+
+func (m *mockTimeTrackingService) StopTimer(_ context.Context, taskID uint64, userID string) (task.TimeEntry, error) {
+	return task.TimeEntry{ID: 1, TaskID: taskID, UserID: userID}, nil
+}
+
+func (m *mockTimeTrackingService) LogTime(_ context.Context, taskID uint64, input task.LogTimeInput) (task.TimeEntry, error) {
+	m.logInput = input
+	if m.loggedTask != nil {
+		m.loggedTask.TimeSpentSeconds += int64(input.DurationMinutes) * 60
+		m.loggedTask.Version++
+	}
+	return task.TimeEntry{ID: 2, TaskID: taskID}, nil
+}
+
+func (m *mockTimeTrackingService) ListTimeEntries(_ context.Context, _ uint64) ([]task.TimeEntry, error) {
+	return []task.TimeEntry{}, nil
+}
+
+func (m *mockTimeTrackingService) DeleteTimeEntry(_ context.Context, _, _ uint64, _ string) error {
+	return nil
+}
+
+func (m *mockTimeTrackingService) Report(_ context.Context, input task.TimeReportInput) ([]task.UserTimeReport, error) {
+	m.reportInput = input
+	return []task.UserTimeReport{}, nil
+}
+
+func TestTimeTrackingHandlerStartTimer(t *testing.T) {
+	svc := &mockTimeTrackingService{}
+	mux := http.NewServeMux()
+	NewTimeTrackingHandler(svc).Register(mux)
+
+	req := httptest.NewRequest(http.MethodPost, "/tasks/5/timer/start", nil)
+	req.Header.Set(userIDHeader, testUserID)
+	rec := httptest.NewRecorder()
+
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, rec.Code)
+	}
+	if svc.startTaskID != 5 || svc.startInput.UserID != testUserID {
+		t.Fatalf("unexpected start call: task=%d input=%+v", svc.startTaskID, svc.startInput)
+	}
+}
+
+func TestTimeTrackingHandlerErrors(t *testing.T) {
+	t.Run("missing user", func(t *testing.T) {
+		mux := http.NewServeMux()
+		NewTimeTrackingHandler(&mockTimeTrackingService{}).Register(mux)
+
+		req := httptest.NewRequest(http.MethodPost, "/tasks/5/timer/start", nil)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("expected status %d, got %d", http.StatusUnauthorized, rec.Code)
+		}
+	})
+
+	t.Run("timer already running", func(t *testing.T) {
+		mux := http.NewServeMux()
+		NewTimeTrackingHandler(&mockTimeTrackingService{startErr: task.ErrTimerAlreadyRunning}).Register(mux)
+
+		req := httptest.NewRequest(http.MethodPost, "/tasks/5/timer/start", bytes.NewBufferString(`{"note":"again"}`))
+		req.Header.Set(userIDHeader, testUserID)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusConflict {
+			t.Fatalf("expected status %d, got %d", http.StatusConflict, rec.Code)
+		}
+	})
+}
+
+func TestTimeTrackingHandlerLogTimeAndReport(t *testing.T) {
+	svc := &mockTimeTrackingService{}
+	mux := http.NewServeMux()
+	NewTimeTrackingHandler(svc).Register(mux)
+
+	req := httptest.NewRequest(http.MethodPost, "/tasks/5/time-entries", bytes.NewBufferString(`{
+		"started_at":"2026-03-04T09:00:00Z",
+		"duration_minutes":45,
+		"note":"pairing"
+	}`))
+	req.Header.Set(userIDHeader, testUserID)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, rec.Code)
+	}
+	if svc.logInput.StartedAt == nil || !svc.logInput.StartedAt.Equal(time.Date(2026, 3, 4, 9, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected started_at: %v", svc.logInput.StartedAt)
+	}
+	if svc.logInput.DurationMinutes != 45 || svc.logInput.UserID != testUserID {
+		t.Fatalf("unexpected log input: %+v", svc.logInput)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/reports/time?user_id="+testUserID+"&from=2026-03-01T00:00:00Z", nil)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	if svc.reportInput.UserID != testUserID || svc.reportInput.From == nil || svc.reportInput.To != nil {
+		t.Fatalf("unexpected report input: %+v", svc.reportInput)
+	}
+}
+
+func TestTimeTrackingHandlerLogTime_ChangesETag(t *testing.T) {
+	tasks := &mockService{getResult: task.Task{ID: 5, Title: "Ship", Version: 3}}
+	mux := http.NewServeMux()
+	NewHandler(tasks).Register(mux)
+	NewTimeTrackingHandler(&mockTimeTrackingService{loggedTask: &tasks.getResult}).Register(mux)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tasks/5", nil))
+	etag := rec.Header().Get("ETag")
+
+	req := httptest.NewRequest(http.MethodPost, "/tasks/5/time-entries", bytes.NewBufferString(`{"duration_minutes":30}`))
+	req.Header.Set(userIDHeader, testUserID)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/tasks/5", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") == etag || !bytes.Contains(rec.Body.Bytes(), []byte(`"time_spent_seconds":1800`)) {
+		t.Fatalf("expected the changed task with a new ETag, got status=%d etag=%s body=%s", rec.Code, rec.Header().Get("ETag"), rec.Body.String())
+	}
+}
@@ -0,0 +1,25 @@
+package httpapi
+
+import (
+	"net/http"
+	"strings"
+)
+
+// userIDHeader identifies the acting user. The API has no authentication of
+// its own and expects a gateway in front of it to set this header.
+const userIDHeader = "X-User-ID"
+
+func requireUserID(w http.ResponseWriter, r *http.Request) (string, bool) {
+	userID := strings.TrimSpace(r.Header.Get(userIDHeader))
+	if userID == "" {
+		writeError(w, http.StatusUnauthorized, errorResponse{Error: userIDHeader + " header is required"})
+		return "", false
+	}
+	return userID, true
+}
+
+// optionalUserID returns the acting user for endpoints that also work
+// anonymously.
+func optionalUserID(r *http.Request) string {
+	return strings.TrimSpace(r.Header.Get(userIDHeader))
+}
@@ -0,0 +1,73 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/task"
+)
+
+type WatcherHandler struct {
+	service task.WatcherService
+}
+
+func NewWatcherHandler(service task.WatcherService) *WatcherHandler {
+	return &WatcherHandler{service: service}
+}
+
+func (h *WatcherHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /tasks/{id}/watchers", h.listWatchers)
+	mux.HandleFunc("POST /tasks/{id}/watch", h.watch)
+	mux.HandleFunc("DELETE /tasks/{id}/watch", h.unwatch)
+}
+
+func (h *WatcherHandler) listWatchers(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+
+	watchers, err := h.service.List(r.Context(), id)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, watchers)
+}
+
+func (h *WatcherHandler) watch(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+	userID, ok := requireUserID(w, r)
+	if !ok {
+		return
+	}
+
+	watchers, err := h.service.Watch(r.Context(), id, userID)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, watchers)
+}
+
+func (h *WatcherHandler) unwatch(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseTaskID(w, r)
+	if !ok {
+		return
+	}
+	userID, ok := requireUserID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Unwatch(r.Context(), id, userID); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,97 @@
+package httpapi
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/task"
+)
+
+type mockWatcherService struct {
+	watchTaskID   uint64
+	watchUserID   string
+	unwatchUserID string
+}
+
+func (m *mockWatcherService) List(_ context.Context, _ uint64) ([]task.Watcher, error) {
+	return []task.Watcher{}, nil
+}
+
+func (m *mockWatcherService) Watch(_ context.Context, taskID uint64, userID string) ([]task.Watcher, error) {
+	m.watchTaskID = taskID
+	m.watchUserID = userID
+	return []task.Watcher{{TaskID: taskID, UserID: userID}}, nil
+}
+
+func (m *mockWatcherService) Unwatch(_ context.Context, _ uint64, userID string) error {
+	m.unwatchUserID = userID
+	return nil
+}
+
+type mockCommentService struct {
+	addTaskID uint64
+	addInput  task.AddCommentInput
+}
+
+func (m *mockCommentService) Add(_ context.Context, taskID uint64, input task.AddCommentInput) (task.Comment, error) {
+	m.addTaskID = taskID
+	m.addInput = input
+	return task.Comment{ID: 1, TaskID: taskID, AuthorID: input.AuthorID, Body: input.Body}, nil
+}
+
+func (m *mockCommentService) List(_ context.Context, _ uint64) ([]task.Comment, error) {
+	return []task.Comment{}, nil
+}
+
+func TestWatcherHandlerWatchAndUnwatch(t *testing.T) {
+	svc := &mockWatcherService{}
+	mux := http.NewServeMux()
+	NewWatcherHandler(svc).Register(mux)
+
+	req := httptest.NewRequest(http.MethodPost, "/tasks/5/watch", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status %d without user, got %d", http.StatusUnauthorized, rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/tasks/5/watch", nil)
+	req.Header.Set(userIDHeader, testUserID)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	if svc.watchTaskID != 5 || svc.watchUserID != testUserID {
+		t.Fatalf("unexpected watch call: task=%d user=%q", svc.watchTaskID, svc.watchUserID)
+	}
+
+	req = httptest.NewRequest(http.MethodDelete, "/tasks/5/watch", nil)
+	req.Header.Set(userIDHeader, testUserID)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNoContent || svc.unwatchUserID != testUserID {
+		t.Fatalf("unexpected unwatch: status=%d user=%q", rec.Code, svc.unwatchUserID)
+	}
+}
+
+func TestCommentHandlerAddComment(t *testing.T) {
+	svc := &mockCommentService{}
+	mux := http.NewServeMux()
+	NewCommentHandler(svc).Register(mux)
+
+	req := httptest.NewRequest(http.MethodPost, "/tasks/8/comments", bytes.NewBufferString(`{"body":"Looks good"}`))
+	req.Header.Set(userIDHeader, testUserID)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, rec.Code)
+	}
+	if svc.addTaskID != 8 || svc.addInput.AuthorID != testUserID || svc.addInput.Body != "Looks good" {
+		t.Fatalf("unexpected add call: task=%d input=%+v", svc.addTaskID, svc.addInput)
+	}
+}
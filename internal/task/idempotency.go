@@ -0,0 +1,151 @@
+package task
+
+import (
+	"context"
+	"time"
+)
+
+const maxIdempotencyKeyLength = 255
+
+// IdempotentResponse is the response stored for a request made with an
+// idempotency key.
+type IdempotentResponse struct {
+	Status int
+	Body   []byte
+}
+
+// IdempotencyRecord.Response is nil while the first request with the key is
+// still in progress. Fingerprint identifies the request body.
+type IdempotencyRecord struct {
+	UserID      string
+	Key         string
+	Fingerprint string
+	Response    *IdempotentResponse
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
+
+// IdempotencyReservation identifies the reservation of a key made by Begin.
+// CreatedAt tells it apart from a later reservation of the same key.
+type IdempotencyReservation struct {
+	UserID      string
+	Key         string
+	Fingerprint string
+	CreatedAt   time.Time
+}
+
+// Reclaimable reports whether a new request may take over the key because
+// the record expired. A record whose request has not completed is held until
+// then too, since that request may still be running.
+func (r IdempotencyRecord) Reclaimable(now time.Time) bool {
+	return !now.Before(r.ExpiresAt)
+}
+
+type IdempotencyRepository interface {
+	RunInTx(ctx context.Context, fn func(ctx context.Context) error) error
+	// ReserveIdempotencyKey stores the record unless the key is held by a
+	// record that is not reclaimable at record.CreatedAt; that record is
+	// returned instead.
+	ReserveIdempotencyKey(ctx context.Context, record IdempotencyRecord) (*IdempotencyRecord, error)
+	// CompleteIdempotencyKey and ReleaseIdempotencyKey return
+	// ErrIdempotencyKeyLost if the key is no longer held by the reservation.
+	CompleteIdempotencyKey(ctx context.Context, reservation IdempotencyReservation, response IdempotentResponse) error
+	ReleaseIdempotencyKey(ctx context.Context, reservation IdempotencyReservation) error
+}
+
+// IdempotencyService makes retried requests safe: the first request with a
+// key is executed and its response stored, later ones with the same key and
+// body get the stored response. Keys are scoped to the acting user.
+type IdempotencyService interface {
+	// Begin returns the stored response if the key was already used for the
+	// same request. Otherwise it reserves the key, and the caller should
+	// execute the request with Complete, or Release the key.
+	Begin(ctx context.Context, userID, key, fingerprint string) (IdempotencyReservation, *IdempotentResponse, error)
+	// Complete runs execute and stores the response it returns in one
+	// transaction, so nothing execute writes is kept unless the response is
+	// stored for the reservation.
+	Complete(ctx context.Context, reservation IdempotencyReservation, execute func(ctx context.Context) (IdempotentResponse, error)) (IdempotentResponse, error)
+	Release(ctx context.Context, reservation IdempotencyReservation) error
+}
+
+type idempotencyService struct {
+	repo IdempotencyRepository
+	ttl  time.Duration
+	now  func() time.Time
+}
+
+func NewIdempotencyService(repo IdempotencyRepository, ttl time.Duration) IdempotencyService {
+	return &idempotencyService{repo: repo, ttl: ttl, now: time.Now}
+}
+
+func (s *idempotencyService) Begin(ctx context.Context, userID, key, fingerprint string) (IdempotencyReservation, *IdempotentResponse, error) {
+	if err := validateIdempotencyKey(key); err != nil {
+		return IdempotencyReservation{}, nil, err
+	}
+
+	// The reservation is matched on created_at later, which is stored with
+	// microsecond precision.
+	now := s.now().UTC().Truncate(time.Microsecond)
+	reservation := IdempotencyReservation{UserID: userID, Key: key, Fingerprint: fingerprint, CreatedAt: now}
+	existing, err := s.repo.ReserveIdempotencyKey(ctx, IdempotencyRecord{
+		UserID:      userID,
+		Key:         key,
+		Fingerprint: fingerprint,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(s.ttl),
+	})
+	if err != nil {
+		return IdempotencyReservation{}, nil, err
+	}
+	if existing == nil {
+		return reservation, nil, nil
+	}
+
+	if existing.Fingerprint != fingerprint {
+		return IdempotencyReservation{}, nil, ErrIdempotencyKeyReused
+	}
+	if existing.Response == nil {
+		return IdempotencyReservation{}, nil, ErrIdempotencyKeyInProgress
+	}
+	return IdempotencyReservation{}, existing.Response, nil
+}
+
+// Complete holds back the notifications sent by execute until the
+// transaction is committed.
+func (s *idempotencyService) Complete(ctx context.Context, reservation IdempotencyReservation, execute func(ctx context.Context) (IdempotentResponse, error)) (IdempotentResponse, error) {
+	var response IdempotentResponse
+	pending := &pendingNotifications{}
+	err := s.repo.RunInTx(withPendingNotifications(ctx, pending), func(ctx context.Context) error {
+		var err error
+		response, err = execute(ctx)
+		if err != nil {
+			return err
+		}
+		return s.repo.CompleteIdempotencyKey(ctx, reservation, response)
+	})
+	if err != nil {
+		return IdempotentResponse{}, err
+	}
+
+	pending.send(ctx)
+	return response, nil
+}
+
+func (s *idempotencyService) Release(ctx context.Context, reservation IdempotencyReservation) error {
+	return s.repo.ReleaseIdempotencyKey(ctx, reservation)
+}
+
+func validateIdempotencyKey(key string) error {
+	if key == "" {
+		return ValidationError{Field: "Idempotency-Key", Message: "must not be empty"}
+	}
+	if len(key) > maxIdempotencyKeyLength {
+		return ValidationError{Field: "Idempotency-Key", Message: "must be at most 255 characters"}
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x21 || key[i] > 0x7e {
+			return ValidationError{Field: "Idempotency-Key", Message: "must contain printable ASCII characters only"}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/notification"
+)
+
+type mockIdempotencyRepository struct {
+	reserved *IdempotencyRecord
+	existing *IdempotencyRecord
+
+	completeErr error
+	completed   *IdempotencyReservation
+	inTx        bool
+	txErr       error
+}
+
+func (m *mockIdempotencyRepository) RunInTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.inTx = true
+	m.txErr = fn(ctx)
+	m.inTx = false
+	return m.txErr
+}
+
+func (m *mockIdempotencyRepository) ReserveIdempotencyKey(_ context.Context, record IdempotencyRecord) (*IdempotencyRecord, error) {
+	m.reserved = &record
+	return m.existing, nil
+}
+
+func (m *mockIdempotencyRepository) CompleteIdempotencyKey(_ context.Context, reservation IdempotencyReservation, _ IdempotentResponse) error {
+	if !m.inTx {
+		return errors.New("completed outside of a transaction")
+	}
+	if m.completeErr != nil {
+		return m.completeErr
+	}
+	m.completed = &reservation
+	return nil
+}
+
+func (m *mockIdempotencyRepository) ReleaseIdempotencyKey(context.Context, IdempotencyReservation) error {
+	return nil
+}
+
+func TestIdempotencyServiceBegin(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 1500, time.UTC)
+	stored := &IdempotentResponse{Status: 201, Body: []byte(`{"id":1}`)}
+
+	tests := []struct {
+		name     string
+		existing *IdempotencyRecord
+		response *IdempotentResponse
+		err      error
+	}{
+		{name: "new key"},
+		{name: "replay", existing: &IdempotencyRecord{Fingerprint: "abc", Response: stored}, response: stored},
+		{name: "different request", existing: &IdempotencyRecord{Fingerprint: "other", Response: stored}, err: ErrIdempotencyKeyReused},
+		{name: "in progress", existing: &IdempotencyRecord{Fingerprint: "abc"}, err: ErrIdempotencyKeyInProgress},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockIdempotencyRepository{existing: tc.existing}
+			svc := &idempotencyService{repo: repo, ttl: time.Hour, now: func() time.Time { return now }}
+
+			reservation, response, err := svc.Begin(context.Background(), "user-1", "key-1", "abc")
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("expected error %v, got %v", tc.err, err)
+			}
+			if response != tc.response {
+				t.Fatalf("unexpected response: %+v", response)
+			}
+			createdAt := now.Truncate(time.Microsecond)
+			if repo.reserved == nil || repo.reserved.UserID != "user-1" || !repo.reserved.CreatedAt.Equal(createdAt) || !repo.reserved.ExpiresAt.Equal(createdAt.Add(time.Hour)) {
+				t.Fatalf("unexpected reservation: %+v", repo.reserved)
+			}
+			if tc.existing == nil && (reservation.Fingerprint != "abc" || !reservation.CreatedAt.Equal(createdAt)) {
+				t.Fatalf("expected the reservation of the new key, got %+v", reservation)
+			}
+		})
+	}
+}
+
+func TestIdempotencyServiceBegin_InvalidKey(t *testing.T) {
+	svc := NewIdempotencyService(&mockIdempotencyRepository{}, time.Hour)
+
+	for _, key := range []string{"", "has space", strings.Repeat("k", 256)} {
+		_, _, err := svc.Begin(context.Background(), "", key, "abc")
+		var validationErr ValidationError
+		if !errors.As(err, &validationErr) || validationErr.Field != "Idempotency-Key" {
+			t.Fatalf("expected Idempotency-Key validation error for %q, got %v", key, err)
+		}
+	}
+}
+
+func TestIdempotencyServiceComplete(t *testing.T) {
+	reservation := IdempotencyReservation{UserID: "user-1", Key: "key-1", Fingerprint: "abc"}
+	notifier := &mockNotifier{}
+	execute := func(ctx context.Context) (IdempotentResponse, error) {
+		notify(ctx, notifier, notification.Notification{TaskID: 1})
+		return IdempotentResponse{Status: 201, Body: []byte(`{"id":1}`)}, nil
+	}
+
+	repo := &mockIdempotencyRepository{}
+	svc := NewIdempotencyService(repo, time.Hour)
+	response, err := svc.Complete(context.Background(), reservation, execute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.Status != 201 || repo.completed == nil || *repo.completed != reservation {
+		t.Fatalf("expected the response to be stored for the reservation, got %+v and %+v", response, repo.completed)
+	}
+	if len(notifier.notifications) != 1 {
+		t.Fatalf("expected the notification after commit, got %d", len(notifier.notifications))
+	}
+
+	notifier.notifications = nil
+	repo = &mockIdempotencyRepository{completeErr: ErrIdempotencyKeyLost}
+	_, err = NewIdempotencyService(repo, time.Hour).Complete(context.Background(), reservation, execute)
+	if !errors.Is(err, ErrIdempotencyKeyLost) || !errors.Is(repo.txErr, ErrIdempotencyKeyLost) {
+		t.Fatalf("expected the transaction to fail with a lost key, got %v", err)
+	}
+	if len(notifier.notifications) != 0 {
+		t.Fatalf("expected no notification of a rolled back request, got %d", len(notifier.notifications))
+	}
+}
+
+func TestIdempotencyRecordReclaimable(t *testing.T) {
+	createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	pending := IdempotencyRecord{CreatedAt: createdAt, ExpiresAt: createdAt.Add(time.Hour)}
+	completed := pending
+	completed.Response = &IdempotentResponse{Status: 201}
+
+	if pending.Reclaimable(createdAt.Add(59 * time.Minute)) {
+		t.Fatal("expected a pending record to be held until it expires")
+	}
+	if completed.Reclaimable(createdAt.Add(59 * time.Minute)) {
+		t.Fatal("expected a completed record to be held until it expires")
+	}
+	if !pending.Reclaimable(createdAt.Add(time.Hour)) || !completed.Reclaimable(createdAt.Add(time.Hour)) {
+		t.Fatal("expected an expired record to be reclaimable")
+	}
+}
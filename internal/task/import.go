@@ -0,0 +1,570 @@
+package task
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/job"
+)
+
+const (
+	maxImportRows = 10000
+	// ImportJobType is the job type of imports run in the background.
+	ImportJobType = "task.import"
+)
+
+type ImportFormat string
+
+const (
+	ImportCSV  ImportFormat = "csv"
+	ImportJSON ImportFormat = "json"
+)
+
+// ParseImportFormat defaults to CSV.
+func ParseImportFormat(raw string) (ImportFormat, error) {
+	switch format := ImportFormat(strings.ToLower(strings.TrimSpace(raw))); format {
+	case "":
+		return ImportCSV, nil
+	case ImportCSV, ImportJSON:
+		return format, nil
+	default:
+		return "", ValidationError{Field: "format", Message: "must be one of: csv, json"}
+	}
+}
+
+// ImportInput.Content is a CSV file with a header row or a JSON array of
+// objects. Mapping maps CSV columns and JSON keys to task fields; a column
+// named like a task field is imported into it unless Mapping says otherwise,
+// a column mapped to "" is ignored, and so are all other columns. ReporterID
+// is the reporter of rows without a reporter_id. DryRun validates every row
+// like a real import, including the checks against stored data, but creates
+// nothing, so it cannot catch what only the database would reject.
+type ImportInput struct {
+	Format     string
+	Content    string
+	Mapping    map[string]string
+	DryRun     bool
+	ReporterID string
+}
+
+type ImportRowStatus string
+
+const (
+	ImportRowCreated ImportRowStatus = "created"
+	ImportRowSkipped ImportRowStatus = "skipped"
+	ImportRowFailed  ImportRowStatus = "failed"
+)
+
+// ImportRowResult.Row numbers the rows from 1 without the CSV header; Line is
+// the line of the file a CSV row starts on. In a dry run created means the
+// row would be created, and TaskID is not set.
+type ImportRowResult struct {
+	Row    int             `json:"row"`
+	Line   int             `json:"line,omitempty"`
+	Status ImportRowStatus `json:"status"`
+	TaskID uint64          `json:"task_id,omitempty"`
+	Title  string          `json:"title,omitempty"`
+	Error  string          `json:"error,omitempty"`
+	Field  string          `json:"field,omitempty"`
+}
+
+type ImportResult struct {
+	DryRun  bool              `json:"dry_run"`
+	Created int               `json:"created"`
+	Skipped int               `json:"skipped"`
+	Failed  int               `json:"failed"`
+	Rows    []ImportRowResult `json:"rows"`
+}
+
+// ImportSubmission holds the result of an import, or the queued job if the
+// import was moved to the background.
+type ImportSubmission struct {
+	Result *ImportResult
+	Job    *job.Job
+}
+
+type ImportService interface {
+	// Submit imports the rows, or queues the import as a job if there are
+	// more rows than the async threshold. A file that cannot be read is
+	// rejected as a whole; a row that cannot be imported only fails itself.
+	Submit(ctx context.Context, input ImportInput) (ImportSubmission, error)
+	Run(ctx context.Context, input ImportInput) (ImportResult, error)
+}
+
+type importService struct {
+	tasks          Service
+	jobs           job.Service
+	asyncThreshold int
+}
+
+// NewImportService creates every row through tasks, so rows are validated
+// like single tasks. tasks should not send notifications: imported tasks are
+// usually old ones.
+func NewImportService(tasks Service, jobs job.Service, asyncThreshold int) ImportService {
+	return &importService{tasks: tasks, jobs: jobs, asyncThreshold: asyncThreshold}
+}
+
+// NewImportJobHandler runs the imports queued by Submit.
+func NewImportJobHandler(service ImportService) job.Handler {
+	return func(ctx context.Context, payload json.RawMessage) (any, error) {
+		var input ImportInput
+		if err := json.Unmarshal(payload, &input); err != nil {
+			return nil, err
+		}
+		return service.Run(ctx, input)
+	}
+}
+
+func (s *importService) Submit(ctx context.Context, input ImportInput) (ImportSubmission, error) {
+	rows, err := parseImport(input)
+	if err != nil {
+		return ImportSubmission{}, err
+	}
+
+	if s.jobs != nil && s.asyncThreshold > 0 && len(rows) > s.asyncThreshold {
+		queued, err := s.jobs.Enqueue(ctx, ImportJobType, input)
+		if err != nil {
+			return ImportSubmission{}, err
+		}
+		return ImportSubmission{Job: &queued}, nil
+	}
+
+	result, err := s.run(ctx, input.DryRun, rows)
+	if err != nil {
+		return ImportSubmission{}, err
+	}
+	return ImportSubmission{Result: &result}, nil
+}
+
+func (s *importService) Run(ctx context.Context, input ImportInput) (ImportResult, error) {
+	rows, err := parseImport(input)
+	if err != nil {
+		return ImportResult{}, err
+	}
+	return s.run(ctx, input.DryRun, rows)
+}
+
+func (s *importService) run(ctx context.Context, dryRun bool, rows []importRow) (ImportResult, error) {
+	result := ImportResult{DryRun: dryRun, Rows: make([]ImportRowResult, len(rows))}
+	for i, row := range rows {
+		result.Rows[i] = s.importRow(ctx, row, dryRun)
+	}
+	result.count()
+	return result, nil
+}
+
+func (s *importService) importRow(ctx context.Context, row importRow, dryRun bool) ImportRowResult {
+	item := ImportRowResult{Row: row.row, Line: row.line, Title: strings.TrimSpace(row.input.Title)}
+	switch {
+	case row.blank:
+		item.Status = ImportRowSkipped
+		return item
+	case row.err != nil:
+		item.Status = ImportRowFailed
+		item.Error, item.Field = bulkItemError(row.err)
+		return item
+	}
+
+	if dryRun {
+		if err := s.tasks.ValidateCreate(ctx, row.input); err != nil {
+			item.Status = ImportRowFailed
+			item.Error, item.Field = bulkItemError(err)
+			return item
+		}
+		item.Status = ImportRowCreated
+		return item
+	}
+
+	created, err := s.tasks.Create(ctx, row.input)
+	if err != nil {
+		item.Status = ImportRowFailed
+		item.Error, item.Field = bulkItemError(err)
+		return item
+	}
+
+	item.Status = ImportRowCreated
+	item.TaskID = created.ID
+	return item
+}
+
+func (r *ImportResult) count() {
+	for _, row := range r.Rows {
+		switch row.Status {
+		case ImportRowCreated:
+			r.Created++
+		case ImportRowSkipped:
+			r.Skipped++
+		case ImportRowFailed:
+			r.Failed++
+		}
+	}
+}
+
+// importRow is a row of an import file; err is set if it could not be
+// converted into a task, and blank if it has no value for any task field.
+type importRow struct {
+	row   int
+	line  int
+	input CreateTaskInput
+	blank bool
+	err   error
+}
+
+func parseImport(input ImportInput) ([]importRow, error) {
+	format, err := ParseImportFormat(input.Format)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateImportMapping(input.Mapping); err != nil {
+		return nil, err
+	}
+
+	var rows []importRow
+	switch format {
+	case ImportJSON:
+		rows, err = parseJSONImport(input.Content, input.Mapping)
+	default:
+		rows, err = parseCSVImport(input.Content, input.Mapping)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return nil, ValidationError{Field: "content", Message: "must contain at least one row"}
+	}
+	for i := range rows {
+		if rows[i].input.ReporterID == "" {
+			rows[i].input.ReporterID = input.ReporterID
+		}
+	}
+	return rows, nil
+}
+
+func parseCSVImport(content string, mapping map[string]string) ([]importRow, error) {
+	// Spreadsheet applications often start UTF-8 files with a byte order mark.
+	reader := csv.NewReader(strings.NewReader(strings.TrimPrefix(content, "\ufeff")))
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, ValidationError{Field: "content", Message: "must start with a header row"}
+	}
+	if err != nil {
+		return nil, csvImportError(err)
+	}
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+	}
+
+	fields, err := importColumns(header, mapping)
+	if err != nil {
+		return nil, err
+	}
+	for column := range mapping {
+		if !slices.Contains(header, column) {
+			return nil, ValidationError{Field: "mapping." + column, Message: "is not a column of the file"}
+		}
+	}
+
+	rows := make([]importRow, 0)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, csvImportError(err)
+		}
+		if len(rows) == maxImportRows {
+			return nil, tooManyImportRows()
+		}
+
+		line, _ := reader.FieldPos(0)
+		row := importRow{row: len(rows) + 1, line: line}
+		if len(record) != len(header) {
+			row.err = ValidationError{Message: fmt.Sprintf("has %d columns, the header has %d", len(record), len(header))}
+		} else {
+			row.blank = true
+			for i, field := range fields {
+				if field == "" {
+					continue
+				}
+				if strings.TrimSpace(record[i]) != "" {
+					row.blank = false
+				}
+				if err := importFields[field](&row.input, record[i]); err != nil && row.err == nil {
+					row.err = err
+				}
+			}
+		}
+		rows = append(rows, row)
+	}
+}
+
+func parseJSONImport(content string, mapping map[string]string) ([]importRow, error) {
+	var objects []json.RawMessage
+	if err := json.Unmarshal([]byte(content), &objects); err != nil {
+		return nil, ValidationError{Field: "content", Message: "must be a JSON array of objects"}
+	}
+	if len(objects) > maxImportRows {
+		return nil, tooManyImportRows()
+	}
+
+	rows := make([]importRow, 0, len(objects))
+	for i, raw := range objects {
+		row := importRow{row: i + 1}
+		row.blank, row.err = convertJSONImportRow(raw, mapping, &row.input)
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func convertJSONImportRow(raw json.RawMessage, mapping map[string]string, input *CreateTaskInput) (bool, error) {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &object); err != nil {
+		return false, ValidationError{Message: "must be a JSON object"}
+	}
+
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	fields, err := importColumns(keys, mapping)
+	if err != nil {
+		return false, err
+	}
+
+	blank := true
+	for i, field := range fields {
+		if field == "" {
+			continue
+		}
+		value := object[keys[i]]
+		switch trimmed := strings.TrimSpace(string(value)); {
+		case trimmed == "null", trimmed == `""`:
+		case field == "labels" && strings.HasPrefix(trimmed, "["):
+			blank = false
+			if err := json.Unmarshal(value, &input.Labels); err != nil {
+				return false, ValidationError{Field: field, Message: "must be a list of strings"}
+			}
+			continue
+		case field == "custom_fields" && strings.HasPrefix(trimmed, "{"):
+			blank = false
+			if err := importFields[field](input, trimmed); err != nil {
+				return false, err
+			}
+			continue
+		default:
+			blank = false
+		}
+
+		text, err := jsonImportText(value)
+		if err != nil {
+			return false, ValidationError{Field: field, Message: err.Error()}
+		}
+		if err := importFields[field](input, text); err != nil {
+			return false, err
+		}
+	}
+	return blank, nil
+}
+
+// jsonImportText converts a JSON value into the text a CSV cell would hold.
+func jsonImportText(value json.RawMessage) (string, error) {
+	trimmed := strings.TrimSpace(string(value))
+	switch {
+	case trimmed == "null":
+		return "", nil
+	case strings.HasPrefix(trimmed, `"`):
+		var text string
+		err := json.Unmarshal(value, &text)
+		return text, err
+	case strings.HasPrefix(trimmed, "{"), strings.HasPrefix(trimmed, "["):
+		return "", errors.New("must be a string or a number")
+	default:
+		return trimmed, nil
+	}
+}
+
+// importColumns resolves the task field of every column; "" means the column
+// is ignored.
+func importColumns(columns []string, mapping map[string]string) ([]string, error) {
+	fields := make([]string, len(columns))
+	mappedFrom := make(map[string]string, len(columns))
+	for i, column := range columns {
+		field, ok := mapping[column]
+		if !ok {
+			if _, known := importFields[column]; known {
+				field = column
+			}
+		}
+		if field == "" {
+			continue
+		}
+		if previous, ok := mappedFrom[field]; ok {
+			return nil, ValidationError{Field: "mapping", Message: fmt.Sprintf("columns %q and %q are both mapped to %s", previous, column, field)}
+		}
+		mappedFrom[field] = column
+		fields[i] = field
+	}
+	return fields, nil
+}
+
+func validateImportMapping(mapping map[string]string) error {
+	for column, field := range mapping {
+		if _, ok := importFields[field]; !ok && field != "" {
+			return ValidationError{Field: "mapping." + column, Message: "must be one of: " + strings.Join(importFieldNames(), ", ")}
+		}
+	}
+	return nil
+}
+
+// importFields sets a task field from the text of a CSV cell. The text of a
+// CSV export can be imported as is.
+var importFields = map[string]func(input *CreateTaskInput, value string) error{
+	"title": func(input *CreateTaskInput, value string) error {
+		input.Title = spreadsheetText(value)
+		return nil
+	},
+	"description": func(input *CreateTaskInput, value string) error {
+		input.Description = spreadsheetText(value)
+		return nil
+	},
+	"status": func(input *CreateTaskInput, value string) error {
+		input.Status = strings.TrimSpace(value)
+		return nil
+	},
+	"priority": func(input *CreateTaskInput, value string) error {
+		priority, err := parseImportInt("priority", value)
+		if err != nil || priority == nil {
+			return err
+		}
+		input.Priority = *priority
+		return nil
+	},
+	"project_id": func(input *CreateTaskInput, value string) (err error) {
+		input.ProjectID, err = parseImportID("project_id", value)
+		return err
+	},
+	"sprint_id": func(input *CreateTaskInput, value string) (err error) {
+		input.SprintID, err = parseImportID("sprint_id", value)
+		return err
+	},
+	"milestone_id": func(input *CreateTaskInput, value string) (err error) {
+		input.MilestoneID, err = parseImportID("milestone_id", value)
+		return err
+	},
+	"reporter_id": func(input *CreateTaskInput, value string) error {
+		input.ReporterID = strings.TrimSpace(value)
+		return nil
+	},
+	"assignee_id": func(input *CreateTaskInput, value string) error {
+		if assigneeID := strings.TrimSpace(value); assigneeID != "" {
+			input.AssigneeID = &assigneeID
+		}
+		return nil
+	},
+	"labels": func(input *CreateTaskInput, value string) error {
+		input.Labels = nil
+		for _, label := range strings.Split(spreadsheetText(value), ";") {
+			if label = strings.TrimSpace(label); label != "" {
+				input.Labels = append(input.Labels, label)
+			}
+		}
+		return nil
+	},
+	"estimate_minutes": func(input *CreateTaskInput, value string) (err error) {
+		input.EstimateMinutes, err = parseImportInt("estimate_minutes", value)
+		return err
+	},
+	"story_points": func(input *CreateTaskInput, value string) (err error) {
+		input.StoryPoints, err = parseImportInt("story_points", value)
+		return err
+	},
+	"due_at": func(input *CreateTaskInput, value string) error {
+		if value = strings.TrimSpace(value); value == "" {
+			return nil
+		}
+		dueAt, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return ValidationError{Field: "due_at", Message: "must be a valid RFC3339 timestamp"}
+		}
+		input.DueAt = &dueAt
+		return nil
+	},
+	"recurrence_rule": func(input *CreateTaskInput, value string) error {
+		input.RecurrenceRule = strings.TrimSpace(value)
+		return nil
+	},
+	"custom_fields": func(input *CreateTaskInput, value string) error {
+		if value = strings.TrimSpace(value); value == "" {
+			return nil
+		}
+		if err := json.Unmarshal([]byte(value), &input.CustomFields); err != nil || input.CustomFields == nil {
+			return ValidationError{Field: "custom_fields", Message: "must be a JSON object"}
+		}
+		return nil
+	},
+}
+
+func importFieldNames() []string {
+	names := make([]string, 0, len(importFields))
+	for name := range importFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func parseImportInt(field, value string) (*int, error) {
+	if value = strings.TrimSpace(value); value == "" {
+		return nil, nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, ValidationError{Field: field, Message: "must be an integer"}
+	}
+	return &parsed, nil
+}
+
+func parseImportID(field, value string) (*uint64, error) {
+	if value = strings.TrimSpace(value); value == "" {
+		return nil, nil
+	}
+	parsed, err := strconv.ParseUint(value, 10, 64)
+	if err != nil || parsed == 0 {
+		return nil, ValidationError{Field: field, Message: "must be a positive integer"}
+	}
+	return &parsed, nil
+}
+
+// spreadsheetText undoes spreadsheetSafe.
+func spreadsheetText(value string) string {
+	if len(value) > 1 && value[0] == '\'' && strings.ContainsRune("=+-@\t\r", rune(value[1])) {
+		return value[1:]
+	}
+	return value
+}
+
+func csvImportError(err error) error {
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return ValidationError{Field: "content", Message: fmt.Sprintf("line %d: %v", parseErr.Line, parseErr.Err)}
+	}
+	return err
+}
+
+func tooManyImportRows() error {
+	return ValidationError{Field: "content", Message: fmt.Sprintf("must contain at most %d rows", maxImportRows)}
+}
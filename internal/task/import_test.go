@@ -0,0 +1,177 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func importStatuses(result ImportResult) []ImportRowStatus {
+	statuses := make([]ImportRowStatus, 0, len(result.Rows))
+	for _, row := range result.Rows {
+		statuses = append(statuses, row.Status)
+	}
+	return statuses
+}
+
+func TestImportServiceRun_CSV(t *testing.T) {
+	tasks := &mockBulkTaskService{createErrs: map[string]error{
+		"Taken": ErrProjectNotFound,
+	}}
+	svc := NewImportService(tasks, nil, 0)
+
+	content := "\ufeffSummary,Details,priority,labels,due_at,Legacy ID\n" +
+		"Ship release,\"multi\nline\",5,backend; release,2026-03-10T12:00:00Z,OLD-1\n" +
+		"'=SUM(A1),,,,,OLD-2\n" +
+		",,,,,OLD-3\n" +
+		"Broken,,high,,,OLD-4\n" +
+		"Late,,,,tomorrow,OLD-5\n" +
+		"Taken,,,,,OLD-6\n" +
+		"Short,row\n"
+
+	result, err := svc.Run(context.Background(), ImportInput{
+		Content:    content,
+		Mapping:    map[string]string{"Summary": "title", "Details": "description", "Legacy ID": ""},
+		ReporterID: "3b241101-e2bb-4255-8caf-4136c566a962",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []ImportRowStatus{ImportRowCreated, ImportRowCreated, ImportRowSkipped, ImportRowFailed, ImportRowFailed, ImportRowFailed, ImportRowFailed}
+	if !reflect.DeepEqual(importStatuses(result), expected) {
+		t.Fatalf("unexpected statuses: %v", importStatuses(result))
+	}
+	if result.Created != 2 || result.Skipped != 1 || result.Failed != 4 || result.DryRun {
+		t.Fatalf("unexpected counts: %+v", result)
+	}
+	if !reflect.DeepEqual(tasks.created, []string{"Ship release", "=SUM(A1)"}) {
+		t.Fatalf("unexpected created tasks: %v", tasks.created)
+	}
+
+	first := result.Rows[0]
+	if first.Row != 1 || first.Line != 2 || first.TaskID != 101 {
+		t.Fatalf("unexpected first row: %+v", first)
+	}
+	if result.Rows[2].Line != 5 {
+		t.Fatalf("expected rows to keep their line after a multiline cell, got %d", result.Rows[2].Line)
+	}
+	for i, field := range map[int]string{3: "priority", 4: "due_at", 5: "", 6: ""} {
+		if row := result.Rows[i]; row.Field != field || row.Error == "" {
+			t.Fatalf("unexpected error of row %d: %+v", row.Row, row)
+		}
+	}
+}
+
+func TestImportServiceRun_JSON(t *testing.T) {
+	tasks := &mockBulkTaskService{createErrs: map[string]error{
+		"": ValidationError{Field: "title", Message: "must not be empty"},
+	}}
+	svc := NewImportService(tasks, nil, 0)
+
+	exported, _ := json.Marshal(exportedTasks()[:1])
+	for _, content := range []string{
+		string(exported),
+		`[{"name": "Triage", "labels": ["ops"], "priority": 2, "custom_fields": {"team": "core"}, "project_id": 1}, {"title": null}, 7, {"title": ""}, {"name": "Bad", "labels": "a;b", "priority": [1]}]`,
+	} {
+		tasks.created = nil
+		result, err := svc.Run(context.Background(), ImportInput{Format: "json", Content: content, Mapping: map[string]string{"name": "title"}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(result.Rows) == 1 {
+			if result.Created != 1 || tasks.created[0] != exportedTasks()[0].Title {
+				t.Fatalf("expected an exported task to be imported, got %+v", result)
+			}
+			continue
+		}
+
+		expected := []ImportRowStatus{ImportRowCreated, ImportRowSkipped, ImportRowFailed, ImportRowSkipped, ImportRowFailed}
+		if !reflect.DeepEqual(importStatuses(result), expected) {
+			t.Fatalf("unexpected statuses: %v", importStatuses(result))
+		}
+		if result.Rows[4].Field != "priority" || result.Rows[2].Error != "must be a JSON object" {
+			t.Fatalf("unexpected row errors: %+v", result.Rows)
+		}
+	}
+}
+
+func TestImportServiceRun_DryRun(t *testing.T) {
+	tasks := &mockBulkTaskService{createErrs: map[string]error{"Second": ErrSprintNotFound}}
+	svc := NewImportService(tasks, nil, 0)
+
+	result, err := svc.Run(context.Background(), ImportInput{Content: "title\nFirst\nSecond\n", DryRun: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tasks.created) != 0 || !reflect.DeepEqual(tasks.validated, []string{"First", "Second"}) {
+		t.Fatalf("expected the rows to be validated only, got created %v and validated %v", tasks.created, tasks.validated)
+	}
+	if !result.DryRun || result.Created != 1 || result.Failed != 1 || result.Rows[0].TaskID != 0 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+}
+
+func TestImportServiceSubmit_InvalidFile(t *testing.T) {
+	tests := []struct {
+		name  string
+		input ImportInput
+		field string
+	}{
+		{name: "format", input: ImportInput{Format: "xlsx", Content: "title\nA\n"}, field: "format"},
+		{name: "mapping target", input: ImportInput{Content: "Summary\nA\n", Mapping: map[string]string{"Summary": "name"}}, field: "mapping.Summary"},
+		{name: "mapping column", input: ImportInput{Content: "title\nA\n", Mapping: map[string]string{"Summary": "title"}}, field: "mapping.Summary"},
+		{name: "duplicate field", input: ImportInput{Content: "title,Summary\nA,B\n", Mapping: map[string]string{"Summary": "title"}}, field: "mapping"},
+		{name: "empty", input: ImportInput{Content: ""}, field: "content"},
+		{name: "header only", input: ImportInput{Content: "title\n"}, field: "content"},
+		{name: "malformed csv", input: ImportInput{Content: "title\n\"unterminated\n"}, field: "content"},
+		{name: "malformed json", input: ImportInput{Format: "json", Content: `{"title": "A"}`}, field: "content"},
+		{name: "too many rows", input: ImportInput{Content: "title\n" + strings.Repeat("A\n", maxImportRows+1)}, field: "content"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tasks := &mockBulkTaskService{}
+			_, err := NewImportService(tasks, nil, 0).Submit(context.Background(), tc.input)
+			var validationErr ValidationError
+			if !errors.As(err, &validationErr) || validationErr.Field != tc.field {
+				t.Fatalf("expected %s validation error, got %v", tc.field, err)
+			}
+			if len(tasks.created) != 0 {
+				t.Fatalf("expected nothing to be created, got %v", tasks.created)
+			}
+		})
+	}
+}
+
+func TestImportServiceSubmit_Async(t *testing.T) {
+	tasks := &mockBulkTaskService{}
+	jobs := &mockJobService{}
+	svc := NewImportService(tasks, jobs, 2)
+
+	submission, err := svc.Submit(context.Background(), ImportInput{Content: "title\nA\nB\n"})
+	if err != nil || submission.Result == nil || submission.Result.Created != 2 {
+		t.Fatalf("expected an import at the threshold to run, got %+v (%v)", submission, err)
+	}
+
+	input := ImportInput{Content: "title\nA\nB\nC\n", ReporterID: "3b241101-e2bb-4255-8caf-4136c566a962"}
+	submission, err = svc.Submit(context.Background(), input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if submission.Job == nil || jobs.jobType != ImportJobType || len(tasks.created) != 2 {
+		t.Fatalf("expected the import to be queued, got %+v", submission)
+	}
+
+	payload, _ := json.Marshal(jobs.payload)
+	result, err := NewImportJobHandler(svc)(context.Background(), payload)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.(ImportResult).Created != 3 {
+		t.Fatalf("unexpected job result: %+v", result)
+	}
+}
@@ -0,0 +1,290 @@
+package task
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+var sortableFields = map[SortField]bool{
+	SortByCreatedAt:       true,
+	SortByUpdatedAt:       true,
+	SortByPriority:        true,
+	SortByDueAt:           true,
+	SortByEstimateMinutes: true,
+	SortByStoryPoints:     true,
+	SortByRank:            true,
+	SortByRelevance:       true,
+}
+
+func buildListFilter(input ListTasksInput, now time.Time) (ListFilter, error) {
+	var err error
+	filter := ListFilter{
+		Query:          strings.TrimSpace(input.Query),
+		IncludeDeleted: input.IncludeDeleted,
+		HasDueDate:     input.HasDueDate,
+		Overdue:        input.Overdue,
+		Now:            now.UTC(),
+		Limit:          input.Limit,
+		Offset:         input.Offset,
+	}
+
+	if filter.SearchMode, err = parseSearchMode(input.SearchMode); err != nil {
+		return ListFilter{}, err
+	}
+	if err := validateSearchQuery(filter.Query, filter.SearchMode); err != nil {
+		return ListFilter{}, err
+	}
+
+	archived, err := parseArchivedFilter(input.Archived)
+	if err != nil {
+		return ListFilter{}, err
+	}
+	filter.Archived = archived
+
+	if filter.Statuses, err = parseStatuses(input.Status); err != nil {
+		return ListFilter{}, err
+	}
+
+	if filter.PriorityMin, filter.PriorityMax, err = parseRange(
+		"priority", input.PriorityMin, input.PriorityMax, validatePriority,
+	); err != nil {
+		return ListFilter{}, err
+	}
+
+	if filter.EstimateMinutesMin, filter.EstimateMinutesMax, err = parseRange(
+		"estimate_minutes", input.EstimateMinutesMin, input.EstimateMinutesMax, validateEstimateMinutes,
+	); err != nil {
+		return ListFilter{}, err
+	}
+
+	if filter.StoryPointsMin, filter.StoryPointsMax, err = parseRange(
+		"story_points", input.StoryPointsMin, input.StoryPointsMax, validateStoryPoints,
+	); err != nil {
+		return ListFilter{}, err
+	}
+
+	if input.ProjectID != nil {
+		if *input.ProjectID == 0 {
+			return ListFilter{}, ValidationError{Field: "project_id", Message: "must be greater than 0"}
+		}
+		filter.ProjectID = input.ProjectID
+	}
+
+	if input.SprintID != nil {
+		if *input.SprintID == 0 {
+			return ListFilter{}, ValidationError{Field: "sprint_id", Message: "must be greater than 0"}
+		}
+		filter.SprintID = input.SprintID
+	}
+
+	if input.MilestoneID != nil {
+		if *input.MilestoneID == 0 {
+			return ListFilter{}, ValidationError{Field: "milestone_id", Message: "must be greater than 0"}
+		}
+		filter.MilestoneID = input.MilestoneID
+	}
+
+	if err := applyTimeFilters(&filter, input); err != nil {
+		return ListFilter{}, err
+	}
+
+	if filter.CustomFields, err = parseCustomFieldFilters(input.CustomFields); err != nil {
+		return ListFilter{}, err
+	}
+
+	if strings.TrimSpace(input.Filter) != "" {
+		if filter.Expr, err = ParseFilter(input.Filter, now); err != nil {
+			return ListFilter{}, err
+		}
+	}
+
+	if filter.Sort, err = parseSort(input.Sort); err != nil {
+		return ListFilter{}, err
+	}
+	if err := applyRelevanceSort(&filter); err != nil {
+		return ListFilter{}, err
+	}
+
+	if strings.TrimSpace(input.Cursor) != "" {
+		cursor, err := DecodeCursor(input.Cursor)
+		if err != nil {
+			return ListFilter{}, err
+		}
+		if input.Offset != 0 {
+			return ListFilter{}, ValidationError{Field: "offset", Message: "cannot be combined with cursor"}
+		}
+		if !supportsCursor(filter.Sort) {
+			return ListFilter{}, ValidationError{Field: "cursor", Message: "is only supported when sorting by created_at"}
+		}
+		filter.After = &cursor
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = defaultLimit
+	}
+	if filter.Limit > maxLimit {
+		filter.Limit = maxLimit
+	}
+	if filter.Offset < 0 {
+		return ListFilter{}, ValidationError{Field: "offset", Message: "must be greater or equal to 0"}
+	}
+
+	return filter, nil
+}
+
+// applyRelevanceSort orders searches by relevance unless another order is
+// requested. Relevance always puts the best matches first.
+func applyRelevanceSort(filter *ListFilter) error {
+	if filter.Query != "" && len(filter.Sort) == 0 {
+		filter.Sort = []Sort{{Field: SortByRelevance, Desc: true}}
+		return nil
+	}
+	for i := range filter.Sort {
+		if filter.Sort[i].Field != SortByRelevance {
+			continue
+		}
+		if filter.Query == "" {
+			return ValidationError{Field: "sort", Message: "relevance requires q"}
+		}
+		filter.Sort[i].Desc = true
+	}
+	return nil
+}
+
+// supportsCursor reports whether tasks listed in the given order can be paged
+// with a Cursor.
+func supportsCursor(sorts []Sort) bool {
+	return len(sorts) == 0 || (len(sorts) == 1 && sorts[0].Field == SortByCreatedAt)
+}
+
+// parseStatuses parses a comma separated list of statuses, e.g.
+// "new,in_progress".
+func parseStatuses(raw string) ([]Status, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(raw, ",")
+	statuses := make([]Status, 0, len(parts))
+	seen := make(map[Status]bool, len(parts))
+	for _, part := range parts {
+		status, err := parseStatus(part)
+		if err != nil {
+			return nil, err
+		}
+		if seen[status] {
+			continue
+		}
+		seen[status] = true
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+func applyTimeFilters(filter *ListFilter, input ListTasksInput) error {
+	bounds := []struct {
+		field  string
+		value  *time.Time
+		target **time.Time
+	}{
+		{field: "due_before", value: input.DueBefore, target: &filter.DueBefore},
+		{field: "due_after", value: input.DueAfter, target: &filter.DueAfter},
+		{field: "created_after", value: input.CreatedAfter, target: &filter.CreatedAfter},
+		{field: "updated_after", value: input.UpdatedAfter, target: &filter.UpdatedAfter},
+	}
+	for _, bound := range bounds {
+		if bound.value == nil {
+			continue
+		}
+		if bound.value.IsZero() {
+			return ValidationError{Field: bound.field, Message: "must be a valid timestamp"}
+		}
+		normalized := bound.value.UTC()
+		*bound.target = &normalized
+	}
+
+	if filter.DueBefore != nil && filter.DueAfter != nil && !filter.DueAfter.Before(*filter.DueBefore) {
+		return ValidationError{Field: "due_after", Message: "must be before due_before"}
+	}
+
+	if filter.HasDueDate != nil && !*filter.HasDueDate {
+		switch {
+		case filter.DueBefore != nil:
+			return ValidationError{Field: "due_before", Message: "cannot be combined with has_due_date=false"}
+		case filter.DueAfter != nil:
+			return ValidationError{Field: "due_after", Message: "cannot be combined with has_due_date=false"}
+		case filter.Overdue != nil && *filter.Overdue:
+			return ValidationError{Field: "overdue", Message: "cannot be combined with has_due_date=false"}
+		}
+	}
+	return nil
+}
+
+// parseSort parses a comma separated list of fields, each optionally
+// prefixed with "-" for descending order, e.g. "-story_points,created_at".
+func parseSort(raw string) ([]Sort, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(raw, ",")
+	sorts := make([]Sort, 0, len(parts))
+	seen := make(map[SortField]bool, len(parts))
+	for _, part := range parts {
+		part = strings.ToLower(strings.TrimSpace(part))
+		sort := Sort{}
+		if strings.HasPrefix(part, "-") {
+			sort.Desc = true
+			part = part[1:]
+		}
+		sort.Field = SortField(part)
+
+		if !sortableFields[sort.Field] {
+			return nil, ValidationError{Field: "sort", Message: "unsupported sort field " + strings.TrimSpace(part)}
+		}
+		if seen[sort.Field] {
+			return nil, ValidationError{Field: "sort", Message: "duplicate sort field " + part}
+		}
+		seen[sort.Field] = true
+		sorts = append(sorts, sort)
+	}
+
+	return sorts, nil
+}
+
+func parseRange[T any](field string, rawMin, rawMax *int, validate func(int) (T, error)) (*T, *T, error) {
+	var minValue, maxValue *T
+
+	if rawMin != nil {
+		validated, err := validate(*rawMin)
+		if err != nil {
+			return nil, nil, withField(err, field+"_min")
+		}
+		minValue = &validated
+	}
+	if rawMax != nil {
+		validated, err := validate(*rawMax)
+		if err != nil {
+			return nil, nil, withField(err, field+"_max")
+		}
+		maxValue = &validated
+	}
+	if rawMin != nil && rawMax != nil && *rawMin > *rawMax {
+		return nil, nil, ValidationError{Field: field + "_min", Message: "must not be greater than " + field + "_max"}
+	}
+
+	return minValue, maxValue, nil
+}
+
+// withField reports a ValidationError under a different field name, e.g. a
+// query parameter derived from the validated task field.
+func withField(err error, field string) error {
+	var validationErr ValidationError
+	if errors.As(err, &validationErr) {
+		validationErr.Field = field
+		return validationErr
+	}
+	return err
+}
@@ -0,0 +1,78 @@
+package task
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBuildListFilter_RichFilters(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.FixedZone("CET", 3600))
+	dueAfter := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	dueBefore := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	priorityMin, priorityMax := 2, 4
+	overdue := true
+
+	filter, err := buildListFilter(ListTasksInput{
+		Status:      "new, IN_PROGRESS,new",
+		PriorityMin: &priorityMin,
+		PriorityMax: &priorityMax,
+		DueAfter:    &dueAfter,
+		DueBefore:   &dueBefore,
+		Overdue:     &overdue,
+		Sort:        "priority,-due_at",
+	}, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(filter.Statuses) != 2 || filter.Statuses[0] != StatusNew || filter.Statuses[1] != StatusInProgress {
+		t.Fatalf("unexpected statuses: %v", filter.Statuses)
+	}
+	if *filter.PriorityMin != 2 || *filter.PriorityMax != 4 {
+		t.Fatalf("unexpected priority range: %d-%d", *filter.PriorityMin, *filter.PriorityMax)
+	}
+	if !filter.DueAfter.Equal(dueAfter) || !filter.DueBefore.Equal(dueBefore) {
+		t.Fatalf("unexpected due range: %v-%v", filter.DueAfter, filter.DueBefore)
+	}
+	if filter.Overdue == nil || !*filter.Overdue || !filter.Now.Equal(now) || filter.Now.Location() != time.UTC {
+		t.Fatalf("unexpected overdue filter: %v at %v", filter.Overdue, filter.Now)
+	}
+	if len(filter.Sort) != 2 || filter.Sort[0] != (Sort{Field: SortByPriority}) || filter.Sort[1] != (Sort{Field: SortByDueAt, Desc: true}) {
+		t.Fatalf("unexpected sort: %+v", filter.Sort)
+	}
+}
+
+func TestBuildListFilter_RichFilterErrors(t *testing.T) {
+	early := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	late := early.AddDate(0, 1, 0)
+	zero := 0
+	three, two := 3, 2
+	noDueDate := false
+	overdue := true
+
+	tests := []struct {
+		name  string
+		input ListTasksInput
+		field string
+	}{
+		{name: "unknown status", input: ListTasksInput{Status: "new,blocked"}, field: "status"},
+		{name: "priority out of range", input: ListTasksInput{PriorityMin: &zero}, field: "priority_min"},
+		{name: "inverted priority range", input: ListTasksInput{PriorityMin: &three, PriorityMax: &two}, field: "priority_min"},
+		{name: "inverted due range", input: ListTasksInput{DueAfter: &late, DueBefore: &early}, field: "due_after"},
+		{name: "zero timestamp", input: ListTasksInput{CreatedAfter: &time.Time{}}, field: "created_after"},
+		{name: "overdue without due date", input: ListTasksInput{HasDueDate: &noDueDate, Overdue: &overdue}, field: "overdue"},
+		{name: "due bound without due date", input: ListTasksInput{HasDueDate: &noDueDate, DueBefore: &late}, field: "due_before"},
+		{name: "unknown sort", input: ListTasksInput{Sort: "priority,title"}, field: "sort"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := buildListFilter(tc.input, time.Now())
+			var validationErr ValidationError
+			if !errors.As(err, &validationErr) || validationErr.Field != tc.field {
+				t.Fatalf("expected %s validation error, got %v", tc.field, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package task
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/notification"
+)
+
+const maxMentionsPerText = 50
+
+// mentionPattern matches @username that is not part of a word or an e-mail
+// address. Usernames may contain dots and hyphens but do not end with them,
+// so "@alice." mentions alice.
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w.@])@(\w(?:[\w.-]{0,253}\w)?)`)
+
+type User struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+}
+
+// Mention records that a user was mentioned in the description of a task or,
+// when CommentID is set, in one of its comments.
+type Mention struct {
+	ID        uint64    `json:"id"`
+	TaskID    uint64    `json:"task_id"`
+	TaskTitle string    `json:"task_title"`
+	CommentID *uint64   `json:"comment_id,omitempty"`
+	UserID    string    `json:"user_id"`
+	AuthorID  *string   `json:"author_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ListMentionsInput struct {
+	UserID string
+	Limit  int
+	Offset int
+}
+
+type UserRepository interface {
+	FindUsersByUsername(ctx context.Context, usernames []string) ([]User, error)
+}
+
+type MentionRepository interface {
+	ListMentions(ctx context.Context, userID string, limit, offset int) ([]Mention, error)
+}
+
+type MentionService interface {
+	ListForUser(ctx context.Context, input ListMentionsInput) ([]Mention, error)
+}
+
+type mentionService struct {
+	repo MentionRepository
+}
+
+func NewMentionService(repo MentionRepository) MentionService {
+	return &mentionService{repo: repo}
+}
+
+func (s *mentionService) ListForUser(ctx context.Context, input ListMentionsInput) ([]Mention, error) {
+	userID, err := normalizeUserID(input.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := input.Limit
+	if limit == 0 {
+		limit = defaultLimit
+	}
+	if limit < 1 || limit > maxLimit {
+		return nil, ValidationError{Field: "limit", Message: "must be between 1 and 100"}
+	}
+	if input.Offset < 0 {
+		return nil, ValidationError{Field: "offset", Message: "must be greater or equal to 0"}
+	}
+
+	return s.repo.ListMentions(ctx, userID, limit, input.Offset)
+}
+
+// ParseMentions returns the lower-cased usernames mentioned in text in order
+// of appearance, without duplicates.
+func ParseMentions(text string) []string {
+	var usernames []string
+	seen := make(map[string]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		username := strings.ToLower(match[1])
+		if seen[username] {
+			continue
+		}
+		seen[username] = true
+		usernames = append(usernames, username)
+		if len(usernames) == maxMentionsPerText {
+			break
+		}
+	}
+	return usernames
+}
+
+// resolveMentions looks up the users mentioned in text. Usernames that do not
+// belong to a user are left as plain text.
+func resolveMentions(ctx context.Context, users UserRepository, text string) ([]User, error) {
+	usernames := ParseMentions(text)
+	if len(usernames) == 0 {
+		return nil, nil
+	}
+	return users.FindUsersByUsername(ctx, usernames)
+}
+
+// newlyMentioned drops the users that were already mentioned in the previous
+// version of a text so that editing a text does not notify them again.
+func newlyMentioned(users []User, previous string) []User {
+	already := make(map[string]bool)
+	for _, username := range ParseMentions(previous) {
+		already[username] = true
+	}
+
+	var mentioned []User
+	for _, user := range users {
+		if !already[strings.ToLower(user.Username)] {
+			mentioned = append(mentioned, user)
+		}
+	}
+	return mentioned
+}
+
+func mentionedUserIDs(users []User) []string {
+	ids := make([]string, 0, len(users))
+	for _, user := range users {
+		ids = append(ids, user.ID)
+	}
+	return ids
+}
+
+// notifyMentioned notifies mentioned users except the author of the text on a
+// best-effort basis like notifyWatchers.
+func notifyMentioned(ctx context.Context, notifier notification.Notifier, users []User, authorID string, n notification.Notification) {
+	if notifier == nil {
+		return
+	}
+
+	for _, user := range users {
+		if user.ID != authorID {
+			n.Recipients = append(n.Recipients, user.ID)
+		}
+	}
+	if len(n.Recipients) == 0 {
+		return
+	}
+
+	n.Type = notification.TypeTaskMentioned
+	n.CreatedAt = time.Now().UTC()
+	notify(ctx, notifier, n)
+}
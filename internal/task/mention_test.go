@@ -0,0 +1,149 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/notification"
+)
+
+var (
+	testAlice = User{ID: "1a5e0f3c-6b7d-4c8e-9f10-2a3b4c5d6e7f", Username: "alice"}
+	testBob   = User{ID: "2b6f1a4d-7c8e-4d9f-a021-3b4c5d6e7f80", Username: "Bob.Smith"}
+)
+
+func TestParseMentions(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{text: "ping @alice and @Bob.Smith.", want: []string{"alice", "bob.smith"}},
+		{text: "@alice @ALICE", want: []string{"alice"}},
+		{text: "mail alice@example.com", want: nil},
+		{text: "(@carol) done", want: []string{"carol"}},
+		{text: "@@dave @ nobody", want: nil},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.text, func(t *testing.T) {
+			got := ParseMentions(tc.text)
+			if !slices.Equal(got, tc.want) {
+				t.Fatalf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestServiceCreate_StoresAndNotifiesMentions(t *testing.T) {
+	repo := &mockRepository{
+		users:        []User{testAlice, testBob},
+		createResult: Task{ID: 3, Title: "Release", Description: "@alice @bob.smith @ghost"},
+	}
+	notifier := &mockNotifier{}
+	svc := NewService(repo, WithNotifier(notifier))
+
+	_, err := svc.Create(context.Background(), CreateTaskInput{
+		Title:       "Release",
+		Description: "@alice @bob.smith @ghost",
+		ReporterID:  testBob.ID,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !slices.Equal(repo.createParams.Mentions, []string{testAlice.ID, testBob.ID}) {
+		t.Fatalf("unexpected mentions: %v", repo.createParams.Mentions)
+	}
+	if repo.createParams.Description != "@alice @bob.smith @ghost" {
+		t.Fatalf("description must stay unchanged, got %q", repo.createParams.Description)
+	}
+
+	if len(notifier.notifications) != 1 {
+		t.Fatalf("expected one notification, got %d", len(notifier.notifications))
+	}
+	sent := notifier.notifications[0]
+	if sent.Type != notification.TypeTaskMentioned || !slices.Equal(sent.Recipients, []string{testAlice.ID}) {
+		t.Fatalf("unexpected notification: %+v", sent)
+	}
+}
+
+func TestServiceUpdate_NotifiesOnlyNewMentions(t *testing.T) {
+	repo := &mockRepository{
+		users:        []User{testAlice, testBob},
+		getResult:    Task{ID: 3, Title: "Release", Description: "cc @alice"},
+		updateResult: Task{ID: 3, Title: "Release", Description: "cc @alice @bob.smith"},
+	}
+	notifier := &mockNotifier{}
+	svc := NewService(repo, WithNotifier(notifier))
+
+	description := "cc @alice @bob.smith"
+	_, err := svc.Update(context.Background(), 3, UpdateTaskInput{ActorID: testReporterID, Description: &description})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(repo.updateParams.Mentions) != 2 || repo.updateParams.ActorID == nil || *repo.updateParams.ActorID != testReporterID {
+		t.Fatalf("unexpected update params: %+v", repo.updateParams)
+	}
+
+	var mentionNotifications []notification.Notification
+	for _, sent := range notifier.notifications {
+		if sent.Type == notification.TypeTaskMentioned {
+			mentionNotifications = append(mentionNotifications, sent)
+		}
+	}
+	if len(mentionNotifications) != 1 || !slices.Equal(mentionNotifications[0].Recipients, []string{testBob.ID}) {
+		t.Fatalf("expected only bob to be notified, got %+v", mentionNotifications)
+	}
+}
+
+func TestCommentServiceAdd_Mentions(t *testing.T) {
+	repo := &mockCommentRepository{users: []User{testAlice}}
+	notifier := &mockNotifier{}
+	svc := NewCommentService(repo, notifier)
+
+	_, err := svc.Add(context.Background(), 4, AddCommentInput{AuthorID: testReporterID, Body: "@alice please review"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !slices.Equal(repo.params.Mentions, []string{testAlice.ID}) {
+		t.Fatalf("unexpected mentions: %v", repo.params.Mentions)
+	}
+	if len(notifier.notifications) != 1 || notifier.notifications[0].Type != notification.TypeTaskMentioned {
+		t.Fatalf("unexpected notifications: %+v", notifier.notifications)
+	}
+}
+
+type mockMentionRepository struct {
+	userID string
+	limit  int
+	offset int
+}
+
+func (m *mockMentionRepository) ListMentions(_ context.Context, userID string, limit, offset int) ([]Mention, error) {
+	m.userID = userID
+	m.limit = limit
+	m.offset = offset
+	return []Mention{}, nil
+}
+
+func TestMentionServiceListForUser(t *testing.T) {
+	repo := &mockMentionRepository{}
+	svc := NewMentionService(repo)
+
+	if _, err := svc.ListForUser(context.Background(), ListMentionsInput{UserID: testAlice.ID}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.userID != testAlice.ID || repo.limit != defaultLimit || repo.offset != 0 {
+		t.Fatalf("unexpected repository call: %+v", repo)
+	}
+
+	_, err := svc.ListForUser(context.Background(), ListMentionsInput{UserID: testAlice.ID, Limit: 500})
+	var validationErr ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Field != "limit" {
+		t.Fatalf("expected limit validation error, got %v", err)
+	}
+}
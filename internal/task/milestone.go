@@ -0,0 +1,214 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	maxMilestoneNameLength = 255
+	milestoneDateLayout    = "2006-01-02"
+)
+
+// MilestoneProgress rolls up the tasks attached to a milestone. LateTasks are
+// open tasks due after the target date.
+type MilestoneProgress struct {
+	Total      int64 `json:"total"`
+	New        int64 `json:"new"`
+	InProgress int64 `json:"in_progress"`
+	Done       int64 `json:"done"`
+	LateTasks  int64 `json:"late_tasks"`
+	Percent    int   `json:"percent"`
+}
+
+// Milestone.TargetDate is a date in YYYY-MM-DD format. The milestone is at
+// risk when any of its open tasks is due after that date.
+type Milestone struct {
+	ID          uint64            `json:"id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	TargetDate  string            `json:"target_date"`
+	Progress    MilestoneProgress `json:"progress"`
+	AtRisk      bool              `json:"at_risk"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+type CreateMilestoneInput struct {
+	Name        string
+	Description string
+	TargetDate  string
+}
+
+type UpdateMilestoneInput struct {
+	Name        *string
+	Description *string
+	TargetDate  *string
+}
+
+type MilestoneParams struct {
+	Name        string
+	Description string
+	TargetDate  string
+}
+
+type UpdateMilestoneParams struct {
+	Name        *string
+	Description *string
+	TargetDate  *string
+}
+
+type MilestoneRepository interface {
+	CreateMilestone(ctx context.Context, params MilestoneParams) (Milestone, error)
+	GetMilestone(ctx context.Context, id uint64) (Milestone, error)
+	ListMilestones(ctx context.Context) ([]Milestone, error)
+	UpdateMilestone(ctx context.Context, id uint64, params UpdateMilestoneParams) (Milestone, error)
+	DeleteMilestone(ctx context.Context, id uint64) error
+}
+
+type MilestoneService interface {
+	Create(ctx context.Context, input CreateMilestoneInput) (Milestone, error)
+	GetByID(ctx context.Context, id uint64) (Milestone, error)
+	List(ctx context.Context) ([]Milestone, error)
+	Update(ctx context.Context, id uint64, input UpdateMilestoneInput) (Milestone, error)
+	Delete(ctx context.Context, id uint64) error
+}
+
+type milestoneService struct {
+	repo MilestoneRepository
+}
+
+func NewMilestoneService(repo MilestoneRepository) MilestoneService {
+	return &milestoneService{repo: repo}
+}
+
+func (s *milestoneService) Create(ctx context.Context, input CreateMilestoneInput) (Milestone, error) {
+	name, err := normalizeMilestoneName(input.Name)
+	if err != nil {
+		return Milestone{}, err
+	}
+	targetDate, err := normalizeMilestoneDate(input.TargetDate)
+	if err != nil {
+		return Milestone{}, err
+	}
+
+	created, err := s.repo.CreateMilestone(ctx, MilestoneParams{
+		Name:        name,
+		Description: strings.TrimSpace(input.Description),
+		TargetDate:  targetDate,
+	})
+	if err != nil {
+		return Milestone{}, err
+	}
+	return withProgress(created), nil
+}
+
+func (s *milestoneService) GetByID(ctx context.Context, id uint64) (Milestone, error) {
+	if id == 0 {
+		return Milestone{}, ValidationError{Field: "id", Message: "must be greater than 0"}
+	}
+	found, err := s.repo.GetMilestone(ctx, id)
+	if err != nil {
+		return Milestone{}, err
+	}
+	return withProgress(found), nil
+}
+
+func (s *milestoneService) List(ctx context.Context) ([]Milestone, error) {
+	milestones, err := s.repo.ListMilestones(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range milestones {
+		milestones[i] = withProgress(milestones[i])
+	}
+	return milestones, nil
+}
+
+func (s *milestoneService) Update(ctx context.Context, id uint64, input UpdateMilestoneInput) (Milestone, error) {
+	if id == 0 {
+		return Milestone{}, ValidationError{Field: "id", Message: "must be greater than 0"}
+	}
+
+	params := UpdateMilestoneParams{}
+	if input.Name != nil {
+		name, err := normalizeMilestoneName(*input.Name)
+		if err != nil {
+			return Milestone{}, err
+		}
+		params.Name = &name
+	}
+	if input.Description != nil {
+		description := strings.TrimSpace(*input.Description)
+		params.Description = &description
+	}
+	if input.TargetDate != nil {
+		targetDate, err := normalizeMilestoneDate(*input.TargetDate)
+		if err != nil {
+			return Milestone{}, err
+		}
+		params.TargetDate = &targetDate
+	}
+
+	if params.Name == nil && params.Description == nil && params.TargetDate == nil {
+		return Milestone{}, ValidationError{Field: "body", Message: "at least one field must be provided for update"}
+	}
+
+	updated, err := s.repo.UpdateMilestone(ctx, id, params)
+	if err != nil {
+		return Milestone{}, err
+	}
+	return withProgress(updated), nil
+}
+
+func (s *milestoneService) Delete(ctx context.Context, id uint64) error {
+	if id == 0 {
+		return ValidationError{Field: "id", Message: "must be greater than 0"}
+	}
+	return s.repo.DeleteMilestone(ctx, id)
+}
+
+// withProgress derives the completion percentage and the risk flag from the
+// task counts loaded by the repository.
+func withProgress(milestone Milestone) Milestone {
+	progress := &milestone.Progress
+	progress.Percent = 0
+	if progress.Total > 0 {
+		progress.Percent = int(progress.Done * 100 / progress.Total)
+	}
+	milestone.AtRisk = progress.LateTasks > 0
+	return milestone
+}
+
+// lookupMilestone checks that the milestone a task is attached to exists.
+func lookupMilestone(ctx context.Context, repo Repository, id uint64) (Milestone, error) {
+	if id == 0 {
+		return Milestone{}, ValidationError{Field: "milestone_id", Message: "must be greater than 0"}
+	}
+	milestone, err := repo.GetMilestone(ctx, id)
+	if errors.Is(err, ErrMilestoneNotFound) {
+		return Milestone{}, ValidationError{Field: "milestone_id", Message: "milestone does not exist"}
+	}
+	return milestone, err
+}
+
+func normalizeMilestoneName(raw string) (string, error) {
+	name := strings.TrimSpace(raw)
+	if name == "" {
+		return "", ValidationError{Field: "name", Message: "must not be empty"}
+	}
+	if len(name) > maxMilestoneNameLength {
+		return "", ValidationError{Field: "name", Message: "must be at most 255 characters"}
+	}
+	return name, nil
+}
+
+func normalizeMilestoneDate(raw string) (string, error) {
+	date, err := time.Parse(milestoneDateLayout, strings.TrimSpace(raw))
+	if err != nil {
+		return "", ValidationError{Field: "target_date", Message: "must be a date in YYYY-MM-DD format"}
+	}
+	return date.Format(milestoneDateLayout), nil
+}
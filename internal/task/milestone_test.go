@@ -0,0 +1,124 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type mockMilestoneRepository struct {
+	milestones   []Milestone
+	createParams MilestoneParams
+	updateParams UpdateMilestoneParams
+}
+
+func (m *mockMilestoneRepository) CreateMilestone(_ context.Context, params MilestoneParams) (Milestone, error) {
+	m.createParams = params
+	return Milestone{ID: 1, Name: params.Name, TargetDate: params.TargetDate}, nil
+}
+
+func (m *mockMilestoneRepository) GetMilestone(_ context.Context, id uint64) (Milestone, error) {
+	for _, milestone := range m.milestones {
+		if milestone.ID == id {
+			return milestone, nil
+		}
+	}
+	return Milestone{}, ErrMilestoneNotFound
+}
+
+func (m *mockMilestoneRepository) ListMilestones(_ context.Context) ([]Milestone, error) {
+	return append([]Milestone(nil), m.milestones...), nil
+}
+
+func (m *mockMilestoneRepository) UpdateMilestone(_ context.Context, id uint64, params UpdateMilestoneParams) (Milestone, error) {
+	m.updateParams = params
+	return m.GetMilestone(context.Background(), id)
+}
+
+func (m *mockMilestoneRepository) DeleteMilestone(_ context.Context, _ uint64) error {
+	return nil
+}
+
+func TestMilestoneServiceCreate(t *testing.T) {
+	repo := &mockMilestoneRepository{}
+	svc := NewMilestoneService(repo)
+
+	if _, err := svc.Create(context.Background(), CreateMilestoneInput{Name: " Beta ", TargetDate: " 2026-06-01 "}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.createParams.Name != "Beta" || repo.createParams.TargetDate != "2026-06-01" {
+		t.Fatalf("unexpected params: %+v", repo.createParams)
+	}
+
+	tests := []struct {
+		input CreateMilestoneInput
+		field string
+	}{
+		{input: CreateMilestoneInput{TargetDate: "2026-06-01"}, field: "name"},
+		{input: CreateMilestoneInput{Name: "Beta", TargetDate: "2026-06-31"}, field: "target_date"},
+		{input: CreateMilestoneInput{Name: "Beta", TargetDate: "2026-06-01T00:00:00Z"}, field: "target_date"},
+	}
+	for _, tc := range tests {
+		_, err := svc.Create(context.Background(), tc.input)
+		var validationErr ValidationError
+		if !errors.As(err, &validationErr) || validationErr.Field != tc.field {
+			t.Fatalf("expected validation error for %s, got %v", tc.field, err)
+		}
+	}
+}
+
+func TestMilestoneServiceProgress(t *testing.T) {
+	repo := &mockMilestoneRepository{milestones: []Milestone{
+		{ID: 1, Progress: MilestoneProgress{Total: 3, New: 1, InProgress: 1, Done: 1}},
+		{ID: 2, Progress: MilestoneProgress{Total: 4, Done: 2, InProgress: 2, LateTasks: 1}},
+		{ID: 3},
+	}}
+
+	milestones, err := NewMilestoneService(repo).List(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []struct {
+		percent int
+		atRisk  bool
+	}{
+		{percent: 33},
+		{percent: 50, atRisk: true},
+		{percent: 0},
+	}
+	for i, milestone := range milestones {
+		if milestone.Progress.Percent != want[i].percent || milestone.AtRisk != want[i].atRisk {
+			t.Fatalf("milestone %d: unexpected progress %+v, at risk %v", milestone.ID, milestone.Progress, milestone.AtRisk)
+		}
+	}
+}
+
+func TestMilestoneServiceUpdate_RequiresField(t *testing.T) {
+	repo := &mockMilestoneRepository{milestones: []Milestone{{ID: 1}}}
+
+	_, err := NewMilestoneService(repo).Update(context.Background(), 1, UpdateMilestoneInput{})
+	var validationErr ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Field != "body" {
+		t.Fatalf("expected body validation error, got %v", err)
+	}
+}
+
+func TestServiceUpdate_Milestone(t *testing.T) {
+	milestoneID := uint64(4)
+	repo := &mockRepository{milestone: Milestone{ID: 4}}
+
+	if _, err := NewService(repo).Update(context.Background(), 1, UpdateTaskInput{MilestoneID: &milestoneID}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.updateParams.MilestoneID == nil || *repo.updateParams.MilestoneID != 4 {
+		t.Fatalf("unexpected milestone id: %v", repo.updateParams.MilestoneID)
+	}
+
+	missing := uint64(9)
+	_, err := NewService(repo).Create(context.Background(), CreateTaskInput{Title: "Task", MilestoneID: &missing})
+	var validationErr ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Field != "milestone_id" {
+		t.Fatalf("expected milestone_id validation error, got %v", err)
+	}
+}
@@ -0,0 +1,255 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	maxProjectNameLength     = 255
+	maxCustomFieldNameLength = 64
+	maxCustomFieldOptions    = 50
+	maxCustomFieldValueLen   = 255
+)
+
+var customFieldNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+type CustomFieldType string
+
+const (
+	CustomFieldText         CustomFieldType = "text"
+	CustomFieldNumber       CustomFieldType = "number"
+	CustomFieldDate         CustomFieldType = "date"
+	CustomFieldSingleSelect CustomFieldType = "single_select"
+	CustomFieldMultiSelect  CustomFieldType = "multi_select"
+	CustomFieldUser         CustomFieldType = "user"
+)
+
+func (t CustomFieldType) IsValid() bool {
+	switch t {
+	case CustomFieldText, CustomFieldNumber, CustomFieldDate, CustomFieldSingleSelect, CustomFieldMultiSelect, CustomFieldUser:
+		return true
+	default:
+		return false
+	}
+}
+
+func (t CustomFieldType) hasOptions() bool {
+	return t == CustomFieldSingleSelect || t == CustomFieldMultiSelect
+}
+
+type Project struct {
+	ID           uint64                  `json:"id"`
+	Name         string                  `json:"name"`
+	CustomFields []CustomFieldDefinition `json:"custom_fields"`
+	WIPLimits    map[Status]int          `json:"wip_limits"`
+	CreatedAt    time.Time               `json:"created_at"`
+	UpdatedAt    time.Time               `json:"updated_at"`
+}
+
+// CustomField returns the definition with the given name.
+func (p Project) CustomField(name string) (CustomFieldDefinition, bool) {
+	for _, definition := range p.CustomFields {
+		if definition.Name == name {
+			return definition, true
+		}
+	}
+	return CustomFieldDefinition{}, false
+}
+
+type CustomFieldDefinition struct {
+	ID        uint64          `json:"id"`
+	ProjectID uint64          `json:"project_id"`
+	Name      string          `json:"name"`
+	Type      CustomFieldType `json:"type"`
+	Required  bool            `json:"required"`
+	Options   []string        `json:"options,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type CreateProjectInput struct {
+	Name string
+}
+
+type CreateCustomFieldInput struct {
+	Name     string
+	Type     string
+	Required bool
+	Options  []string
+}
+
+type CustomFieldParams struct {
+	ProjectID uint64
+	Name      string
+	Type      CustomFieldType
+	Required  bool
+	Options   []string
+}
+
+type ProjectRepository interface {
+	CreateProject(ctx context.Context, name string) (Project, error)
+	GetProject(ctx context.Context, id uint64) (Project, error)
+	ListProjects(ctx context.Context) ([]Project, error)
+	CreateCustomField(ctx context.Context, params CustomFieldParams) (CustomFieldDefinition, error)
+	DeleteCustomField(ctx context.Context, projectID, fieldID uint64) error
+	SetWIPLimits(ctx context.Context, projectID uint64, limits map[Status]int) error
+}
+
+type ProjectService interface {
+	CreateProject(ctx context.Context, input CreateProjectInput) (Project, error)
+	GetProject(ctx context.Context, id uint64) (Project, error)
+	ListProjects(ctx context.Context) ([]Project, error)
+	CreateCustomField(ctx context.Context, projectID uint64, input CreateCustomFieldInput) (CustomFieldDefinition, error)
+	DeleteCustomField(ctx context.Context, projectID, fieldID uint64) error
+	SetWIPLimits(ctx context.Context, projectID uint64, limits map[string]*int) (Project, error)
+}
+
+type projectService struct {
+	repo ProjectRepository
+}
+
+func NewProjectService(repo ProjectRepository) ProjectService {
+	return &projectService{repo: repo}
+}
+
+func (s *projectService) CreateProject(ctx context.Context, input CreateProjectInput) (Project, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return Project{}, ValidationError{Field: "name", Message: "must not be empty"}
+	}
+	if len(name) > maxProjectNameLength {
+		return Project{}, ValidationError{Field: "name", Message: "must be at most 255 characters"}
+	}
+
+	return s.repo.CreateProject(ctx, name)
+}
+
+func (s *projectService) GetProject(ctx context.Context, id uint64) (Project, error) {
+	if id == 0 {
+		return Project{}, ValidationError{Field: "id", Message: "must be greater than 0"}
+	}
+	return s.repo.GetProject(ctx, id)
+}
+
+func (s *projectService) ListProjects(ctx context.Context) ([]Project, error) {
+	return s.repo.ListProjects(ctx)
+}
+
+func (s *projectService) CreateCustomField(ctx context.Context, projectID uint64, input CreateCustomFieldInput) (CustomFieldDefinition, error) {
+	if projectID == 0 {
+		return CustomFieldDefinition{}, ValidationError{Field: "id", Message: "must be greater than 0"}
+	}
+
+	name := strings.TrimSpace(input.Name)
+	if err := validateCustomFieldName("name", name); err != nil {
+		return CustomFieldDefinition{}, err
+	}
+
+	fieldType := CustomFieldType(strings.ToLower(strings.TrimSpace(input.Type)))
+	if !fieldType.IsValid() {
+		return CustomFieldDefinition{}, ValidationError{
+			Field:   "type",
+			Message: "must be one of: text, number, date, single_select, multi_select, user",
+		}
+	}
+
+	options, err := normalizeCustomFieldOptions(fieldType, input.Options)
+	if err != nil {
+		return CustomFieldDefinition{}, err
+	}
+
+	return s.repo.CreateCustomField(ctx, CustomFieldParams{
+		ProjectID: projectID,
+		Name:      name,
+		Type:      fieldType,
+		Required:  input.Required,
+		Options:   options,
+	})
+}
+
+func (s *projectService) DeleteCustomField(ctx context.Context, projectID, fieldID uint64) error {
+	if projectID == 0 {
+		return ValidationError{Field: "id", Message: "must be greater than 0"}
+	}
+	if fieldID == 0 {
+		return ValidationError{Field: "field_id", Message: "must be greater than 0"}
+	}
+	return s.repo.DeleteCustomField(ctx, projectID, fieldID)
+}
+
+// SetWIPLimits replaces the WIP limits of all status columns of a project;
+// columns without a limit are unlimited.
+func (s *projectService) SetWIPLimits(ctx context.Context, projectID uint64, raw map[string]*int) (Project, error) {
+	if projectID == 0 {
+		return Project{}, ValidationError{Field: "id", Message: "must be greater than 0"}
+	}
+
+	limits, err := normalizeWIPLimits(raw)
+	if err != nil {
+		return Project{}, err
+	}
+
+	if err := s.repo.SetWIPLimits(ctx, projectID, limits); err != nil {
+		return Project{}, err
+	}
+	return s.repo.GetProject(ctx, projectID)
+}
+
+func validateCustomFieldName(field, name string) error {
+	if len(name) > maxCustomFieldNameLength || !customFieldNamePattern.MatchString(name) {
+		return ValidationError{
+			Field:   field,
+			Message: "must start with a lowercase letter, contain only lowercase letters, digits and underscores and be at most 64 characters",
+		}
+	}
+	return nil
+}
+
+func normalizeCustomFieldOptions(fieldType CustomFieldType, raw []string) ([]string, error) {
+	if !fieldType.hasOptions() {
+		if len(raw) > 0 {
+			return nil, ValidationError{Field: "options", Message: "are only supported by select fields"}
+		}
+		return nil, nil
+	}
+
+	options := make([]string, 0, len(raw))
+	seen := make(map[string]bool, len(raw))
+	for _, option := range raw {
+		option = strings.TrimSpace(option)
+		if option == "" {
+			return nil, ValidationError{Field: "options", Message: "must not contain empty values"}
+		}
+		if len(option) > maxCustomFieldValueLen {
+			return nil, ValidationError{Field: "options", Message: "each option must be at most 255 characters"}
+		}
+		if seen[option] {
+			continue
+		}
+		seen[option] = true
+		options = append(options, option)
+	}
+	if len(options) == 0 {
+		return nil, ValidationError{Field: "options", Message: "must contain at least one option"}
+	}
+	if len(options) > maxCustomFieldOptions {
+		return nil, ValidationError{Field: "options", Message: "must contain at most 50 options"}
+	}
+	return options, nil
+}
+
+// lookupProject loads the project a task is assigned to. A missing project is
+// reported against the project_id field of the task.
+func lookupProject(ctx context.Context, repo Repository, id uint64) (Project, error) {
+	if id == 0 {
+		return Project{}, ValidationError{Field: "project_id", Message: "must be greater than 0"}
+	}
+	project, err := repo.GetProject(ctx, id)
+	if errors.Is(err, ErrProjectNotFound) {
+		return Project{}, ValidationError{Field: "project_id", Message: "project does not exist"}
+	}
+	return project, err
+}
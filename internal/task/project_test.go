@@ -0,0 +1,133 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type mockProjectRepository struct {
+	createdName       string
+	customFieldParams CustomFieldParams
+	customFieldCalls  int
+	wipLimits         map[Status]int
+	wipLimitsCalled   bool
+}
+
+func (m *mockProjectRepository) SetWIPLimits(_ context.Context, _ uint64, limits map[Status]int) error {
+	m.wipLimitsCalled = true
+	m.wipLimits = limits
+	return nil
+}
+
+func (m *mockProjectRepository) CreateProject(_ context.Context, name string) (Project, error) {
+	m.createdName = name
+	return Project{ID: 1, Name: name}, nil
+}
+
+func (m *mockProjectRepository) GetProject(_ context.Context, id uint64) (Project, error) {
+	return Project{ID: id}, nil
+}
+
+func (m *mockProjectRepository) ListProjects(_ context.Context) ([]Project, error) {
+	return nil, nil
+}
+
+func (m *mockProjectRepository) CreateCustomField(_ context.Context, params CustomFieldParams) (CustomFieldDefinition, error) {
+	m.customFieldCalls++
+	m.customFieldParams = params
+	return CustomFieldDefinition{ID: 1, ProjectID: params.ProjectID, Name: params.Name, Type: params.Type}, nil
+}
+
+func (m *mockProjectRepository) DeleteCustomField(_ context.Context, _, _ uint64) error {
+	return nil
+}
+
+func TestProjectServiceCreateProject_TrimsName(t *testing.T) {
+	repo := &mockProjectRepository{}
+	svc := NewProjectService(repo)
+
+	if _, err := svc.CreateProject(context.Background(), CreateProjectInput{Name: "  Support "}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.createdName != "Support" {
+		t.Fatalf("unexpected name: %q", repo.createdName)
+	}
+
+	_, err := svc.CreateProject(context.Background(), CreateProjectInput{Name: " "})
+	var validationErr ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Field != "name" {
+		t.Fatalf("expected name validation error, got %v", err)
+	}
+}
+
+func TestProjectServiceCreateCustomField_NormalizesOptions(t *testing.T) {
+	repo := &mockProjectRepository{}
+	svc := NewProjectService(repo)
+
+	_, err := svc.CreateCustomField(context.Background(), 3, CreateCustomFieldInput{
+		Name:    "severity",
+		Type:    "Single_Select",
+		Options: []string{" low", "high", "low"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	params := repo.customFieldParams
+	if params.ProjectID != 3 || params.Type != CustomFieldSingleSelect {
+		t.Fatalf("unexpected params: %+v", params)
+	}
+	if len(params.Options) != 2 || params.Options[0] != "low" || params.Options[1] != "high" {
+		t.Fatalf("unexpected options: %v", params.Options)
+	}
+}
+
+func TestProjectServiceCreateCustomField_Validation(t *testing.T) {
+	tests := []struct {
+		name  string
+		input CreateCustomFieldInput
+		field string
+	}{
+		{name: "invalid name", input: CreateCustomFieldInput{Name: "Severity Level", Type: "text"}, field: "name"},
+		{name: "unknown type", input: CreateCustomFieldInput{Name: "severity", Type: "enum"}, field: "type"},
+		{name: "select without options", input: CreateCustomFieldInput{Name: "severity", Type: "multi_select"}, field: "options"},
+		{name: "options on text field", input: CreateCustomFieldInput{Name: "customer", Type: "text", Options: []string{"a"}}, field: "options"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockProjectRepository{}
+			svc := NewProjectService(repo)
+
+			_, err := svc.CreateCustomField(context.Background(), 1, tc.input)
+			var validationErr ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("expected validation error, got %v", err)
+			}
+			if validationErr.Field != tc.field {
+				t.Fatalf("expected field %q, got %q", tc.field, validationErr.Field)
+			}
+			if repo.customFieldCalls != 0 {
+				t.Fatal("repository must not be called")
+			}
+		})
+	}
+}
+
+func TestCustomFieldDefinitionDecode(t *testing.T) {
+	number := CustomFieldDefinition{Type: CustomFieldNumber}
+	if got := number.Decode([]string{"2.5"}); got != 2.5 {
+		t.Fatalf("unexpected number: %v", got)
+	}
+
+	multi := CustomFieldDefinition{Type: CustomFieldMultiSelect}
+	if got, ok := multi.Decode([]string{"a", "b"}).([]string); !ok || len(got) != 2 {
+		t.Fatalf("unexpected multi-select value: %v", got)
+	}
+
+	text := CustomFieldDefinition{Type: CustomFieldText}
+	if got := text.Decode([]string{"ACME"}); got != "ACME" {
+		t.Fatalf("unexpected text value: %v", got)
+	}
+}
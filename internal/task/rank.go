@@ -0,0 +1,111 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Ranks order tasks within a status column. A rank is a string of base-36
+// digits compared byte by byte, read as a fraction: a new rank can always be
+// generated between two others, so moving a task changes only that task.
+// Ranks never end with "0", otherwise no rank would fit between "a" and "a0".
+const (
+	rankDigits    = "0123456789abcdefghijklmnopqrstuvwxyz"
+	maxRankLength = 128
+)
+
+// ErrRankExhausted is returned when the rank between two neighbours would
+// exceed the maximum length; the column has to be rebalanced with EvenRanks.
+var ErrRankExhausted = errors.New("no rank left between the neighbours")
+
+// RankBetween returns a rank that sorts after lower and before upper. An
+// empty lower is the start and an empty upper the end of the column.
+func RankBetween(lower, upper string) (string, error) {
+	for _, rank := range []string{lower, upper} {
+		if !validRank(rank) {
+			return "", fmt.Errorf("invalid rank %q", rank)
+		}
+	}
+	if upper != "" && lower >= upper {
+		return "", fmt.Errorf("rank %q does not sort before %q", lower, upper)
+	}
+
+	rank := rankMidpoint(lower, upper)
+	if len(rank) > maxRankLength {
+		return "", ErrRankExhausted
+	}
+	return rank, nil
+}
+
+// EvenRanks returns n evenly spaced ranks of equal length in ascending order.
+func EvenRanks(n int) []string {
+	width := 1
+	for capacity := len(rankDigits); capacity <= n; capacity *= len(rankDigits) {
+		width++
+	}
+
+	ranks := make([]string, n)
+	for i := range ranks {
+		digits := make([]byte, width)
+		value := i + 1
+		for j := width - 1; j >= 0; j-- {
+			digits[j] = rankDigits[value%len(rankDigits)]
+			value /= len(rankDigits)
+		}
+		ranks[i] = string(digits) + "i"
+	}
+	return ranks
+}
+
+func rankMidpoint(lower, upper string) string {
+	if upper != "" {
+		n := 0
+		for n < len(upper) && rankDigitAt(lower, n) == upper[n] {
+			n++
+		}
+		if n > 0 {
+			return upper[:n] + rankMidpoint(rankSuffix(lower, n), upper[n:])
+		}
+	}
+
+	lo := strings.IndexByte(rankDigits, rankDigitAt(lower, 0))
+	hi := len(rankDigits)
+	if upper != "" {
+		hi = strings.IndexByte(rankDigits, upper[0])
+	}
+
+	if hi-lo > 1 {
+		return string(rankDigits[(lo+hi)/2])
+	}
+	if len(upper) > 1 {
+		return upper[:1]
+	}
+	return string(rankDigits[lo]) + rankMidpoint(rankSuffix(lower, 1), "")
+}
+
+func rankDigitAt(rank string, i int) byte {
+	if i < len(rank) {
+		return rank[i]
+	}
+	return rankDigits[0]
+}
+
+func rankSuffix(rank string, n int) string {
+	if n < len(rank) {
+		return rank[n:]
+	}
+	return ""
+}
+
+func validRank(rank string) bool {
+	if strings.HasSuffix(rank, "0") {
+		return false
+	}
+	for i := 0; i < len(rank); i++ {
+		if strings.IndexByte(rankDigits, rank[i]) < 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,128 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestRankBetween(t *testing.T) {
+	tests := []struct {
+		lower, upper string
+		want         string
+	}{
+		{lower: "", upper: "", want: "i"},
+		{lower: "i", upper: "", want: "r"},
+		{lower: "", upper: "i", want: "9"},
+		{lower: "a", upper: "b", want: "ai"},
+		{lower: "0001i", upper: "0002i", want: "0002"},
+		{lower: "z", upper: "", want: "zi"},
+		{lower: "", upper: "01", want: "00i"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.lower+"_"+tc.upper, func(t *testing.T) {
+			got, err := RankBetween(tc.lower, tc.upper)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf("expected %q, got %q", tc.want, got)
+			}
+			if got <= tc.lower || (tc.upper != "" && got >= tc.upper) {
+				t.Fatalf("rank %q is not between %q and %q", got, tc.lower, tc.upper)
+			}
+		})
+	}
+}
+
+func TestRankBetween_RepeatedInsertsKeepOrder(t *testing.T) {
+	lower, upper := "", ""
+	for i := 0; i < 200; i++ {
+		rank, err := RankBetween(lower, upper)
+		if errors.Is(err, ErrRankExhausted) {
+			return
+		}
+		if err != nil {
+			t.Fatalf("unexpected error after %d inserts: %v", i, err)
+		}
+		if rank <= lower || (upper != "" && rank >= upper) || strings.HasSuffix(rank, "0") {
+			t.Fatalf("invalid rank %q between %q and %q", rank, lower, upper)
+		}
+		if i%2 == 0 {
+			upper = rank
+		} else {
+			lower = rank
+		}
+	}
+}
+
+func TestRankBetween_InvalidInput(t *testing.T) {
+	for _, bounds := range [][2]string{{"b", "a"}, {"a", "a"}, {"a0", ""}, {"A", ""}} {
+		if _, err := RankBetween(bounds[0], bounds[1]); err == nil {
+			t.Fatalf("expected error for %q, %q", bounds[0], bounds[1])
+		}
+	}
+}
+
+func TestEvenRanks(t *testing.T) {
+	ranks := EvenRanks(40)
+	if len(ranks) != 40 || ranks[0] != "01i" {
+		t.Fatalf("unexpected ranks: %v", ranks[:2])
+	}
+	if !sort.StringsAreSorted(ranks) {
+		t.Fatal("ranks must be sorted")
+	}
+	if _, err := RankBetween(ranks[0], ranks[1]); err != nil {
+		t.Fatalf("expected room between even ranks: %v", err)
+	}
+}
+
+func TestServiceMove_Validation(t *testing.T) {
+	five := uint64(5)
+	zero := uint64(0)
+	seven := uint64(7)
+
+	tests := []struct {
+		name  string
+		input MoveTaskInput
+		field string
+	}{
+		{name: "no neighbours", input: MoveTaskInput{}, field: "body"},
+		{name: "zero neighbour", input: MoveTaskInput{AfterID: &zero}, field: "after_id"},
+		{name: "self", input: MoveTaskInput{BeforeID: &five}, field: "before_id"},
+		{name: "same neighbours", input: MoveTaskInput{BeforeID: &seven, AfterID: &seven}, field: "before_id"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockRepository{}
+			_, err := NewService(repo).Move(context.Background(), 5, tc.input)
+			var validationErr ValidationError
+			if !errors.As(err, &validationErr) || validationErr.Field != tc.field {
+				t.Fatalf("expected %s validation error, got %v", tc.field, err)
+			}
+			if repo.moveCalled {
+				t.Fatal("repository must not be called")
+			}
+		})
+	}
+}
+
+func TestServiceMove_PassesNeighbours(t *testing.T) {
+	repo := &mockRepository{getResult: Task{ID: 5, Rank: "ai"}}
+	after := uint64(3)
+
+	moved, err := NewService(repo).Move(context.Background(), 5, MoveTaskInput{AfterID: &after})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.moveID != 5 || repo.moveParams.AfterID != &after || repo.moveParams.BeforeID != nil {
+		t.Fatalf("unexpected move params: %+v", repo.moveParams)
+	}
+	if moved.Rank != "ai" {
+		t.Fatalf("expected the reloaded task, got %+v", moved)
+	}
+}
@@ -0,0 +1,221 @@
+package task
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Frequency string
+
+const (
+	FrequencyDaily   Frequency = "DAILY"
+	FrequencyWeekly  Frequency = "WEEKLY"
+	FrequencyMonthly Frequency = "MONTHLY"
+)
+
+const (
+	maxRecurrenceInterval = 366
+	recurrenceUntilLayout = "20060102T150405Z"
+	recurrenceDateLayout  = "20060102"
+)
+
+var weekdayCodes = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// RecurrenceRule is the supported subset of an RFC 5545 RRULE:
+// FREQ (DAILY, WEEKLY, MONTHLY), INTERVAL, BYDAY (weekly only), UNTIL and COUNT.
+type RecurrenceRule struct {
+	Frequency Frequency
+	Interval  int
+	ByWeekday []time.Weekday
+	Until     *time.Time
+	Count     int
+}
+
+func ParseRecurrenceRule(raw string) (RecurrenceRule, error) {
+	raw = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(raw)), "RRULE:")
+	if raw == "" {
+		return RecurrenceRule{}, recurrenceError("must not be empty")
+	}
+
+	rule := RecurrenceRule{Interval: 1}
+	seen := make(map[string]bool)
+
+	for _, part := range strings.Split(raw, ";") {
+		key, value, found := strings.Cut(part, "=")
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		if !found || key == "" || value == "" {
+			return RecurrenceRule{}, recurrenceError("must be a list of KEY=VALUE pairs separated by ';'")
+		}
+		if seen[key] {
+			return RecurrenceRule{}, recurrenceError(key + " must not be repeated")
+		}
+		seen[key] = true
+
+		switch key {
+		case "FREQ":
+			frequency := Frequency(value)
+			if frequency != FrequencyDaily && frequency != FrequencyWeekly && frequency != FrequencyMonthly {
+				return RecurrenceRule{}, recurrenceError("FREQ must be one of: DAILY, WEEKLY, MONTHLY")
+			}
+			rule.Frequency = frequency
+		case "INTERVAL":
+			interval, err := strconv.Atoi(value)
+			if err != nil || interval < 1 || interval > maxRecurrenceInterval {
+				return RecurrenceRule{}, recurrenceError("INTERVAL must be between 1 and 366")
+			}
+			rule.Interval = interval
+		case "BYDAY":
+			for _, code := range strings.Split(value, ",") {
+				weekday, ok := weekdayCodes[strings.TrimSpace(code)]
+				if !ok {
+					return RecurrenceRule{}, recurrenceError("BYDAY must contain only MO, TU, WE, TH, FR, SA, SU")
+				}
+				rule.ByWeekday = append(rule.ByWeekday, weekday)
+			}
+		case "UNTIL":
+			until, err := parseRecurrenceUntil(value)
+			if err != nil {
+				return RecurrenceRule{}, err
+			}
+			rule.Until = &until
+		case "COUNT":
+			count, err := strconv.Atoi(value)
+			if err != nil || count < 1 {
+				return RecurrenceRule{}, recurrenceError("COUNT must be a positive integer")
+			}
+			rule.Count = count
+		default:
+			return RecurrenceRule{}, recurrenceError("unsupported key " + key)
+		}
+	}
+
+	if rule.Frequency == "" {
+		return RecurrenceRule{}, recurrenceError("FREQ is required")
+	}
+	if len(rule.ByWeekday) > 0 && rule.Frequency != FrequencyWeekly {
+		return RecurrenceRule{}, recurrenceError("BYDAY is supported only with FREQ=WEEKLY")
+	}
+	if rule.Until != nil && rule.Count > 0 {
+		return RecurrenceRule{}, recurrenceError("UNTIL and COUNT must not be combined")
+	}
+
+	return rule, nil
+}
+
+func (r RecurrenceRule) String() string {
+	parts := []string{"FREQ=" + string(r.Frequency)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByWeekday) > 0 {
+		codes := make([]string, 0, len(r.ByWeekday))
+		for _, weekday := range r.ByWeekday {
+			codes = append(codes, strings.ToUpper(weekday.String()[:2]))
+		}
+		parts = append(parts, "BYDAY="+strings.Join(codes, ","))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format(recurrenceUntilLayout))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Next returns the occurrence following from and the rule that the next
+// occurrence should carry. COUNT is decremented so that every occurrence
+// knows how many repetitions remain. ok is false when the series is over.
+func (r RecurrenceRule) Next(from time.Time) (time.Time, RecurrenceRule, bool) {
+	if r.Count == 1 {
+		return time.Time{}, RecurrenceRule{}, false
+	}
+
+	var next time.Time
+	switch r.Frequency {
+	case FrequencyDaily:
+		next = from.AddDate(0, 0, r.Interval)
+	case FrequencyWeekly:
+		next = r.nextWeekly(from)
+	case FrequencyMonthly:
+		next = addMonthsClamped(from, r.Interval)
+	default:
+		return time.Time{}, RecurrenceRule{}, false
+	}
+
+	if r.Until != nil && next.After(*r.Until) {
+		return time.Time{}, RecurrenceRule{}, false
+	}
+
+	nextRule := r
+	if nextRule.Count > 0 {
+		nextRule.Count--
+	}
+	return next, nextRule, true
+}
+
+func (r RecurrenceRule) nextWeekly(from time.Time) time.Time {
+	if len(r.ByWeekday) == 0 {
+		return from.AddDate(0, 0, 7*r.Interval)
+	}
+
+	allowed := make(map[time.Weekday]bool, len(r.ByWeekday))
+	for _, weekday := range r.ByWeekday {
+		allowed[weekday] = true
+	}
+
+	weekStart := startOfWeek(from)
+	for offset := 1; offset <= 7*r.Interval+7; offset++ {
+		candidate := from.AddDate(0, 0, offset)
+		weeks := int(startOfWeek(candidate).Sub(weekStart).Hours()/24) / 7
+		if weeks%r.Interval == 0 && allowed[candidate.Weekday()] {
+			return candidate
+		}
+	}
+
+	return from.AddDate(0, 0, 7*r.Interval)
+}
+
+// startOfWeek returns midnight of the Monday of the week containing t,
+// which is the RFC 5545 default WKST.
+func startOfWeek(t time.Time) time.Time {
+	daysSinceMonday := (int(t.Weekday()) + 6) % 7
+	year, month, day := t.Date()
+	return time.Date(year, month, day-daysSinceMonday, 0, 0, 0, 0, t.Location())
+}
+
+// addMonthsClamped moves t by the given number of months and clamps the day
+// to the last day of shorter months instead of overflowing into the next one.
+func addMonthsClamped(t time.Time, months int) time.Time {
+	year, month, day := t.Date()
+	firstOfTarget := time.Date(year, month+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	lastDay := firstOfTarget.AddDate(0, 1, -1).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return firstOfTarget.AddDate(0, 0, day-1)
+}
+
+func parseRecurrenceUntil(value string) (time.Time, error) {
+	if parsed, err := time.Parse(recurrenceUntilLayout, value); err == nil {
+		return parsed.UTC(), nil
+	}
+	if parsed, err := time.Parse(recurrenceDateLayout, value); err == nil {
+		return parsed.Add(24*time.Hour - time.Second).UTC(), nil
+	}
+	return time.Time{}, recurrenceError("UNTIL must be in YYYYMMDD or YYYYMMDDTHHMMSSZ format")
+}
+
+func recurrenceError(message string) ValidationError {
+	return ValidationError{Field: "recurrence_rule", Message: message}
+}
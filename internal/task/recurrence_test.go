@@ -0,0 +1,100 @@
+package task
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseRecurrenceRule(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    string
+		invalid bool
+	}{
+		{raw: "FREQ=DAILY", want: "FREQ=DAILY"},
+		{raw: "RRULE:freq=weekly;interval=2;byday=mo,we", want: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE"},
+		{raw: "FREQ=MONTHLY;UNTIL=20261231", want: "FREQ=MONTHLY;UNTIL=20261231T235959Z"},
+		{raw: "FREQ=DAILY;COUNT=5;INTERVAL=1", want: "FREQ=DAILY;COUNT=5"},
+		{raw: "", invalid: true},
+		{raw: "INTERVAL=2", invalid: true},
+		{raw: "FREQ=YEARLY", invalid: true},
+		{raw: "FREQ=DAILY;BYDAY=MO", invalid: true},
+		{raw: "FREQ=WEEKLY;BYDAY=XX", invalid: true},
+		{raw: "FREQ=DAILY;INTERVAL=0", invalid: true},
+		{raw: "FREQ=DAILY;COUNT=2;UNTIL=20261231", invalid: true},
+		{raw: "FREQ=DAILY;FREQ=WEEKLY", invalid: true},
+		{raw: "FREQ=DAILY;BYMONTH=1", invalid: true},
+	}
+
+	for _, tc := range tests {
+		rule, err := ParseRecurrenceRule(tc.raw)
+		if tc.invalid {
+			var verr ValidationError
+			if !errors.As(err, &verr) || verr.Field != "recurrence_rule" {
+				t.Fatalf("%q: expected recurrence_rule ValidationError, got %v", tc.raw, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", tc.raw, err)
+		}
+		if got := rule.String(); got != tc.want {
+			t.Fatalf("%q: expected %q, got %q", tc.raw, tc.want, got)
+		}
+	}
+}
+
+func TestRecurrenceRuleNext(t *testing.T) {
+	// 2026-03-04 is a Wednesday.
+	from := time.Date(2026, 3, 4, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		rule string
+		from time.Time
+		want time.Time
+	}{
+		{rule: "FREQ=DAILY;INTERVAL=3", from: from, want: time.Date(2026, 3, 7, 9, 30, 0, 0, time.UTC)},
+		{rule: "FREQ=WEEKLY", from: from, want: time.Date(2026, 3, 11, 9, 30, 0, 0, time.UTC)},
+		{rule: "FREQ=WEEKLY;BYDAY=MO,FR", from: from, want: time.Date(2026, 3, 6, 9, 30, 0, 0, time.UTC)},
+		{rule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO", from: from, want: time.Date(2026, 3, 16, 9, 30, 0, 0, time.UTC)},
+		{rule: "FREQ=MONTHLY", from: from, want: time.Date(2026, 4, 4, 9, 30, 0, 0, time.UTC)},
+		{
+			rule: "FREQ=MONTHLY",
+			from: time.Date(2026, 1, 31, 8, 0, 0, 0, time.UTC),
+			want: time.Date(2026, 2, 28, 8, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tc := range tests {
+		rule, err := ParseRecurrenceRule(tc.rule)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", tc.rule, err)
+		}
+		got, _, ok := rule.Next(tc.from)
+		if !ok {
+			t.Fatalf("%q: expected next occurrence", tc.rule)
+		}
+		if !got.Equal(tc.want) {
+			t.Fatalf("%q: expected %v, got %v", tc.rule, tc.want, got)
+		}
+	}
+}
+
+func TestRecurrenceRuleNext_SeriesEnd(t *testing.T) {
+	from := time.Date(2026, 3, 4, 9, 0, 0, 0, time.UTC)
+
+	countRule, _ := ParseRecurrenceRule("FREQ=DAILY;COUNT=2")
+	_, nextRule, ok := countRule.Next(from)
+	if !ok || nextRule.Count != 1 {
+		t.Fatalf("expected next occurrence with COUNT=1, got ok=%v count=%d", ok, nextRule.Count)
+	}
+	if _, _, ok := nextRule.Next(from); ok {
+		t.Fatal("expected series to end after the last counted occurrence")
+	}
+
+	untilRule, _ := ParseRecurrenceRule("FREQ=WEEKLY;UNTIL=20260310")
+	if _, _, ok := untilRule.Next(from); ok {
+		t.Fatal("expected series to end after UNTIL")
+	}
+}
@@ -0,0 +1,168 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/notification"
+)
+
+const reminderBatchSize = 100
+
+// ReminderThreshold is a point relative to a task's due date at which a
+// reminder is sent: negative offsets are before the due date, zero is the due
+// date itself and positive offsets are after it.
+type ReminderThreshold struct {
+	Name   string
+	Offset time.Duration
+}
+
+// ReminderWindow selects not done tasks with DueAfter < due_at <= DueUntil
+// that have not been reminded about Threshold yet. DueAfter is nil for the
+// last threshold.
+type ReminderWindow struct {
+	Threshold ReminderThreshold
+	DueAfter  *time.Time
+	DueUntil  time.Time
+}
+
+type ReminderRepository interface {
+	ListReminderCandidates(ctx context.Context, window ReminderWindow, limit int) ([]Task, error)
+	MarkReminderSent(ctx context.Context, taskID uint64, threshold string, dueAt time.Time) (bool, error)
+	UnmarkReminderSent(ctx context.Context, taskID uint64, threshold string, dueAt time.Time) error
+}
+
+type ReminderService interface {
+	SendDueReminders(ctx context.Context, now time.Time) (int, error)
+}
+
+type reminderService struct {
+	repo       ReminderRepository
+	notifier   notification.Notifier
+	thresholds []ReminderThreshold
+}
+
+func NewReminderService(repo ReminderRepository, notifier notification.Notifier, offsets []time.Duration) ReminderService {
+	return &reminderService{
+		repo:       repo,
+		notifier:   notifier,
+		thresholds: NewReminderThresholds(offsets),
+	}
+}
+
+// NewReminderThresholds sorts and de-duplicates offsets and names them
+// "due_in_<offset>", "due" and "overdue_<offset>".
+func NewReminderThresholds(offsets []time.Duration) []ReminderThreshold {
+	sorted := append([]time.Duration(nil), offsets...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	thresholds := make([]ReminderThreshold, 0, len(sorted))
+	for i, offset := range sorted {
+		if i > 0 && offset == sorted[i-1] {
+			continue
+		}
+		thresholds = append(thresholds, ReminderThreshold{Name: thresholdName(offset), Offset: offset})
+	}
+	return thresholds
+}
+
+// SendDueReminders sends every reminder that became due by now. Only the
+// latest reached threshold is sent for a task, so a task that is discovered
+// long after its due date gets a single overdue reminder instead of the whole
+// sequence. A reminder is recorded before delivery and the record is removed
+// again if delivery fails, so delivery is at-most-once: a restart never
+// repeats a reminder, but a crash between recording and delivery loses it.
+func (s *reminderService) SendDueReminders(ctx context.Context, now time.Time) (int, error) {
+	now = now.UTC()
+	sent := 0
+
+	for i, threshold := range s.thresholds {
+		window := ReminderWindow{
+			Threshold: threshold,
+			DueUntil:  now.Add(-threshold.Offset),
+		}
+		if i+1 < len(s.thresholds) {
+			dueAfter := now.Add(-s.thresholds[i+1].Offset)
+			window.DueAfter = &dueAfter
+		}
+
+		candidates, err := s.repo.ListReminderCandidates(ctx, window, reminderBatchSize)
+		if err != nil {
+			return sent, err
+		}
+
+		for _, candidate := range candidates {
+			delivered, err := s.remind(ctx, candidate, threshold)
+			if err != nil {
+				return sent, err
+			}
+			if delivered {
+				sent++
+			}
+		}
+	}
+
+	return sent, nil
+}
+
+func (s *reminderService) remind(ctx context.Context, candidate Task, threshold ReminderThreshold) (bool, error) {
+	if candidate.DueAt == nil {
+		return false, nil
+	}
+
+	claimed, err := s.repo.MarkReminderSent(ctx, candidate.ID, threshold.Name, *candidate.DueAt)
+	if err != nil || !claimed {
+		return false, err
+	}
+
+	notifyErr := s.notifier.Notify(ctx, notification.Notification{
+		Type:      notification.TypeTaskReminder,
+		TaskID:    candidate.ID,
+		Subject:   reminderSubject(candidate, threshold),
+		Message:   fmt.Sprintf("Task #%d is due at %s", candidate.ID, candidate.DueAt.UTC().Format(time.RFC3339)),
+		CreatedAt: time.Now().UTC(),
+	})
+	if notifyErr != nil {
+		unmarkErr := s.repo.UnmarkReminderSent(ctx, candidate.ID, threshold.Name, *candidate.DueAt)
+		return false, errors.Join(notifyErr, unmarkErr)
+	}
+
+	return true, nil
+}
+
+func reminderSubject(candidate Task, threshold ReminderThreshold) string {
+	switch {
+	case threshold.Offset < 0:
+		return fmt.Sprintf("Task %q is due in %s", candidate.Title, formatOffset(-threshold.Offset))
+	case threshold.Offset == 0:
+		return fmt.Sprintf("Task %q is due now", candidate.Title)
+	default:
+		return fmt.Sprintf("Task %q is overdue by %s", candidate.Title, formatOffset(threshold.Offset))
+	}
+}
+
+func thresholdName(offset time.Duration) string {
+	switch {
+	case offset < 0:
+		return "due_in_" + formatOffset(-offset)
+	case offset == 0:
+		return "due"
+	default:
+		return "overdue_" + formatOffset(offset)
+	}
+}
+
+func formatOffset(offset time.Duration) string {
+	formatted := offset.String()
+	if strings.HasSuffix(formatted, "m0s") {
+		formatted = strings.TrimSuffix(formatted, "0s")
+	}
+	if strings.HasSuffix(formatted, "h0m") {
+		formatted = strings.TrimSuffix(formatted, "0m")
+	}
+	return formatted
+}
@@ -0,0 +1,135 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/notification"
+)
+
+type mockReminderRepository struct {
+	windows    []ReminderWindow
+	candidates map[string][]Task
+	sent       map[string]bool
+	unmarked   []string
+
+	listErr error
+}
+
+func (m *mockReminderRepository) ListReminderCandidates(_ context.Context, window ReminderWindow, _ int) ([]Task, error) {
+	m.windows = append(m.windows, window)
+	if m.listErr != nil {
+		return nil, m.listErr
+	}
+	return m.candidates[window.Threshold.Name], nil
+}
+
+func (m *mockReminderRepository) MarkReminderSent(_ context.Context, taskID uint64, threshold string, _ time.Time) (bool, error) {
+	key := fmt.Sprintf("%s/%d", threshold, taskID)
+	if m.sent[key] {
+		return false, nil
+	}
+	m.sent[key] = true
+	return true, nil
+}
+
+func (m *mockReminderRepository) UnmarkReminderSent(_ context.Context, taskID uint64, threshold string, _ time.Time) error {
+	key := fmt.Sprintf("%s/%d", threshold, taskID)
+	delete(m.sent, key)
+	m.unmarked = append(m.unmarked, key)
+	return nil
+}
+
+type mockNotifier struct {
+	notifications []notification.Notification
+	err           error
+}
+
+func (m *mockNotifier) Notify(_ context.Context, n notification.Notification) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.notifications = append(m.notifications, n)
+	return nil
+}
+
+func TestNewReminderThresholds(t *testing.T) {
+	thresholds := NewReminderThresholds([]time.Duration{24 * time.Hour, -90 * time.Minute, 0, 24 * time.Hour})
+
+	want := []string{"due_in_1h30m", "due", "overdue_24h"}
+	if len(thresholds) != len(want) {
+		t.Fatalf("expected %d thresholds, got %d", len(want), len(thresholds))
+	}
+	for i, name := range want {
+		if thresholds[i].Name != name {
+			t.Fatalf("threshold %d: expected %q, got %q", i, name, thresholds[i].Name)
+		}
+	}
+}
+
+func TestReminderServiceSendDueReminders(t *testing.T) {
+	now := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+	dueSoon := now.Add(2 * time.Hour)
+	overdue := now.Add(-48 * time.Hour)
+
+	repo := &mockReminderRepository{
+		candidates: map[string][]Task{
+			"due_in_24h":  {{ID: 1, Title: "Soon", DueAt: &dueSoon}},
+			"overdue_24h": {{ID: 2, Title: "Late", DueAt: &overdue}},
+		},
+		sent: map[string]bool{},
+	}
+	notifier := &mockNotifier{}
+	svc := NewReminderService(repo, notifier, []time.Duration{-24 * time.Hour, 0, 24 * time.Hour})
+
+	sent, err := svc.SendDueReminders(context.Background(), now)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if sent != 2 || len(notifier.notifications) != 2 {
+		t.Fatalf("expected 2 reminders, got sent=%d notifications=%d", sent, len(notifier.notifications))
+	}
+	if notifier.notifications[0].Subject != `Task "Soon" is due in 24h` {
+		t.Fatalf("unexpected subject: %q", notifier.notifications[0].Subject)
+	}
+
+	if len(repo.windows) != 3 {
+		t.Fatalf("expected a window per threshold, got %d", len(repo.windows))
+	}
+	first := repo.windows[0]
+	if !first.DueUntil.Equal(now.Add(24*time.Hour)) || first.DueAfter == nil || !first.DueAfter.Equal(now) {
+		t.Fatalf("unexpected first window: %+v", first)
+	}
+	if last := repo.windows[2]; last.DueAfter != nil || !last.DueUntil.Equal(now.Add(-24*time.Hour)) {
+		t.Fatalf("unexpected last window: %+v", last)
+	}
+
+	sent, err = svc.SendDueReminders(context.Background(), now)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if sent != 0 {
+		t.Fatalf("expected already sent reminders to be skipped, got %d", sent)
+	}
+}
+
+func TestReminderServiceSendDueReminders_UnmarksOnDeliveryFailure(t *testing.T) {
+	now := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+	repo := &mockReminderRepository{
+		candidates: map[string][]Task{"due": {{ID: 3, DueAt: &now}}},
+		sent:       map[string]bool{},
+	}
+	deliveryErr := errors.New("smtp down")
+	svc := NewReminderService(repo, &mockNotifier{err: deliveryErr}, []time.Duration{0})
+
+	_, err := svc.SendDueReminders(context.Background(), now)
+	if !errors.Is(err, deliveryErr) {
+		t.Fatalf("expected delivery error, got %v", err)
+	}
+	if len(repo.unmarked) != 1 || len(repo.sent) != 0 {
+		t.Fatalf("expected reminder to be released for retry, unmarked=%v sent=%v", repo.unmarked, repo.sent)
+	}
+}
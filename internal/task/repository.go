@@ -6,6 +6,17 @@ type Repository interface {
 	Create(ctx context.Context, params CreateParams) (Task, error)
 	GetByID(ctx context.Context, id uint64) (Task, error)
 	List(ctx context.Context, filter ListFilter) ([]Task, error)
+	Summarize(ctx context.Context, filter ListFilter) (ListSummary, error)
 	Update(ctx context.Context, id uint64, params UpdateParams) (Task, error)
-	Delete(ctx context.Context, id uint64) error
+	Delete(ctx context.Context, id uint64, expectedVersion *uint64) error
+	Restore(ctx context.Context, id uint64) error
+	Archive(ctx context.Context, id uint64) error
+	Unarchive(ctx context.Context, id uint64) error
+	Move(ctx context.Context, id uint64, params MoveParams) error
+	CreateNextOccurrence(ctx context.Context, sourceID uint64, params CreateParams) (Task, error)
+	GetProject(ctx context.Context, id uint64) (Project, error)
+	GetSprint(ctx context.Context, id uint64) (Sprint, error)
+	GetMilestone(ctx context.Context, id uint64) (Milestone, error)
+	ListWatchers(ctx context.Context, taskID uint64) ([]Watcher, error)
+	FindUsersByUsername(ctx context.Context, usernames []string) ([]User, error)
 }
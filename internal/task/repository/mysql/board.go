@@ -0,0 +1,113 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/task"
+)
+
+var _ task.BoardRepository = (*Repository)(nil)
+
+func (r *Repository) SetWIPLimits(ctx context.Context, projectID uint64, limits map[task.Status]int) error {
+	return r.withTx(ctx, func(tx *sql.Tx) error {
+		if err := lockProject(ctx, tx, projectID); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM project_wip_limits WHERE project_id = ?`, projectID); err != nil {
+			return err
+		}
+
+		for status, limit := range limits {
+			_, err := tx.ExecContext(
+				ctx,
+				`INSERT INTO project_wip_limits (project_id, status, wip_limit) VALUES (?, ?, ?)`,
+				projectID,
+				status,
+				limit,
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err := tx.ExecContext(ctx, `UPDATE projects SET updated_at = CURRENT_TIMESTAMP WHERE id = ?`, projectID)
+		return err
+	})
+}
+
+func (r *Repository) attachWIPLimits(ctx context.Context, projects []task.Project) error {
+	if len(projects) == 0 {
+		return nil
+	}
+
+	indexByID := make(map[uint64]int, len(projects))
+	ids := make([]any, 0, len(projects))
+	for i := range projects {
+		indexByID[projects[i].ID] = i
+		ids = append(ids, projects[i].ID)
+	}
+
+	query := fmt.Sprintf(
+		"SELECT project_id, status, wip_limit FROM project_wip_limits WHERE project_id IN (%s)",
+		placeholders(len(ids)),
+	)
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, ids...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			projectID uint64
+			status    task.Status
+			limit     int
+		)
+		if err := rows.Scan(&projectID, &status, &limit); err != nil {
+			return err
+		}
+		if i, ok := indexByID[projectID]; ok {
+			projects[i].WIPLimits[status] = limit
+		}
+	}
+
+	return rows.Err()
+}
+
+// checkWIPLimit fails when moving a task into a status column would exceed
+// the column's limit. The project row is locked so that concurrent moves into
+// the same column are counted one after another.
+func checkWIPLimit(ctx context.Context, tx *sql.Tx, taskID uint64, status task.Status, check task.WIPLimitCheck) error {
+	if err := lockProject(ctx, tx, check.ProjectID); err != nil {
+		return err
+	}
+
+	const query = `
+		SELECT COUNT(*)
+		FROM tasks
+		WHERE project_id = ? AND status = ? AND id <> ? AND deleted_at IS NULL AND archived_at IS NULL
+	`
+
+	var count int
+	if err := tx.QueryRowContext(ctx, query, check.ProjectID, status, taskID).Scan(&count); err != nil {
+		return err
+	}
+	if count >= check.Limit {
+		return task.ErrWIPLimitExceeded
+	}
+	return nil
+}
+
+func lockProject(ctx context.Context, tx *sql.Tx, id uint64) error {
+	var lockedID uint64
+	err := tx.QueryRowContext(ctx, `SELECT id FROM projects WHERE id = ? FOR UPDATE`, id).Scan(&lockedID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return task.ErrProjectNotFound
+	}
+	return err
+}
@@ -0,0 +1,100 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/task"
+)
+
+var _ task.CalendarRepository = (*Repository)(nil)
+
+const calendarFeedColumns = `id, owner_id, project_id, created_at`
+
+func (r *Repository) CreateCalendarFeed(ctx context.Context, params task.CalendarFeedParams) (task.CalendarFeed, error) {
+	result, err := r.conn(ctx).ExecContext(
+		ctx,
+		`INSERT INTO calendar_feeds (owner_id, project_id, token_hash) VALUES (?, ?, ?)`,
+		params.OwnerID,
+		asNullable(params.ProjectID),
+		params.TokenHash,
+	)
+	if err != nil {
+		return task.CalendarFeed{}, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return task.CalendarFeed{}, err
+	}
+
+	feeds, err := r.queryCalendarFeeds(ctx, `SELECT `+calendarFeedColumns+` FROM calendar_feeds WHERE id = ?`, id)
+	if err != nil {
+		return task.CalendarFeed{}, err
+	}
+	if len(feeds) == 0 {
+		return task.CalendarFeed{}, task.ErrCalendarFeedNotFound
+	}
+	return feeds[0], nil
+}
+
+func (r *Repository) FindCalendarFeed(ctx context.Context, tokenHash string) (task.CalendarFeed, error) {
+	feeds, err := r.queryCalendarFeeds(ctx, `SELECT `+calendarFeedColumns+` FROM calendar_feeds WHERE token_hash = ?`, tokenHash)
+	if err != nil {
+		return task.CalendarFeed{}, err
+	}
+	if len(feeds) == 0 {
+		return task.CalendarFeed{}, task.ErrCalendarFeedNotFound
+	}
+	return feeds[0], nil
+}
+
+func (r *Repository) ListCalendarFeeds(ctx context.Context, ownerID string) ([]task.CalendarFeed, error) {
+	return r.queryCalendarFeeds(ctx, `SELECT `+calendarFeedColumns+` FROM calendar_feeds WHERE owner_id = ? ORDER BY created_at, id`, ownerID)
+}
+
+func (r *Repository) DeleteCalendarFeed(ctx context.Context, id uint64, ownerID string) error {
+	result, err := r.conn(ctx).ExecContext(ctx, `DELETE FROM calendar_feeds WHERE id = ? AND owner_id = ?`, id, ownerID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return task.ErrCalendarFeedNotFound
+	}
+
+	return nil
+}
+
+func (r *Repository) queryCalendarFeeds(ctx context.Context, query string, args ...any) ([]task.CalendarFeed, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	feeds := make([]task.CalendarFeed, 0)
+	for rows.Next() {
+		var (
+			feed      task.CalendarFeed
+			projectID sql.NullInt64
+			createdAt time.Time
+		)
+		if err := rows.Scan(&feed.ID, &feed.OwnerID, &projectID, &createdAt); err != nil {
+			return nil, err
+		}
+		if projectID.Valid {
+			id := uint64(projectID.Int64)
+			feed.ProjectID = &id
+		}
+		feed.CreatedAt = createdAt.UTC()
+		feeds = append(feeds, feed)
+	}
+
+	return feeds, rows.Err()
+}
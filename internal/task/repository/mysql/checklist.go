@@ -0,0 +1,270 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/task"
+)
+
+var _ task.ChecklistRepository = (*Repository)(nil)
+
+func (r *Repository) ListChecklistItems(ctx context.Context, taskID uint64) ([]task.ChecklistItem, error) {
+	if err := r.ensureTaskExists(ctx, taskID); err != nil {
+		return nil, err
+	}
+
+	const query = `
+		SELECT id, task_id, position, text, checked_at, created_at, updated_at
+		FROM task_checklist_items
+		WHERE task_id = ?
+		ORDER BY position, id
+	`
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]task.ChecklistItem, 0)
+	for rows.Next() {
+		var (
+			item      task.ChecklistItem
+			checkedAt sql.NullTime
+			createdAt time.Time
+			updatedAt time.Time
+		)
+		if err := rows.Scan(&item.ID, &item.TaskID, &item.Position, &item.Text, &checkedAt, &createdAt, &updatedAt); err != nil {
+			return nil, err
+		}
+		item.CheckedAt = nullableTime(checkedAt)
+		item.Checked = item.CheckedAt != nil
+		item.CreatedAt = createdAt.UTC()
+		item.UpdatedAt = updatedAt.UTC()
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+// AddChecklistItem inserts an item and shifts the following ones. Checklist
+// positions are kept dense (0..n-1) per task and every change locks the task
+// row so that concurrent changes cannot interleave.
+func (r *Repository) AddChecklistItem(ctx context.Context, taskID uint64, params task.AddChecklistItemParams) error {
+	return r.withTx(ctx, func(tx *sql.Tx) error {
+		if err := lockTask(ctx, tx, taskID); err != nil {
+			return err
+		}
+
+		count, err := countChecklistItems(ctx, tx, taskID)
+		if err != nil {
+			return err
+		}
+		if count >= params.MaxItems {
+			return task.ErrChecklistFull
+		}
+
+		position := count
+		if params.Position != nil && *params.Position < count {
+			position = *params.Position
+			_, err := tx.ExecContext(
+				ctx,
+				`UPDATE task_checklist_items SET position = position + 1 WHERE task_id = ? AND position >= ?`,
+				taskID,
+				position,
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.ExecContext(
+			ctx,
+			`INSERT INTO task_checklist_items (task_id, position, text) VALUES (?, ?, ?)`,
+			taskID,
+			position,
+			params.Text,
+		)
+		if err != nil {
+			return err
+		}
+
+		return touchTask(ctx, tx, taskID)
+	})
+}
+
+func (r *Repository) UpdateChecklistItem(ctx context.Context, taskID, itemID uint64, params task.UpdateChecklistItemParams) error {
+	return r.withTx(ctx, func(tx *sql.Tx) error {
+		if err := lockTask(ctx, tx, taskID); err != nil {
+			return err
+		}
+
+		current, err := checklistItemPosition(ctx, tx, taskID, itemID)
+		if err != nil {
+			return err
+		}
+
+		setClauses := make([]string, 0, 3)
+		args := make([]any, 0, 4)
+		if params.Text != nil {
+			setClauses = append(setClauses, "text = ?")
+			args = append(args, *params.Text)
+		}
+		if params.Checked != nil {
+			setClauses = append(setClauses, "checked_at = IF(?, COALESCE(checked_at, CURRENT_TIMESTAMP), NULL)")
+			args = append(args, *params.Checked)
+		}
+
+		if params.Position != nil {
+			count, err := countChecklistItems(ctx, tx, taskID)
+			if err != nil {
+				return err
+			}
+			target := min(*params.Position, count-1)
+			if err := shiftChecklistItems(ctx, tx, taskID, current, target); err != nil {
+				return err
+			}
+			setClauses = append(setClauses, "position = ?")
+			args = append(args, target)
+		}
+
+		if len(setClauses) > 0 {
+			query := fmt.Sprintf("UPDATE task_checklist_items SET %s WHERE id = ?", strings.Join(setClauses, ", "))
+			if _, err := tx.ExecContext(ctx, query, append(args, itemID)...); err != nil {
+				return err
+			}
+		}
+
+		return touchTask(ctx, tx, taskID)
+	})
+}
+
+func (r *Repository) DeleteChecklistItem(ctx context.Context, taskID, itemID uint64) error {
+	return r.withTx(ctx, func(tx *sql.Tx) error {
+		if err := lockTask(ctx, tx, taskID); err != nil {
+			return err
+		}
+
+		position, err := checklistItemPosition(ctx, tx, taskID, itemID)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM task_checklist_items WHERE id = ?`, itemID); err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(
+			ctx,
+			`UPDATE task_checklist_items SET position = position - 1 WHERE task_id = ? AND position > ?`,
+			taskID,
+			position,
+		)
+		if err != nil {
+			return err
+		}
+
+		return touchTask(ctx, tx, taskID)
+	})
+}
+
+func (r *Repository) attachChecklistProgress(ctx context.Context, tasks []task.Task, indexByID map[uint64]int, ids []any) error {
+	query := fmt.Sprintf(`
+		SELECT task_id, COUNT(*), COUNT(checked_at)
+		FROM task_checklist_items
+		WHERE task_id IN (%s)
+		GROUP BY task_id
+	`, placeholders(len(ids)))
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, ids...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			taskID   uint64
+			progress task.ChecklistProgress
+		)
+		if err := rows.Scan(&taskID, &progress.Total, &progress.Completed); err != nil {
+			return err
+		}
+		if i, ok := indexByID[taskID]; ok {
+			tasks[i].Checklist = progress
+		}
+	}
+
+	return rows.Err()
+}
+
+// shiftChecklistItems makes room at position to for the item currently at
+// position from.
+func shiftChecklistItems(ctx context.Context, tx *sql.Tx, taskID uint64, from, to int) error {
+	var err error
+	switch {
+	case to < from:
+		_, err = tx.ExecContext(
+			ctx,
+			`UPDATE task_checklist_items SET position = position + 1 WHERE task_id = ? AND position >= ? AND position < ?`,
+			taskID, to, from,
+		)
+	case to > from:
+		_, err = tx.ExecContext(
+			ctx,
+			`UPDATE task_checklist_items SET position = position - 1 WHERE task_id = ? AND position > ? AND position <= ?`,
+			taskID, from, to,
+		)
+	}
+	return err
+}
+
+func checklistItemPosition(ctx context.Context, tx *sql.Tx, taskID, itemID uint64) (int, error) {
+	var position int
+	err := tx.QueryRowContext(
+		ctx,
+		`SELECT position FROM task_checklist_items WHERE id = ? AND task_id = ?`,
+		itemID,
+		taskID,
+	).Scan(&position)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, task.ErrChecklistItemNotFound
+	}
+	return position, err
+}
+
+func countChecklistItems(ctx context.Context, tx *sql.Tx, taskID uint64) (int, error) {
+	var count int
+	err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM task_checklist_items WHERE task_id = ?`, taskID).Scan(&count)
+	return count, err
+}
+
+// copyChecklist copies the checklist of a task to another one with every item
+// unchecked.
+func copyChecklist(ctx context.Context, tx *sql.Tx, sourceID, targetID uint64) error {
+	const query = `
+		INSERT INTO task_checklist_items (task_id, position, text)
+		SELECT ?, position, text
+		FROM task_checklist_items
+		WHERE task_id = ?
+	`
+	_, err := tx.ExecContext(ctx, query, targetID, sourceID)
+	return err
+}
+
+func touchTask(ctx context.Context, tx *sql.Tx, taskID uint64) error {
+	_, err := tx.ExecContext(ctx, `UPDATE tasks SET updated_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id = ?`, taskID)
+	return err
+}
+
+// bumpTaskVersion changes the ETag of a task whose body changes through
+// another table, without touching updated_at.
+func bumpTaskVersion(ctx context.Context, tx *sql.Tx, taskID uint64) error {
+	_, err := tx.ExecContext(ctx, `UPDATE tasks SET version = version + 1 WHERE id = ?`, taskID)
+	return err
+}
@@ -0,0 +1,91 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/task"
+)
+
+var _ task.CommentRepository = (*Repository)(nil)
+
+const commentColumns = `id, task_id, author_id, body, created_at`
+
+func (r *Repository) CreateComment(ctx context.Context, params task.CommentParams) (task.Comment, error) {
+	var id uint64
+	err := r.withTx(ctx, func(tx *sql.Tx) error {
+		if err := lockTask(ctx, tx, params.TaskID); err != nil {
+			return err
+		}
+
+		result, err := tx.ExecContext(
+			ctx,
+			`INSERT INTO task_comments (task_id, author_id, body) VALUES (?, ?, ?)`,
+			params.TaskID,
+			params.AuthorID,
+			params.Body,
+		)
+		if err != nil {
+			return err
+		}
+		insertedID, err := result.LastInsertId()
+		if err != nil {
+			return err
+		}
+		id = uint64(insertedID)
+
+		if err := addWatcher(ctx, tx, params.TaskID, params.AuthorID); err != nil {
+			return err
+		}
+		return addMentions(ctx, tx, params.TaskID, &id, &params.AuthorID, params.Mentions)
+	})
+	if err != nil {
+		return task.Comment{}, err
+	}
+
+	const query = `SELECT ` + commentColumns + ` FROM task_comments WHERE id = ?`
+	return scanComment(r.conn(ctx).QueryRowContext(ctx, query, id))
+}
+
+func (r *Repository) ListComments(ctx context.Context, taskID uint64) ([]task.Comment, error) {
+	if err := r.ensureTaskExists(ctx, taskID); err != nil {
+		return nil, err
+	}
+
+	const query = `
+		SELECT ` + commentColumns + `
+		FROM task_comments
+		WHERE task_id = ?
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	comments := make([]task.Comment, 0)
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, comment)
+	}
+
+	return comments, rows.Err()
+}
+
+func scanComment(scanner sqlScanner) (task.Comment, error) {
+	var (
+		comment   task.Comment
+		createdAt time.Time
+	)
+	if err := scanner.Scan(&comment.ID, &comment.TaskID, &comment.AuthorID, &comment.Body, &createdAt); err != nil {
+		return task.Comment{}, err
+	}
+	comment.CreatedAt = createdAt.UTC()
+	return comment, nil
+}
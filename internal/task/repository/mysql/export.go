@@ -0,0 +1,74 @@
+package mysql
+
+import (
+	"context"
+	"strings"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/task"
+)
+
+var _ task.ExportRepository = (*Repository)(nil)
+
+// exportBatchSize is how many streamed tasks are hydrated at once.
+const exportBatchSize = 500
+
+// StreamTasks reads the tasks from a single query and hydrates them in
+// batches, so memory use does not grow with the number of tasks. The batches
+// are hydrated over another connection while the query is still being read,
+// so StreamTasks cannot take part in a RunInTx transaction.
+func (r *Repository) StreamTasks(ctx context.Context, filter task.ListFilter, fn func(task.Task) error) error {
+	var queryBuilder strings.Builder
+	queryBuilder.WriteString(`
+		SELECT ` + taskColumns + `
+		FROM tasks
+	`)
+
+	conditions, args := listConditions(filter)
+	if len(conditions) > 0 {
+		queryBuilder.WriteString(" WHERE ")
+		queryBuilder.WriteString(strings.Join(conditions, " AND "))
+	}
+
+	order, orderArgs := orderBy(filter)
+	queryBuilder.WriteString(" ORDER BY ")
+	queryBuilder.WriteString(order)
+	args = append(args, orderArgs...)
+
+	rows, err := r.conn(ctx).QueryContext(ctx, queryBuilder.String(), args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	batch := make([]task.Task, 0, exportBatchSize)
+	flush := func() error {
+		if err := r.hydrate(ctx, batch); err != nil {
+			return err
+		}
+		for _, streamed := range batch {
+			if err := fn(streamed); err != nil {
+				return err
+			}
+		}
+		batch = batch[:0]
+		return nil
+	}
+
+	for rows.Next() {
+		streamed, err := scanTask(rows)
+		if err != nil {
+			return err
+		}
+		batch = append(batch, streamed)
+		if len(batch) == exportBatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	return flush()
+}
@@ -0,0 +1,114 @@
+package mysql
+
+import (
+	"strings"
+	"time"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/task"
+)
+
+// filterColumns maps filter fields to task columns. Labels are matched
+// through task_labels instead.
+var filterColumns = map[task.FilterField]struct {
+	column   string
+	nullable bool
+}{
+	task.FilterStatus:      {column: "status"},
+	task.FilterPriority:    {column: "priority"},
+	task.FilterTitle:       {column: "title"},
+	task.FilterDescription: {column: "description", nullable: true},
+	task.FilterAssignee:    {column: "assignee_id", nullable: true},
+	task.FilterReporter:    {column: "reporter_id", nullable: true},
+	task.FilterProject:     {column: "project_id", nullable: true},
+	task.FilterSprint:      {column: "sprint_id", nullable: true},
+	task.FilterMilestone:   {column: "milestone_id", nullable: true},
+	task.FilterEstimate:    {column: "estimate_minutes", nullable: true},
+	task.FilterPoints:      {column: "story_points", nullable: true},
+	task.FilterDue:         {column: "due_at", nullable: true},
+	task.FilterCreated:     {column: "created_at"},
+	task.FilterUpdated:     {column: "updated_at"},
+}
+
+var filterComparisons = map[task.FilterOperator]string{
+	task.FilterGt:  ">",
+	task.FilterGte: ">=",
+	task.FilterLt:  "<",
+	task.FilterLte: "<=",
+}
+
+// filterCondition translates a parsed filter expression into a condition on
+// tasks with all values passed as arguments. Every comparison is either true
+// or false, also on NULL columns, so that NOT inverts it as expected.
+func filterCondition(expr task.FilterExpr) (string, []any) {
+	switch node := expr.(type) {
+	case task.FilterAnd:
+		return joinFilterConditions(node.Operands, " AND ")
+	case task.FilterOr:
+		return joinFilterConditions(node.Operands, " OR ")
+	case task.FilterNot:
+		condition, args := filterCondition(node.Operand)
+		return "NOT " + condition, args
+	case task.FilterComparison:
+		return comparisonCondition(node)
+	}
+	return "FALSE", nil
+}
+
+func joinFilterConditions(operands []task.FilterExpr, separator string) (string, []any) {
+	conditions := make([]string, 0, len(operands))
+	args := make([]any, 0, len(operands))
+	for _, operand := range operands {
+		condition, operandArgs := filterCondition(operand)
+		conditions = append(conditions, condition)
+		args = append(args, operandArgs...)
+	}
+	return "(" + strings.Join(conditions, separator) + ")", args
+}
+
+func comparisonCondition(comparison task.FilterComparison) (string, []any) {
+	value := comparison.Value
+	if status, ok := value.(task.Status); ok {
+		value = string(status)
+	}
+	if timestamp, ok := value.(time.Time); ok {
+		value = timestamp.UTC()
+	}
+
+	if comparison.Field == task.FilterLabel {
+		const condition = "EXISTS (SELECT 1 FROM task_labels tl WHERE tl.task_id = tasks.id AND tl.label = ?)"
+		if comparison.Operator == task.FilterNe {
+			return "NOT " + condition, []any{value}
+		}
+		return condition, []any{value}
+	}
+
+	column, ok := filterColumns[comparison.Field]
+	if !ok {
+		return "FALSE", nil
+	}
+
+	switch comparison.Operator {
+	case task.FilterEq:
+		return "(" + column.column + " <=> ?)", []any{value}
+	case task.FilterNe:
+		return "NOT (" + column.column + " <=> ?)", []any{value}
+	case task.FilterContains:
+		text, _ := value.(string)
+		return "(COALESCE(" + column.column + ", '') LIKE ?)", []any{"%" + escapeLike(text) + "%"}
+	}
+
+	operator, ok := filterComparisons[comparison.Operator]
+	if !ok {
+		return "FALSE", nil
+	}
+	if column.nullable {
+		return "(" + column.column + " IS NOT NULL AND " + column.column + " " + operator + " ?)", []any{value}
+	}
+	return "(" + column.column + " " + operator + " ?)", []any{value}
+}
+
+// escapeLike escapes the LIKE wildcards of a literal with the default escape
+// character.
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
@@ -0,0 +1,56 @@
+package mysql
+
+import (
+	"context"
+	"time"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/task"
+)
+
+var _ task.HousekeepingRepository = (*Repository)(nil)
+
+func (r *Repository) PurgeDeleted(ctx context.Context, deletedBefore time.Time, limit int) (int, error) {
+	const query = `
+		DELETE FROM tasks
+		WHERE deleted_at IS NOT NULL AND deleted_at < ?
+		ORDER BY deleted_at
+		LIMIT ?
+	`
+
+	result, err := r.conn(ctx).ExecContext(ctx, query, deletedBefore.UTC(), limit)
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(rowsAffected), nil
+}
+
+func (r *Repository) ArchiveCompleted(ctx context.Context, completedBefore time.Time, limit int) (int, error) {
+	const query = `
+		UPDATE tasks
+		SET archived_at = CURRENT_TIMESTAMP, version = version + 1
+		WHERE status = ?
+			AND completed_at < ?
+			AND archived_at IS NULL
+			AND deleted_at IS NULL
+		ORDER BY completed_at
+		LIMIT ?
+	`
+
+	result, err := r.conn(ctx).ExecContext(ctx, query, task.StatusDone, completedBefore.UTC(), limit)
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(rowsAffected), nil
+}
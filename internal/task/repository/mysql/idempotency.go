@@ -0,0 +1,136 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/task"
+)
+
+var _ task.IdempotencyRepository = (*Repository)(nil)
+
+// ReserveIdempotencyKey relies on the primary key to let only one of several
+// concurrent requests with the same key insert its record; the others lock
+// and read the winner's record.
+func (r *Repository) ReserveIdempotencyKey(ctx context.Context, record task.IdempotencyRecord) (*task.IdempotencyRecord, error) {
+	var existing *task.IdempotencyRecord
+	err := r.withTx(ctx, func(tx *sql.Tx) error {
+		const insert = `
+			INSERT INTO idempotency_keys (user_id, idempotency_key, fingerprint, created_at, expires_at)
+			VALUES (?, ?, ?, ?, ?)
+		`
+		_, err := tx.ExecContext(ctx, insert, record.UserID, record.Key, record.Fingerprint, record.CreatedAt.UTC(), record.ExpiresAt.UTC())
+		if err == nil || !isDuplicateKey(err) {
+			return err
+		}
+
+		const query = `
+			SELECT fingerprint, response_status, response_body, created_at, expires_at
+			FROM idempotency_keys
+			WHERE user_id = ? AND idempotency_key = ?
+			FOR UPDATE
+		`
+		found := task.IdempotencyRecord{UserID: record.UserID, Key: record.Key}
+		var (
+			responseStatus sql.NullInt64
+			responseBody   []byte
+			createdAt      time.Time
+			expiresAt      time.Time
+		)
+		err = tx.QueryRowContext(ctx, query, record.UserID, record.Key).Scan(&found.Fingerprint, &responseStatus, &responseBody, &createdAt, &expiresAt)
+		if err != nil {
+			return err
+		}
+		if responseStatus.Valid {
+			found.Response = &task.IdempotentResponse{Status: int(responseStatus.Int64), Body: responseBody}
+		}
+		found.CreatedAt = createdAt.UTC()
+		found.ExpiresAt = expiresAt.UTC()
+
+		if !found.Reclaimable(record.CreatedAt) {
+			existing = &found
+			return nil
+		}
+
+		const reclaim = `
+			UPDATE idempotency_keys
+			SET fingerprint = ?, response_status = NULL, response_body = NULL, created_at = ?, expires_at = ?
+			WHERE user_id = ? AND idempotency_key = ?
+		`
+		_, err = tx.ExecContext(ctx, reclaim, record.Fingerprint, record.CreatedAt.UTC(), record.ExpiresAt.UTC(), record.UserID, record.Key)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
+// CompleteIdempotencyKey and ReleaseIdempotencyKey only touch the record of
+// the reservation, not one that took the key over after it expired.
+func (r *Repository) CompleteIdempotencyKey(ctx context.Context, reservation task.IdempotencyReservation, response task.IdempotentResponse) error {
+	const query = `
+		UPDATE idempotency_keys
+		SET response_status = ?, response_body = ?
+		WHERE user_id = ? AND idempotency_key = ? AND fingerprint = ? AND created_at = ? AND response_status IS NULL
+	`
+	result, err := r.conn(ctx).ExecContext(
+		ctx,
+		query,
+		response.Status,
+		response.Body,
+		reservation.UserID,
+		reservation.Key,
+		reservation.Fingerprint,
+		reservation.CreatedAt.UTC(),
+	)
+	if err != nil {
+		return err
+	}
+	return ensureIdempotencyKeyHeld(result)
+}
+
+func (r *Repository) ReleaseIdempotencyKey(ctx context.Context, reservation task.IdempotencyReservation) error {
+	const query = `
+		DELETE FROM idempotency_keys
+		WHERE user_id = ? AND idempotency_key = ? AND fingerprint = ? AND created_at = ? AND response_status IS NULL
+	`
+	result, err := r.conn(ctx).ExecContext(ctx, query, reservation.UserID, reservation.Key, reservation.Fingerprint, reservation.CreatedAt.UTC())
+	if err != nil {
+		return err
+	}
+	return ensureIdempotencyKeyHeld(result)
+}
+
+func ensureIdempotencyKeyHeld(result sql.Result) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return task.ErrIdempotencyKeyLost
+	}
+	return nil
+}
+
+func (r *Repository) PurgeIdempotencyKeys(ctx context.Context, expiredBefore time.Time, limit int) (int, error) {
+	const query = `
+		DELETE FROM idempotency_keys
+		WHERE expires_at < ?
+		ORDER BY expires_at
+		LIMIT ?
+	`
+
+	result, err := r.conn(ctx).ExecContext(ctx, query, expiredBefore.UTC(), limit)
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(rowsAffected), nil
+}
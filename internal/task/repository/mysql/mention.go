@@ -0,0 +1,126 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/task"
+)
+
+var (
+	_ task.UserRepository    = (*Repository)(nil)
+	_ task.MentionRepository = (*Repository)(nil)
+)
+
+// FindUsersByUsername relies on the case-insensitive collation of
+// users.username.
+func (r *Repository) FindUsersByUsername(ctx context.Context, usernames []string) ([]task.User, error) {
+	if len(usernames) == 0 {
+		return nil, nil
+	}
+
+	args := make([]any, 0, len(usernames))
+	for _, username := range usernames {
+		args = append(args, username)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, username
+		FROM users
+		WHERE username IN (%s) AND deleted_at IS NULL
+		ORDER BY username
+	`, placeholders(len(args)))
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := make([]task.User, 0, len(usernames))
+	for rows.Next() {
+		var user task.User
+		if err := rows.Scan(&user.ID, &user.Username); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	return users, rows.Err()
+}
+
+func (r *Repository) ListMentions(ctx context.Context, userID string, limit, offset int) ([]task.Mention, error) {
+	const query = `
+		SELECT task_mentions.id, task_mentions.task_id, tasks.title, task_mentions.comment_id,
+			task_mentions.user_id, task_mentions.author_id, task_mentions.created_at
+		FROM task_mentions
+		JOIN tasks ON tasks.id = task_mentions.task_id
+		WHERE task_mentions.user_id = ? AND tasks.deleted_at IS NULL
+		ORDER BY task_mentions.created_at DESC, task_mentions.id DESC
+		LIMIT ? OFFSET ?
+	`
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	mentions := make([]task.Mention, 0)
+	for rows.Next() {
+		var (
+			mention   task.Mention
+			commentID sql.NullInt64
+			authorID  sql.NullString
+			createdAt time.Time
+		)
+		err := rows.Scan(
+			&mention.ID,
+			&mention.TaskID,
+			&mention.TaskTitle,
+			&commentID,
+			&mention.UserID,
+			&authorID,
+			&createdAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if commentID.Valid {
+			id := uint64(commentID.Int64)
+			mention.CommentID = &id
+		}
+		if authorID.Valid {
+			mention.AuthorID = &authorID.String
+		}
+		mention.CreatedAt = createdAt.UTC()
+		mentions = append(mentions, mention)
+	}
+
+	return mentions, rows.Err()
+}
+
+// addMentions records mentions of users in a task description, or in a
+// comment when commentID is set, and makes the users watchers of the task.
+// Repeated mentions in the same text are stored once.
+func addMentions(ctx context.Context, tx *sql.Tx, taskID uint64, commentID *uint64, authorID *string, userIDs []string) error {
+	for _, userID := range userIDs {
+		_, err := tx.ExecContext(
+			ctx,
+			`INSERT IGNORE INTO task_mentions (task_id, comment_id, user_id, author_id) VALUES (?, ?, ?, ?)`,
+			taskID,
+			asNullable(commentID),
+			userID,
+			asNullable(authorID),
+		)
+		if err != nil {
+			return err
+		}
+		if err := addWatcher(ctx, tx, taskID, userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
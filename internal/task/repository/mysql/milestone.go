@@ -0,0 +1,202 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/task"
+)
+
+var _ task.MilestoneRepository = (*Repository)(nil)
+
+const milestoneColumns = `id, name, description, target_date, created_at, updated_at`
+
+func (r *Repository) CreateMilestone(ctx context.Context, params task.MilestoneParams) (task.Milestone, error) {
+	result, err := r.conn(ctx).ExecContext(
+		ctx,
+		`INSERT INTO milestones (name, description, target_date) VALUES (?, ?, ?)`,
+		params.Name,
+		asNullableString(params.Description),
+		params.TargetDate,
+	)
+	if err != nil {
+		if isDuplicateKey(err) {
+			return task.Milestone{}, task.ErrMilestoneExists
+		}
+		return task.Milestone{}, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return task.Milestone{}, err
+	}
+
+	return r.GetMilestone(ctx, uint64(id))
+}
+
+func (r *Repository) GetMilestone(ctx context.Context, id uint64) (task.Milestone, error) {
+	milestones, err := r.queryMilestones(ctx, `SELECT `+milestoneColumns+` FROM milestones WHERE id = ?`, id)
+	if err != nil {
+		return task.Milestone{}, err
+	}
+	if len(milestones) == 0 {
+		return task.Milestone{}, task.ErrMilestoneNotFound
+	}
+	return milestones[0], nil
+}
+
+func (r *Repository) ListMilestones(ctx context.Context) ([]task.Milestone, error) {
+	return r.queryMilestones(ctx, `SELECT `+milestoneColumns+` FROM milestones ORDER BY target_date, id`)
+}
+
+func (r *Repository) UpdateMilestone(ctx context.Context, id uint64, params task.UpdateMilestoneParams) (task.Milestone, error) {
+	setClauses := make([]string, 0, 3)
+	args := make([]any, 0, 4)
+
+	if params.Name != nil {
+		setClauses = append(setClauses, "name = ?")
+		args = append(args, *params.Name)
+	}
+	if params.Description != nil {
+		setClauses = append(setClauses, "description = ?")
+		args = append(args, asNullableString(*params.Description))
+	}
+	if params.TargetDate != nil {
+		setClauses = append(setClauses, "target_date = ?")
+		args = append(args, *params.TargetDate)
+	}
+
+	if len(setClauses) == 0 {
+		return task.Milestone{}, task.ValidationError{Field: "body", Message: "at least one field must be provided for update"}
+	}
+
+	query := fmt.Sprintf("UPDATE milestones SET %s WHERE id = ?", strings.Join(setClauses, ", "))
+	if _, err := r.conn(ctx).ExecContext(ctx, query, append(args, id)...); err != nil {
+		if isDuplicateKey(err) {
+			return task.Milestone{}, task.ErrMilestoneExists
+		}
+		return task.Milestone{}, err
+	}
+
+	return r.GetMilestone(ctx, id)
+}
+
+// DeleteMilestone detaches the tasks of the milestone through the foreign
+// key.
+func (r *Repository) DeleteMilestone(ctx context.Context, id uint64) error {
+	result, err := r.conn(ctx).ExecContext(ctx, `DELETE FROM milestones WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return task.ErrMilestoneNotFound
+	}
+
+	return nil
+}
+
+func (r *Repository) queryMilestones(ctx context.Context, query string, args ...any) ([]task.Milestone, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	milestones := make([]task.Milestone, 0)
+	for rows.Next() {
+		found, err := scanMilestone(rows)
+		if err != nil {
+			return nil, err
+		}
+		milestones = append(milestones, found)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.attachMilestoneProgress(ctx, milestones); err != nil {
+		return nil, err
+	}
+	return milestones, nil
+}
+
+// attachMilestoneProgress counts the tasks of the milestones by status. Open
+// tasks due on a later day than the target date are counted as late.
+func (r *Repository) attachMilestoneProgress(ctx context.Context, milestones []task.Milestone) error {
+	if len(milestones) == 0 {
+		return nil
+	}
+
+	indexByID := make(map[uint64]int, len(milestones))
+	ids := make([]any, 0, len(milestones))
+	for i := range milestones {
+		indexByID[milestones[i].ID] = i
+		ids = append(ids, milestones[i].ID)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			t.milestone_id,
+			COUNT(*),
+			COALESCE(SUM(t.status = 'new'), 0),
+			COALESCE(SUM(t.status = 'in_progress'), 0),
+			COALESCE(SUM(t.status = 'done'), 0),
+			COALESCE(SUM(t.status <> 'done' AND t.due_at >= m.target_date + INTERVAL 1 DAY), 0)
+		FROM tasks t
+		JOIN milestones m ON m.id = t.milestone_id
+		WHERE t.milestone_id IN (%s) AND t.deleted_at IS NULL
+		GROUP BY t.milestone_id
+	`, placeholders(len(ids)))
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, ids...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			milestoneID uint64
+			progress    task.MilestoneProgress
+		)
+		err := rows.Scan(&milestoneID, &progress.Total, &progress.New, &progress.InProgress, &progress.Done, &progress.LateTasks)
+		if err != nil {
+			return err
+		}
+		if i, ok := indexByID[milestoneID]; ok {
+			milestones[i].Progress = progress
+		}
+	}
+
+	return rows.Err()
+}
+
+func scanMilestone(scanner sqlScanner) (task.Milestone, error) {
+	var (
+		found       task.Milestone
+		description sql.NullString
+		targetDate  time.Time
+		createdAt   time.Time
+		updatedAt   time.Time
+	)
+
+	err := scanner.Scan(&found.ID, &found.Name, &description, &targetDate, &createdAt, &updatedAt)
+	if err != nil {
+		return task.Milestone{}, err
+	}
+
+	found.Description = description.String
+	found.TargetDate = targetDate.Format("2006-01-02")
+	found.CreatedAt = createdAt.UTC()
+	found.UpdatedAt = updatedAt.UTC()
+
+	return found, nil
+}
@@ -0,0 +1,354 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/task"
+)
+
+var _ task.ProjectRepository = (*Repository)(nil)
+
+const customFieldColumns = `id, project_id, name, type, required, options, created_at`
+
+func (r *Repository) CreateProject(ctx context.Context, name string) (task.Project, error) {
+	result, err := r.conn(ctx).ExecContext(ctx, `INSERT INTO projects (name) VALUES (?)`, name)
+	if err != nil {
+		if isDuplicateKey(err) {
+			return task.Project{}, task.ErrProjectExists
+		}
+		return task.Project{}, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return task.Project{}, err
+	}
+
+	return r.GetProject(ctx, uint64(id))
+}
+
+func (r *Repository) GetProject(ctx context.Context, id uint64) (task.Project, error) {
+	projects, err := r.queryProjects(ctx, `SELECT id, name, created_at, updated_at FROM projects WHERE id = ?`, id)
+	if err != nil {
+		return task.Project{}, err
+	}
+	if len(projects) == 0 {
+		return task.Project{}, task.ErrProjectNotFound
+	}
+	return projects[0], nil
+}
+
+func (r *Repository) ListProjects(ctx context.Context) ([]task.Project, error) {
+	return r.queryProjects(ctx, `SELECT id, name, created_at, updated_at FROM projects ORDER BY name`)
+}
+
+func (r *Repository) CreateCustomField(ctx context.Context, params task.CustomFieldParams) (task.CustomFieldDefinition, error) {
+	const query = `
+		INSERT INTO project_custom_fields (project_id, name, type, required, options)
+		SELECT id, ?, ?, ?, ?
+		FROM projects
+		WHERE id = ?
+	`
+
+	var options any
+	if len(params.Options) > 0 {
+		encoded, err := json.Marshal(params.Options)
+		if err != nil {
+			return task.CustomFieldDefinition{}, err
+		}
+		options = string(encoded)
+	}
+
+	result, err := r.conn(ctx).ExecContext(ctx, query, params.Name, params.Type, params.Required, options, params.ProjectID)
+	if err != nil {
+		if isDuplicateKey(err) {
+			return task.CustomFieldDefinition{}, task.ErrCustomFieldExists
+		}
+		return task.CustomFieldDefinition{}, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return task.CustomFieldDefinition{}, err
+	}
+	if rowsAffected == 0 {
+		return task.CustomFieldDefinition{}, task.ErrProjectNotFound
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return task.CustomFieldDefinition{}, err
+	}
+
+	row := r.conn(ctx).QueryRowContext(ctx, `SELECT `+customFieldColumns+` FROM project_custom_fields WHERE id = ?`, id)
+	return scanCustomField(row)
+}
+
+// DeleteCustomField bumps the version of the tasks that lose a value of the
+// field before the values are deleted with it.
+func (r *Repository) DeleteCustomField(ctx context.Context, projectID, fieldID uint64) error {
+	return r.withTx(ctx, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(
+			ctx,
+			`UPDATE tasks SET version = version + 1 WHERE id IN (
+				SELECT cfv.task_id
+				FROM task_custom_field_values cfv
+				JOIN project_custom_fields cf ON cf.id = cfv.field_id
+				WHERE cf.id = ? AND cf.project_id = ?
+			)`,
+			fieldID,
+			projectID,
+		)
+		if err != nil {
+			return err
+		}
+
+		result, err := tx.ExecContext(ctx, `DELETE FROM project_custom_fields WHERE id = ? AND project_id = ?`, fieldID, projectID)
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return task.ErrCustomFieldNotFound
+		}
+
+		return nil
+	})
+}
+
+func (r *Repository) queryProjects(ctx context.Context, query string, args ...any) ([]task.Project, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	projects := make([]task.Project, 0)
+	for rows.Next() {
+		var project task.Project
+		if err := rows.Scan(&project.ID, &project.Name, &project.CreatedAt, &project.UpdatedAt); err != nil {
+			return nil, err
+		}
+		project.CreatedAt = project.CreatedAt.UTC()
+		project.UpdatedAt = project.UpdatedAt.UTC()
+		project.CustomFields = []task.CustomFieldDefinition{}
+		project.WIPLimits = map[task.Status]int{}
+		projects = append(projects, project)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.attachCustomFieldDefinitions(ctx, projects); err != nil {
+		return nil, err
+	}
+	if err := r.attachWIPLimits(ctx, projects); err != nil {
+		return nil, err
+	}
+
+	return projects, nil
+}
+
+func (r *Repository) attachCustomFieldDefinitions(ctx context.Context, projects []task.Project) error {
+	if len(projects) == 0 {
+		return nil
+	}
+
+	indexByID := make(map[uint64]int, len(projects))
+	ids := make([]any, 0, len(projects))
+	for i := range projects {
+		indexByID[projects[i].ID] = i
+		ids = append(ids, projects[i].ID)
+	}
+
+	query := fmt.Sprintf(
+		"SELECT "+customFieldColumns+" FROM project_custom_fields WHERE project_id IN (%s) ORDER BY name",
+		placeholders(len(ids)),
+	)
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, ids...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		definition, err := scanCustomField(rows)
+		if err != nil {
+			return err
+		}
+		if i, ok := indexByID[definition.ProjectID]; ok {
+			projects[i].CustomFields = append(projects[i].CustomFields, definition)
+		}
+	}
+
+	return rows.Err()
+}
+
+func (r *Repository) attachCustomFields(ctx context.Context, tasks []task.Task, indexByID map[uint64]int, ids []any) error {
+	query := fmt.Sprintf(`
+		SELECT cfv.task_id, cf.name, cf.type, cfv.value
+		FROM task_custom_field_values cfv
+		JOIN project_custom_fields cf ON cf.id = cfv.field_id
+		WHERE cfv.task_id IN (%s)
+		ORDER BY cfv.task_id, cf.name, cfv.value
+	`, placeholders(len(ids)))
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, ids...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	type fieldValues struct {
+		definition task.CustomFieldDefinition
+		values     []string
+	}
+	collected := make(map[uint64]map[string]*fieldValues)
+
+	for rows.Next() {
+		var (
+			taskID    uint64
+			name      string
+			fieldType task.CustomFieldType
+			value     string
+		)
+		if err := rows.Scan(&taskID, &name, &fieldType, &value); err != nil {
+			return err
+		}
+
+		byName, ok := collected[taskID]
+		if !ok {
+			byName = make(map[string]*fieldValues)
+			collected[taskID] = byName
+		}
+		field, ok := byName[name]
+		if !ok {
+			field = &fieldValues{definition: task.CustomFieldDefinition{Name: name, Type: fieldType}}
+			byName[name] = field
+		}
+		field.values = append(field.values, value)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for taskID, byName := range collected {
+		i, ok := indexByID[taskID]
+		if !ok {
+			continue
+		}
+		tasks[i].CustomFields = make(map[string]any, len(byName))
+		for name, field := range byName {
+			tasks[i].CustomFields[name] = field.definition.Decode(field.values)
+		}
+	}
+
+	return nil
+}
+
+// replaceCustomFieldValues replaces the values of the given fields only.
+func replaceCustomFieldValues(ctx context.Context, tx *sql.Tx, taskID uint64, values []task.CustomFieldValue) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	fieldIDs := make([]any, 0, len(values)+1)
+	fieldIDs = append(fieldIDs, taskID)
+	rowPlaceholders := make([]string, 0, len(values))
+	args := make([]any, 0, len(values)*3)
+	for _, value := range values {
+		fieldIDs = append(fieldIDs, value.FieldID)
+		for _, item := range value.Values {
+			rowPlaceholders = append(rowPlaceholders, "(?, ?, ?)")
+			args = append(args, taskID, value.FieldID, item)
+		}
+	}
+
+	deleteQuery := fmt.Sprintf(
+		"DELETE FROM task_custom_field_values WHERE task_id = ? AND field_id IN (%s)",
+		placeholders(len(values)),
+	)
+	if _, err := tx.ExecContext(ctx, deleteQuery, fieldIDs...); err != nil {
+		return err
+	}
+	if len(rowPlaceholders) == 0 {
+		return nil
+	}
+
+	insertQuery := "INSERT INTO task_custom_field_values (task_id, field_id, value) VALUES " + strings.Join(rowPlaceholders, ", ")
+	_, err := tx.ExecContext(ctx, insertQuery, args...)
+	return err
+}
+
+// removeForeignCustomFieldValues removes the values of fields that do not
+// belong to projectID, or all values when the task has no project anymore.
+func removeForeignCustomFieldValues(ctx context.Context, tx *sql.Tx, taskID uint64, projectID *uint64) error {
+	if projectID == nil {
+		_, err := tx.ExecContext(ctx, `DELETE FROM task_custom_field_values WHERE task_id = ?`, taskID)
+		return err
+	}
+
+	const query = `
+		DELETE cfv
+		FROM task_custom_field_values cfv
+		JOIN project_custom_fields cf ON cf.id = cfv.field_id
+		WHERE cfv.task_id = ? AND cf.project_id <> ?
+	`
+	_, err := tx.ExecContext(ctx, query, taskID, *projectID)
+	return err
+}
+
+func copyCustomFieldValues(ctx context.Context, tx *sql.Tx, sourceID, targetID uint64) error {
+	const query = `
+		INSERT INTO task_custom_field_values (task_id, field_id, value)
+		SELECT ?, field_id, value
+		FROM task_custom_field_values
+		WHERE task_id = ?
+	`
+	_, err := tx.ExecContext(ctx, query, targetID, sourceID)
+	return err
+}
+
+func scanCustomField(scanner sqlScanner) (task.CustomFieldDefinition, error) {
+	var (
+		definition task.CustomFieldDefinition
+		options    sql.NullString
+		createdAt  time.Time
+	)
+
+	err := scanner.Scan(
+		&definition.ID,
+		&definition.ProjectID,
+		&definition.Name,
+		&definition.Type,
+		&definition.Required,
+		&options,
+		&createdAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return task.CustomFieldDefinition{}, task.ErrCustomFieldNotFound
+		}
+		return task.CustomFieldDefinition{}, err
+	}
+
+	if options.Valid {
+		if err := json.Unmarshal([]byte(options.String), &definition.Options); err != nil {
+			return task.CustomFieldDefinition{}, err
+		}
+	}
+	definition.CreatedAt = createdAt.UTC()
+
+	return definition, nil
+}
@@ -0,0 +1,198 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/task"
+)
+
+type rankedTask struct {
+	status task.Status
+	rank   string
+}
+
+// Move gives the task a rank between its new neighbours. Only the moved task
+// changes unless the neighbours have no rank left between them, in which case
+// the whole column is renumbered once.
+func (r *Repository) Move(ctx context.Context, id uint64, params task.MoveParams) error {
+	return r.withTx(ctx, func(tx *sql.Tx) error {
+		moved, err := lockRankedTask(ctx, tx, id)
+		if errors.Is(err, sql.ErrNoRows) {
+			return task.ErrTaskNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		for attempt := 0; ; attempt++ {
+			lower, upper, err := moveBounds(ctx, tx, id, moved.status, params)
+			if err != nil {
+				return err
+			}
+
+			if upper == "" || lower < upper {
+				rank, err := task.RankBetween(lower, upper)
+				if err == nil {
+					_, err = tx.ExecContext(ctx, `UPDATE tasks SET board_rank = ?, version = version + 1 WHERE id = ?`, rank, id)
+					return err
+				}
+				if !errors.Is(err, task.ErrRankExhausted) {
+					return err
+				}
+			} else if params.AfterID != nil && params.BeforeID != nil && lower > upper {
+				return task.ValidationError{Field: "after_id", Message: "must be ranked above before_id"}
+			}
+
+			// Equal neighbour ranks or exhausted keys: renumber and retry.
+			if attempt > 0 {
+				return task.ErrRankExhausted
+			}
+			if err := rebalanceColumn(ctx, tx, moved.status); err != nil {
+				return err
+			}
+		}
+	})
+}
+
+// moveBounds returns the ranks the moved task has to fit between. A missing
+// neighbour is taken from the column, so "after X" means between X and the
+// task that currently follows X.
+func moveBounds(ctx context.Context, tx *sql.Tx, id uint64, status task.Status, params task.MoveParams) (string, string, error) {
+	var lower, upper string
+
+	if params.AfterID != nil {
+		after, err := neighbourRank(ctx, tx, "after_id", *params.AfterID, status)
+		if err != nil {
+			return "", "", err
+		}
+		lower = after
+	}
+	if params.BeforeID != nil {
+		before, err := neighbourRank(ctx, tx, "before_id", *params.BeforeID, status)
+		if err != nil {
+			return "", "", err
+		}
+		upper = before
+	}
+
+	const next = `
+		SELECT board_rank FROM tasks
+		WHERE status = ? AND deleted_at IS NULL AND id <> ? AND board_rank > ?
+		ORDER BY board_rank, id
+		LIMIT 1
+	`
+	const previous = `
+		SELECT board_rank FROM tasks
+		WHERE status = ? AND deleted_at IS NULL AND id <> ? AND board_rank < ?
+		ORDER BY board_rank DESC, id DESC
+		LIMIT 1
+	`
+
+	var err error
+	switch {
+	case params.BeforeID == nil:
+		err = tx.QueryRowContext(ctx, next, status, id, lower).Scan(&upper)
+	case params.AfterID == nil:
+		err = tx.QueryRowContext(ctx, previous, status, id, upper).Scan(&lower)
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return "", "", err
+	}
+
+	return lower, upper, nil
+}
+
+func neighbourRank(ctx context.Context, tx *sql.Tx, field string, id uint64, status task.Status) (string, error) {
+	neighbour, err := lockRankedTask(ctx, tx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", task.ValidationError{Field: field, Message: "task does not exist"}
+	}
+	if err != nil {
+		return "", err
+	}
+	if neighbour.status != status {
+		return "", task.ValidationError{Field: field, Message: "must be in the same status column as the moved task"}
+	}
+	return neighbour.rank, nil
+}
+
+func lockRankedTask(ctx context.Context, tx *sql.Tx, id uint64) (rankedTask, error) {
+	var ranked rankedTask
+	err := tx.QueryRowContext(
+		ctx,
+		`SELECT status, board_rank FROM tasks WHERE id = ? AND deleted_at IS NULL FOR UPDATE`,
+		id,
+	).Scan(&ranked.status, &ranked.rank)
+	return ranked, err
+}
+
+// appendRank returns a rank after the last task of a status column.
+func appendRank(ctx context.Context, tx *sql.Tx, status task.Status) (string, error) {
+	last, err := lastRank(ctx, tx, status)
+	if err != nil {
+		return "", err
+	}
+
+	rank, err := task.RankBetween(last, "")
+	if errors.Is(err, task.ErrRankExhausted) {
+		if err := rebalanceColumn(ctx, tx, status); err != nil {
+			return "", err
+		}
+		if last, err = lastRank(ctx, tx, status); err != nil {
+			return "", err
+		}
+		return task.RankBetween(last, "")
+	}
+	return rank, err
+}
+
+func lastRank(ctx context.Context, tx *sql.Tx, status task.Status) (string, error) {
+	var last string
+	err := tx.QueryRowContext(
+		ctx,
+		`SELECT board_rank FROM tasks WHERE status = ? AND deleted_at IS NULL ORDER BY board_rank DESC LIMIT 1`,
+		status,
+	).Scan(&last)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return last, err
+}
+
+// rebalanceColumn renumbers a status column with evenly spaced ranks while
+// keeping its order.
+func rebalanceColumn(ctx context.Context, tx *sql.Tx, status task.Status) error {
+	rows, err := tx.QueryContext(
+		ctx,
+		`SELECT id FROM tasks WHERE status = ? AND deleted_at IS NULL ORDER BY board_rank, id FOR UPDATE`,
+		status,
+	)
+	if err != nil {
+		return err
+	}
+
+	var ids []uint64
+	for rows.Next() {
+		var id uint64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for i, rank := range task.EvenRanks(len(ids)) {
+		if _, err := tx.ExecContext(ctx, `UPDATE tasks SET board_rank = ?, version = version + 1 WHERE id = ?`, rank, ids[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
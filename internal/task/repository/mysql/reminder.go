@@ -0,0 +1,64 @@
+package mysql
+
+import (
+	"context"
+	"time"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/task"
+)
+
+var _ task.ReminderRepository = (*Repository)(nil)
+
+func (r *Repository) ListReminderCandidates(ctx context.Context, window task.ReminderWindow, limit int) ([]task.Task, error) {
+	query := `
+		SELECT ` + taskColumns + `
+		FROM tasks
+		WHERE status <> ?
+			AND deleted_at IS NULL
+			AND due_at IS NOT NULL
+			AND due_at <= ?
+	`
+	args := []any{task.StatusDone, window.DueUntil.UTC()}
+
+	if window.DueAfter != nil {
+		query += " AND due_at > ?"
+		args = append(args, window.DueAfter.UTC())
+	}
+
+	query += `
+			AND NOT EXISTS (
+				SELECT 1 FROM task_reminders
+				WHERE task_reminders.task_id = tasks.id
+					AND task_reminders.threshold = ?
+					AND task_reminders.due_at = tasks.due_at
+			)
+		ORDER BY due_at ASC
+		LIMIT ?
+	`
+	args = append(args, window.Threshold.Name, limit)
+
+	return r.queryTasks(ctx, query, args...)
+}
+
+func (r *Repository) MarkReminderSent(ctx context.Context, taskID uint64, threshold string, dueAt time.Time) (bool, error) {
+	const query = `INSERT IGNORE INTO task_reminders (task_id, threshold, due_at) VALUES (?, ?, ?)`
+
+	result, err := r.conn(ctx).ExecContext(ctx, query, taskID, threshold, dueAt.UTC())
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected == 1, nil
+}
+
+func (r *Repository) UnmarkReminderSent(ctx context.Context, taskID uint64, threshold string, dueAt time.Time) error {
+	const query = `DELETE FROM task_reminders WHERE task_id = ? AND threshold = ? AND due_at = ?`
+
+	_, err := r.conn(ctx).ExecContext(ctx, query, taskID, threshold, dueAt.UTC())
+	return err
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/PavelFesenkoFirst/task_tracker/internal/task"
+	mysqldriver "github.com/go-sql-driver/mysql"
 )
 
 type Repository struct {
@@ -16,46 +17,38 @@ type Repository struct {
 }
 
 var _ task.Repository = (*Repository)(nil)
+var _ task.BulkRepository = (*Repository)(nil)
+
+const mysqlErrDuplicateEntry = 1062
+
+const taskColumns = `id, project_id, sprint_id, milestone_id, parent_id, reporter_id, assignee_id, title, description, status, priority, board_rank, estimate_minutes, story_points, due_at, recurrence_rule, next_occurrence_id, created_at, updated_at, completed_at, archived_at, deleted_at, version`
 
 func New(db *sql.DB) *Repository {
 	return &Repository{db: db}
 }
 
 func (r *Repository) Create(ctx context.Context, params task.CreateParams) (task.Task, error) {
-	const query = `
-		INSERT INTO tasks (title, description, status, priority, due_at)
-		VALUES (?, ?, ?, ?, ?)
-	`
-
-	result, err := r.db.ExecContext(
-		ctx,
-		query,
-		params.Title,
-		params.Description,
-		params.Status,
-		params.Priority,
-		asNullableTime(params.DueAt),
-	)
-	if err != nil {
-		return task.Task{}, err
-	}
-
-	id, err := result.LastInsertId()
+	var id uint64
+	err := r.withTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		id, err = insertTask(ctx, tx, params)
+		return err
+	})
 	if err != nil {
 		return task.Task{}, err
 	}
 
-	return r.GetByID(ctx, uint64(id))
+	return r.GetByID(ctx, id)
 }
 
 func (r *Repository) GetByID(ctx context.Context, id uint64) (task.Task, error) {
-	const query = `
-		SELECT id, title, description, status, priority, due_at, created_at, updated_at
+	query := `
+		SELECT ` + taskColumns + `
 		FROM tasks
-		WHERE id = ?
+		WHERE id = ? AND deleted_at IS NULL
 	`
 
-	row := r.db.QueryRowContext(ctx, query, id)
+	row := r.conn(ctx).QueryRowContext(ctx, query, id)
 	foundTask, err := scanTask(row)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -64,64 +57,291 @@ func (r *Repository) GetByID(ctx context.Context, id uint64) (task.Task, error)
 		return task.Task{}, err
 	}
 
-	return foundTask, nil
+	tasks := []task.Task{foundTask}
+	if err := r.hydrate(ctx, tasks); err != nil {
+		return task.Task{}, err
+	}
+
+	return tasks[0], nil
 }
 
 func (r *Repository) List(ctx context.Context, filter task.ListFilter) ([]task.Task, error) {
 	var queryBuilder strings.Builder
 	queryBuilder.WriteString(`
-		SELECT id, title, description, status, priority, due_at, created_at, updated_at
+		SELECT ` + taskColumns + `
 		FROM tasks
 	`)
 
-	args := make([]any, 0, 6)
-	conditions := make([]string, 0, 2)
-
-	if filter.Status != nil {
-		conditions = append(conditions, "status = ?")
-		args = append(args, *filter.Status)
+	conditions, args := listConditions(filter)
+	if filter.After != nil {
+		condition, afterArgs := keysetCondition(filter)
+		conditions = append(conditions, condition)
+		args = append(args, afterArgs...)
 	}
-
-	if filter.Query != "" {
-		conditions = append(conditions, "(title LIKE ? OR description LIKE ?)")
-		likeExpr := "%" + filter.Query + "%"
-		args = append(args, likeExpr, likeExpr)
-	}
-
 	if len(conditions) > 0 {
 		queryBuilder.WriteString(" WHERE ")
 		queryBuilder.WriteString(strings.Join(conditions, " AND "))
 	}
 
-	queryBuilder.WriteString(" ORDER BY created_at DESC LIMIT ? OFFSET ?")
+	order, orderArgs := orderBy(filter)
+	queryBuilder.WriteString(" ORDER BY ")
+	queryBuilder.WriteString(order)
+	queryBuilder.WriteString(" LIMIT ? OFFSET ?")
+	args = append(args, orderArgs...)
 	args = append(args, filter.Limit, filter.Offset)
 
-	rows, err := r.db.QueryContext(ctx, queryBuilder.String(), args...)
+	return r.queryTasks(ctx, queryBuilder.String(), args...)
+}
+
+func (r *Repository) Summarize(ctx context.Context, filter task.ListFilter) (task.ListSummary, error) {
+	query := `
+		SELECT COUNT(*), COALESCE(SUM(estimate_minutes), 0), COALESCE(SUM(story_points), 0)
+		FROM tasks
+	`
+
+	conditions, args := listConditions(filter)
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var summary task.ListSummary
+	err := r.conn(ctx).QueryRowContext(ctx, query, args...).Scan(&summary.Count, &summary.EstimateMinutes, &summary.StoryPoints)
 	if err != nil {
-		return nil, err
+		return task.ListSummary{}, err
 	}
-	defer rows.Close()
 
-	tasks := make([]task.Task, 0, filter.Limit)
-	for rows.Next() {
-		taskItem, scanErr := scanTask(rows)
-		if scanErr != nil {
-			return nil, scanErr
+	return summary, nil
+}
+
+func listConditions(filter task.ListFilter) ([]string, []any) {
+	args := make([]any, 0, 8)
+	conditions := make([]string, 0, 8)
+
+	if !filter.IncludeDeleted {
+		conditions = append(conditions, "deleted_at IS NULL")
+	}
+
+	switch filter.Archived {
+	case task.ArchivedOnly:
+		conditions = append(conditions, "archived_at IS NOT NULL")
+	case task.ArchivedAny:
+	default:
+		conditions = append(conditions, "archived_at IS NULL")
+	}
+
+	if filter.ProjectID != nil {
+		conditions = append(conditions, "project_id = ?")
+		args = append(args, *filter.ProjectID)
+	}
+
+	if filter.SprintID != nil {
+		conditions = append(conditions, "sprint_id = ?")
+		args = append(args, *filter.SprintID)
+	}
+
+	if filter.MilestoneID != nil {
+		conditions = append(conditions, "milestone_id = ?")
+		args = append(args, *filter.MilestoneID)
+	}
+
+	if len(filter.Statuses) > 0 {
+		conditions = append(conditions, fmt.Sprintf("status IN (%s)", placeholders(len(filter.Statuses))))
+		for _, status := range filter.Statuses {
+			args = append(args, status)
 		}
-		tasks = append(tasks, taskItem)
 	}
 
-	if err := rows.Err(); err != nil {
-		return nil, err
+	if filter.PriorityMin != nil {
+		conditions = append(conditions, "priority >= ?")
+		args = append(args, *filter.PriorityMin)
+	}
+	if filter.PriorityMax != nil {
+		conditions = append(conditions, "priority <= ?")
+		args = append(args, *filter.PriorityMax)
 	}
 
-	return tasks, nil
+	if filter.DueAfter != nil {
+		conditions = append(conditions, "due_at > ?")
+		args = append(args, *filter.DueAfter)
+	}
+	if filter.DueBefore != nil {
+		conditions = append(conditions, "due_at < ?")
+		args = append(args, *filter.DueBefore)
+	}
+	if filter.HasDueDate != nil {
+		if *filter.HasDueDate {
+			conditions = append(conditions, "due_at IS NOT NULL")
+		} else {
+			conditions = append(conditions, "due_at IS NULL")
+		}
+	}
+	if filter.Overdue != nil {
+		if *filter.Overdue {
+			conditions = append(conditions, "due_at < ? AND status <> 'done'")
+		} else {
+			conditions = append(conditions, "(due_at IS NULL OR due_at >= ? OR status = 'done')")
+		}
+		args = append(args, filter.Now)
+	}
+
+	if filter.CreatedAfter != nil {
+		conditions = append(conditions, "created_at > ?")
+		args = append(args, *filter.CreatedAfter)
+	}
+	if filter.UpdatedAfter != nil {
+		conditions = append(conditions, "updated_at > ?")
+		args = append(args, *filter.UpdatedAfter)
+	}
+
+	if filter.Query != "" {
+		conditions = append(conditions, matchExpression(filter.SearchMode))
+		args = append(args, filter.Query)
+	}
+
+	if filter.EstimateMinutesMin != nil {
+		conditions = append(conditions, "estimate_minutes >= ?")
+		args = append(args, *filter.EstimateMinutesMin)
+	}
+	if filter.EstimateMinutesMax != nil {
+		conditions = append(conditions, "estimate_minutes <= ?")
+		args = append(args, *filter.EstimateMinutesMax)
+	}
+	if filter.StoryPointsMin != nil {
+		conditions = append(conditions, "story_points >= ?")
+		args = append(args, *filter.StoryPointsMin)
+	}
+	if filter.StoryPointsMax != nil {
+		conditions = append(conditions, "story_points <= ?")
+		args = append(args, *filter.StoryPointsMax)
+	}
+
+	if filter.Expr != nil {
+		condition, exprArgs := filterCondition(filter.Expr)
+		conditions = append(conditions, condition)
+		args = append(args, exprArgs...)
+	}
+
+	for _, customField := range filter.CustomFields {
+		conditions = append(conditions, fmt.Sprintf(`EXISTS (
+			SELECT 1
+			FROM task_custom_field_values cfv
+			JOIN project_custom_fields cf ON cf.id = cfv.field_id
+			WHERE cfv.task_id = tasks.id AND cf.name = ? AND cfv.value IN (%s)
+		)`, placeholders(len(customField.Values))))
+		args = append(args, customField.Name)
+		for _, value := range customField.Values {
+			args = append(args, value)
+		}
+	}
+
+	return conditions, args
 }
 
-func (r *Repository) Update(ctx context.Context, id uint64, params task.UpdateParams) (task.Task, error) {
-	setClauses := make([]string, 0, 5)
-	args := make([]any, 0, 6)
+// keysetCondition selects the tasks after the cursor in the order produced by
+// orderBy for the default sort or a sort by created_at, where ties are always
+// broken by descending id.
+func keysetCondition(filter task.ListFilter) (string, []any) {
+	comparison := "<"
+	if len(filter.Sort) == 1 && !filter.Sort[0].Desc {
+		comparison = ">"
+	}
 
+	createdAt := filter.After.CreatedAt.UTC()
+	condition := "(created_at " + comparison + " ? OR (created_at = ? AND id < ?))"
+	return condition, []any{createdAt, createdAt, filter.After.ID}
+}
+
+// sortColumns maps validated sort fields to columns. Nullable columns sort
+// NULLs last in both directions.
+var sortColumns = map[task.SortField]struct {
+	column   string
+	nullable bool
+}{
+	task.SortByCreatedAt:       {column: "created_at"},
+	task.SortByUpdatedAt:       {column: "updated_at"},
+	task.SortByPriority:        {column: "priority"},
+	task.SortByDueAt:           {column: "due_at", nullable: true},
+	task.SortByEstimateMinutes: {column: "estimate_minutes", nullable: true},
+	task.SortByStoryPoints:     {column: "story_points", nullable: true},
+	task.SortByRank:            {column: "board_rank"},
+}
+
+// matchExpression searches the FULLTEXT index on title and description; its
+// value is the relevance of a task.
+func matchExpression(mode task.SearchMode) string {
+	if mode == task.SearchBoolean {
+		return "MATCH (title, description) AGAINST (? IN BOOLEAN MODE)"
+	}
+	return "MATCH (title, description) AGAINST (? IN NATURAL LANGUAGE MODE)"
+}
+
+func orderBy(filter task.ListFilter) (string, []any) {
+	if len(filter.Sort) == 0 {
+		return "created_at DESC, id DESC", nil
+	}
+
+	clauses := make([]string, 0, len(filter.Sort)*2+1)
+	args := make([]any, 0, 1)
+	for _, sort := range filter.Sort {
+		if sort.Field == task.SortByRelevance {
+			clauses = append(clauses, matchExpression(filter.SearchMode)+" DESC")
+			args = append(args, filter.Query)
+			continue
+		}
+		column, ok := sortColumns[sort.Field]
+		if !ok {
+			continue
+		}
+		if column.nullable {
+			clauses = append(clauses, column.column+" IS NULL")
+		}
+		direction := " ASC"
+		if sort.Desc {
+			direction = " DESC"
+		}
+		clauses = append(clauses, column.column+direction)
+	}
+	clauses = append(clauses, "id DESC")
+
+	return strings.Join(clauses, ", "), args
+}
+
+func (r *Repository) Update(ctx context.Context, id uint64, params task.UpdateParams) (task.Task, error) {
+	setClauses := make([]string, 0, 7)
+	args := make([]any, 0, 8)
+
+	// The sprint is checked against the project before project_id changes,
+	// as MySQL applies the assignments from left to right.
+	if params.SprintID != nil {
+		setClauses = append(setClauses, "sprint_id = ?")
+		args = append(args, *params.SprintID)
+	} else if params.ClearSprintID || params.ClearProjectID {
+		setClauses = append(setClauses, "sprint_id = NULL")
+	} else if params.ProjectID != nil {
+		setClauses = append(setClauses, "sprint_id = IF(project_id <=> ?, sprint_id, NULL)")
+		args = append(args, *params.ProjectID)
+	}
+	if params.ProjectID != nil {
+		setClauses = append(setClauses, "project_id = ?")
+		args = append(args, *params.ProjectID)
+	}
+	if params.ClearProjectID {
+		setClauses = append(setClauses, "project_id = NULL")
+	}
+	if params.MilestoneID != nil {
+		setClauses = append(setClauses, "milestone_id = ?")
+		args = append(args, *params.MilestoneID)
+	}
+	if params.ClearMilestoneID {
+		setClauses = append(setClauses, "milestone_id = NULL")
+	}
+	if params.AssigneeID != nil {
+		setClauses = append(setClauses, "assignee_id = ?")
+		args = append(args, *params.AssigneeID)
+	}
+	if params.ClearAssigneeID {
+		setClauses = append(setClauses, "assignee_id = NULL")
+	}
 	if params.Title != nil {
 		setClauses = append(setClauses, "title = ?")
 		args = append(args, *params.Title)
@@ -131,13 +351,34 @@ func (r *Repository) Update(ctx context.Context, id uint64, params task.UpdatePa
 		args = append(args, *params.Description)
 	}
 	if params.Status != nil {
-		setClauses = append(setClauses, "status = ?")
-		args = append(args, *params.Status)
+		// completed_at keeps the time of the first transition to done and is
+		// reset together with the archive mark when the task is reopened.
+		setClauses = append(
+			setClauses,
+			"completed_at = IF(? = 'done', COALESCE(completed_at, CURRENT_TIMESTAMP), NULL)",
+			"archived_at = IF(? = 'done', archived_at, NULL)",
+			"status = ?",
+		)
+		args = append(args, *params.Status, *params.Status, *params.Status)
 	}
 	if params.Priority != nil {
 		setClauses = append(setClauses, "priority = ?")
 		args = append(args, *params.Priority)
 	}
+	if params.EstimateMinutes != nil {
+		setClauses = append(setClauses, "estimate_minutes = ?")
+		args = append(args, *params.EstimateMinutes)
+	}
+	if params.ClearEstimateMinutes {
+		setClauses = append(setClauses, "estimate_minutes = NULL")
+	}
+	if params.StoryPoints != nil {
+		setClauses = append(setClauses, "story_points = ?")
+		args = append(args, *params.StoryPoints)
+	}
+	if params.ClearStoryPoints {
+		setClauses = append(setClauses, "story_points = NULL")
+	}
 	if params.DueAt != nil {
 		setClauses = append(setClauses, "due_at = ?")
 		args = append(args, params.DueAt.UTC())
@@ -145,94 +386,577 @@ func (r *Repository) Update(ctx context.Context, id uint64, params task.UpdatePa
 	if params.ClearDueAt {
 		setClauses = append(setClauses, "due_at = NULL")
 	}
+	if params.RecurrenceRule != nil {
+		setClauses = append(setClauses, "recurrence_rule = ?")
+		args = append(args, *params.RecurrenceRule)
+	}
+	if params.ClearRecurrenceRule {
+		setClauses = append(setClauses, "recurrence_rule = NULL")
+	}
 
-	if len(setClauses) == 0 {
+	if len(setClauses) == 0 && params.Labels == nil && params.CustomFields == nil {
 		return task.Task{}, task.ValidationError{Field: "body", Message: "at least one field must be provided for update"}
 	}
 
-	query := fmt.Sprintf("UPDATE tasks SET %s WHERE id = ?", strings.Join(setClauses, ", "))
-	args = append(args, id)
+	err := r.withTx(ctx, func(tx *sql.Tx) error {
+		if err := lockTask(ctx, tx, id); err != nil {
+			return err
+		}
+		if params.ExpectedVersion != nil {
+			if err := checkTaskVersion(ctx, tx, id, *params.ExpectedVersion); err != nil {
+				return err
+			}
+		}
+
+		// Changes stored outside of the tasks table still bump updated_at.
+		touched := false
+		if params.Labels != nil {
+			if err := replaceLabels(ctx, tx, id, *params.Labels); err != nil {
+				return err
+			}
+			touched = true
+		}
+
+		if params.ProjectID != nil || params.ClearProjectID {
+			if err := removeForeignCustomFieldValues(ctx, tx, id, params.ProjectID); err != nil {
+				return err
+			}
+		}
+
+		if params.CustomFields != nil {
+			if err := replaceCustomFieldValues(ctx, tx, id, params.CustomFields); err != nil {
+				return err
+			}
+			touched = true
+		}
+
+		if params.AssigneeID != nil {
+			if err := addWatcher(ctx, tx, id, *params.AssigneeID); err != nil {
+				return err
+			}
+		}
+
+		// A task that changes its status goes to the end of the new column.
+		if params.Status != nil {
+			var current task.Status
+			if err := tx.QueryRowContext(ctx, `SELECT status FROM tasks WHERE id = ?`, id).Scan(&current); err != nil {
+				return err
+			}
+			if current != *params.Status {
+				if params.WIPLimit != nil {
+					if err := checkWIPLimit(ctx, tx, id, *params.Status, *params.WIPLimit); err != nil {
+						return err
+					}
+				}
+				rank, err := appendRank(ctx, tx, *params.Status)
+				if err != nil {
+					return err
+				}
+				setClauses = append(setClauses, "board_rank = ?")
+				args = append(args, rank)
+			}
+		}
+
+		if err := addMentions(ctx, tx, id, nil, params.ActorID, params.Mentions); err != nil {
+			return err
+		}
+
+		if touched {
+			setClauses = append(setClauses, "updated_at = CURRENT_TIMESTAMP")
+		}
+		setClauses = append(setClauses, "version = version + 1")
 
-	result, err := r.db.ExecContext(ctx, query, args...)
+		query := fmt.Sprintf("UPDATE tasks SET %s WHERE id = ?", strings.Join(setClauses, ", "))
+		_, err := tx.ExecContext(ctx, query, append(args, id)...)
+		return err
+	})
 	if err != nil {
 		return task.Task{}, err
 	}
 
+	return r.GetByID(ctx, id)
+}
+
+func (r *Repository) Delete(ctx context.Context, id uint64, expectedVersion *uint64) error {
+	return r.withTx(ctx, func(tx *sql.Tx) error {
+		if err := lockTask(ctx, tx, id); err != nil {
+			return err
+		}
+		if expectedVersion != nil {
+			if err := checkTaskVersion(ctx, tx, id, *expectedVersion); err != nil {
+				return err
+			}
+		}
+
+		_, err := tx.ExecContext(ctx, `UPDATE tasks SET deleted_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id = ?`, id)
+		return err
+	})
+}
+
+func (r *Repository) Restore(ctx context.Context, id uint64) error {
+	const query = `UPDATE tasks SET deleted_at = NULL, version = version + 1 WHERE id = ? AND deleted_at IS NOT NULL`
+
+	result, err := r.conn(ctx).ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return task.Task{}, err
+		return err
 	}
 	if rowsAffected == 0 {
-		return task.Task{}, task.ErrTaskNotFound
+		return task.ErrTaskNotFound
+	}
+
+	return nil
+}
+
+func (r *Repository) Archive(ctx context.Context, id uint64) error {
+	const query = `UPDATE tasks SET archived_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id = ? AND archived_at IS NULL AND deleted_at IS NULL`
+
+	_, err := r.conn(ctx).ExecContext(ctx, query, id)
+	return err
+}
+
+func (r *Repository) Unarchive(ctx context.Context, id uint64) error {
+	const query = `UPDATE tasks SET archived_at = NULL, version = version + 1 WHERE id = ? AND archived_at IS NOT NULL AND deleted_at IS NULL`
+
+	_, err := r.conn(ctx).ExecContext(ctx, query, id)
+	return err
+}
+
+func (r *Repository) CreateNextOccurrence(ctx context.Context, sourceID uint64, params task.CreateParams) (task.Task, error) {
+	var id uint64
+	err := r.withTx(ctx, func(tx *sql.Tx) error {
+		var nextOccurrenceID sql.NullInt64
+		err := tx.QueryRowContext(ctx, `SELECT next_occurrence_id FROM tasks WHERE id = ? AND deleted_at IS NULL FOR UPDATE`, sourceID).Scan(&nextOccurrenceID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return task.ErrTaskNotFound
+			}
+			return err
+		}
+		if nextOccurrenceID.Valid {
+			return task.ErrNextOccurrenceExists
+		}
+
+		id, err = insertTask(ctx, tx, params)
+		if err != nil {
+			return err
+		}
+
+		if err := copyCustomFieldValues(ctx, tx, sourceID, id); err != nil {
+			return err
+		}
+		if err := copyChecklist(ctx, tx, sourceID, id); err != nil {
+			return err
+		}
+		if err := copyWatchers(ctx, tx, sourceID, id); err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `UPDATE tasks SET next_occurrence_id = ?, version = version + 1 WHERE id = ?`, id, sourceID)
+		return err
+	})
+	if err != nil {
+		return task.Task{}, err
 	}
 
 	return r.GetByID(ctx, id)
 }
 
-func (r *Repository) Delete(ctx context.Context, id uint64) error {
-	const query = `DELETE FROM tasks WHERE id = ?`
+func (r *Repository) queryTasks(ctx context.Context, query string, args ...any) ([]task.Task, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tasks := make([]task.Task, 0)
+	for rows.Next() {
+		taskItem, scanErr := scanTask(rows)
+		if scanErr != nil {
+			return nil, scanErr
+		}
+		tasks = append(tasks, taskItem)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.hydrate(ctx, tasks); err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+type txContextKey struct{}
+
+// querier is implemented by both *sql.DB and *sql.Tx.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// RunInTx runs fn in a transaction that every repository call made with the
+// context passed to fn takes part in.
+func (r *Repository) RunInTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return r.withTx(ctx, func(tx *sql.Tx) error {
+		return fn(context.WithValue(ctx, txContextKey{}, tx))
+	})
+}
+
+func (r *Repository) conn(ctx context.Context) querier {
+	if tx, ok := ctx.Value(txContextKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return r.db
+}
+
+// withTx joins the transaction started by RunInTx, if any; that transaction
+// is then committed or rolled back by RunInTx.
+func (r *Repository) withTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	if tx, ok := ctx.Value(txContextKey{}).(*sql.Tx); ok {
+		return fn(tx)
+	}
 
-	result, err := r.db.ExecContext(ctx, query, id)
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
 		return err
 	}
-	if rowsAffected == 0 {
+
+	return tx.Commit()
+}
+
+// insertTask appends the new task to the end of its status column.
+func insertTask(ctx context.Context, tx *sql.Tx, params task.CreateParams) (uint64, error) {
+	const query = `
+		INSERT INTO tasks (project_id, sprint_id, milestone_id, parent_id, reporter_id, assignee_id, title, description, status, priority, board_rank, estimate_minutes, story_points, due_at, recurrence_rule, completed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, IF(? = 'done', CURRENT_TIMESTAMP, NULL))
+	`
+
+	rank, err := appendRank(ctx, tx, params.Status)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := tx.ExecContext(
+		ctx,
+		query,
+		asNullable(params.ProjectID),
+		asNullable(params.SprintID),
+		asNullable(params.MilestoneID),
+		asNullable(params.ParentID),
+		asNullable(params.ReporterID),
+		asNullable(params.AssigneeID),
+		params.Title,
+		params.Description,
+		params.Status,
+		params.Priority,
+		rank,
+		asNullable(params.EstimateMinutes),
+		asNullable(params.StoryPoints),
+		asNullableTime(params.DueAt),
+		asNullableString(params.RecurrenceRule),
+		params.Status,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	if err := replaceLabels(ctx, tx, uint64(id), params.Labels); err != nil {
+		return 0, err
+	}
+
+	if err := replaceCustomFieldValues(ctx, tx, uint64(id), params.CustomFields); err != nil {
+		return 0, err
+	}
+
+	for _, userID := range []*string{params.ReporterID, params.AssigneeID} {
+		if userID == nil {
+			continue
+		}
+		if err := addWatcher(ctx, tx, uint64(id), *userID); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := addMentions(ctx, tx, uint64(id), nil, params.ReporterID, params.Mentions); err != nil {
+		return 0, err
+	}
+
+	return uint64(id), nil
+}
+
+func lockTask(ctx context.Context, tx *sql.Tx, id uint64) error {
+	var lockedID uint64
+	err := tx.QueryRowContext(ctx, `SELECT id FROM tasks WHERE id = ? AND deleted_at IS NULL FOR UPDATE`, id).Scan(&lockedID)
+	if errors.Is(err, sql.ErrNoRows) {
 		return task.ErrTaskNotFound
 	}
+	return err
+}
 
+// checkTaskVersion compares the version of a task locked by lockTask with the
+// one the caller has read.
+func checkTaskVersion(ctx context.Context, tx *sql.Tx, id uint64, expected uint64) error {
+	var version uint64
+	if err := tx.QueryRowContext(ctx, `SELECT version FROM tasks WHERE id = ?`, id).Scan(&version); err != nil {
+		return err
+	}
+	if version != expected {
+		return task.ErrVersionConflict
+	}
 	return nil
 }
 
+func replaceLabels(ctx context.Context, tx *sql.Tx, taskID uint64, labels []string) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM task_labels WHERE task_id = ?`, taskID); err != nil {
+		return err
+	}
+	if len(labels) == 0 {
+		return nil
+	}
+
+	placeholders := make([]string, 0, len(labels))
+	args := make([]any, 0, len(labels)*2)
+	for _, label := range labels {
+		placeholders = append(placeholders, "(?, ?)")
+		args = append(args, taskID, label)
+	}
+
+	query := "INSERT INTO task_labels (task_id, label) VALUES " + strings.Join(placeholders, ", ")
+	_, err := tx.ExecContext(ctx, query, args...)
+	return err
+}
+
+// hydrate loads the data stored outside of the tasks table for the given
+// tasks.
+func (r *Repository) hydrate(ctx context.Context, tasks []task.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	indexByID := make(map[uint64]int, len(tasks))
+	ids := make([]any, 0, len(tasks))
+	for i := range tasks {
+		indexByID[tasks[i].ID] = i
+		ids = append(ids, tasks[i].ID)
+	}
+
+	if err := r.attachLabels(ctx, tasks, indexByID, ids); err != nil {
+		return err
+	}
+	if err := r.attachCustomFields(ctx, tasks, indexByID, ids); err != nil {
+		return err
+	}
+	if err := r.attachChecklistProgress(ctx, tasks, indexByID, ids); err != nil {
+		return err
+	}
+	return r.attachTimeSpent(ctx, tasks, indexByID, ids)
+}
+
+func (r *Repository) attachLabels(ctx context.Context, tasks []task.Task, indexByID map[uint64]int, ids []any) error {
+	for i := range tasks {
+		tasks[i].Labels = []string{}
+	}
+
+	query := fmt.Sprintf(
+		"SELECT task_id, label FROM task_labels WHERE task_id IN (%s) ORDER BY label",
+		placeholders(len(ids)),
+	)
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, ids...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			taskID uint64
+			label  string
+		)
+		if err := rows.Scan(&taskID, &label); err != nil {
+			return err
+		}
+		if i, ok := indexByID[taskID]; ok {
+			tasks[i].Labels = append(tasks[i].Labels, label)
+		}
+	}
+
+	return rows.Err()
+}
+
 type sqlScanner interface {
 	Scan(dest ...any) error
 }
 
 func scanTask(scanner sqlScanner) (task.Task, error) {
 	var (
-		foundTask    task.Task
-		description  sql.NullString
-		dueAt        sql.NullTime
-		createdAtRaw time.Time
-		updatedAtRaw time.Time
+		foundTask        task.Task
+		projectID        sql.NullInt64
+		sprintID         sql.NullInt64
+		milestoneID      sql.NullInt64
+		parentID         sql.NullInt64
+		reporterID       sql.NullString
+		assigneeID       sql.NullString
+		description      sql.NullString
+		estimateMinutes  sql.NullInt64
+		storyPoints      sql.NullInt64
+		dueAt            sql.NullTime
+		recurrenceRule   sql.NullString
+		nextOccurrenceID sql.NullInt64
+		createdAtRaw     time.Time
+		updatedAtRaw     time.Time
+		completedAt      sql.NullTime
+		archivedAt       sql.NullTime
+		deletedAt        sql.NullTime
 	)
 
 	err := scanner.Scan(
 		&foundTask.ID,
+		&projectID,
+		&sprintID,
+		&milestoneID,
+		&parentID,
+		&reporterID,
+		&assigneeID,
 		&foundTask.Title,
 		&description,
 		&foundTask.Status,
 		&foundTask.Priority,
+		&foundTask.Rank,
+		&estimateMinutes,
+		&storyPoints,
 		&dueAt,
+		&recurrenceRule,
+		&nextOccurrenceID,
 		&createdAtRaw,
 		&updatedAtRaw,
+		&completedAt,
+		&archivedAt,
+		&deletedAt,
+		&foundTask.Version,
 	)
 	if err != nil {
 		return task.Task{}, err
 	}
 
+	if projectID.Valid {
+		id := uint64(projectID.Int64)
+		foundTask.ProjectID = &id
+	}
+
+	if sprintID.Valid {
+		id := uint64(sprintID.Int64)
+		foundTask.SprintID = &id
+	}
+
+	if milestoneID.Valid {
+		id := uint64(milestoneID.Int64)
+		foundTask.MilestoneID = &id
+	}
+
+	if parentID.Valid {
+		id := uint64(parentID.Int64)
+		foundTask.ParentID = &id
+	}
+
+	if reporterID.Valid {
+		foundTask.ReporterID = &reporterID.String
+	}
+
+	if assigneeID.Valid {
+		foundTask.AssigneeID = &assigneeID.String
+	}
+
 	if description.Valid {
 		foundTask.Description = description.String
 	}
 
+	if estimateMinutes.Valid {
+		value := uint32(estimateMinutes.Int64)
+		foundTask.EstimateMinutes = &value
+	}
+
+	if storyPoints.Valid {
+		value := uint16(storyPoints.Int64)
+		foundTask.StoryPoints = &value
+	}
+
 	if dueAt.Valid {
 		normalized := dueAt.Time.UTC()
 		foundTask.DueAt = &normalized
 	}
 
+	if recurrenceRule.Valid {
+		foundTask.RecurrenceRule = recurrenceRule.String
+	}
+
+	if nextOccurrenceID.Valid {
+		id := uint64(nextOccurrenceID.Int64)
+		foundTask.NextOccurrenceID = &id
+	}
+
 	foundTask.CreatedAt = createdAtRaw.UTC()
 	foundTask.UpdatedAt = updatedAtRaw.UTC()
 
+	foundTask.CompletedAt = nullableTime(completedAt)
+	foundTask.ArchivedAt = nullableTime(archivedAt)
+	foundTask.DeletedAt = nullableTime(deletedAt)
+
 	return foundTask, nil
 }
 
+func isDuplicateKey(err error) bool {
+	var mysqlErr *mysqldriver.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrDuplicateEntry
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+func nullableTime(value sql.NullTime) *time.Time {
+	if !value.Valid {
+		return nil
+	}
+	normalized := value.Time.UTC()
+	return &normalized
+}
+
 func asNullableTime(value *time.Time) any {
 	if value == nil {
 		return nil
 	}
 	return value.UTC()
 }
+
+func asNullable[T any](value *T) any {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
+
+func asNullableString(value string) any {
+	if value == "" {
+		return nil
+	}
+	return value
+}
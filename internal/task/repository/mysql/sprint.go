@@ -0,0 +1,314 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/task"
+)
+
+var _ task.SprintRepository = (*Repository)(nil)
+
+const sprintColumns = `id, project_id, name, goal, state, starts_at, ends_at, committed_tasks, committed_points, completed_tasks, completed_points, carried_over_tasks, carried_over_points, next_sprint_id, created_at, updated_at, started_at, closed_at`
+
+// sprintSummaryQuery totals the tasks of a sprint and the done ones among
+// them.
+const sprintSummaryQuery = `
+	SELECT
+		COUNT(*),
+		COALESCE(SUM(story_points), 0),
+		COALESCE(SUM(status = 'done'), 0),
+		COALESCE(SUM(IF(status = 'done', story_points, 0)), 0)
+	FROM tasks
+	WHERE sprint_id = ? AND deleted_at IS NULL
+`
+
+func (r *Repository) CreateSprint(ctx context.Context, params task.SprintParams) (task.Sprint, error) {
+	const query = `
+		INSERT INTO sprints (project_id, name, goal, starts_at, ends_at)
+		SELECT id, ?, ?, ?, ?
+		FROM projects
+		WHERE id = ?
+	`
+
+	result, err := r.conn(ctx).ExecContext(ctx, query, params.Name, asNullableString(params.Goal), params.StartsAt.UTC(), params.EndsAt.UTC(), params.ProjectID)
+	if err != nil {
+		if isDuplicateKey(err) {
+			return task.Sprint{}, task.ErrSprintExists
+		}
+		return task.Sprint{}, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return task.Sprint{}, err
+	}
+	if rowsAffected == 0 {
+		return task.Sprint{}, task.ErrProjectNotFound
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return task.Sprint{}, err
+	}
+
+	return r.GetSprint(ctx, uint64(id))
+}
+
+func (r *Repository) GetSprint(ctx context.Context, id uint64) (task.Sprint, error) {
+	row := r.conn(ctx).QueryRowContext(ctx, `SELECT `+sprintColumns+` FROM sprints WHERE id = ?`, id)
+	found, err := scanSprint(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return task.Sprint{}, task.ErrSprintNotFound
+	}
+	return found, err
+}
+
+func (r *Repository) ListSprints(ctx context.Context, projectID uint64) ([]task.Sprint, error) {
+	var exists bool
+	if err := r.conn(ctx).QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM projects WHERE id = ?)`, projectID).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, task.ErrProjectNotFound
+	}
+
+	rows, err := r.conn(ctx).QueryContext(ctx, `SELECT `+sprintColumns+` FROM sprints WHERE project_id = ? ORDER BY starts_at, id`, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sprints := make([]task.Sprint, 0)
+	for rows.Next() {
+		found, err := scanSprint(rows)
+		if err != nil {
+			return nil, err
+		}
+		sprints = append(sprints, found)
+	}
+
+	return sprints, rows.Err()
+}
+
+func (r *Repository) UpdateSprint(ctx context.Context, id uint64, params task.UpdateSprintParams) (task.Sprint, error) {
+	setClauses := make([]string, 0, 4)
+	args := make([]any, 0, 5)
+
+	if params.Name != nil {
+		setClauses = append(setClauses, "name = ?")
+		args = append(args, *params.Name)
+	}
+	if params.Goal != nil {
+		setClauses = append(setClauses, "goal = ?")
+		args = append(args, asNullableString(*params.Goal))
+	}
+	if params.StartsAt != nil {
+		setClauses = append(setClauses, "starts_at = ?")
+		args = append(args, params.StartsAt.UTC())
+	}
+	if params.EndsAt != nil {
+		setClauses = append(setClauses, "ends_at = ?")
+		args = append(args, params.EndsAt.UTC())
+	}
+
+	if len(setClauses) == 0 {
+		return task.Sprint{}, task.ValidationError{Field: "body", Message: "at least one field must be provided for update"}
+	}
+
+	query := fmt.Sprintf("UPDATE sprints SET %s WHERE id = ?", strings.Join(setClauses, ", "))
+	if _, err := r.conn(ctx).ExecContext(ctx, query, append(args, id)...); err != nil {
+		if isDuplicateKey(err) {
+			return task.Sprint{}, task.ErrSprintExists
+		}
+		return task.Sprint{}, err
+	}
+
+	return r.GetSprint(ctx, id)
+}
+
+// StartSprint activates a planned sprint and records its current scope as
+// committed.
+func (r *Repository) StartSprint(ctx context.Context, id uint64) error {
+	return r.withTx(ctx, func(tx *sql.Tx) error {
+		locked, err := lockSprint(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if locked.State != task.SprintPlanned {
+			return task.ErrInvalidSprintTransition
+		}
+
+		summary, err := scanSprintSummary(tx.QueryRowContext(ctx, sprintSummaryQuery, id))
+		if err != nil {
+			return err
+		}
+
+		const query = `
+			UPDATE sprints
+			SET state = 'active', started_at = CURRENT_TIMESTAMP, committed_tasks = ?, committed_points = ?
+			WHERE id = ?
+		`
+		_, err = tx.ExecContext(ctx, query, summary.Total.Tasks, summary.Total.Points, id)
+		if isDuplicateKey(err) {
+			return task.ErrActiveSprintExists
+		}
+		return err
+	})
+}
+
+// CloseSprint records what was completed and moves the unfinished tasks to
+// the next sprint, or to the backlog when nextSprintID is nil.
+func (r *Repository) CloseSprint(ctx context.Context, id uint64, nextSprintID *uint64) error {
+	return r.withTx(ctx, func(tx *sql.Tx) error {
+		locked, err := lockSprint(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if locked.State != task.SprintActive {
+			return task.ErrInvalidSprintTransition
+		}
+
+		if nextSprintID != nil {
+			next, err := lockSprint(ctx, tx, *nextSprintID)
+			if errors.Is(err, task.ErrSprintNotFound) {
+				return task.ValidationError{Field: "next_sprint_id", Message: "sprint does not exist"}
+			}
+			if err != nil {
+				return err
+			}
+			if next.ProjectID != locked.ProjectID {
+				return task.ValidationError{Field: "next_sprint_id", Message: "sprint belongs to another project"}
+			}
+			if next.State == task.SprintClosed {
+				return task.ValidationError{Field: "next_sprint_id", Message: "sprint is closed"}
+			}
+		}
+
+		summary, err := scanSprintSummary(tx.QueryRowContext(ctx, sprintSummaryQuery, id))
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(
+			ctx,
+			`UPDATE tasks SET sprint_id = ?, version = version + 1 WHERE sprint_id = ? AND status <> 'done' AND deleted_at IS NULL`,
+			asNullable(nextSprintID),
+			id,
+		)
+		if err != nil {
+			return err
+		}
+
+		const query = `
+			UPDATE sprints
+			SET state = 'closed', closed_at = CURRENT_TIMESTAMP,
+				completed_tasks = ?, completed_points = ?,
+				carried_over_tasks = ?, carried_over_points = ?,
+				next_sprint_id = ?
+			WHERE id = ?
+		`
+		_, err = tx.ExecContext(
+			ctx,
+			query,
+			summary.Done.Tasks,
+			summary.Done.Points,
+			summary.Total.Tasks-summary.Done.Tasks,
+			summary.Total.Points-summary.Done.Points,
+			asNullable(nextSprintID),
+			id,
+		)
+		return err
+	})
+}
+
+func (r *Repository) SummarizeSprint(ctx context.Context, id uint64) (task.SprintSummary, error) {
+	return scanSprintSummary(r.conn(ctx).QueryRowContext(ctx, sprintSummaryQuery, id))
+}
+
+func lockSprint(ctx context.Context, tx *sql.Tx, id uint64) (task.Sprint, error) {
+	locked := task.Sprint{ID: id}
+	err := tx.QueryRowContext(ctx, `SELECT project_id, state FROM sprints WHERE id = ? FOR UPDATE`, id).Scan(&locked.ProjectID, &locked.State)
+	if errors.Is(err, sql.ErrNoRows) {
+		return task.Sprint{}, task.ErrSprintNotFound
+	}
+	return locked, err
+}
+
+func scanSprintSummary(scanner sqlScanner) (task.SprintSummary, error) {
+	var summary task.SprintSummary
+	err := scanner.Scan(&summary.Total.Tasks, &summary.Total.Points, &summary.Done.Tasks, &summary.Done.Points)
+	return summary, err
+}
+
+func scanSprint(scanner sqlScanner) (task.Sprint, error) {
+	var (
+		found             task.Sprint
+		goal              sql.NullString
+		startsAt          time.Time
+		endsAt            time.Time
+		committedTasks    sql.NullInt64
+		committedPoints   sql.NullInt64
+		completedTasks    sql.NullInt64
+		completedPoints   sql.NullInt64
+		carriedOverTasks  sql.NullInt64
+		carriedOverPoints sql.NullInt64
+		nextSprintID      sql.NullInt64
+		createdAt         time.Time
+		updatedAt         time.Time
+		startedAt         sql.NullTime
+		closedAt          sql.NullTime
+	)
+
+	err := scanner.Scan(
+		&found.ID,
+		&found.ProjectID,
+		&found.Name,
+		&goal,
+		&found.State,
+		&startsAt,
+		&endsAt,
+		&committedTasks,
+		&committedPoints,
+		&completedTasks,
+		&completedPoints,
+		&carriedOverTasks,
+		&carriedOverPoints,
+		&nextSprintID,
+		&createdAt,
+		&updatedAt,
+		&startedAt,
+		&closedAt,
+	)
+	if err != nil {
+		return task.Sprint{}, err
+	}
+
+	found.Goal = goal.String
+	found.StartsAt = startsAt.UTC()
+	found.EndsAt = endsAt.UTC()
+	found.Committed = nullableTotals(committedTasks, committedPoints)
+	found.Completed = nullableTotals(completedTasks, completedPoints)
+	found.CarriedOver = nullableTotals(carriedOverTasks, carriedOverPoints)
+	if nextSprintID.Valid {
+		id := uint64(nextSprintID.Int64)
+		found.NextSprintID = &id
+	}
+	found.CreatedAt = createdAt.UTC()
+	found.UpdatedAt = updatedAt.UTC()
+	found.StartedAt = nullableTime(startedAt)
+	found.ClosedAt = nullableTime(closedAt)
+
+	return found, nil
+}
+
+func nullableTotals(tasks, points sql.NullInt64) *task.SprintTotals {
+	if !tasks.Valid || !points.Valid {
+		return nil
+	}
+	return &task.SprintTotals{Tasks: tasks.Int64, Points: points.Int64}
+}
@@ -0,0 +1,151 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/task"
+)
+
+var _ task.TemplateRepository = (*Repository)(nil)
+
+const templateColumns = `id, name, definition, created_at, updated_at`
+
+func (r *Repository) CreateTemplate(ctx context.Context, name string, definition task.TemplateTask) (task.Template, error) {
+	encoded, err := json.Marshal(definition)
+	if err != nil {
+		return task.Template{}, err
+	}
+
+	result, err := r.conn(ctx).ExecContext(ctx, `INSERT INTO task_templates (name, definition) VALUES (?, ?)`, name, string(encoded))
+	if err != nil {
+		if isDuplicateKey(err) {
+			return task.Template{}, task.ErrTemplateExists
+		}
+		return task.Template{}, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return task.Template{}, err
+	}
+
+	return r.GetTemplate(ctx, uint64(id))
+}
+
+func (r *Repository) GetTemplate(ctx context.Context, id uint64) (task.Template, error) {
+	row := r.conn(ctx).QueryRowContext(ctx, `SELECT `+templateColumns+` FROM task_templates WHERE id = ?`, id)
+	found, err := scanTemplate(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return task.Template{}, task.ErrTemplateNotFound
+	}
+	return found, err
+}
+
+func (r *Repository) ListTemplates(ctx context.Context) ([]task.Template, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, `SELECT `+templateColumns+` FROM task_templates ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	templates := make([]task.Template, 0)
+	for rows.Next() {
+		found, err := scanTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, found)
+	}
+
+	return templates, rows.Err()
+}
+
+func (r *Repository) DeleteTemplate(ctx context.Context, id uint64) error {
+	result, err := r.conn(ctx).ExecContext(ctx, `DELETE FROM task_templates WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return task.ErrTemplateNotFound
+	}
+
+	return nil
+}
+
+// CreateTaskTree inserts the tasks depth-first in one transaction and returns
+// them in that order, so the first task is the root of the tree.
+func (r *Repository) CreateTaskTree(ctx context.Context, root task.TaskTreeParams) ([]task.Task, error) {
+	ids := make([]any, 0, 1)
+	err := r.withTx(ctx, func(tx *sql.Tx) error {
+		var insert func(node task.TaskTreeParams, parentID *uint64) error
+		insert = func(node task.TaskTreeParams, parentID *uint64) error {
+			params := node.Task
+			params.ParentID = parentID
+
+			id, err := insertTask(ctx, tx, params)
+			if err != nil {
+				return err
+			}
+			ids = append(ids, id)
+
+			for position, text := range node.Checklist {
+				_, err := tx.ExecContext(
+					ctx,
+					`INSERT INTO task_checklist_items (task_id, position, text) VALUES (?, ?, ?)`,
+					id,
+					position,
+					text,
+				)
+				if err != nil {
+					return err
+				}
+			}
+
+			for _, subtask := range node.Subtasks {
+				if err := insert(subtask, &id); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		return insert(root, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`SELECT `+taskColumns+` FROM tasks WHERE id IN (%s) ORDER BY id`, placeholders(len(ids)))
+	return r.queryTasks(ctx, query, ids...)
+}
+
+func scanTemplate(scanner sqlScanner) (task.Template, error) {
+	var (
+		found      task.Template
+		definition []byte
+		createdAt  time.Time
+		updatedAt  time.Time
+	)
+
+	if err := scanner.Scan(&found.ID, &found.Name, &definition, &createdAt, &updatedAt); err != nil {
+		return task.Template{}, err
+	}
+	if err := json.Unmarshal(definition, &found.Task); err != nil {
+		return task.Template{}, err
+	}
+
+	found.CreatedAt = createdAt.UTC()
+	found.UpdatedAt = updatedAt.UTC()
+
+	return found, nil
+}
@@ -0,0 +1,289 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/task"
+)
+
+var _ task.TimeEntryRepository = (*Repository)(nil)
+
+const timeEntryColumns = `id, task_id, user_id, started_at, ended_at, duration_seconds, note, created_at`
+
+func (r *Repository) CreateTimeEntry(ctx context.Context, params task.TimeEntryParams) (task.TimeEntry, error) {
+	const query = `
+		INSERT INTO time_entries (task_id, user_id, started_at, ended_at, duration_seconds, note)
+		SELECT id, ?, ?, ?, ?, ?
+		FROM tasks
+		WHERE id = ? AND deleted_at IS NULL
+	`
+
+	var duration any
+	if params.EndedAt != nil {
+		duration = int64(params.EndedAt.Sub(params.StartedAt) / time.Second)
+	}
+
+	var id int64
+	err := r.withTx(ctx, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(
+			ctx,
+			query,
+			params.UserID,
+			params.StartedAt.UTC(),
+			asNullableTime(params.EndedAt),
+			duration,
+			params.Note,
+			params.TaskID,
+		)
+		if err != nil {
+			if isDuplicateKey(err) {
+				return task.ErrTimerAlreadyRunning
+			}
+			return err
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return task.ErrTaskNotFound
+		}
+
+		id, err = result.LastInsertId()
+		if err != nil {
+			return err
+		}
+
+		return bumpTaskVersion(ctx, tx, params.TaskID)
+	})
+	if err != nil {
+		return task.TimeEntry{}, err
+	}
+
+	return r.getTimeEntry(ctx, uint64(id))
+}
+
+func (r *Repository) StopTimer(ctx context.Context, taskID uint64, userID string, endedAt time.Time) (task.TimeEntry, error) {
+	var entryID uint64
+	err := r.withTx(ctx, func(tx *sql.Tx) error {
+		var startedAt time.Time
+		err := tx.QueryRowContext(
+			ctx,
+			`SELECT id, started_at FROM time_entries WHERE task_id = ? AND user_id = ? AND ended_at IS NULL FOR UPDATE`,
+			taskID,
+			userID,
+		).Scan(&entryID, &startedAt)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return task.ErrTimerNotRunning
+			}
+			return err
+		}
+
+		duration := int64(endedAt.Sub(startedAt.UTC()) / time.Second)
+		if duration < 0 {
+			duration = 0
+		}
+
+		_, err = tx.ExecContext(
+			ctx,
+			`UPDATE time_entries SET ended_at = ?, duration_seconds = ? WHERE id = ?`,
+			endedAt.UTC(),
+			duration,
+			entryID,
+		)
+		if err != nil {
+			return err
+		}
+
+		return bumpTaskVersion(ctx, tx, taskID)
+	})
+	if err != nil {
+		return task.TimeEntry{}, err
+	}
+
+	return r.getTimeEntry(ctx, entryID)
+}
+
+func (r *Repository) ListTimeEntries(ctx context.Context, taskID uint64) ([]task.TimeEntry, error) {
+	if _, err := r.GetByID(ctx, taskID); err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT ` + timeEntryColumns + `
+		FROM time_entries
+		WHERE task_id = ?
+		ORDER BY started_at DESC, id DESC
+	`
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]task.TimeEntry, 0)
+	for rows.Next() {
+		entry, scanErr := scanTimeEntry(rows)
+		if scanErr != nil {
+			return nil, scanErr
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+func (r *Repository) DeleteTimeEntry(ctx context.Context, taskID, entryID uint64, userID string) error {
+	const query = `DELETE FROM time_entries WHERE id = ? AND task_id = ? AND user_id = ?`
+
+	return r.withTx(ctx, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, query, entryID, taskID, userID)
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return task.ErrTimeEntryNotFound
+		}
+
+		return bumpTaskVersion(ctx, tx, taskID)
+	})
+}
+
+func (r *Repository) TimeReport(ctx context.Context, filter task.TimeReportFilter) ([]task.UserTimeReport, error) {
+	conditions := []string{"time_entries.ended_at IS NOT NULL"}
+	args := make([]any, 0, 3)
+
+	if filter.UserID != "" {
+		conditions = append(conditions, "time_entries.user_id = ?")
+		args = append(args, filter.UserID)
+	}
+	if filter.From != nil {
+		conditions = append(conditions, "time_entries.started_at >= ?")
+		args = append(args, filter.From.UTC())
+	}
+	if filter.To != nil {
+		conditions = append(conditions, "time_entries.started_at < ?")
+		args = append(args, filter.To.UTC())
+	}
+
+	query := fmt.Sprintf(`
+		SELECT time_entries.user_id, tasks.id, tasks.title, SUM(time_entries.duration_seconds) AS total
+		FROM time_entries
+		JOIN tasks ON tasks.id = time_entries.task_id
+		WHERE %s
+		GROUP BY time_entries.user_id, tasks.id, tasks.title
+		ORDER BY time_entries.user_id, total DESC, tasks.id
+	`, strings.Join(conditions, " AND "))
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reports := make([]task.UserTimeReport, 0)
+	for rows.Next() {
+		var (
+			userID   string
+			taskTime task.TaskTime
+		)
+		if err := rows.Scan(&userID, &taskTime.TaskID, &taskTime.TaskTitle, &taskTime.DurationSeconds); err != nil {
+			return nil, err
+		}
+
+		if len(reports) == 0 || reports[len(reports)-1].UserID != userID {
+			reports = append(reports, task.UserTimeReport{UserID: userID, Tasks: []task.TaskTime{}})
+		}
+		report := &reports[len(reports)-1]
+		report.DurationSeconds += taskTime.DurationSeconds
+		report.Tasks = append(report.Tasks, taskTime)
+	}
+
+	return reports, rows.Err()
+}
+
+func (r *Repository) getTimeEntry(ctx context.Context, id uint64) (task.TimeEntry, error) {
+	query := `SELECT ` + timeEntryColumns + ` FROM time_entries WHERE id = ?`
+
+	entry, err := scanTimeEntry(r.conn(ctx).QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return task.TimeEntry{}, task.ErrTimeEntryNotFound
+		}
+		return task.TimeEntry{}, err
+	}
+
+	return entry, nil
+}
+
+func (r *Repository) attachTimeSpent(ctx context.Context, tasks []task.Task, indexByID map[uint64]int, ids []any) error {
+	query := fmt.Sprintf(
+		"SELECT task_id, SUM(duration_seconds) FROM time_entries WHERE task_id IN (%s) AND ended_at IS NOT NULL GROUP BY task_id",
+		placeholders(len(ids)),
+	)
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, ids...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			taskID uint64
+			total  int64
+		)
+		if err := rows.Scan(&taskID, &total); err != nil {
+			return err
+		}
+		if i, ok := indexByID[taskID]; ok {
+			tasks[i].TimeSpentSeconds = total
+		}
+	}
+
+	return rows.Err()
+}
+
+func scanTimeEntry(scanner sqlScanner) (task.TimeEntry, error) {
+	var (
+		entry    task.TimeEntry
+		endedAt  sql.NullTime
+		duration sql.NullInt64
+	)
+
+	err := scanner.Scan(
+		&entry.ID,
+		&entry.TaskID,
+		&entry.UserID,
+		&entry.StartedAt,
+		&endedAt,
+		&duration,
+		&entry.Note,
+		&entry.CreatedAt,
+	)
+	if err != nil {
+		return task.TimeEntry{}, err
+	}
+
+	entry.StartedAt = entry.StartedAt.UTC()
+	entry.EndedAt = nullableTime(endedAt)
+	entry.CreatedAt = entry.CreatedAt.UTC()
+	if duration.Valid {
+		entry.DurationSeconds = duration.Int64
+	}
+
+	return entry, nil
+}
@@ -0,0 +1,89 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/task"
+)
+
+var _ task.WatcherRepository = (*Repository)(nil)
+
+func (r *Repository) ListWatchers(ctx context.Context, taskID uint64) ([]task.Watcher, error) {
+	if err := r.ensureTaskExists(ctx, taskID); err != nil {
+		return nil, err
+	}
+
+	const query = `
+		SELECT task_id, user_id, created_at
+		FROM task_watchers
+		WHERE task_id = ?
+		ORDER BY created_at, user_id
+	`
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	watchers := make([]task.Watcher, 0)
+	for rows.Next() {
+		var (
+			watcher   task.Watcher
+			createdAt time.Time
+		)
+		if err := rows.Scan(&watcher.TaskID, &watcher.UserID, &createdAt); err != nil {
+			return nil, err
+		}
+		watcher.CreatedAt = createdAt.UTC()
+		watchers = append(watchers, watcher)
+	}
+
+	return watchers, rows.Err()
+}
+
+func (r *Repository) AddWatcher(ctx context.Context, taskID uint64, userID string) error {
+	return r.withTx(ctx, func(tx *sql.Tx) error {
+		if err := lockTask(ctx, tx, taskID); err != nil {
+			return err
+		}
+		return addWatcher(ctx, tx, taskID, userID)
+	})
+}
+
+func (r *Repository) RemoveWatcher(ctx context.Context, taskID uint64, userID string) error {
+	if err := r.ensureTaskExists(ctx, taskID); err != nil {
+		return err
+	}
+
+	_, err := r.conn(ctx).ExecContext(ctx, `DELETE FROM task_watchers WHERE task_id = ? AND user_id = ?`, taskID, userID)
+	return err
+}
+
+func (r *Repository) ensureTaskExists(ctx context.Context, taskID uint64) error {
+	var exists bool
+	err := r.conn(ctx).QueryRowContext(ctx, `SELECT TRUE FROM tasks WHERE id = ? AND deleted_at IS NULL`, taskID).Scan(&exists)
+	if errors.Is(err, sql.ErrNoRows) {
+		return task.ErrTaskNotFound
+	}
+	return err
+}
+
+func addWatcher(ctx context.Context, tx *sql.Tx, taskID uint64, userID string) error {
+	_, err := tx.ExecContext(ctx, `INSERT IGNORE INTO task_watchers (task_id, user_id) VALUES (?, ?)`, taskID, userID)
+	return err
+}
+
+func copyWatchers(ctx context.Context, tx *sql.Tx, sourceID, targetID uint64) error {
+	const query = `
+		INSERT IGNORE INTO task_watchers (task_id, user_id)
+		SELECT ?, user_id
+		FROM task_watchers
+		WHERE task_id = ?
+	`
+	_, err := tx.ExecContext(ctx, query, targetID, sourceID)
+	return err
+}
@@ -0,0 +1,202 @@
+package task
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+const (
+	maxSearchQueryLength = 255
+	// minSearchTermLength matches the default innodb_ft_min_token_size;
+	// shorter words are not indexed and therefore not highlighted.
+	minSearchTermLength = 3
+	snippetLeadingRunes = 60
+	snippetRunes        = 200
+)
+
+// SearchMode selects how the q filter is interpreted: as natural language or
+// with the MySQL boolean operators (+word -word word* "phrase" and groups).
+type SearchMode string
+
+const (
+	SearchNatural SearchMode = "natural"
+	SearchBoolean SearchMode = "boolean"
+)
+
+// SearchHighlight is returned for tasks found by q: the title and a snippet of
+// the description around the first match, HTML escaped and with the matched
+// words wrapped in <mark>. Description is empty when only the title matched.
+type SearchHighlight struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+}
+
+func parseSearchMode(raw string) (SearchMode, error) {
+	mode := SearchMode(strings.ToLower(strings.TrimSpace(raw)))
+	switch mode {
+	case "":
+		return SearchNatural, nil
+	case SearchNatural, SearchBoolean:
+		return mode, nil
+	default:
+		return "", ValidationError{Field: "search_mode", Message: "must be one of: natural, boolean"}
+	}
+}
+
+func validateSearchQuery(query string, mode SearchMode) error {
+	if len(query) > maxSearchQueryLength {
+		return ValidationError{Field: "q", Message: "must be at most 255 characters"}
+	}
+	if mode != SearchBoolean {
+		return nil
+	}
+
+	depth := 0
+	quoted := false
+	for _, c := range query {
+		switch {
+		case c == '"':
+			quoted = !quoted
+		case quoted:
+		case c == '@' || c == '<' || c == '>':
+			// InnoDB rejects these operators in most positions with a syntax
+			// error, e.g. in q=alice@example.com.
+			return ValidationError{Field: "q", Message: "must not contain @, < or > outside a phrase in boolean mode"}
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth < 0 {
+				return ValidationError{Field: "q", Message: "has an unmatched )"}
+			}
+		}
+	}
+	if quoted {
+		return ValidationError{Field: "q", Message: "has an unterminated phrase"}
+	}
+	if depth != 0 {
+		return ValidationError{Field: "q", Message: "has an unmatched ("}
+	}
+	return nil
+}
+
+type searchTerm struct {
+	word   string
+	prefix bool
+}
+
+// searchTerms extracts the words worth highlighting; in boolean mode excluded
+// words are skipped and a trailing * makes a prefix.
+func searchTerms(query string, mode SearchMode) []searchTerm {
+	terms := make([]searchTerm, 0)
+	excluded := false
+	runes := []rune(strings.ToLower(query))
+	for i := 0; i < len(runes); {
+		if !isSearchWordRune(runes[i]) {
+			excluded = mode == SearchBoolean && runes[i] == '-'
+			i++
+			continue
+		}
+
+		start := i
+		for i < len(runes) && isSearchWordRune(runes[i]) {
+			i++
+		}
+		term := searchTerm{word: string(runes[start:i])}
+		if mode == SearchBoolean && i < len(runes) && runes[i] == '*' {
+			term.prefix = true
+		}
+		if !excluded && len(runes[start:i]) >= minSearchTermLength {
+			terms = append(terms, term)
+		}
+		excluded = false
+	}
+	return terms
+}
+
+func highlightTasks(tasks []Task, query string, mode SearchMode) {
+	terms := searchTerms(query, mode)
+	if len(terms) == 0 {
+		return
+	}
+
+	for i := range tasks {
+		title, _ := highlight([]rune(tasks[i].Title), terms, false)
+		description, matched := highlight([]rune(tasks[i].Description), terms, true)
+		if !matched {
+			description = ""
+		}
+		tasks[i].Highlight = &SearchHighlight{Title: title, Description: description}
+	}
+}
+
+// highlight marks the words of text that match one of the terms. As a
+// snippet, the text is cut down to a window around the first match.
+func highlight(text []rune, terms []searchTerm, snippet bool) (string, bool) {
+	type span struct{ start, end int }
+
+	matches := make([]span, 0)
+	for i := 0; i < len(text); {
+		if !isSearchWordRune(text[i]) {
+			i++
+			continue
+		}
+		start := i
+		for i < len(text) && isSearchWordRune(text[i]) {
+			i++
+		}
+		if matchesSearchTerm(strings.ToLower(string(text[start:i])), terms) {
+			matches = append(matches, span{start: start, end: i})
+		}
+	}
+	if len(matches) == 0 && snippet {
+		return "", false
+	}
+
+	from, to := 0, len(text)
+	if snippet {
+		from = max(0, matches[0].start-snippetLeadingRunes)
+		for from > 0 && isSearchWordRune(text[from-1]) {
+			from++
+		}
+		to = min(len(text), from+snippetRunes)
+		for to < len(text) && to > matches[0].end && isSearchWordRune(text[to]) {
+			to--
+		}
+	}
+
+	var builder strings.Builder
+	if from > 0 {
+		builder.WriteString("…")
+	}
+	position := from
+	for _, match := range matches {
+		if match.start < from || match.end > to {
+			continue
+		}
+		builder.WriteString(html.EscapeString(string(text[position:match.start])))
+		builder.WriteString("<mark>")
+		builder.WriteString(html.EscapeString(string(text[match.start:match.end])))
+		builder.WriteString("</mark>")
+		position = match.end
+	}
+	builder.WriteString(html.EscapeString(string(text[position:to])))
+	if to < len(text) {
+		builder.WriteString("…")
+	}
+	return builder.String(), len(matches) > 0
+}
+
+func matchesSearchTerm(word string, terms []searchTerm) bool {
+	for _, term := range terms {
+		if word == term.word || (term.prefix && strings.HasPrefix(word, term.word)) {
+			return true
+		}
+	}
+	return false
+}
+
+func isSearchWordRune(c rune) bool {
+	return c == '_' || unicode.IsLetter(c) || unicode.IsDigit(c)
+}
@@ -0,0 +1,135 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildListFilter_Search(t *testing.T) {
+	filter, err := buildListFilter(ListTasksInput{Query: " deploy ", SearchMode: "BOOLEAN"}, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if filter.Query != "deploy" || filter.SearchMode != SearchBoolean {
+		t.Fatalf("unexpected search: %q in %s mode", filter.Query, filter.SearchMode)
+	}
+	if !reflect.DeepEqual(filter.Sort, []Sort{{Field: SortByRelevance, Desc: true}}) {
+		t.Fatalf("expected relevance order, got %+v", filter.Sort)
+	}
+
+	filter, err = buildListFilter(ListTasksInput{Query: "deploy", Sort: "priority,relevance"}, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if filter.SearchMode != SearchNatural || !reflect.DeepEqual(filter.Sort, []Sort{{Field: SortByPriority}, {Field: SortByRelevance, Desc: true}}) {
+		t.Fatalf("unexpected filter: %+v", filter)
+	}
+}
+
+func TestBuildListFilter_SearchErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input ListTasksInput
+		field string
+	}{
+		{name: "unknown mode", input: ListTasksInput{Query: "deploy", SearchMode: "fuzzy"}, field: "search_mode"},
+		{name: "too long", input: ListTasksInput{Query: strings.Repeat("a", 256)}, field: "q"},
+		{name: "unterminated phrase", input: ListTasksInput{Query: `"blue green`, SearchMode: "boolean"}, field: "q"},
+		{name: "unmatched group", input: ListTasksInput{Query: "+deploy (blue", SearchMode: "boolean"}, field: "q"},
+		{name: "at sign", input: ListTasksInput{Query: "alice@example.com", SearchMode: "boolean"}, field: "q"},
+		{name: "relevance operator", input: ListTasksInput{Query: "deploy >blue", SearchMode: "boolean"}, field: "q"},
+		{name: "relevance without q", input: ListTasksInput{Sort: "relevance"}, field: "sort"},
+		{name: "cursor with relevance", input: ListTasksInput{Query: "deploy", Cursor: EncodeCursor(Task{ID: 1})}, field: "cursor"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := buildListFilter(tc.input, time.Now())
+			var validationErr ValidationError
+			if !errors.As(err, &validationErr) || validationErr.Field != tc.field {
+				t.Fatalf("expected %s validation error, got %v", tc.field, err)
+			}
+		})
+	}
+}
+
+func TestSearchTerms(t *testing.T) {
+	terms := searchTerms(`+deploy -staging "blue green" roll* to`, SearchBoolean)
+	expected := []searchTerm{
+		{word: "deploy"},
+		{word: "blue"},
+		{word: "green"},
+		{word: "roll", prefix: true},
+	}
+	if !reflect.DeepEqual(terms, expected) {
+		t.Fatalf("unexpected terms: %+v", terms)
+	}
+
+	terms = searchTerms("deploy -staging roll*", SearchNatural)
+	expected = []searchTerm{{word: "deploy"}, {word: "staging"}, {word: "roll"}}
+	if !reflect.DeepEqual(terms, expected) {
+		t.Fatalf("unexpected natural terms: %+v", terms)
+	}
+}
+
+func TestHighlightTasks(t *testing.T) {
+	tasks := []Task{
+		{Title: "Deploy <api> & rollback", Description: strings.Repeat("filler ", 20) + "then deploy the rollout." + strings.Repeat(" tail", 60)},
+		{Title: "Deploy docs", Description: "Nothing relevant"},
+	}
+
+	highlightTasks(tasks, "deploy roll*", SearchBoolean)
+
+	if tasks[0].Highlight.Title != "<mark>Deploy</mark> &lt;api&gt; &amp; <mark>rollback</mark>" {
+		t.Fatalf("unexpected title: %s", tasks[0].Highlight.Title)
+	}
+	description := tasks[0].Highlight.Description
+	if !strings.HasPrefix(description, "…filler") || !strings.HasSuffix(description, "tail…") {
+		t.Fatalf("expected a snippet cut at word boundaries, got %q", description)
+	}
+	if !strings.Contains(description, "then <mark>deploy</mark> the <mark>rollout</mark>.") {
+		t.Fatalf("expected highlighted matches, got %q", description)
+	}
+	if tasks[1].Highlight.Title != "<mark>Deploy</mark> docs" || tasks[1].Highlight.Description != "" {
+		t.Fatalf("unexpected highlight: %+v", tasks[1].Highlight)
+	}
+}
+
+func TestServiceList_Highlights(t *testing.T) {
+	repo := &mockRepository{listResult: []Task{{ID: 1, Title: "Deploy api"}}}
+	svc := NewService(repo)
+
+	tasks, err := svc.List(context.Background(), ListTasksInput{Query: "deploy"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tasks[0].Highlight == nil || tasks[0].Highlight.Title != "<mark>Deploy</mark> api" {
+		t.Fatalf("unexpected highlight: %+v", tasks[0].Highlight)
+	}
+
+	repo.listResult = []Task{{ID: 1, Title: "Deploy api"}}
+	tasks, err = svc.List(context.Background(), ListTasksInput{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tasks[0].Highlight != nil {
+		t.Fatalf("expected no highlight without q, got %+v", tasks[0].Highlight)
+	}
+}
+
+func TestServiceListPage_OffsetOrder(t *testing.T) {
+	repo := &mockRepository{listResult: []Task{{ID: 3}, {ID: 2}, {ID: 1}}}
+	svc := NewService(repo)
+
+	page, err := svc.ListPage(context.Background(), ListTasksInput{Query: "deploy", Limit: 2, Offset: 4})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.Items) != 2 || page.NextCursor != "" || page.NextOffset != 6 {
+		t.Fatalf("unexpected page: %+v", page)
+	}
+}
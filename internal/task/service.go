@@ -2,49 +2,151 @@ package task
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"strings"
 	"time"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/notification"
 )
 
 const (
-	defaultPriority uint8 = 3
-	minPriority     uint8 = 1
-	maxPriority     uint8 = 5
-	defaultLimit          = 20
-	maxLimit              = 100
-	maxTitleLength        = 255
+	defaultPriority    uint8 = 3
+	minPriority        uint8 = 1
+	maxPriority        uint8 = 5
+	defaultLimit             = 20
+	maxLimit                 = 100
+	maxTitleLength           = 255
+	maxLabels                = 20
+	maxLabelLength           = 64
+	maxEstimateMinutes       = 525600
+	maxStoryPoints           = 100
 )
 
 type Service interface {
 	Create(ctx context.Context, input CreateTaskInput) (Task, error)
+	// ValidateCreate checks a new task like Create, including the checks
+	// against stored data, without creating it.
+	ValidateCreate(ctx context.Context, input CreateTaskInput) error
 	GetByID(ctx context.Context, id uint64) (Task, error)
 	List(ctx context.Context, input ListTasksInput) ([]Task, error)
+	ListPage(ctx context.Context, input ListTasksInput) (TaskPage, error)
+	Summarize(ctx context.Context, input ListTasksInput) (ListSummary, error)
 	Update(ctx context.Context, id uint64, input UpdateTaskInput) (Task, error)
-	Delete(ctx context.Context, id uint64) error
+	Delete(ctx context.Context, id uint64, input DeleteTaskInput) error
+	Restore(ctx context.Context, id uint64) (Task, error)
+	Archive(ctx context.Context, id uint64) (Task, error)
+	Unarchive(ctx context.Context, id uint64) (Task, error)
+	Move(ctx context.Context, id uint64, input MoveTaskInput) (Task, error)
 }
 
 type service struct {
-	repo Repository
+	repo     Repository
+	notifier notification.Notifier
+	now      func() time.Time
+}
+
+type ServiceOption func(*service)
+
+// WithNotifier sends a notification about every task update to the watchers
+// of the task.
+func WithNotifier(notifier notification.Notifier) ServiceOption {
+	return func(s *service) {
+		s.notifier = notifier
+	}
 }
 
-func NewService(repo Repository) Service {
-	return &service{repo: repo}
+func NewService(repo Repository, opts ...ServiceOption) Service {
+	s := &service{repo: repo, now: time.Now}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func (s *service) Create(ctx context.Context, input CreateTaskInput) (Task, error) {
+	params, mentioned, err := s.prepareCreate(ctx, input)
+	if err != nil {
+		return Task{}, err
+	}
+
+	createdTask, err := s.repo.Create(ctx, params)
+	if err != nil {
+		return Task{}, err
+	}
+
+	reporterID := ""
+	if params.ReporterID != nil {
+		reporterID = *params.ReporterID
+	}
+	notifyMentioned(ctx, s.notifier, mentioned, reporterID, notification.Notification{
+		TaskID:  createdTask.ID,
+		Subject: fmt.Sprintf("You were mentioned in task %q", createdTask.Title),
+		Message: createdTask.Description,
+	})
+
+	return createdTask, nil
+}
+
+func (s *service) ValidateCreate(ctx context.Context, input CreateTaskInput) error {
+	_, _, err := s.prepareCreate(ctx, input)
+	return err
+}
+
+// prepareCreate validates a new task and resolves the stored data it refers
+// to, returning the parameters to create it with and the mentioned users.
+func (s *service) prepareCreate(ctx context.Context, input CreateTaskInput) (CreateParams, []User, error) {
+	params, err := newCreateParams(input)
+	if err != nil {
+		return CreateParams{}, nil, err
+	}
+
+	params.ProjectID, params.CustomFields, err = s.resolveCustomFields(ctx, input.ProjectID, input.CustomFields)
+	if err != nil {
+		return CreateParams{}, nil, err
+	}
+
+	if input.SprintID != nil {
+		sprint, err := lookupSprint(ctx, s.repo, *input.SprintID, params.ProjectID)
+		if err != nil {
+			return CreateParams{}, nil, err
+		}
+		params.SprintID = &sprint.ID
+	}
+
+	if input.MilestoneID != nil {
+		milestone, err := lookupMilestone(ctx, s.repo, *input.MilestoneID)
+		if err != nil {
+			return CreateParams{}, nil, err
+		}
+		params.MilestoneID = &milestone.ID
+	}
+
+	mentioned, err := resolveMentions(ctx, s.repo, params.Description)
+	if err != nil {
+		return CreateParams{}, nil, err
+	}
+	params.Mentions = mentionedUserIDs(mentioned)
+
+	return params, mentioned, nil
+}
+
+// newCreateParams applies the validation and defaults of a new task that do
+// not depend on stored data.
+func newCreateParams(input CreateTaskInput) (CreateParams, error) {
 	title := strings.TrimSpace(input.Title)
 	if title == "" {
-		return Task{}, ValidationError{Field: "title", Message: "must not be empty"}
+		return CreateParams{}, ValidationError{Field: "title", Message: "must not be empty"}
 	}
 	if len(title) > maxTitleLength {
-		return Task{}, ValidationError{Field: "title", Message: "must be at most 255 characters"}
+		return CreateParams{}, ValidationError{Field: "title", Message: "must be at most 255 characters"}
 	}
 
 	status := StatusNew
 	if input.Status != "" {
 		parsedStatus, err := parseStatus(input.Status)
 		if err != nil {
-			return Task{}, err
+			return CreateParams{}, err
 		}
 		status = parsedStatus
 	}
@@ -53,23 +155,79 @@ func (s *service) Create(ctx context.Context, input CreateTaskInput) (Task, erro
 	if input.Priority != 0 {
 		validatedPriority, err := validatePriority(input.Priority)
 		if err != nil {
-			return Task{}, err
+			return CreateParams{}, err
 		}
 		priority = validatedPriority
 	}
 
+	var estimateMinutes *uint32
+	if input.EstimateMinutes != nil {
+		validated, err := validateEstimateMinutes(*input.EstimateMinutes)
+		if err != nil {
+			return CreateParams{}, err
+		}
+		estimateMinutes = &validated
+	}
+
+	var storyPoints *uint16
+	if input.StoryPoints != nil {
+		validated, err := validateStoryPoints(*input.StoryPoints)
+		if err != nil {
+			return CreateParams{}, err
+		}
+		storyPoints = &validated
+	}
+
+	labels, err := normalizeLabels(input.Labels)
+	if err != nil {
+		return CreateParams{}, err
+	}
+
 	dueAt, err := normalizeDueAt(input.DueAt)
 	if err != nil {
-		return Task{}, err
+		return CreateParams{}, err
 	}
 
-	return s.repo.Create(ctx, CreateParams{
-		Title:       title,
-		Description: strings.TrimSpace(input.Description),
-		Status:      status,
-		Priority:    priority,
-		DueAt:       dueAt,
-	})
+	recurrenceRule := ""
+	if strings.TrimSpace(input.RecurrenceRule) != "" {
+		rule, err := ParseRecurrenceRule(input.RecurrenceRule)
+		if err != nil {
+			return CreateParams{}, err
+		}
+		recurrenceRule = rule.String()
+	}
+
+	var reporterID *string
+	if strings.TrimSpace(input.ReporterID) != "" {
+		normalized, err := normalizeUserID(input.ReporterID)
+		if err != nil {
+			return CreateParams{}, withField(err, "reporter_id")
+		}
+		reporterID = &normalized
+	}
+
+	var assigneeID *string
+	if input.AssigneeID != nil {
+		normalized, err := normalizeUserID(*input.AssigneeID)
+		if err != nil {
+			return CreateParams{}, withField(err, "assignee_id")
+		}
+		assigneeID = &normalized
+	}
+
+	return CreateParams{
+		ReporterID:      reporterID,
+		AssigneeID:      assigneeID,
+		Title:           title,
+		Description:     strings.TrimSpace(input.Description),
+		Status:          status,
+		Priority:        priority,
+		EstimateMinutes: estimateMinutes,
+		StoryPoints:     storyPoints,
+		Labels:          labels,
+		DueAt:           dueAt,
+		RecurrenceRule:  recurrenceRule,
+	}, nil
 }
 
 func (s *service) GetByID(ctx context.Context, id uint64) (Task, error) {
@@ -80,31 +238,59 @@ func (s *service) GetByID(ctx context.Context, id uint64) (Task, error) {
 }
 
 func (s *service) List(ctx context.Context, input ListTasksInput) ([]Task, error) {
-	filter := ListFilter{
-		Query:  strings.TrimSpace(input.Query),
-		Limit:  input.Limit,
-		Offset: input.Offset,
+	filter, err := buildListFilter(input, s.now())
+	if err != nil {
+		return nil, err
 	}
 
-	if input.Status != "" {
-		parsedStatus, err := parseStatus(input.Status)
-		if err != nil {
-			return nil, err
-		}
-		filter.Status = &parsedStatus
+	tasks, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if filter.Query != "" {
+		highlightTasks(tasks, filter.Query, filter.SearchMode)
+	}
+	return tasks, nil
+}
+
+// ListPage lists tasks like List and returns the cursor of the next page if
+// there is one, or its offset if the order does not support cursors.
+func (s *service) ListPage(ctx context.Context, input ListTasksInput) (TaskPage, error) {
+	filter, err := buildListFilter(input, s.now())
+	if err != nil {
+		return TaskPage{}, err
 	}
 
-	if filter.Limit <= 0 {
-		filter.Limit = defaultLimit
+	limit := filter.Limit
+	filter.Limit++
+	tasks, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return TaskPage{}, err
+	}
+
+	if filter.Query != "" {
+		highlightTasks(tasks, filter.Query, filter.SearchMode)
 	}
-	if filter.Limit > maxLimit {
-		filter.Limit = maxLimit
+
+	page := TaskPage{Items: tasks}
+	if len(tasks) > limit {
+		page.Items = tasks[:limit]
+		if supportsCursor(filter.Sort) {
+			page.NextCursor = EncodeCursor(page.Items[limit-1])
+		} else {
+			page.NextOffset = filter.Offset + limit
+		}
 	}
-	if filter.Offset < 0 {
-		return nil, ValidationError{Field: "offset", Message: "must be greater or equal to 0"}
+	return page, nil
+}
+
+func (s *service) Summarize(ctx context.Context, input ListTasksInput) (ListSummary, error) {
+	filter, err := buildListFilter(input, s.now())
+	if err != nil {
+		return ListSummary{}, err
 	}
 
-	return s.repo.List(ctx, filter)
+	return s.repo.Summarize(ctx, filter)
 }
 
 func (s *service) Update(ctx context.Context, id uint64, input UpdateTaskInput) (Task, error) {
@@ -115,6 +301,42 @@ func (s *service) Update(ctx context.Context, id uint64, input UpdateTaskInput)
 	if input.ClearDueAt && input.DueAt != nil {
 		return Task{}, ValidationError{Field: "due_at", Message: "cannot be provided when clear_due_at is true"}
 	}
+	if input.ClearEstimateMinutes && input.EstimateMinutes != nil {
+		return Task{}, ValidationError{Field: "estimate_minutes", Message: "cannot be provided when clear_estimate_minutes is true"}
+	}
+	if input.ClearStoryPoints && input.StoryPoints != nil {
+		return Task{}, ValidationError{Field: "story_points", Message: "cannot be provided when clear_story_points is true"}
+	}
+	if input.ClearRecurrenceRule && input.RecurrenceRule != nil {
+		return Task{}, ValidationError{Field: "recurrence_rule", Message: "cannot be provided when clear_recurrence_rule is true"}
+	}
+	if input.ClearProjectID && input.ProjectID != nil {
+		return Task{}, ValidationError{Field: "project_id", Message: "cannot be provided when clear_project_id is true"}
+	}
+	if input.ClearProjectID && len(input.CustomFields) > 0 {
+		return Task{}, ValidationError{Field: "custom_fields", Message: "cannot be provided when clear_project_id is true"}
+	}
+	if input.ClearSprintID && input.SprintID != nil {
+		return Task{}, ValidationError{Field: "sprint_id", Message: "cannot be provided when clear_sprint_id is true"}
+	}
+	if input.ClearProjectID && input.SprintID != nil {
+		return Task{}, ValidationError{Field: "sprint_id", Message: "cannot be provided when clear_project_id is true"}
+	}
+	if input.ClearMilestoneID && input.MilestoneID != nil {
+		return Task{}, ValidationError{Field: "milestone_id", Message: "cannot be provided when clear_milestone_id is true"}
+	}
+	if input.ClearAssigneeID && input.AssigneeID != nil {
+		return Task{}, ValidationError{Field: "assignee_id", Message: "cannot be provided when clear_assignee_id is true"}
+	}
+
+	actorID := ""
+	if strings.TrimSpace(input.ActorID) != "" {
+		normalized, err := normalizeUserID(input.ActorID)
+		if err != nil {
+			return Task{}, err
+		}
+		actorID = normalized
+	}
 
 	params := UpdateParams{}
 	fieldsToUpdate := 0
@@ -131,10 +353,21 @@ func (s *service) Update(ctx context.Context, id uint64, input UpdateTaskInput)
 		fieldsToUpdate++
 	}
 
+	var mentioned []User
 	if input.Description != nil {
 		description := strings.TrimSpace(*input.Description)
 		params.Description = &description
 		fieldsToUpdate++
+
+		var err error
+		mentioned, err = resolveMentions(ctx, s.repo, description)
+		if err != nil {
+			return Task{}, err
+		}
+		params.Mentions = mentionedUserIDs(mentioned)
+		if actorID != "" {
+			params.ActorID = &actorID
+		}
 	}
 
 	if input.Status != nil {
@@ -155,6 +388,43 @@ func (s *service) Update(ctx context.Context, id uint64, input UpdateTaskInput)
 		fieldsToUpdate++
 	}
 
+	if input.EstimateMinutes != nil {
+		estimateMinutes, err := validateEstimateMinutes(*input.EstimateMinutes)
+		if err != nil {
+			return Task{}, err
+		}
+		params.EstimateMinutes = &estimateMinutes
+		fieldsToUpdate++
+	}
+
+	if input.ClearEstimateMinutes {
+		params.ClearEstimateMinutes = true
+		fieldsToUpdate++
+	}
+
+	if input.StoryPoints != nil {
+		storyPoints, err := validateStoryPoints(*input.StoryPoints)
+		if err != nil {
+			return Task{}, err
+		}
+		params.StoryPoints = &storyPoints
+		fieldsToUpdate++
+	}
+
+	if input.ClearStoryPoints {
+		params.ClearStoryPoints = true
+		fieldsToUpdate++
+	}
+
+	if input.Labels != nil {
+		labels, err := normalizeLabels(*input.Labels)
+		if err != nil {
+			return Task{}, err
+		}
+		params.Labels = &labels
+		fieldsToUpdate++
+	}
+
 	if input.DueAt != nil {
 		dueAt, err := normalizeDueAt(input.DueAt)
 		if err != nil {
@@ -169,18 +439,365 @@ func (s *service) Update(ctx context.Context, id uint64, input UpdateTaskInput)
 		fieldsToUpdate++
 	}
 
+	if input.RecurrenceRule != nil {
+		rule, err := ParseRecurrenceRule(*input.RecurrenceRule)
+		if err != nil {
+			return Task{}, err
+		}
+		normalized := rule.String()
+		params.RecurrenceRule = &normalized
+		fieldsToUpdate++
+	}
+
+	if input.ClearRecurrenceRule {
+		params.ClearRecurrenceRule = true
+		fieldsToUpdate++
+	}
+
+	if input.AssigneeID != nil {
+		assigneeID, err := normalizeUserID(*input.AssigneeID)
+		if err != nil {
+			return Task{}, withField(err, "assignee_id")
+		}
+		params.AssigneeID = &assigneeID
+		fieldsToUpdate++
+	}
+
+	if input.ClearAssigneeID {
+		params.ClearAssigneeID = true
+		fieldsToUpdate++
+	}
+
+	// The task is read once for the checks and notifications below. It may
+	// change before the update is written; the repository's version check is
+	// what guards against that, not this read.
+	var before Task
+	updatesProject := !input.ClearProjectID && (input.ProjectID != nil || input.CustomFields != nil)
+	if s.notifier != nil || params.Status != nil || updatesProject || input.SprintID != nil {
+		found, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return Task{}, err
+		}
+		before = found
+	}
+
+	if input.ClearProjectID {
+		params.ClearProjectID = true
+		fieldsToUpdate++
+	} else if updatesProject {
+		if err := s.prepareCustomFieldUpdate(ctx, before, input, &params); err != nil {
+			return Task{}, err
+		}
+		fieldsToUpdate++
+	}
+
+	if input.SprintID != nil {
+		if err := s.prepareSprintUpdate(ctx, before, input, &params); err != nil {
+			return Task{}, err
+		}
+		fieldsToUpdate++
+	}
+
+	if input.ClearSprintID {
+		params.ClearSprintID = true
+		fieldsToUpdate++
+	}
+
+	if input.MilestoneID != nil {
+		milestone, err := lookupMilestone(ctx, s.repo, *input.MilestoneID)
+		if err != nil {
+			return Task{}, err
+		}
+		params.MilestoneID = &milestone.ID
+		fieldsToUpdate++
+	}
+
+	if input.ClearMilestoneID {
+		params.ClearMilestoneID = true
+		fieldsToUpdate++
+	}
+
 	if fieldsToUpdate == 0 {
 		return Task{}, ValidationError{Field: "body", Message: "at least one field must be provided for update"}
 	}
+	params.ExpectedVersion = input.ExpectedVersion
 
-	return s.repo.Update(ctx, id, params)
+	if params.Status != nil && *params.Status != before.Status && !input.Force {
+		check, err := s.wipLimitCheck(ctx, before, input, *params.Status)
+		if err != nil {
+			return Task{}, err
+		}
+		params.WIPLimit = check
+	}
+
+	updatedTask, err := s.repo.Update(ctx, id, params)
+	if err != nil {
+		return Task{}, err
+	}
+
+	if s.notifier != nil {
+		if changes := describeChanges(before, updatedTask); len(changes) > 0 {
+			notifyWatchers(ctx, s.repo, s.notifier, actorID, notification.Notification{
+				Type:    notification.TypeTaskUpdated,
+				TaskID:  id,
+				Subject: fmt.Sprintf("Task %q was updated", updatedTask.Title),
+				Message: strings.Join(changes, "; "),
+			})
+		}
+
+		notifyMentioned(ctx, s.notifier, newlyMentioned(mentioned, before.Description), actorID, notification.Notification{
+			TaskID:  id,
+			Subject: fmt.Sprintf("You were mentioned in task %q", updatedTask.Title),
+			Message: updatedTask.Description,
+		})
+	}
+
+	if params.Status != nil && *params.Status == StatusDone {
+		return s.scheduleNextOccurrence(ctx, updatedTask)
+	}
+
+	return updatedTask, nil
 }
 
-func (s *service) Delete(ctx context.Context, id uint64) error {
+func (s *service) Delete(ctx context.Context, id uint64, input DeleteTaskInput) error {
 	if id == 0 {
 		return ValidationError{Field: "id", Message: "must be greater than 0"}
 	}
-	return s.repo.Delete(ctx, id)
+	return s.repo.Delete(ctx, id, input.ExpectedVersion)
+}
+
+func (s *service) Archive(ctx context.Context, id uint64) (Task, error) {
+	foundTask, err := s.GetByID(ctx, id)
+	if err != nil {
+		return Task{}, err
+	}
+	if foundTask.ArchivedAt != nil {
+		return foundTask, nil
+	}
+	if foundTask.Status != StatusDone {
+		return Task{}, ValidationError{Field: "status", Message: "only done tasks can be archived"}
+	}
+
+	if err := s.repo.Archive(ctx, id); err != nil {
+		return Task{}, err
+	}
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *service) Unarchive(ctx context.Context, id uint64) (Task, error) {
+	foundTask, err := s.GetByID(ctx, id)
+	if err != nil {
+		return Task{}, err
+	}
+	if foundTask.ArchivedAt == nil {
+		return foundTask, nil
+	}
+
+	if err := s.repo.Unarchive(ctx, id); err != nil {
+		return Task{}, err
+	}
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *service) Restore(ctx context.Context, id uint64) (Task, error) {
+	if id == 0 {
+		return Task{}, ValidationError{Field: "id", Message: "must be greater than 0"}
+	}
+	if err := s.repo.Restore(ctx, id); err != nil {
+		return Task{}, err
+	}
+	return s.repo.GetByID(ctx, id)
+}
+
+// Move changes the rank of a task within its status column. The neighbours
+// must be in the same column; with a single neighbour the task is placed
+// next to it, with both it is placed between them.
+func (s *service) Move(ctx context.Context, id uint64, input MoveTaskInput) (Task, error) {
+	if id == 0 {
+		return Task{}, ValidationError{Field: "id", Message: "must be greater than 0"}
+	}
+	if input.BeforeID == nil && input.AfterID == nil {
+		return Task{}, ValidationError{Field: "body", Message: "before_id or after_id must be provided"}
+	}
+
+	neighbours := []struct {
+		field string
+		id    *uint64
+	}{
+		{field: "before_id", id: input.BeforeID},
+		{field: "after_id", id: input.AfterID},
+	}
+	for _, neighbour := range neighbours {
+		if neighbour.id == nil {
+			continue
+		}
+		if *neighbour.id == 0 {
+			return Task{}, ValidationError{Field: neighbour.field, Message: "must be greater than 0"}
+		}
+		if *neighbour.id == id {
+			return Task{}, ValidationError{Field: neighbour.field, Message: "must not be the moved task"}
+		}
+	}
+	if input.BeforeID != nil && input.AfterID != nil && *input.BeforeID == *input.AfterID {
+		return Task{}, ValidationError{Field: "before_id", Message: "must differ from after_id"}
+	}
+
+	if err := s.repo.Move(ctx, id, MoveParams{BeforeID: input.BeforeID, AfterID: input.AfterID}); err != nil {
+		return Task{}, err
+	}
+	return s.repo.GetByID(ctx, id)
+}
+
+// wipLimitCheck returns the WIP limit of the status column the task moves to
+// in the project it belongs to after the update, if that column has one.
+func (s *service) wipLimitCheck(ctx context.Context, before Task, input UpdateTaskInput, status Status) (*WIPLimitCheck, error) {
+	projectID := before.ProjectID
+	if input.ProjectID != nil {
+		projectID = input.ProjectID
+	}
+	if projectID == nil || input.ClearProjectID {
+		return nil, nil
+	}
+
+	project, err := lookupProject(ctx, s.repo, *projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	limit, ok := project.WIPLimits[status]
+	if !ok {
+		return nil, nil
+	}
+	return &WIPLimitCheck{ProjectID: project.ID, Limit: limit}, nil
+}
+
+// resolveCustomFields validates the custom field values of a new task against
+// the definitions of its project.
+func (s *service) resolveCustomFields(ctx context.Context, projectID *uint64, raw map[string]any) (*uint64, []CustomFieldValue, error) {
+	if projectID == nil {
+		if len(raw) > 0 {
+			return nil, nil, ValidationError{Field: "custom_fields", Message: "require project_id"}
+		}
+		return nil, nil, nil
+	}
+
+	project, err := lookupProject(ctx, s.repo, *projectID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	values, err := normalizeCustomFields(project, raw)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := checkRequiredCustomFields(project, values, true); err != nil {
+		return nil, nil, err
+	}
+
+	return &project.ID, values, nil
+}
+
+// prepareCustomFieldUpdate validates a project change and/or custom field
+// values of an existing task. Values are validated against the new project
+// when it changes, in which case its required fields must be provided as well.
+func (s *service) prepareCustomFieldUpdate(ctx context.Context, current Task, input UpdateTaskInput, params *UpdateParams) error {
+	projectChanged := false
+	var projectID uint64
+	switch {
+	case input.ProjectID != nil:
+		projectID = *input.ProjectID
+		projectChanged = current.ProjectID == nil || *current.ProjectID != projectID
+	case current.ProjectID != nil:
+		projectID = *current.ProjectID
+	default:
+		return ValidationError{Field: "custom_fields", Message: "require the task to belong to a project"}
+	}
+
+	project, err := lookupProject(ctx, s.repo, projectID)
+	if err != nil {
+		return err
+	}
+
+	values, err := normalizeCustomFields(project, input.CustomFields)
+	if err != nil {
+		return err
+	}
+	if err := checkRequiredCustomFields(project, values, projectChanged); err != nil {
+		return err
+	}
+
+	if input.ProjectID != nil {
+		params.ProjectID = &project.ID
+	}
+	if input.CustomFields != nil {
+		params.CustomFields = values
+	}
+	return nil
+}
+
+// prepareSprintUpdate checks that the sprint a task is assigned to belongs to
+// the project the task has after the update.
+func (s *service) prepareSprintUpdate(ctx context.Context, current Task, input UpdateTaskInput, params *UpdateParams) error {
+	projectID := input.ProjectID
+	if projectID == nil {
+		projectID = current.ProjectID
+	}
+
+	sprint, err := lookupSprint(ctx, s.repo, *input.SprintID, projectID)
+	if err != nil {
+		return err
+	}
+	params.SprintID = &sprint.ID
+	return nil
+}
+
+// scheduleNextOccurrence creates the follow-up task of a completed recurring
+// task. The repository guarantees that at most one follow-up is created even
+// if the task is reopened and completed again, and copies the custom field
+// values, the unchecked checklist and the watchers of the completed task.
+func (s *service) scheduleNextOccurrence(ctx context.Context, completed Task) (Task, error) {
+	if completed.RecurrenceRule == "" || completed.NextOccurrenceID != nil {
+		return completed, nil
+	}
+
+	rule, err := ParseRecurrenceRule(completed.RecurrenceRule)
+	if err != nil {
+		return Task{}, err
+	}
+
+	base := completed.UpdatedAt
+	if completed.DueAt != nil {
+		base = *completed.DueAt
+	}
+
+	nextDueAt, nextRule, ok := rule.Next(base)
+	if !ok {
+		return completed, nil
+	}
+
+	nextTask, err := s.repo.CreateNextOccurrence(ctx, completed.ID, CreateParams{
+		ProjectID:       completed.ProjectID,
+		ReporterID:      completed.ReporterID,
+		AssigneeID:      completed.AssigneeID,
+		Title:           completed.Title,
+		Description:     completed.Description,
+		Status:          StatusNew,
+		Priority:        completed.Priority,
+		EstimateMinutes: completed.EstimateMinutes,
+		StoryPoints:     completed.StoryPoints,
+		Labels:          completed.Labels,
+		DueAt:           &nextDueAt,
+		RecurrenceRule:  nextRule.String(),
+	})
+	if err != nil {
+		if errors.Is(err, ErrNextOccurrenceExists) {
+			return s.repo.GetByID(ctx, completed.ID)
+		}
+		return Task{}, err
+	}
+
+	completed.NextOccurrenceID = &nextTask.ID
+	return completed, nil
 }
 
 func parseStatus(raw string) (Status, error) {
@@ -191,6 +808,18 @@ func parseStatus(raw string) (Status, error) {
 	return status, nil
 }
 
+func parseArchivedFilter(raw string) (ArchivedFilter, error) {
+	archived := ArchivedFilter(strings.ToLower(strings.TrimSpace(raw)))
+	switch archived {
+	case "":
+		return ArchivedExclude, nil
+	case ArchivedExclude, ArchivedOnly, ArchivedAny:
+		return archived, nil
+	default:
+		return "", ValidationError{Field: "archived", Message: "must be one of: true, false, any"}
+	}
+}
+
 func validatePriority(raw int) (uint8, error) {
 	if raw < int(minPriority) || raw > int(maxPriority) {
 		return 0, ValidationError{Field: "priority", Message: "must be between 1 and 5"}
@@ -198,6 +827,43 @@ func validatePriority(raw int) (uint8, error) {
 	return uint8(raw), nil
 }
 
+func validateEstimateMinutes(raw int) (uint32, error) {
+	if raw < 0 || raw > maxEstimateMinutes {
+		return 0, ValidationError{Field: "estimate_minutes", Message: "must be between 0 and 525600"}
+	}
+	return uint32(raw), nil
+}
+
+func validateStoryPoints(raw int) (uint16, error) {
+	if raw < 0 || raw > maxStoryPoints {
+		return 0, ValidationError{Field: "story_points", Message: "must be between 0 and 100"}
+	}
+	return uint16(raw), nil
+}
+
+func normalizeLabels(raw []string) ([]string, error) {
+	labels := make([]string, 0, len(raw))
+	seen := make(map[string]bool, len(raw))
+	for _, label := range raw {
+		label = strings.ToLower(strings.TrimSpace(label))
+		if label == "" {
+			return nil, ValidationError{Field: "labels", Message: "must not contain empty values"}
+		}
+		if len(label) > maxLabelLength {
+			return nil, ValidationError{Field: "labels", Message: "each label must be at most 64 characters"}
+		}
+		if seen[label] {
+			continue
+		}
+		seen[label] = true
+		labels = append(labels, label)
+	}
+	if len(labels) > maxLabels {
+		return nil, ValidationError{Field: "labels", Message: "must contain at most 20 labels"}
+	}
+	return labels, nil
+}
+
 func normalizeDueAt(dueAt *time.Time) (*time.Time, error) {
 	if dueAt == nil {
 		return nil, nil
@@ -9,17 +9,32 @@ import (
 )
 
 type mockRepository struct {
-	createParams CreateParams
-	updateParams UpdateParams
-	listFilter   ListFilter
-	getID        uint64
-	updateID     uint64
-	deleteID     uint64
+	createParams  CreateParams
+	updateParams  UpdateParams
+	listFilter    ListFilter
+	getID         uint64
+	updateID      uint64
+	deleteID      uint64
+	deleteVersion *uint64
+
+	nextOccurrenceSourceID uint64
+	nextOccurrenceParams   CreateParams
+	nextOccurrenceCalled   bool
+	nextOccurrenceResult   Task
+	nextOccurrenceErr      error
+
+	restoreID     uint64
+	restoreCalled bool
+	restoreErr    error
+
+	archiveCalled   bool
+	unarchiveCalled bool
 
 	createCalled bool
 	updateCalled bool
 	listCalled   bool
 	getCalled    bool
+	getCalls     int
 	deleteCalled bool
 
 	createResult Task
@@ -27,6 +42,21 @@ type mockRepository struct {
 	listResult   []Task
 	getResult    Task
 
+	summaryResult ListSummary
+
+	project    Project
+	projectErr error
+
+	sprint    Sprint
+	milestone Milestone
+
+	watchers []Watcher
+	users    []User
+
+	moveID     uint64
+	moveParams MoveParams
+	moveCalled bool
+
 	createErr error
 	updateErr error
 	listErr   error
@@ -45,6 +75,7 @@ func (m *mockRepository) Create(_ context.Context, params CreateParams) (Task, e
 
 func (m *mockRepository) GetByID(_ context.Context, id uint64) (Task, error) {
 	m.getCalled = true
+	m.getCalls++
 	m.getID = id
 	if m.getErr != nil {
 		return Task{}, m.getErr
@@ -61,6 +92,51 @@ func (m *mockRepository) List(_ context.Context, filter ListFilter) ([]Task, err
 	return m.listResult, nil
 }
 
+func (m *mockRepository) Summarize(_ context.Context, filter ListFilter) (ListSummary, error) {
+	m.listFilter = filter
+	return m.summaryResult, nil
+}
+
+func (m *mockRepository) GetProject(_ context.Context, id uint64) (Project, error) {
+	if m.projectErr != nil {
+		return Project{}, m.projectErr
+	}
+	if m.project.ID != id {
+		return Project{}, ErrProjectNotFound
+	}
+	return m.project, nil
+}
+
+func (m *mockRepository) GetSprint(_ context.Context, id uint64) (Sprint, error) {
+	if m.sprint.ID != id {
+		return Sprint{}, ErrSprintNotFound
+	}
+	return m.sprint, nil
+}
+
+func (m *mockRepository) GetMilestone(_ context.Context, id uint64) (Milestone, error) {
+	if m.milestone.ID != id {
+		return Milestone{}, ErrMilestoneNotFound
+	}
+	return m.milestone, nil
+}
+
+func (m *mockRepository) ListWatchers(_ context.Context, _ uint64) ([]Watcher, error) {
+	return m.watchers, nil
+}
+
+func (m *mockRepository) FindUsersByUsername(_ context.Context, usernames []string) ([]User, error) {
+	var found []User
+	for _, user := range m.users {
+		for _, username := range usernames {
+			if strings.EqualFold(user.Username, username) {
+				found = append(found, user)
+			}
+		}
+	}
+	return found, nil
+}
+
 func (m *mockRepository) Update(_ context.Context, id uint64, params UpdateParams) (Task, error) {
 	m.updateCalled = true
 	m.updateParams = params
@@ -71,12 +147,46 @@ func (m *mockRepository) Update(_ context.Context, id uint64, params UpdateParam
 	return m.updateResult, nil
 }
 
-func (m *mockRepository) Delete(_ context.Context, id uint64) error {
+func (m *mockRepository) Delete(_ context.Context, id uint64, expectedVersion *uint64) error {
 	m.deleteCalled = true
 	m.deleteID = id
+	m.deleteVersion = expectedVersion
 	return m.deleteErr
 }
 
+func (m *mockRepository) Restore(_ context.Context, id uint64) error {
+	m.restoreCalled = true
+	m.restoreID = id
+	return m.restoreErr
+}
+
+func (m *mockRepository) Archive(_ context.Context, _ uint64) error {
+	m.archiveCalled = true
+	return nil
+}
+
+func (m *mockRepository) Unarchive(_ context.Context, _ uint64) error {
+	m.unarchiveCalled = true
+	return nil
+}
+
+func (m *mockRepository) Move(_ context.Context, id uint64, params MoveParams) error {
+	m.moveCalled = true
+	m.moveID = id
+	m.moveParams = params
+	return nil
+}
+
+func (m *mockRepository) CreateNextOccurrence(_ context.Context, sourceID uint64, params CreateParams) (Task, error) {
+	m.nextOccurrenceCalled = true
+	m.nextOccurrenceSourceID = sourceID
+	m.nextOccurrenceParams = params
+	if m.nextOccurrenceErr != nil {
+		return Task{}, m.nextOccurrenceErr
+	}
+	return m.nextOccurrenceResult, nil
+}
+
 func TestServiceCreate_DefaultsAndTrims(t *testing.T) {
 	repo := &mockRepository{
 		createResult: Task{ID: 10, Title: "Do work"},
@@ -158,6 +268,16 @@ func TestServiceCreate_ValidationErrors(t *testing.T) {
 			input: CreateTaskInput{Title: "ok", Status: " donee "},
 			field: "status",
 		},
+		{
+			name:  "empty label",
+			input: CreateTaskInput{Title: "ok", Labels: []string{"bug", " "}},
+			field: "labels",
+		},
+		{
+			name:  "invalid recurrence rule",
+			input: CreateTaskInput{Title: "ok", RecurrenceRule: "FREQ=YEARLY"},
+			field: "recurrence_rule",
+		},
 		{
 			name:  "valid due at should not fail",
 			input: CreateTaskInput{Title: "ok", DueAt: &now, Status: "done", Priority: 4},
@@ -264,8 +384,8 @@ func TestServiceList_NormalizationAndValidation(t *testing.T) {
 	if !repo.listCalled {
 		t.Fatal("expected repository list to be called")
 	}
-	if repo.listFilter.Status == nil || *repo.listFilter.Status != StatusDone {
-		t.Fatalf("expected status filter %q, got %#v", StatusDone, repo.listFilter.Status)
+	if len(repo.listFilter.Statuses) != 1 || repo.listFilter.Statuses[0] != StatusDone {
+		t.Fatalf("expected status filter %q, got %#v", StatusDone, repo.listFilter.Statuses)
 	}
 	if repo.listFilter.Query != "search text" {
 		t.Fatalf("unexpected query: %q", repo.listFilter.Query)
@@ -470,7 +590,7 @@ func TestServiceDelete_ValidationAndPassThrough(t *testing.T) {
 	repo := &mockRepository{}
 	svc := NewService(repo)
 
-	err := svc.Delete(context.Background(), 0)
+	err := svc.Delete(context.Background(), 0, DeleteTaskInput{})
 	if err == nil {
 		t.Fatal("expected validation error for zero id")
 	}
@@ -478,7 +598,8 @@ func TestServiceDelete_ValidationAndPassThrough(t *testing.T) {
 		t.Fatal("repository should not be called for zero id")
 	}
 
-	err = svc.Delete(context.Background(), 12)
+	version := uint64(4)
+	err = svc.Delete(context.Background(), 12, DeleteTaskInput{ExpectedVersion: &version})
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -488,6 +609,95 @@ func TestServiceDelete_ValidationAndPassThrough(t *testing.T) {
 	if repo.deleteID != 12 {
 		t.Fatalf("expected delete id 12, got %d", repo.deleteID)
 	}
+	if repo.deleteVersion != &version {
+		t.Fatalf("expected expected version to be passed, got %v", repo.deleteVersion)
+	}
+}
+
+func TestServiceRestore(t *testing.T) {
+	repo := &mockRepository{getResult: Task{ID: 12}}
+	svc := NewService(repo)
+
+	if _, err := svc.Restore(context.Background(), 0); err == nil {
+		t.Fatal("expected validation error for zero id")
+	}
+	if repo.restoreCalled {
+		t.Fatal("repository should not be called for zero id")
+	}
+
+	got, err := svc.Restore(context.Background(), 12)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !repo.restoreCalled || repo.restoreID != 12 {
+		t.Fatalf("unexpected restore call: called=%v id=%d", repo.restoreCalled, repo.restoreID)
+	}
+	if got.ID != 12 {
+		t.Fatalf("expected restored task ID 12, got %d", got.ID)
+	}
+
+	notFoundRepo := &mockRepository{restoreErr: ErrTaskNotFound}
+	svc = NewService(notFoundRepo)
+	if _, err := svc.Restore(context.Background(), 13); !errors.Is(err, ErrTaskNotFound) {
+		t.Fatalf("expected ErrTaskNotFound, got %v", err)
+	}
+	if notFoundRepo.getCalled {
+		t.Fatal("repository get should not be called when restore fails")
+	}
+}
+
+func TestServiceList_ArchivedFilter(t *testing.T) {
+	repo := &mockRepository{}
+	svc := NewService(repo)
+
+	if _, err := svc.List(context.Background(), ListTasksInput{}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if repo.listFilter.Archived != ArchivedExclude {
+		t.Fatalf("expected archived tasks to be excluded by default, got %q", repo.listFilter.Archived)
+	}
+
+	if _, err := svc.List(context.Background(), ListTasksInput{Archived: " ANY "}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if repo.listFilter.Archived != ArchivedAny {
+		t.Fatalf("expected archived=any, got %q", repo.listFilter.Archived)
+	}
+
+	_, err := svc.List(context.Background(), ListTasksInput{Archived: "maybe"})
+	var verr ValidationError
+	if !errors.As(err, &verr) || verr.Field != "archived" {
+		t.Fatalf("expected archived ValidationError, got %v", err)
+	}
+}
+
+func TestServiceArchive(t *testing.T) {
+	openRepo := &mockRepository{getResult: Task{ID: 1, Status: StatusInProgress}}
+	_, err := NewService(openRepo).Archive(context.Background(), 1)
+	var verr ValidationError
+	if !errors.As(err, &verr) || verr.Field != "status" {
+		t.Fatalf("expected status ValidationError, got %v", err)
+	}
+	if openRepo.archiveCalled {
+		t.Fatal("repository should not archive tasks that are not done")
+	}
+
+	doneRepo := &mockRepository{getResult: Task{ID: 2, Status: StatusDone}}
+	if _, err := NewService(doneRepo).Archive(context.Background(), 2); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !doneRepo.archiveCalled {
+		t.Fatal("expected repository archive to be called")
+	}
+
+	archivedAt := time.Now()
+	unarchiveRepo := &mockRepository{getResult: Task{ID: 3, Status: StatusDone, ArchivedAt: &archivedAt}}
+	if _, err := NewService(unarchiveRepo).Unarchive(context.Background(), 3); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !unarchiveRepo.unarchiveCalled {
+		t.Fatal("expected repository unarchive to be called")
+	}
 }
 
 func TestService_RepositoryErrorsArePropagated(t *testing.T) {
@@ -553,7 +763,7 @@ func TestService_RepositoryErrorsArePropagated(t *testing.T) {
 		repo := &mockRepository{deleteErr: repoErr}
 		svc := NewService(repo)
 
-		err := svc.Delete(context.Background(), 1)
+		err := svc.Delete(context.Background(), 1, DeleteTaskInput{})
 		if !errors.Is(err, repoErr) {
 			t.Fatalf("expected error %v, got %v", repoErr, err)
 		}
@@ -562,3 +772,464 @@ func TestService_RepositoryErrorsArePropagated(t *testing.T) {
 		}
 	})
 }
+
+func TestServiceValidateCreate(t *testing.T) {
+	repo := &mockRepository{}
+	svc := NewService(repo)
+
+	if err := svc.ValidateCreate(context.Background(), CreateTaskInput{Title: "Ship"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	milestoneID := uint64(4)
+	err := svc.ValidateCreate(context.Background(), CreateTaskInput{Title: "Ship", MilestoneID: &milestoneID})
+	var validationErr ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Field != "milestone_id" {
+		t.Fatalf("expected stored data to be checked, got %v", err)
+	}
+	if repo.createCalled {
+		t.Fatal("expected nothing to be created")
+	}
+}
+
+func TestServiceCreate_NormalizesLabelsAndRecurrence(t *testing.T) {
+	repo := &mockRepository{createResult: Task{ID: 1}}
+	svc := NewService(repo)
+
+	_, err := svc.Create(context.Background(), CreateTaskInput{
+		Title:          "Weekly report",
+		Labels:         []string{" Ops ", "ops", "reports"},
+		RecurrenceRule: "rrule:freq=weekly;byday=mo,fr",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if strings.Join(repo.createParams.Labels, ",") != "ops,reports" {
+		t.Fatalf("unexpected labels: %#v", repo.createParams.Labels)
+	}
+	if repo.createParams.RecurrenceRule != "FREQ=WEEKLY;BYDAY=MO,FR" {
+		t.Fatalf("unexpected recurrence rule: %q", repo.createParams.RecurrenceRule)
+	}
+}
+
+func TestServiceUpdate_CreatesNextOccurrenceWhenDone(t *testing.T) {
+	dueAt := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	repo := &mockRepository{
+		updateResult: Task{
+			ID:             5,
+			Title:          "Rotate logs",
+			Description:    "weekly chore",
+			Status:         StatusDone,
+			Priority:       4,
+			Labels:         []string{"ops"},
+			DueAt:          &dueAt,
+			RecurrenceRule: "FREQ=WEEKLY;COUNT=3",
+		},
+		nextOccurrenceResult: Task{ID: 6},
+	}
+	svc := NewService(repo)
+
+	done := "done"
+	got, err := svc.Update(context.Background(), 5, UpdateTaskInput{Status: &done})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !repo.nextOccurrenceCalled || repo.nextOccurrenceSourceID != 5 {
+		t.Fatalf("unexpected next occurrence call: called=%v source=%d", repo.nextOccurrenceCalled, repo.nextOccurrenceSourceID)
+	}
+
+	params := repo.nextOccurrenceParams
+	if params.Title != "Rotate logs" || params.Description != "weekly chore" || params.Priority != 4 {
+		t.Fatalf("expected task fields to be preserved, got %+v", params)
+	}
+	if params.Status != StatusNew {
+		t.Fatalf("expected next occurrence status new, got %q", params.Status)
+	}
+	if len(params.Labels) != 1 || params.Labels[0] != "ops" {
+		t.Fatalf("expected labels to be preserved, got %#v", params.Labels)
+	}
+	if params.DueAt == nil || !params.DueAt.Equal(dueAt.AddDate(0, 0, 7)) {
+		t.Fatalf("unexpected next due_at: %v", params.DueAt)
+	}
+	if params.RecurrenceRule != "FREQ=WEEKLY;COUNT=2" {
+		t.Fatalf("unexpected next recurrence rule: %q", params.RecurrenceRule)
+	}
+	if got.NextOccurrenceID == nil || *got.NextOccurrenceID != 6 {
+		t.Fatalf("expected next occurrence id 6, got %v", got.NextOccurrenceID)
+	}
+}
+
+func TestServiceUpdate_SkipsNextOccurrenceWhenSeriesEnds(t *testing.T) {
+	dueAt := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	repo := &mockRepository{
+		updateResult: Task{
+			ID:             5,
+			Status:         StatusDone,
+			DueAt:          &dueAt,
+			RecurrenceRule: "FREQ=DAILY;COUNT=1",
+		},
+	}
+	svc := NewService(repo)
+
+	done := "done"
+	if _, err := svc.Update(context.Background(), 5, UpdateTaskInput{Status: &done}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if repo.nextOccurrenceCalled {
+		t.Fatal("repository should not create an occurrence after the last one")
+	}
+}
+
+func TestServiceCreate_ValidatesEstimates(t *testing.T) {
+	tests := []struct {
+		name  string
+		input CreateTaskInput
+		field string
+	}{
+		{name: "negative estimate", input: CreateTaskInput{Title: "t", EstimateMinutes: intPtr(-1)}, field: "estimate_minutes"},
+		{name: "estimate too large", input: CreateTaskInput{Title: "t", EstimateMinutes: intPtr(maxEstimateMinutes + 1)}, field: "estimate_minutes"},
+		{name: "negative story points", input: CreateTaskInput{Title: "t", StoryPoints: intPtr(-3)}, field: "story_points"},
+		{name: "story points too large", input: CreateTaskInput{Title: "t", StoryPoints: intPtr(maxStoryPoints + 1)}, field: "story_points"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockRepository{}
+			svc := NewService(repo)
+
+			_, err := svc.Create(context.Background(), tc.input)
+			var validationErr ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("expected validation error, got %v", err)
+			}
+			if validationErr.Field != tc.field {
+				t.Fatalf("expected field %q, got %q", tc.field, validationErr.Field)
+			}
+			if repo.createCalled {
+				t.Fatal("repository create must not be called")
+			}
+		})
+	}
+}
+
+func TestServiceCreate_PassesEstimates(t *testing.T) {
+	repo := &mockRepository{}
+	svc := NewService(repo)
+
+	_, err := svc.Create(context.Background(), CreateTaskInput{Title: "t", EstimateMinutes: intPtr(90), StoryPoints: intPtr(5)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.createParams.EstimateMinutes == nil || *repo.createParams.EstimateMinutes != 90 {
+		t.Fatalf("unexpected estimate: %v", repo.createParams.EstimateMinutes)
+	}
+	if repo.createParams.StoryPoints == nil || *repo.createParams.StoryPoints != 5 {
+		t.Fatalf("unexpected story points: %v", repo.createParams.StoryPoints)
+	}
+}
+
+func TestServiceList_ParsesSortAndRanges(t *testing.T) {
+	repo := &mockRepository{}
+	svc := NewService(repo)
+
+	_, err := svc.List(context.Background(), ListTasksInput{
+		Sort:           "-story_points, estimate_minutes",
+		StoryPointsMin: intPtr(3),
+		StoryPointsMax: intPtr(8),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []Sort{{Field: SortByStoryPoints, Desc: true}, {Field: SortByEstimateMinutes}}
+	if len(repo.listFilter.Sort) != len(want) {
+		t.Fatalf("unexpected sort: %+v", repo.listFilter.Sort)
+	}
+	for i := range want {
+		if repo.listFilter.Sort[i] != want[i] {
+			t.Fatalf("unexpected sort: %+v", repo.listFilter.Sort)
+		}
+	}
+	if repo.listFilter.StoryPointsMin == nil || *repo.listFilter.StoryPointsMin != 3 {
+		t.Fatalf("unexpected story points min: %v", repo.listFilter.StoryPointsMin)
+	}
+}
+
+func TestServiceList_RejectsInvalidSortAndRanges(t *testing.T) {
+	tests := []struct {
+		name  string
+		input ListTasksInput
+		field string
+	}{
+		{name: "unknown sort field", input: ListTasksInput{Sort: "title"}, field: "sort"},
+		{name: "duplicate sort field", input: ListTasksInput{Sort: "story_points,-story_points"}, field: "sort"},
+		{name: "negative range bound", input: ListTasksInput{EstimateMinutesMin: intPtr(-1)}, field: "estimate_minutes_min"},
+		{name: "inverted range", input: ListTasksInput{StoryPointsMin: intPtr(8), StoryPointsMax: intPtr(3)}, field: "story_points_min"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := NewService(&mockRepository{})
+
+			_, err := svc.List(context.Background(), tc.input)
+			var validationErr ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("expected validation error, got %v", err)
+			}
+			if validationErr.Field != tc.field {
+				t.Fatalf("expected field %q, got %q", tc.field, validationErr.Field)
+			}
+		})
+	}
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func testProject() Project {
+	return Project{
+		ID:   7,
+		Name: "Support",
+		CustomFields: []CustomFieldDefinition{
+			{ID: 1, ProjectID: 7, Name: "severity", Type: CustomFieldSingleSelect, Required: true, Options: []string{"low", "high"}},
+			{ID: 2, ProjectID: 7, Name: "customer", Type: CustomFieldText},
+			{ID: 3, ProjectID: 7, Name: "environments", Type: CustomFieldMultiSelect, Options: []string{"staging", "production"}},
+			{ID: 4, ProjectID: 7, Name: "impact", Type: CustomFieldNumber},
+			{ID: 5, ProjectID: 7, Name: "reported_on", Type: CustomFieldDate},
+			{ID: 6, ProjectID: 7, Name: "owner", Type: CustomFieldUser},
+		},
+	}
+}
+
+func uint64Ptr(v uint64) *uint64 {
+	return &v
+}
+
+func TestServiceCreate_NormalizesCustomFields(t *testing.T) {
+	repo := &mockRepository{project: testProject()}
+	svc := NewService(repo)
+
+	_, err := svc.Create(context.Background(), CreateTaskInput{
+		Title:     "Checkout fails",
+		ProjectID: uint64Ptr(7),
+		CustomFields: map[string]any{
+			"severity":     "high",
+			"customer":     "  ACME  ",
+			"environments": []any{"production", "staging", "production"},
+			"impact":       float64(2.50),
+			"reported_on":  "2026-03-04",
+			"owner":        "6F9619FF-8B86-D011-B42D-00CF4FC964FF",
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if repo.createParams.ProjectID == nil || *repo.createParams.ProjectID != 7 {
+		t.Fatalf("unexpected project id: %v", repo.createParams.ProjectID)
+	}
+	got := make(map[uint64][]string, len(repo.createParams.CustomFields))
+	for _, value := range repo.createParams.CustomFields {
+		got[value.FieldID] = value.Values
+	}
+	want := map[uint64][]string{
+		1: {"high"},
+		2: {"ACME"},
+		3: {"production", "staging"},
+		4: {"2.5"},
+		5: {"2026-03-04"},
+		6: {"6f9619ff-8b86-d011-b42d-00cf4fc964ff"},
+	}
+	for fieldID, values := range want {
+		if strings.Join(got[fieldID], ",") != strings.Join(values, ",") {
+			t.Fatalf("field %d: expected %v, got %v", fieldID, values, got[fieldID])
+		}
+	}
+}
+
+func TestServiceCreate_RejectsInvalidCustomFields(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  CreateTaskInput
+		field  string
+		called bool
+	}{
+		{
+			name:  "custom fields without project",
+			input: CreateTaskInput{Title: "t", CustomFields: map[string]any{"severity": "high"}},
+			field: "custom_fields",
+		},
+		{
+			name:  "unknown project",
+			input: CreateTaskInput{Title: "t", ProjectID: uint64Ptr(99)},
+			field: "project_id",
+		},
+		{
+			name:  "unknown field",
+			input: CreateTaskInput{Title: "t", ProjectID: uint64Ptr(7), CustomFields: map[string]any{"severity": "high", "color": "red"}},
+			field: "custom_fields.color",
+		},
+		{
+			name:  "missing required field",
+			input: CreateTaskInput{Title: "t", ProjectID: uint64Ptr(7)},
+			field: "custom_fields.severity",
+		},
+		{
+			name:  "option not allowed",
+			input: CreateTaskInput{Title: "t", ProjectID: uint64Ptr(7), CustomFields: map[string]any{"severity": "urgent"}},
+			field: "custom_fields.severity",
+		},
+		{
+			name:  "number of wrong type",
+			input: CreateTaskInput{Title: "t", ProjectID: uint64Ptr(7), CustomFields: map[string]any{"severity": "low", "impact": "a lot"}},
+			field: "custom_fields.impact",
+		},
+		{
+			name:  "invalid date",
+			input: CreateTaskInput{Title: "t", ProjectID: uint64Ptr(7), CustomFields: map[string]any{"severity": "low", "reported_on": "04.03.2026"}},
+			field: "custom_fields.reported_on",
+		},
+		{
+			name:  "invalid user",
+			input: CreateTaskInput{Title: "t", ProjectID: uint64Ptr(7), CustomFields: map[string]any{"severity": "low", "owner": "bob"}},
+			field: "custom_fields.owner",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockRepository{project: testProject()}
+			svc := NewService(repo)
+
+			_, err := svc.Create(context.Background(), tc.input)
+			var validationErr ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("expected validation error, got %v", err)
+			}
+			if validationErr.Field != tc.field {
+				t.Fatalf("expected field %q, got %q", tc.field, validationErr.Field)
+			}
+			if repo.createCalled {
+				t.Fatal("repository create must not be called")
+			}
+		})
+	}
+}
+
+func TestServiceUpdate_CustomFields(t *testing.T) {
+	repo := &mockRepository{
+		project:   testProject(),
+		getResult: Task{ID: 1, ProjectID: uint64Ptr(7)},
+	}
+	svc := NewService(repo)
+
+	_, err := svc.Update(context.Background(), 1, UpdateTaskInput{
+		CustomFields: map[string]any{"customer": nil, "severity": "low"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.updateParams.ProjectID != nil {
+		t.Fatalf("project must not change, got %v", *repo.updateParams.ProjectID)
+	}
+	if len(repo.updateParams.CustomFields) != 2 {
+		t.Fatalf("unexpected custom fields: %+v", repo.updateParams.CustomFields)
+	}
+	for _, value := range repo.updateParams.CustomFields {
+		if value.FieldID == 2 && len(value.Values) != 0 {
+			t.Fatalf("expected customer to be cleared, got %v", value.Values)
+		}
+	}
+
+	_, err = svc.Update(context.Background(), 1, UpdateTaskInput{
+		CustomFields: map[string]any{"severity": nil},
+	})
+	var validationErr ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Field != "custom_fields.severity" {
+		t.Fatalf("expected required field error, got %v", err)
+	}
+}
+
+func TestServiceUpdate_ChangeProjectRequiresFields(t *testing.T) {
+	repo := &mockRepository{
+		project:   testProject(),
+		getResult: Task{ID: 1},
+	}
+	svc := NewService(repo)
+
+	_, err := svc.Update(context.Background(), 1, UpdateTaskInput{ProjectID: uint64Ptr(7)})
+	var validationErr ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Field != "custom_fields.severity" {
+		t.Fatalf("expected required field error, got %v", err)
+	}
+
+	_, err = svc.Update(context.Background(), 1, UpdateTaskInput{
+		ProjectID:    uint64Ptr(7),
+		CustomFields: map[string]any{"severity": "high"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.updateParams.ProjectID == nil || *repo.updateParams.ProjectID != 7 {
+		t.Fatalf("unexpected project id: %v", repo.updateParams.ProjectID)
+	}
+}
+
+func TestServiceList_CustomFieldFilters(t *testing.T) {
+	repo := &mockRepository{}
+	svc := NewService(repo)
+
+	_, err := svc.List(context.Background(), ListTasksInput{
+		CustomFields: map[string][]string{"severity": {"high", "critical"}, "customer": {"ACME"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.listFilter.CustomFields) != 2 || repo.listFilter.CustomFields[0].Name != "customer" {
+		t.Fatalf("unexpected custom field filters: %+v", repo.listFilter.CustomFields)
+	}
+
+	_, err = svc.List(context.Background(), ListTasksInput{CustomFields: map[string][]string{"Severity!": {"high"}}})
+	var validationErr ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Field != "cf.Severity!" {
+		t.Fatalf("expected validation error for filter name, got %v", err)
+	}
+}
+
+func TestServiceUpdate_ReadsTaskOnce(t *testing.T) {
+	repo := &mockRepository{
+		project:   testProject(),
+		sprint:    Sprint{ID: 5, ProjectID: 7, State: SprintPlanned},
+		getResult: Task{ID: 1, Status: StatusNew, ProjectID: uint64Ptr(7)},
+	}
+
+	status := "in_progress"
+	sprintID := uint64(5)
+	_, err := NewService(repo, WithNotifier(&mockNotifier{})).Update(context.Background(), 1, UpdateTaskInput{
+		Status:       &status,
+		SprintID:     &sprintID,
+		CustomFields: map[string]any{"severity": "low"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.getCalls != 1 {
+		t.Fatalf("expected the task to be read once, got %d reads", repo.getCalls)
+	}
+}
+
+func TestServiceUpdate_ExpectedVersion(t *testing.T) {
+	repo := &mockRepository{updateErr: ErrVersionConflict}
+	svc := NewService(repo)
+
+	version := uint64(3)
+	title := "Rename"
+	_, err := svc.Update(context.Background(), 5, UpdateTaskInput{Title: &title, ExpectedVersion: &version})
+	if !errors.Is(err, ErrVersionConflict) {
+		t.Fatalf("expected version conflict, got %v", err)
+	}
+	if repo.updateParams.ExpectedVersion == nil || *repo.updateParams.ExpectedVersion != 3 {
+		t.Fatalf("expected version to be passed, got %v", repo.updateParams.ExpectedVersion)
+	}
+}
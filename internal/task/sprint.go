@@ -0,0 +1,361 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+const maxSprintNameLength = 255
+
+type SprintState string
+
+const (
+	SprintPlanned SprintState = "planned"
+	SprintActive  SprintState = "active"
+	SprintClosed  SprintState = "closed"
+)
+
+// SprintTotals counts tasks and the sum of their story points.
+type SprintTotals struct {
+	Tasks  int64 `json:"tasks"`
+	Points int64 `json:"points"`
+}
+
+// Sprint.Committed is recorded when the sprint starts; Completed, CarriedOver
+// and NextSprintID when it closes.
+type Sprint struct {
+	ID           uint64        `json:"id"`
+	ProjectID    uint64        `json:"project_id"`
+	Name         string        `json:"name"`
+	Goal         string        `json:"goal"`
+	State        SprintState   `json:"state"`
+	StartsAt     time.Time     `json:"starts_at"`
+	EndsAt       time.Time     `json:"ends_at"`
+	Committed    *SprintTotals `json:"committed,omitempty"`
+	Completed    *SprintTotals `json:"completed,omitempty"`
+	CarriedOver  *SprintTotals `json:"carried_over,omitempty"`
+	NextSprintID *uint64       `json:"next_sprint_id,omitempty"`
+	CreatedAt    time.Time     `json:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at"`
+	StartedAt    *time.Time    `json:"started_at,omitempty"`
+	ClosedAt     *time.Time    `json:"closed_at,omitempty"`
+}
+
+// SprintSummary is the current scope of a sprint: all of its tasks and the
+// done ones among them.
+type SprintSummary struct {
+	Total SprintTotals
+	Done  SprintTotals
+}
+
+// SprintReport compares the scope committed to at the start of a sprint with
+// what was completed. Until the sprint starts the committed scope is the
+// current one.
+type SprintReport struct {
+	SprintID       uint64       `json:"sprint_id"`
+	State          SprintState  `json:"state"`
+	Committed      SprintTotals `json:"committed"`
+	Completed      SprintTotals `json:"completed"`
+	Remaining      SprintTotals `json:"remaining"`
+	CompletionRate float64      `json:"completion_rate"`
+}
+
+type CreateSprintInput struct {
+	Name     string
+	Goal     string
+	StartsAt *time.Time
+	EndsAt   *time.Time
+}
+
+type UpdateSprintInput struct {
+	Name     *string
+	Goal     *string
+	StartsAt *time.Time
+	EndsAt   *time.Time
+}
+
+// CloseSprintInput.NextSprintID receives the unfinished tasks; without it
+// they go to the earliest planned sprint of the project or, if there is none,
+// back to the backlog.
+type CloseSprintInput struct {
+	NextSprintID *uint64
+}
+
+type SprintParams struct {
+	ProjectID uint64
+	Name      string
+	Goal      string
+	StartsAt  time.Time
+	EndsAt    time.Time
+}
+
+type UpdateSprintParams struct {
+	Name     *string
+	Goal     *string
+	StartsAt *time.Time
+	EndsAt   *time.Time
+}
+
+type SprintRepository interface {
+	CreateSprint(ctx context.Context, params SprintParams) (Sprint, error)
+	GetSprint(ctx context.Context, id uint64) (Sprint, error)
+	ListSprints(ctx context.Context, projectID uint64) ([]Sprint, error)
+	UpdateSprint(ctx context.Context, id uint64, params UpdateSprintParams) (Sprint, error)
+	StartSprint(ctx context.Context, id uint64) error
+	CloseSprint(ctx context.Context, id uint64, nextSprintID *uint64) error
+	SummarizeSprint(ctx context.Context, id uint64) (SprintSummary, error)
+}
+
+type SprintService interface {
+	Create(ctx context.Context, projectID uint64, input CreateSprintInput) (Sprint, error)
+	GetByID(ctx context.Context, id uint64) (Sprint, error)
+	List(ctx context.Context, projectID uint64) ([]Sprint, error)
+	Update(ctx context.Context, id uint64, input UpdateSprintInput) (Sprint, error)
+	Start(ctx context.Context, id uint64) (Sprint, error)
+	Close(ctx context.Context, id uint64, input CloseSprintInput) (Sprint, error)
+	Report(ctx context.Context, id uint64) (SprintReport, error)
+}
+
+type sprintService struct {
+	repo SprintRepository
+}
+
+func NewSprintService(repo SprintRepository) SprintService {
+	return &sprintService{repo: repo}
+}
+
+func (s *sprintService) Create(ctx context.Context, projectID uint64, input CreateSprintInput) (Sprint, error) {
+	if projectID == 0 {
+		return Sprint{}, ValidationError{Field: "id", Message: "must be greater than 0"}
+	}
+
+	name, err := normalizeSprintName(input.Name)
+	if err != nil {
+		return Sprint{}, err
+	}
+	if input.StartsAt == nil {
+		return Sprint{}, ValidationError{Field: "starts_at", Message: "is required"}
+	}
+	if input.EndsAt == nil {
+		return Sprint{}, ValidationError{Field: "ends_at", Message: "is required"}
+	}
+	if err := validateSprintDates(*input.StartsAt, *input.EndsAt); err != nil {
+		return Sprint{}, err
+	}
+
+	return s.repo.CreateSprint(ctx, SprintParams{
+		ProjectID: projectID,
+		Name:      name,
+		Goal:      strings.TrimSpace(input.Goal),
+		StartsAt:  input.StartsAt.UTC(),
+		EndsAt:    input.EndsAt.UTC(),
+	})
+}
+
+func (s *sprintService) GetByID(ctx context.Context, id uint64) (Sprint, error) {
+	if id == 0 {
+		return Sprint{}, ValidationError{Field: "id", Message: "must be greater than 0"}
+	}
+	return s.repo.GetSprint(ctx, id)
+}
+
+func (s *sprintService) List(ctx context.Context, projectID uint64) ([]Sprint, error) {
+	if projectID == 0 {
+		return nil, ValidationError{Field: "id", Message: "must be greater than 0"}
+	}
+	return s.repo.ListSprints(ctx, projectID)
+}
+
+func (s *sprintService) Update(ctx context.Context, id uint64, input UpdateSprintInput) (Sprint, error) {
+	current, err := s.GetByID(ctx, id)
+	if err != nil {
+		return Sprint{}, err
+	}
+
+	params := UpdateSprintParams{Goal: input.Goal}
+	if input.Name != nil {
+		name, err := normalizeSprintName(*input.Name)
+		if err != nil {
+			return Sprint{}, err
+		}
+		params.Name = &name
+	}
+	if params.Goal != nil {
+		goal := strings.TrimSpace(*params.Goal)
+		params.Goal = &goal
+	}
+
+	startsAt, endsAt := current.StartsAt, current.EndsAt
+	if input.StartsAt != nil {
+		startsAt = input.StartsAt.UTC()
+		params.StartsAt = &startsAt
+	}
+	if input.EndsAt != nil {
+		endsAt = input.EndsAt.UTC()
+		params.EndsAt = &endsAt
+	}
+	if err := validateSprintDates(startsAt, endsAt); err != nil {
+		return Sprint{}, err
+	}
+
+	if params.Name == nil && params.Goal == nil && params.StartsAt == nil && params.EndsAt == nil {
+		return Sprint{}, ValidationError{Field: "body", Message: "at least one field must be provided for update"}
+	}
+
+	return s.repo.UpdateSprint(ctx, id, params)
+}
+
+func (s *sprintService) Start(ctx context.Context, id uint64) (Sprint, error) {
+	if id == 0 {
+		return Sprint{}, ValidationError{Field: "id", Message: "must be greater than 0"}
+	}
+	if err := s.repo.StartSprint(ctx, id); err != nil {
+		return Sprint{}, err
+	}
+	return s.repo.GetSprint(ctx, id)
+}
+
+func (s *sprintService) Close(ctx context.Context, id uint64, input CloseSprintInput) (Sprint, error) {
+	current, err := s.GetByID(ctx, id)
+	if err != nil {
+		return Sprint{}, err
+	}
+	if current.State != SprintActive {
+		return Sprint{}, ErrInvalidSprintTransition
+	}
+
+	nextSprintID, err := s.nextSprint(ctx, current, input.NextSprintID)
+	if err != nil {
+		return Sprint{}, err
+	}
+
+	if err := s.repo.CloseSprint(ctx, id, nextSprintID); err != nil {
+		return Sprint{}, err
+	}
+	return s.repo.GetSprint(ctx, id)
+}
+
+func (s *sprintService) Report(ctx context.Context, id uint64) (SprintReport, error) {
+	sprint, err := s.GetByID(ctx, id)
+	if err != nil {
+		return SprintReport{}, err
+	}
+
+	report := SprintReport{SprintID: sprint.ID, State: sprint.State}
+	if sprint.State == SprintClosed && sprint.Committed != nil && sprint.Completed != nil && sprint.CarriedOver != nil {
+		report.Committed = *sprint.Committed
+		report.Completed = *sprint.Completed
+		report.Remaining = *sprint.CarriedOver
+	} else {
+		summary, err := s.repo.SummarizeSprint(ctx, id)
+		if err != nil {
+			return SprintReport{}, err
+		}
+		report.Committed = summary.Total
+		if sprint.Committed != nil {
+			report.Committed = *sprint.Committed
+		}
+		report.Completed = summary.Done
+		report.Remaining = SprintTotals{
+			Tasks:  summary.Total.Tasks - summary.Done.Tasks,
+			Points: summary.Total.Points - summary.Done.Points,
+		}
+	}
+
+	if report.Committed.Points > 0 {
+		report.CompletionRate = float64(report.Completed.Points) / float64(report.Committed.Points)
+	}
+	return report, nil
+}
+
+// nextSprint resolves the sprint that receives the unfinished tasks of a
+// closed sprint.
+func (s *sprintService) nextSprint(ctx context.Context, closing Sprint, requested *uint64) (*uint64, error) {
+	if requested == nil {
+		sprints, err := s.repo.ListSprints(ctx, closing.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+		for _, sprint := range sprints {
+			if sprint.State == SprintPlanned {
+				return &sprint.ID, nil
+			}
+		}
+		return nil, nil
+	}
+
+	if *requested == 0 {
+		return nil, ValidationError{Field: "next_sprint_id", Message: "must be greater than 0"}
+	}
+	if *requested == closing.ID {
+		return nil, ValidationError{Field: "next_sprint_id", Message: "must not be the closed sprint"}
+	}
+
+	next, err := s.repo.GetSprint(ctx, *requested)
+	if errors.Is(err, ErrSprintNotFound) {
+		return nil, ValidationError{Field: "next_sprint_id", Message: "sprint does not exist"}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSprintAssignable(next, closing.ProjectID); err != nil {
+		return nil, withField(err, "next_sprint_id")
+	}
+	return &next.ID, nil
+}
+
+// lookupSprint loads the sprint a task is assigned to and checks that it
+// accepts tasks of the given project.
+func lookupSprint(ctx context.Context, repo Repository, id uint64, projectID *uint64) (Sprint, error) {
+	if id == 0 {
+		return Sprint{}, ValidationError{Field: "sprint_id", Message: "must be greater than 0"}
+	}
+	if projectID == nil {
+		return Sprint{}, ValidationError{Field: "sprint_id", Message: "requires the task to belong to a project"}
+	}
+
+	sprint, err := repo.GetSprint(ctx, id)
+	if errors.Is(err, ErrSprintNotFound) {
+		return Sprint{}, ValidationError{Field: "sprint_id", Message: "sprint does not exist"}
+	}
+	if err != nil {
+		return Sprint{}, err
+	}
+	if err := checkSprintAssignable(sprint, *projectID); err != nil {
+		return Sprint{}, err
+	}
+	return sprint, nil
+}
+
+func checkSprintAssignable(sprint Sprint, projectID uint64) error {
+	if sprint.ProjectID != projectID {
+		return ValidationError{Field: "sprint_id", Message: "sprint belongs to another project"}
+	}
+	if sprint.State == SprintClosed {
+		return ValidationError{Field: "sprint_id", Message: "sprint is closed"}
+	}
+	return nil
+}
+
+func normalizeSprintName(raw string) (string, error) {
+	name := strings.TrimSpace(raw)
+	if name == "" {
+		return "", ValidationError{Field: "name", Message: "must not be empty"}
+	}
+	if len(name) > maxSprintNameLength {
+		return "", ValidationError{Field: "name", Message: "must be at most 255 characters"}
+	}
+	return name, nil
+}
+
+func validateSprintDates(startsAt, endsAt time.Time) error {
+	if startsAt.IsZero() {
+		return ValidationError{Field: "starts_at", Message: "must be a valid timestamp"}
+	}
+	if !endsAt.After(startsAt) {
+		return ValidationError{Field: "ends_at", Message: "must be after starts_at"}
+	}
+	return nil
+}
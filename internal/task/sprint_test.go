@@ -0,0 +1,275 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type mockSprintRepository struct {
+	sprints []Sprint
+	summary SprintSummary
+
+	createParams SprintParams
+	closeID      uint64
+	closeNextID  *uint64
+	closeCalled  bool
+}
+
+func (m *mockSprintRepository) CreateSprint(_ context.Context, params SprintParams) (Sprint, error) {
+	m.createParams = params
+	return Sprint{ID: 1, ProjectID: params.ProjectID, Name: params.Name, State: SprintPlanned}, nil
+}
+
+func (m *mockSprintRepository) GetSprint(_ context.Context, id uint64) (Sprint, error) {
+	for _, sprint := range m.sprints {
+		if sprint.ID == id {
+			return sprint, nil
+		}
+	}
+	return Sprint{}, ErrSprintNotFound
+}
+
+func (m *mockSprintRepository) ListSprints(_ context.Context, projectID uint64) ([]Sprint, error) {
+	var sprints []Sprint
+	for _, sprint := range m.sprints {
+		if sprint.ProjectID == projectID {
+			sprints = append(sprints, sprint)
+		}
+	}
+	return sprints, nil
+}
+
+func (m *mockSprintRepository) UpdateSprint(_ context.Context, id uint64, _ UpdateSprintParams) (Sprint, error) {
+	return m.GetSprint(context.Background(), id)
+}
+
+func (m *mockSprintRepository) StartSprint(_ context.Context, _ uint64) error {
+	return nil
+}
+
+func (m *mockSprintRepository) CloseSprint(_ context.Context, id uint64, nextSprintID *uint64) error {
+	m.closeCalled = true
+	m.closeID = id
+	m.closeNextID = nextSprintID
+	return nil
+}
+
+func (m *mockSprintRepository) SummarizeSprint(_ context.Context, _ uint64) (SprintSummary, error) {
+	return m.summary, nil
+}
+
+func TestSprintServiceCreate(t *testing.T) {
+	repo := &mockSprintRepository{}
+	svc := NewSprintService(repo)
+	startsAt := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	endsAt := startsAt.AddDate(0, 0, 14)
+
+	_, err := svc.Create(context.Background(), 2, CreateSprintInput{Name: "  Sprint 1 ", Goal: "Ship it", StartsAt: &startsAt, EndsAt: &endsAt})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.createParams.ProjectID != 2 || repo.createParams.Name != "Sprint 1" || !repo.createParams.EndsAt.Equal(endsAt) {
+		t.Fatalf("unexpected params: %+v", repo.createParams)
+	}
+
+	tests := []struct {
+		name  string
+		input CreateSprintInput
+		field string
+	}{
+		{name: "empty name", input: CreateSprintInput{StartsAt: &startsAt, EndsAt: &endsAt}, field: "name"},
+		{name: "missing start", input: CreateSprintInput{Name: "Sprint", EndsAt: &endsAt}, field: "starts_at"},
+		{name: "end before start", input: CreateSprintInput{Name: "Sprint", StartsAt: &endsAt, EndsAt: &startsAt}, field: "ends_at"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := svc.Create(context.Background(), 2, tc.input)
+			var validationErr ValidationError
+			if !errors.As(err, &validationErr) || validationErr.Field != tc.field {
+				t.Fatalf("expected validation error for %s, got %v", tc.field, err)
+			}
+		})
+	}
+}
+
+func TestSprintServiceClose(t *testing.T) {
+	sprints := []Sprint{
+		{ID: 1, ProjectID: 2, State: SprintClosed},
+		{ID: 2, ProjectID: 2, State: SprintActive},
+		{ID: 3, ProjectID: 2, State: SprintPlanned},
+		{ID: 4, ProjectID: 2, State: SprintPlanned},
+		{ID: 5, ProjectID: 7, State: SprintPlanned},
+	}
+
+	t.Run("defaults to the next planned sprint", func(t *testing.T) {
+		repo := &mockSprintRepository{sprints: sprints}
+		if _, err := NewSprintService(repo).Close(context.Background(), 2, CloseSprintInput{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if repo.closeNextID == nil || *repo.closeNextID != 3 {
+			t.Fatalf("expected unfinished tasks to move to sprint 3, got %v", repo.closeNextID)
+		}
+	})
+
+	t.Run("backlog without a planned sprint", func(t *testing.T) {
+		repo := &mockSprintRepository{sprints: sprints[:2]}
+		if _, err := NewSprintService(repo).Close(context.Background(), 2, CloseSprintInput{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !repo.closeCalled || repo.closeNextID != nil {
+			t.Fatalf("expected unfinished tasks to move to the backlog, got %v", repo.closeNextID)
+		}
+	})
+
+	t.Run("requested next sprint", func(t *testing.T) {
+		repo := &mockSprintRepository{sprints: sprints}
+		next := uint64(4)
+		if _, err := NewSprintService(repo).Close(context.Background(), 2, CloseSprintInput{NextSprintID: &next}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if repo.closeNextID == nil || *repo.closeNextID != 4 {
+			t.Fatalf("expected sprint 4, got %v", repo.closeNextID)
+		}
+	})
+
+	for _, next := range []uint64{1, 2, 5, 9} {
+		repo := &mockSprintRepository{sprints: sprints}
+		_, err := NewSprintService(repo).Close(context.Background(), 2, CloseSprintInput{NextSprintID: &next})
+		var validationErr ValidationError
+		if !errors.As(err, &validationErr) || validationErr.Field != "next_sprint_id" {
+			t.Fatalf("expected next_sprint_id validation error for sprint %d, got %v", next, err)
+		}
+		if repo.closeCalled {
+			t.Fatalf("sprint must not be closed with next sprint %d", next)
+		}
+	}
+
+	repo := &mockSprintRepository{sprints: sprints}
+	if _, err := NewSprintService(repo).Close(context.Background(), 3, CloseSprintInput{}); !errors.Is(err, ErrInvalidSprintTransition) {
+		t.Fatalf("expected ErrInvalidSprintTransition for a planned sprint, got %v", err)
+	}
+}
+
+func TestSprintServiceReport(t *testing.T) {
+	summary := SprintSummary{
+		Total: SprintTotals{Tasks: 6, Points: 20},
+		Done:  SprintTotals{Tasks: 4, Points: 15},
+	}
+
+	tests := []struct {
+		name      string
+		sprint    Sprint
+		committed SprintTotals
+		completed SprintTotals
+		remaining SprintTotals
+	}{
+		{
+			name:      "planned uses the current scope",
+			sprint:    Sprint{ID: 1, State: SprintPlanned},
+			committed: SprintTotals{Tasks: 6, Points: 20},
+			completed: SprintTotals{Tasks: 4, Points: 15},
+			remaining: SprintTotals{Tasks: 2, Points: 5},
+		},
+		{
+			name:      "active uses the committed scope",
+			sprint:    Sprint{ID: 1, State: SprintActive, Committed: &SprintTotals{Tasks: 5, Points: 30}},
+			committed: SprintTotals{Tasks: 5, Points: 30},
+			completed: SprintTotals{Tasks: 4, Points: 15},
+			remaining: SprintTotals{Tasks: 2, Points: 5},
+		},
+		{
+			name: "closed uses the recorded totals",
+			sprint: Sprint{
+				ID:          1,
+				State:       SprintClosed,
+				Committed:   &SprintTotals{Tasks: 5, Points: 30},
+				Completed:   &SprintTotals{Tasks: 3, Points: 24},
+				CarriedOver: &SprintTotals{Tasks: 2, Points: 8},
+			},
+			committed: SprintTotals{Tasks: 5, Points: 30},
+			completed: SprintTotals{Tasks: 3, Points: 24},
+			remaining: SprintTotals{Tasks: 2, Points: 8},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockSprintRepository{sprints: []Sprint{tc.sprint}, summary: summary}
+			report, err := NewSprintService(repo).Report(context.Background(), 1)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if report.Committed != tc.committed || report.Completed != tc.completed || report.Remaining != tc.remaining {
+				t.Fatalf("unexpected report: %+v", report)
+			}
+			want := float64(tc.completed.Points) / float64(tc.committed.Points)
+			if report.CompletionRate != want {
+				t.Fatalf("expected completion rate %v, got %v", want, report.CompletionRate)
+			}
+		})
+	}
+}
+
+func TestServiceCreate_Sprint(t *testing.T) {
+	projectID := uint64(2)
+	sprintID := uint64(5)
+
+	repo := &mockRepository{
+		project: Project{ID: 2},
+		sprint:  Sprint{ID: 5, ProjectID: 2, State: SprintActive},
+	}
+	if _, err := NewService(repo).Create(context.Background(), CreateTaskInput{Title: "Task", ProjectID: &projectID, SprintID: &sprintID}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.createParams.SprintID == nil || *repo.createParams.SprintID != 5 {
+		t.Fatalf("unexpected sprint id: %v", repo.createParams.SprintID)
+	}
+
+	tests := []struct {
+		name      string
+		projectID *uint64
+		sprint    Sprint
+	}{
+		{name: "without project", sprint: Sprint{ID: 5, ProjectID: 2, State: SprintActive}},
+		{name: "another project", projectID: &projectID, sprint: Sprint{ID: 5, ProjectID: 3, State: SprintActive}},
+		{name: "closed sprint", projectID: &projectID, sprint: Sprint{ID: 5, ProjectID: 2, State: SprintClosed}},
+		{name: "missing sprint", projectID: &projectID},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockRepository{project: Project{ID: 2}, sprint: tc.sprint}
+			_, err := NewService(repo).Create(context.Background(), CreateTaskInput{Title: "Task", ProjectID: tc.projectID, SprintID: &sprintID})
+			var validationErr ValidationError
+			if !errors.As(err, &validationErr) || validationErr.Field != "sprint_id" {
+				t.Fatalf("expected sprint_id validation error, got %v", err)
+			}
+			if repo.createCalled {
+				t.Fatal("task must not be created")
+			}
+		})
+	}
+}
+
+func TestServiceUpdate_Sprint(t *testing.T) {
+	projectID := uint64(2)
+	sprintID := uint64(5)
+	repo := &mockRepository{
+		getResult: Task{ID: 1, ProjectID: &projectID},
+		sprint:    Sprint{ID: 5, ProjectID: 2, State: SprintPlanned},
+	}
+
+	if _, err := NewService(repo).Update(context.Background(), 1, UpdateTaskInput{SprintID: &sprintID}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.updateParams.SprintID == nil || *repo.updateParams.SprintID != 5 {
+		t.Fatalf("unexpected sprint id: %v", repo.updateParams.SprintID)
+	}
+
+	_, err := NewService(repo).Update(context.Background(), 1, UpdateTaskInput{SprintID: &sprintID, ClearProjectID: true})
+	var validationErr ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Field != "sprint_id" {
+		t.Fatalf("expected sprint_id validation error, got %v", err)
+	}
+}
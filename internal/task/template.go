@@ -0,0 +1,395 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/notification"
+)
+
+const (
+	maxTemplateNameLength  = 255
+	maxTemplateTasks       = 50
+	maxTemplateDepth       = 3
+	maxTemplateVariableLen = 255
+)
+
+var (
+	templatePlaceholderPattern = regexp.MustCompile(`\{\{\s*([a-z][a-z0-9_]*)\s*\}\}`)
+	dueOffsetPattern           = regexp.MustCompile(`^([+-])(\d{1,4})([mhdw])$`)
+)
+
+// TemplateTask describes a task created from a template. Title, description,
+// labels and checklist items may contain {{variable}} placeholders and
+// DueOffset is relative to the time of instantiation, e.g. "+3d".
+type TemplateTask struct {
+	Title       string         `json:"title"`
+	Description string         `json:"description,omitempty"`
+	Priority    int            `json:"priority,omitempty"`
+	Labels      []string       `json:"labels,omitempty"`
+	Checklist   []string       `json:"checklist,omitempty"`
+	DueOffset   string         `json:"due_offset,omitempty"`
+	Subtasks    []TemplateTask `json:"subtasks,omitempty"`
+}
+
+type Template struct {
+	ID        uint64       `json:"id"`
+	Name      string       `json:"name"`
+	Task      TemplateTask `json:"task"`
+	Variables []string     `json:"variables"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+type CreateTemplateInput struct {
+	Name string
+	Task TemplateTask
+}
+
+type InstantiateTemplateInput struct {
+	Variables  map[string]string
+	ReporterID string
+}
+
+// TaskTreeParams is a task to create together with its checklist and
+// subtasks.
+type TaskTreeParams struct {
+	Task      CreateParams
+	Checklist []string
+	Subtasks  []TaskTreeParams
+}
+
+type TemplateRepository interface {
+	CreateTemplate(ctx context.Context, name string, task TemplateTask) (Template, error)
+	GetTemplate(ctx context.Context, id uint64) (Template, error)
+	ListTemplates(ctx context.Context) ([]Template, error)
+	DeleteTemplate(ctx context.Context, id uint64) error
+	CreateTaskTree(ctx context.Context, root TaskTreeParams) ([]Task, error)
+	FindUsersByUsername(ctx context.Context, usernames []string) ([]User, error)
+}
+
+type TemplateService interface {
+	Create(ctx context.Context, input CreateTemplateInput) (Template, error)
+	GetByID(ctx context.Context, id uint64) (Template, error)
+	List(ctx context.Context) ([]Template, error)
+	Delete(ctx context.Context, id uint64) error
+	Instantiate(ctx context.Context, id uint64, input InstantiateTemplateInput) ([]Task, error)
+}
+
+type templateService struct {
+	repo     TemplateRepository
+	notifier notification.Notifier
+	now      func() time.Time
+}
+
+func NewTemplateService(repo TemplateRepository, notifier notification.Notifier) TemplateService {
+	return &templateService{repo: repo, notifier: notifier, now: time.Now}
+}
+
+func (s *templateService) Create(ctx context.Context, input CreateTemplateInput) (Template, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return Template{}, ValidationError{Field: "name", Message: "must not be empty"}
+	}
+	if len(name) > maxTemplateNameLength {
+		return Template{}, ValidationError{Field: "name", Message: "must be at most 255 characters"}
+	}
+
+	count := 0
+	root, err := normalizeTemplateTask(input.Task, "task", 1, &count)
+	if err != nil {
+		return Template{}, err
+	}
+
+	created, err := s.repo.CreateTemplate(ctx, name, root)
+	if err != nil {
+		return Template{}, err
+	}
+	return withVariables(created), nil
+}
+
+func (s *templateService) GetByID(ctx context.Context, id uint64) (Template, error) {
+	if id == 0 {
+		return Template{}, ValidationError{Field: "id", Message: "must be greater than 0"}
+	}
+	found, err := s.repo.GetTemplate(ctx, id)
+	if err != nil {
+		return Template{}, err
+	}
+	return withVariables(found), nil
+}
+
+func (s *templateService) List(ctx context.Context) ([]Template, error) {
+	templates, err := s.repo.ListTemplates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range templates {
+		templates[i] = withVariables(templates[i])
+	}
+	return templates, nil
+}
+
+func (s *templateService) Delete(ctx context.Context, id uint64) error {
+	if id == 0 {
+		return ValidationError{Field: "id", Message: "must be greater than 0"}
+	}
+	return s.repo.DeleteTemplate(ctx, id)
+}
+
+// Instantiate creates the task tree of a template in a single transaction.
+// Every placeholder must have a value and every resulting task must pass the
+// same validation as a task created through the API. Like Create, the
+// reporter watches every task and the users mentioned in a description are
+// recorded and notified.
+func (s *templateService) Instantiate(ctx context.Context, id uint64, input InstantiateTemplateInput) ([]Task, error) {
+	if strings.TrimSpace(input.ReporterID) != "" {
+		reporterID, err := normalizeUserID(input.ReporterID)
+		if err != nil {
+			return nil, withField(err, "reporter_id")
+		}
+		input.ReporterID = reporterID
+	}
+
+	found, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range found.Variables {
+		value, ok := input.Variables[name]
+		if !ok || strings.TrimSpace(value) == "" {
+			return nil, ValidationError{Field: "variables." + name, Message: "is required"}
+		}
+		if len(value) > maxTemplateVariableLen {
+			return nil, ValidationError{Field: "variables." + name, Message: "must be at most 255 characters"}
+		}
+	}
+
+	root, err := instantiateTemplateTask(found.Task, "task", input, s.now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	var mentioned [][]User
+	if err := s.resolveTreeMentions(ctx, &root, &mentioned); err != nil {
+		return nil, err
+	}
+
+	created, err := s.repo.CreateTaskTree(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, createdTask := range created {
+		if i >= len(mentioned) {
+			break
+		}
+		notifyMentioned(ctx, s.notifier, mentioned[i], input.ReporterID, notification.Notification{
+			TaskID:  createdTask.ID,
+			Subject: fmt.Sprintf("You were mentioned in task %q", createdTask.Title),
+			Message: createdTask.Description,
+		})
+	}
+
+	return created, nil
+}
+
+// resolveTreeMentions sets the mentions of every task in the tree and appends
+// the mentioned users depth-first, the order in which CreateTaskTree returns
+// the tasks.
+func (s *templateService) resolveTreeMentions(ctx context.Context, node *TaskTreeParams, mentioned *[][]User) error {
+	users, err := resolveMentions(ctx, s.repo, node.Task.Description)
+	if err != nil {
+		return err
+	}
+	node.Task.Mentions = mentionedUserIDs(users)
+	*mentioned = append(*mentioned, users)
+
+	for i := range node.Subtasks {
+		if err := s.resolveTreeMentions(ctx, &node.Subtasks[i], mentioned); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func normalizeTemplateTask(raw TemplateTask, path string, depth int, count *int) (TemplateTask, error) {
+	*count++
+	if *count > maxTemplateTasks {
+		return TemplateTask{}, ValidationError{Field: path, Message: "a template must contain at most 50 tasks"}
+	}
+	if depth > maxTemplateDepth {
+		return TemplateTask{}, ValidationError{Field: path, Message: "subtasks must be nested at most 3 levels deep"}
+	}
+
+	normalized := TemplateTask{
+		Title:       strings.TrimSpace(raw.Title),
+		Description: strings.TrimSpace(raw.Description),
+		Priority:    raw.Priority,
+		DueOffset:   strings.TrimSpace(raw.DueOffset),
+	}
+
+	if normalized.Title == "" {
+		return TemplateTask{}, ValidationError{Field: path + ".title", Message: "must not be empty"}
+	}
+	if normalized.Priority != 0 {
+		if _, err := validatePriority(normalized.Priority); err != nil {
+			return TemplateTask{}, withField(err, path+".priority")
+		}
+	}
+	if normalized.DueOffset != "" {
+		if _, err := ParseDueOffset(normalized.DueOffset); err != nil {
+			return TemplateTask{}, withField(err, path+".due_offset")
+		}
+	}
+
+	labels, err := normalizeLabels(raw.Labels)
+	if err != nil {
+		return TemplateTask{}, withField(err, path+".labels")
+	}
+	if len(labels) > 0 {
+		normalized.Labels = labels
+	}
+
+	if len(raw.Checklist) > maxChecklistItems {
+		return TemplateTask{}, ValidationError{Field: path + ".checklist", Message: "must contain at most 100 items"}
+	}
+	for _, item := range raw.Checklist {
+		text, err := normalizeChecklistText(item)
+		if err != nil {
+			return TemplateTask{}, withField(err, path+".checklist")
+		}
+		normalized.Checklist = append(normalized.Checklist, text)
+	}
+
+	for i, subtask := range raw.Subtasks {
+		child, err := normalizeTemplateTask(subtask, fmt.Sprintf("%s.subtasks[%d]", path, i), depth+1, count)
+		if err != nil {
+			return TemplateTask{}, err
+		}
+		normalized.Subtasks = append(normalized.Subtasks, child)
+	}
+
+	return normalized, nil
+}
+
+func instantiateTemplateTask(template TemplateTask, path string, instantiate InstantiateTemplateInput, now time.Time) (TaskTreeParams, error) {
+	fill := func(text string) string {
+		return templatePlaceholderPattern.ReplaceAllStringFunc(text, func(placeholder string) string {
+			name := templatePlaceholderPattern.FindStringSubmatch(placeholder)[1]
+			return strings.TrimSpace(instantiate.Variables[name])
+		})
+	}
+
+	input := CreateTaskInput{
+		ReporterID:  instantiate.ReporterID,
+		Title:       fill(template.Title),
+		Description: fill(template.Description),
+		Priority:    template.Priority,
+	}
+	for _, label := range template.Labels {
+		input.Labels = append(input.Labels, fill(label))
+	}
+	if template.DueOffset != "" {
+		offset, err := ParseDueOffset(template.DueOffset)
+		if err != nil {
+			return TaskTreeParams{}, withField(err, path+".due_offset")
+		}
+		dueAt := now.Add(offset).Truncate(time.Second)
+		input.DueAt = &dueAt
+	}
+
+	params, err := newCreateParams(input)
+	if err != nil {
+		return TaskTreeParams{}, prefixField(err, path)
+	}
+
+	node := TaskTreeParams{Task: params}
+	for _, item := range template.Checklist {
+		text, err := normalizeChecklistText(fill(item))
+		if err != nil {
+			return TaskTreeParams{}, withField(err, path+".checklist")
+		}
+		node.Checklist = append(node.Checklist, text)
+	}
+
+	for i, subtask := range template.Subtasks {
+		child, err := instantiateTemplateTask(subtask, fmt.Sprintf("%s.subtasks[%d]", path, i), instantiate, now)
+		if err != nil {
+			return TaskTreeParams{}, err
+		}
+		node.Subtasks = append(node.Subtasks, child)
+	}
+
+	return node, nil
+}
+
+// ParseDueOffset parses offsets like "+3d", "+12h", "-30m" or "+2w".
+func ParseDueOffset(raw string) (time.Duration, error) {
+	matches := dueOffsetPattern.FindStringSubmatch(strings.TrimSpace(raw))
+	if matches == nil {
+		return 0, ValidationError{Field: "due_offset", Message: `must look like "+3d" with unit m, h, d or w`}
+	}
+
+	amount, _ := strconv.Atoi(matches[2])
+	unit := map[string]time.Duration{
+		"m": time.Minute,
+		"h": time.Hour,
+		"d": 24 * time.Hour,
+		"w": 7 * 24 * time.Hour,
+	}[matches[3]]
+
+	offset := time.Duration(amount) * unit
+	if matches[1] == "-" {
+		offset = -offset
+	}
+	return offset, nil
+}
+
+// Variables lists the placeholder names used anywhere in the task tree.
+func (t TemplateTask) Variables() []string {
+	seen := make(map[string]bool)
+	t.collectVariables(seen)
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (t TemplateTask) collectVariables(seen map[string]bool) {
+	texts := append([]string{t.Title, t.Description}, t.Labels...)
+	texts = append(texts, t.Checklist...)
+	for _, text := range texts {
+		for _, match := range templatePlaceholderPattern.FindAllStringSubmatch(text, -1) {
+			seen[match[1]] = true
+		}
+	}
+	for _, subtask := range t.Subtasks {
+		subtask.collectVariables(seen)
+	}
+}
+
+func withVariables(template Template) Template {
+	template.Variables = template.Task.Variables()
+	return template
+}
+
+// prefixField reports a ValidationError of a nested object under its path.
+func prefixField(err error, path string) error {
+	var validationErr ValidationError
+	if errors.As(err, &validationErr) {
+		validationErr.Field = path + "." + validationErr.Field
+		return validationErr
+	}
+	return err
+}
@@ -0,0 +1,270 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/notification"
+)
+
+type mockTemplateRepository struct {
+	template    Template
+	createdTask TemplateTask
+	treeRoot    TaskTreeParams
+	treeCalled  bool
+	users       []User
+}
+
+func (m *mockTemplateRepository) CreateTemplate(_ context.Context, name string, definition TemplateTask) (Template, error) {
+	m.createdTask = definition
+	return Template{ID: 1, Name: name, Task: definition}, nil
+}
+
+func (m *mockTemplateRepository) GetTemplate(_ context.Context, id uint64) (Template, error) {
+	if id != m.template.ID {
+		return Template{}, ErrTemplateNotFound
+	}
+	return m.template, nil
+}
+
+func (m *mockTemplateRepository) ListTemplates(_ context.Context) ([]Template, error) {
+	return []Template{m.template}, nil
+}
+
+func (m *mockTemplateRepository) DeleteTemplate(_ context.Context, _ uint64) error {
+	return nil
+}
+
+func (m *mockTemplateRepository) CreateTaskTree(_ context.Context, root TaskTreeParams) ([]Task, error) {
+	m.treeCalled = true
+	m.treeRoot = root
+
+	var created []Task
+	var insert func(node TaskTreeParams)
+	insert = func(node TaskTreeParams) {
+		created = append(created, Task{ID: uint64(len(created) + 1), Title: node.Task.Title, Description: node.Task.Description})
+		for _, subtask := range node.Subtasks {
+			insert(subtask)
+		}
+	}
+	insert(root)
+	return created, nil
+}
+
+func (m *mockTemplateRepository) FindUsersByUsername(_ context.Context, usernames []string) ([]User, error) {
+	var found []User
+	for _, user := range m.users {
+		for _, username := range usernames {
+			if strings.EqualFold(user.Username, username) {
+				found = append(found, user)
+			}
+		}
+	}
+	return found, nil
+}
+
+func onboardingTemplate() Template {
+	return Template{
+		ID:   3,
+		Name: "Onboarding",
+		Task: TemplateTask{
+			Title:     "Onboard {{name}}",
+			Priority:  4,
+			Labels:    []string{"onboarding"},
+			Checklist: []string{"Create account for {{ name }}", "Order laptop"},
+			DueOffset: "+3d",
+			Subtasks: []TemplateTask{
+				{Title: "Introduce {{name}} to {{team}}", DueOffset: "+1w"},
+			},
+		},
+	}
+}
+
+func TestTemplateServiceCreate_Normalizes(t *testing.T) {
+	repo := &mockTemplateRepository{}
+	svc := NewTemplateService(repo, nil)
+
+	created, err := svc.Create(context.Background(), CreateTemplateInput{
+		Name: " Incident ",
+		Task: TemplateTask{
+			Title:     "  Incident {{service}} ",
+			Labels:    []string{"Incident", "incident"},
+			Checklist: []string{" Page on-call "},
+			Subtasks:  []TemplateTask{{Title: "Postmortem for {{service}}", DueOffset: "+2d"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if repo.createdTask.Title != "Incident {{service}}" {
+		t.Fatalf("unexpected title: %q", repo.createdTask.Title)
+	}
+	if len(repo.createdTask.Labels) != 1 || repo.createdTask.Checklist[0] != "Page on-call" {
+		t.Fatalf("unexpected normalized task: %+v", repo.createdTask)
+	}
+	if len(created.Variables) != 1 || created.Variables[0] != "service" {
+		t.Fatalf("unexpected variables: %v", created.Variables)
+	}
+}
+
+func TestTemplateServiceCreate_Validation(t *testing.T) {
+	tests := []struct {
+		name  string
+		input CreateTemplateInput
+		field string
+	}{
+		{name: "empty name", input: CreateTemplateInput{Task: TemplateTask{Title: "t"}}, field: "name"},
+		{name: "empty title", input: CreateTemplateInput{Name: "n"}, field: "task.title"},
+		{
+			name:  "invalid due offset",
+			input: CreateTemplateInput{Name: "n", Task: TemplateTask{Title: "t", DueOffset: "3 days"}},
+			field: "task.due_offset",
+		},
+		{
+			name:  "invalid subtask priority",
+			input: CreateTemplateInput{Name: "n", Task: TemplateTask{Title: "t", Subtasks: []TemplateTask{{Title: "s", Priority: 9}}}},
+			field: "task.subtasks[0].priority",
+		},
+		{
+			name: "too deep",
+			input: CreateTemplateInput{Name: "n", Task: TemplateTask{Title: "1", Subtasks: []TemplateTask{
+				{Title: "2", Subtasks: []TemplateTask{{Title: "3", Subtasks: []TemplateTask{{Title: "4"}}}}},
+			}}},
+			field: "task.subtasks[0].subtasks[0].subtasks[0]",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewTemplateService(&mockTemplateRepository{}, nil).Create(context.Background(), tc.input)
+			var validationErr ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("expected validation error, got %v", err)
+			}
+			if validationErr.Field != tc.field {
+				t.Fatalf("expected field %q, got %q", tc.field, validationErr.Field)
+			}
+		})
+	}
+}
+
+func TestTemplateServiceInstantiate_FillsTree(t *testing.T) {
+	repo := &mockTemplateRepository{template: onboardingTemplate()}
+	now := time.Date(2026, 3, 2, 9, 30, 0, 0, time.UTC)
+	svc := &templateService{repo: repo, now: func() time.Time { return now }}
+
+	_, err := svc.Instantiate(context.Background(), 3, InstantiateTemplateInput{
+		Variables: map[string]string{"name": "Alice", "team": "Platform", "unused": "x"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	root := repo.treeRoot
+	if root.Task.Title != "Onboard Alice" || root.Task.Priority != 4 || root.Task.Status != StatusNew {
+		t.Fatalf("unexpected root task: %+v", root.Task)
+	}
+	if root.Task.DueAt == nil || !root.Task.DueAt.Equal(now.Add(72*time.Hour)) {
+		t.Fatalf("unexpected root due date: %v", root.Task.DueAt)
+	}
+	if len(root.Checklist) != 2 || root.Checklist[0] != "Create account for Alice" {
+		t.Fatalf("unexpected checklist: %v", root.Checklist)
+	}
+	if len(root.Subtasks) != 1 || root.Subtasks[0].Task.Title != "Introduce Alice to Platform" {
+		t.Fatalf("unexpected subtasks: %+v", root.Subtasks)
+	}
+	if !root.Subtasks[0].Task.DueAt.Equal(now.Add(7 * 24 * time.Hour)) {
+		t.Fatalf("unexpected subtask due date: %v", root.Subtasks[0].Task.DueAt)
+	}
+}
+
+func TestTemplateServiceInstantiate_ReporterAndMentions(t *testing.T) {
+	template := Template{ID: 4, Name: "Review", Task: TemplateTask{
+		Title:       "Review {{feature}}",
+		Description: "@alice please review {{feature}}",
+		Subtasks:    []TemplateTask{{Title: "Deploy {{feature}}", Description: "cc @{{owner}}"}},
+	}}
+	repo := &mockTemplateRepository{template: template, users: []User{testAlice, testBob}}
+	notifier := &mockNotifier{}
+	svc := NewTemplateService(repo, notifier)
+
+	_, err := svc.Instantiate(context.Background(), 4, InstantiateTemplateInput{
+		Variables:  map[string]string{"feature": "search", "owner": "bob.smith"},
+		ReporterID: strings.ToUpper(testBob.ID),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	root := repo.treeRoot
+	if root.Task.ReporterID == nil || *root.Task.ReporterID != testBob.ID || root.Subtasks[0].Task.ReporterID == nil {
+		t.Fatalf("expected every task to have the reporter, got %+v", root)
+	}
+	if !slices.Equal(root.Task.Mentions, []string{testAlice.ID}) || !slices.Equal(root.Subtasks[0].Task.Mentions, []string{testBob.ID}) {
+		t.Fatalf("unexpected mentions: %v, %v", root.Task.Mentions, root.Subtasks[0].Task.Mentions)
+	}
+
+	// The reporter is not notified about mentioning themselves.
+	if len(notifier.notifications) != 1 {
+		t.Fatalf("expected one notification, got %+v", notifier.notifications)
+	}
+	sent := notifier.notifications[0]
+	if sent.Type != notification.TypeTaskMentioned || sent.TaskID != 1 || !slices.Equal(sent.Recipients, []string{testAlice.ID}) {
+		t.Fatalf("unexpected notification: %+v", sent)
+	}
+}
+
+func TestTemplateServiceInstantiate_MissingVariable(t *testing.T) {
+	repo := &mockTemplateRepository{template: onboardingTemplate()}
+	svc := NewTemplateService(repo, nil)
+
+	_, err := svc.Instantiate(context.Background(), 3, InstantiateTemplateInput{
+		Variables: map[string]string{"name": "Alice"},
+	})
+	var validationErr ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Field != "variables.team" {
+		t.Fatalf("expected missing variable error, got %v", err)
+	}
+	if repo.treeCalled {
+		t.Fatal("no task must be created")
+	}
+}
+
+func TestParseDueOffset(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    time.Duration
+		wantErr bool
+	}{
+		{raw: "+3d", want: 72 * time.Hour},
+		{raw: "+12h", want: 12 * time.Hour},
+		{raw: "-30m", want: -30 * time.Minute},
+		{raw: "+2w", want: 14 * 24 * time.Hour},
+		{raw: "3d", wantErr: true},
+		{raw: "+1y", wantErr: true},
+		{raw: "+1d12h", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.raw, func(t *testing.T) {
+			got, err := ParseDueOffset(tc.raw)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
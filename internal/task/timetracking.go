@@ -0,0 +1,273 @@
+package task
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	maxTimeEntryNoteLength = 1000
+	maxTimeEntryDuration   = 24 * time.Hour
+)
+
+type TimeEntry struct {
+	ID              uint64     `json:"id"`
+	TaskID          uint64     `json:"task_id"`
+	UserID          string     `json:"user_id"`
+	StartedAt       time.Time  `json:"started_at"`
+	EndedAt         *time.Time `json:"ended_at,omitempty"`
+	DurationSeconds int64      `json:"duration_seconds"`
+	Note            string     `json:"note"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+func (e TimeEntry) IsRunning() bool {
+	return e.EndedAt == nil
+}
+
+type LogTimeInput struct {
+	UserID          string
+	StartedAt       *time.Time
+	EndedAt         *time.Time
+	DurationMinutes int
+	Note            string
+}
+
+type StartTimerInput struct {
+	UserID string
+	Note   string
+}
+
+type TimeReportInput struct {
+	UserID string
+	From   *time.Time
+	To     *time.Time
+}
+
+type TaskTime struct {
+	TaskID          uint64 `json:"task_id"`
+	TaskTitle       string `json:"task_title"`
+	DurationSeconds int64  `json:"duration_seconds"`
+}
+
+type UserTimeReport struct {
+	UserID          string     `json:"user_id"`
+	DurationSeconds int64      `json:"duration_seconds"`
+	Tasks           []TaskTime `json:"tasks"`
+}
+
+type TimeEntryParams struct {
+	TaskID    uint64
+	UserID    string
+	StartedAt time.Time
+	EndedAt   *time.Time
+	Note      string
+}
+
+type TimeReportFilter struct {
+	UserID string
+	From   *time.Time
+	To     *time.Time
+}
+
+type TimeEntryRepository interface {
+	CreateTimeEntry(ctx context.Context, params TimeEntryParams) (TimeEntry, error)
+	StopTimer(ctx context.Context, taskID uint64, userID string, endedAt time.Time) (TimeEntry, error)
+	ListTimeEntries(ctx context.Context, taskID uint64) ([]TimeEntry, error)
+	DeleteTimeEntry(ctx context.Context, taskID, entryID uint64, userID string) error
+	TimeReport(ctx context.Context, filter TimeReportFilter) ([]UserTimeReport, error)
+}
+
+type TimeTrackingService interface {
+	StartTimer(ctx context.Context, taskID uint64, input StartTimerInput) (TimeEntry, error)
+	StopTimer(ctx context.Context, taskID uint64, userID string) (TimeEntry, error)
+	LogTime(ctx context.Context, taskID uint64, input LogTimeInput) (TimeEntry, error)
+	ListTimeEntries(ctx context.Context, taskID uint64) ([]TimeEntry, error)
+	DeleteTimeEntry(ctx context.Context, taskID, entryID uint64, userID string) error
+	Report(ctx context.Context, input TimeReportInput) ([]UserTimeReport, error)
+}
+
+type timeTrackingService struct {
+	repo TimeEntryRepository
+	now  func() time.Time
+}
+
+func NewTimeTrackingService(repo TimeEntryRepository) TimeTrackingService {
+	return &timeTrackingService{repo: repo, now: time.Now}
+}
+
+// StartTimer starts a running time entry. A user can have at most one running
+// timer across all tasks; the repository reports ErrTimerAlreadyRunning
+// otherwise.
+func (s *timeTrackingService) StartTimer(ctx context.Context, taskID uint64, input StartTimerInput) (TimeEntry, error) {
+	if err := validateTaskID(taskID); err != nil {
+		return TimeEntry{}, err
+	}
+	userID, err := normalizeUserID(input.UserID)
+	if err != nil {
+		return TimeEntry{}, err
+	}
+	note, err := normalizeTimeEntryNote(input.Note)
+	if err != nil {
+		return TimeEntry{}, err
+	}
+
+	return s.repo.CreateTimeEntry(ctx, TimeEntryParams{
+		TaskID:    taskID,
+		UserID:    userID,
+		StartedAt: s.now().UTC().Truncate(time.Second),
+		Note:      note,
+	})
+}
+
+func (s *timeTrackingService) StopTimer(ctx context.Context, taskID uint64, userID string) (TimeEntry, error) {
+	if err := validateTaskID(taskID); err != nil {
+		return TimeEntry{}, err
+	}
+	userID, err := normalizeUserID(userID)
+	if err != nil {
+		return TimeEntry{}, err
+	}
+
+	return s.repo.StopTimer(ctx, taskID, userID, s.now().UTC().Truncate(time.Second))
+}
+
+// LogTime records a finished time entry. The entry is described either by
+// started_at and ended_at, or by a duration that ends at ended_at, starts at
+// started_at, or ends now when neither is given.
+func (s *timeTrackingService) LogTime(ctx context.Context, taskID uint64, input LogTimeInput) (TimeEntry, error) {
+	if err := validateTaskID(taskID); err != nil {
+		return TimeEntry{}, err
+	}
+	userID, err := normalizeUserID(input.UserID)
+	if err != nil {
+		return TimeEntry{}, err
+	}
+	note, err := normalizeTimeEntryNote(input.Note)
+	if err != nil {
+		return TimeEntry{}, err
+	}
+
+	now := s.now().UTC()
+	if input.DurationMinutes < 0 {
+		return TimeEntry{}, ValidationError{Field: "duration_minutes", Message: "must be greater than 0"}
+	}
+
+	var startedAt, endedAt time.Time
+	switch {
+	case input.DurationMinutes > 0 && input.StartedAt != nil && input.EndedAt != nil:
+		return TimeEntry{}, ValidationError{Field: "duration_minutes", Message: "cannot be combined with both started_at and ended_at"}
+	case input.DurationMinutes > 0:
+		duration := time.Duration(input.DurationMinutes) * time.Minute
+		switch {
+		case input.StartedAt != nil:
+			startedAt = input.StartedAt.UTC()
+			endedAt = startedAt.Add(duration)
+		case input.EndedAt != nil:
+			endedAt = input.EndedAt.UTC()
+			startedAt = endedAt.Add(-duration)
+		default:
+			endedAt = now
+			startedAt = now.Add(-duration)
+		}
+	case input.StartedAt != nil && input.EndedAt != nil:
+		startedAt = input.StartedAt.UTC()
+		endedAt = input.EndedAt.UTC()
+	default:
+		return TimeEntry{}, ValidationError{Field: "body", Message: "either started_at and ended_at or duration_minutes must be provided"}
+	}
+
+	startedAt = startedAt.Truncate(time.Second)
+	endedAt = endedAt.Truncate(time.Second)
+
+	if !endedAt.After(startedAt) {
+		return TimeEntry{}, ValidationError{Field: "ended_at", Message: "must be after started_at"}
+	}
+	if endedAt.Sub(startedAt) > maxTimeEntryDuration {
+		return TimeEntry{}, ValidationError{Field: "duration_minutes", Message: "a single time entry must be at most 24 hours"}
+	}
+	if endedAt.After(now) {
+		return TimeEntry{}, ValidationError{Field: "ended_at", Message: "must not be in the future"}
+	}
+
+	return s.repo.CreateTimeEntry(ctx, TimeEntryParams{
+		TaskID:    taskID,
+		UserID:    userID,
+		StartedAt: startedAt,
+		EndedAt:   &endedAt,
+		Note:      note,
+	})
+}
+
+func (s *timeTrackingService) ListTimeEntries(ctx context.Context, taskID uint64) ([]TimeEntry, error) {
+	if err := validateTaskID(taskID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListTimeEntries(ctx, taskID)
+}
+
+func (s *timeTrackingService) DeleteTimeEntry(ctx context.Context, taskID, entryID uint64, userID string) error {
+	if err := validateTaskID(taskID); err != nil {
+		return err
+	}
+	if entryID == 0 {
+		return ValidationError{Field: "entry_id", Message: "must be greater than 0"}
+	}
+	userID, err := normalizeUserID(userID)
+	if err != nil {
+		return err
+	}
+	return s.repo.DeleteTimeEntry(ctx, taskID, entryID, userID)
+}
+
+func (s *timeTrackingService) Report(ctx context.Context, input TimeReportInput) ([]UserTimeReport, error) {
+	filter := TimeReportFilter{}
+
+	if strings.TrimSpace(input.UserID) != "" {
+		userID, err := normalizeUserID(input.UserID)
+		if err != nil {
+			return nil, err
+		}
+		filter.UserID = userID
+	}
+
+	if input.From != nil {
+		from := input.From.UTC()
+		filter.From = &from
+	}
+	if input.To != nil {
+		to := input.To.UTC()
+		filter.To = &to
+	}
+	if filter.From != nil && filter.To != nil && !filter.To.After(*filter.From) {
+		return nil, ValidationError{Field: "to", Message: "must be after from"}
+	}
+
+	return s.repo.TimeReport(ctx, filter)
+}
+
+func validateTaskID(id uint64) error {
+	if id == 0 {
+		return ValidationError{Field: "id", Message: "must be greater than 0"}
+	}
+	return nil
+}
+
+func normalizeUserID(raw string) (string, error) {
+	parsed, err := uuid.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return "", ValidationError{Field: "user_id", Message: "must be a valid UUID"}
+	}
+	return parsed.String(), nil
+}
+
+func normalizeTimeEntryNote(raw string) (string, error) {
+	note := strings.TrimSpace(raw)
+	if len(note) > maxTimeEntryNoteLength {
+		return "", ValidationError{Field: "note", Message: "must be at most 1000 characters"}
+	}
+	return note, nil
+}
@@ -0,0 +1,166 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+const testUserID = "7d3f9a52-7c1e-4a59-9b7e-0f6f3c2a1b10"
+
+type mockTimeEntryRepository struct {
+	createParams TimeEntryParams
+	createCalled bool
+	createErr    error
+
+	stopEndedAt  time.Time
+	reportFilter TimeReportFilter
+}
+
+func (m *mockTimeEntryRepository) CreateTimeEntry(_ context.Context, params TimeEntryParams) (TimeEntry, error) {
+	m.createCalled = true
+	m.createParams = params
+	if m.createErr != nil {
+		return TimeEntry{}, m.createErr
+	}
+	return TimeEntry{ID: 1, TaskID: params.TaskID, UserID: params.UserID}, nil
+}
+
+func (m *mockTimeEntryRepository) StopTimer(_ context.Context, taskID uint64, userID string, endedAt time.Time) (TimeEntry, error) {
+	m.stopEndedAt = endedAt
+	return TimeEntry{ID: 1, TaskID: taskID, UserID: userID, EndedAt: &endedAt}, nil
+}
+
+func (m *mockTimeEntryRepository) ListTimeEntries(_ context.Context, _ uint64) ([]TimeEntry, error) {
+	return nil, nil
+}
+
+func (m *mockTimeEntryRepository) DeleteTimeEntry(_ context.Context, _, _ uint64, _ string) error {
+	return nil
+}
+
+func (m *mockTimeEntryRepository) TimeReport(_ context.Context, filter TimeReportFilter) ([]UserTimeReport, error) {
+	m.reportFilter = filter
+	return nil, nil
+}
+
+func newTestTimeTrackingService(repo TimeEntryRepository, now time.Time) TimeTrackingService {
+	return &timeTrackingService{repo: repo, now: func() time.Time { return now }}
+}
+
+func TestTimeTrackingServiceStartTimer(t *testing.T) {
+	now := time.Date(2026, 3, 4, 10, 0, 0, 500, time.UTC)
+	repo := &mockTimeEntryRepository{}
+	svc := newTestTimeTrackingService(repo, now)
+
+	_, err := svc.StartTimer(context.Background(), 3, StartTimerInput{UserID: "not-a-uuid"})
+	var verr ValidationError
+	if !errors.As(err, &verr) || verr.Field != "user_id" {
+		t.Fatalf("expected user_id ValidationError, got %v", err)
+	}
+
+	if _, err := svc.StartTimer(context.Background(), 3, StartTimerInput{UserID: testUserID, Note: " review "}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if repo.createParams.EndedAt != nil {
+		t.Fatal("expected a running entry without ended_at")
+	}
+	if !repo.createParams.StartedAt.Equal(now.Truncate(time.Second)) || repo.createParams.Note != "review" {
+		t.Fatalf("unexpected create params: %+v", repo.createParams)
+	}
+
+	repo.createErr = ErrTimerAlreadyRunning
+	if _, err := svc.StartTimer(context.Background(), 3, StartTimerInput{UserID: testUserID}); !errors.Is(err, ErrTimerAlreadyRunning) {
+		t.Fatalf("expected ErrTimerAlreadyRunning, got %v", err)
+	}
+}
+
+func TestTimeTrackingServiceLogTime(t *testing.T) {
+	now := time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)
+	hourAgo := now.Add(-time.Hour)
+	later := now.Add(time.Hour)
+	dayAgo := now.Add(-25 * time.Hour)
+
+	tests := []struct {
+		name      string
+		input     LogTimeInput
+		field     string
+		wantStart time.Time
+		wantEnd   time.Time
+	}{
+		{
+			name:      "duration ending now",
+			input:     LogTimeInput{UserID: testUserID, DurationMinutes: 30},
+			wantStart: now.Add(-30 * time.Minute),
+			wantEnd:   now,
+		},
+		{
+			name:      "duration from start",
+			input:     LogTimeInput{UserID: testUserID, StartedAt: &hourAgo, DurationMinutes: 15},
+			wantStart: hourAgo,
+			wantEnd:   hourAgo.Add(15 * time.Minute),
+		},
+		{
+			name:      "start and end",
+			input:     LogTimeInput{UserID: testUserID, StartedAt: &hourAgo, EndedAt: &now},
+			wantStart: hourAgo,
+			wantEnd:   now,
+		},
+		{name: "nothing provided", input: LogTimeInput{UserID: testUserID}, field: "body"},
+		{name: "end before start", input: LogTimeInput{UserID: testUserID, StartedAt: &now, EndedAt: &hourAgo}, field: "ended_at"},
+		{name: "in the future", input: LogTimeInput{UserID: testUserID, StartedAt: &now, EndedAt: &later}, field: "ended_at"},
+		{name: "too long", input: LogTimeInput{UserID: testUserID, StartedAt: &dayAgo, EndedAt: &now}, field: "duration_minutes"},
+		{name: "negative duration", input: LogTimeInput{UserID: testUserID, DurationMinutes: -5}, field: "duration_minutes"},
+		{
+			name:  "over-specified",
+			input: LogTimeInput{UserID: testUserID, StartedAt: &hourAgo, EndedAt: &now, DurationMinutes: 5},
+			field: "duration_minutes",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockTimeEntryRepository{}
+			svc := newTestTimeTrackingService(repo, now)
+
+			_, err := svc.LogTime(context.Background(), 1, tc.input)
+			if tc.field != "" {
+				var verr ValidationError
+				if !errors.As(err, &verr) || verr.Field != tc.field {
+					t.Fatalf("expected %s ValidationError, got %v", tc.field, err)
+				}
+				if repo.createCalled {
+					t.Fatal("repository should not be called on validation errors")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if !repo.createParams.StartedAt.Equal(tc.wantStart) || repo.createParams.EndedAt == nil || !repo.createParams.EndedAt.Equal(tc.wantEnd) {
+				t.Fatalf("unexpected interval: %v - %v", repo.createParams.StartedAt, repo.createParams.EndedAt)
+			}
+		})
+	}
+}
+
+func TestTimeTrackingServiceReport(t *testing.T) {
+	repo := &mockTimeEntryRepository{}
+	svc := newTestTimeTrackingService(repo, time.Now())
+
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	if _, err := svc.Report(context.Background(), TimeReportInput{From: &to, To: &from}); err == nil {
+		t.Fatal("expected validation error for inverted range")
+	}
+
+	if _, err := svc.Report(context.Background(), TimeReportInput{UserID: testUserID, From: &from, To: &to}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if repo.reportFilter.UserID != testUserID || !repo.reportFilter.From.Equal(from) || !repo.reportFilter.To.Equal(to) {
+		t.Fatalf("unexpected report filter: %+v", repo.reportFilter)
+	}
+}
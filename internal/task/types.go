@@ -10,6 +10,32 @@ const (
 	StatusDone       Status = "done"
 )
 
+type SortField string
+
+const (
+	SortByCreatedAt       SortField = "created_at"
+	SortByUpdatedAt       SortField = "updated_at"
+	SortByPriority        SortField = "priority"
+	SortByDueAt           SortField = "due_at"
+	SortByEstimateMinutes SortField = "estimate_minutes"
+	SortByStoryPoints     SortField = "story_points"
+	SortByRank            SortField = "rank"
+	SortByRelevance       SortField = "relevance"
+)
+
+type Sort struct {
+	Field SortField
+	Desc  bool
+}
+
+type ArchivedFilter string
+
+const (
+	ArchivedExclude ArchivedFilter = "false"
+	ArchivedOnly    ArchivedFilter = "true"
+	ArchivedAny     ArchivedFilter = "any"
+)
+
 func (s Status) IsValid() bool {
 	switch s {
 	case StatusNew, StatusInProgress, StatusDone:
@@ -20,60 +46,230 @@ func (s Status) IsValid() bool {
 }
 
 type Task struct {
-	ID          uint64     `json:"id"`
-	Title       string     `json:"title"`
-	Description string     `json:"description"`
-	Status      Status     `json:"status"`
-	Priority    uint8      `json:"priority"`
-	DueAt       *time.Time `json:"due_at,omitempty"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
+	ID               uint64            `json:"id"`
+	ProjectID        *uint64           `json:"project_id,omitempty"`
+	SprintID         *uint64           `json:"sprint_id,omitempty"`
+	MilestoneID      *uint64           `json:"milestone_id,omitempty"`
+	ParentID         *uint64           `json:"parent_id,omitempty"`
+	ReporterID       *string           `json:"reporter_id,omitempty"`
+	AssigneeID       *string           `json:"assignee_id,omitempty"`
+	Title            string            `json:"title"`
+	Description      string            `json:"description"`
+	Status           Status            `json:"status"`
+	Priority         uint8             `json:"priority"`
+	Rank             string            `json:"rank"`
+	EstimateMinutes  *uint32           `json:"estimate_minutes,omitempty"`
+	StoryPoints      *uint16           `json:"story_points,omitempty"`
+	Labels           []string          `json:"labels"`
+	CustomFields     map[string]any    `json:"custom_fields,omitempty"`
+	Checklist        ChecklistProgress `json:"checklist"`
+	TimeSpentSeconds int64             `json:"time_spent_seconds"`
+	DueAt            *time.Time        `json:"due_at,omitempty"`
+	RecurrenceRule   string            `json:"recurrence_rule,omitempty"`
+	NextOccurrenceID *uint64           `json:"next_occurrence_id,omitempty"`
+	CreatedAt        time.Time         `json:"created_at"`
+	UpdatedAt        time.Time         `json:"updated_at"`
+	CompletedAt      *time.Time        `json:"completed_at,omitempty"`
+	ArchivedAt       *time.Time        `json:"archived_at,omitempty"`
+	DeletedAt        *time.Time        `json:"deleted_at,omitempty"`
+	Highlight        *SearchHighlight  `json:"highlight,omitempty"`
+	Version          uint64            `json:"version"`
 }
 
 type CreateTaskInput struct {
-	Title       string
-	Description string
-	Status      string
-	Priority    int
-	DueAt       *time.Time
+	ProjectID       *uint64
+	SprintID        *uint64
+	MilestoneID     *uint64
+	ReporterID      string
+	AssigneeID      *string
+	Title           string
+	Description     string
+	Status          string
+	Priority        int
+	EstimateMinutes *int
+	StoryPoints     *int
+	Labels          []string
+	CustomFields    map[string]any
+	DueAt           *time.Time
+	RecurrenceRule  string
 }
 
+// UpdateTaskInput.ActorID is the user making the change; watchers are
+// notified about it except for the actor. Force moves a task into a status
+// column even if that exceeds the column's WIP limit. ExpectedVersion makes
+// the update fail with ErrVersionConflict if the task has changed since.
 type UpdateTaskInput struct {
-	Title       *string
-	Description *string
-	Status      *string
-	Priority    *int
-	DueAt       *time.Time
-	ClearDueAt  bool
+	ActorID              string
+	ProjectID            *uint64
+	ClearProjectID       bool
+	SprintID             *uint64
+	ClearSprintID        bool
+	MilestoneID          *uint64
+	ClearMilestoneID     bool
+	AssigneeID           *string
+	ClearAssigneeID      bool
+	Title                *string
+	Description          *string
+	Status               *string
+	Priority             *int
+	EstimateMinutes      *int
+	ClearEstimateMinutes bool
+	StoryPoints          *int
+	ClearStoryPoints     bool
+	Labels               *[]string
+	CustomFields         map[string]any
+	DueAt                *time.Time
+	ClearDueAt           bool
+	RecurrenceRule       *string
+	ClearRecurrenceRule  bool
+	Force                bool
+	ExpectedVersion      *uint64
+}
+
+// DeleteTaskInput.ExpectedVersion makes the deletion fail with
+// ErrVersionConflict if the task has changed since.
+type DeleteTaskInput struct {
+	ExpectedVersion *uint64
+}
+
+// MoveTaskInput places a task directly after AfterID and/or directly before
+// BeforeID within its status column.
+type MoveTaskInput struct {
+	BeforeID *uint64
+	AfterID  *uint64
 }
 
+type MoveParams struct {
+	BeforeID *uint64
+	AfterID  *uint64
+}
+
+// ListTasksInput.Status is a comma separated list of statuses. The _before
+// and _after bounds are exclusive; Overdue selects open tasks whose due date
+// has passed, or with false excludes them.
 type ListTasksInput struct {
-	Status string
-	Query  string
-	Limit  int
-	Offset int
+	ProjectID          *uint64
+	SprintID           *uint64
+	MilestoneID        *uint64
+	Status             string
+	Query              string
+	SearchMode         string
+	Archived           string
+	IncludeDeleted     bool
+	PriorityMin        *int
+	PriorityMax        *int
+	EstimateMinutesMin *int
+	EstimateMinutesMax *int
+	StoryPointsMin     *int
+	StoryPointsMax     *int
+	DueBefore          *time.Time
+	DueAfter           *time.Time
+	HasDueDate         *bool
+	Overdue            *bool
+	CreatedAfter       *time.Time
+	UpdatedAfter       *time.Time
+	CustomFields       map[string][]string
+	Filter             string
+	Sort               string
+	Cursor             string
+	Limit              int
+	Offset             int
 }
 
+// ListFilter.After continues a keyset pagination; it is only supported with
+// the default order or a sort by created_at and is ignored by Summarize. Now
+// is the reference time of Overdue. Expr is a parsed filter expression that
+// must hold in addition to the other conditions.
 type ListFilter struct {
-	Status *Status
-	Query  string
-	Limit  int
-	Offset int
+	ProjectID          *uint64
+	SprintID           *uint64
+	MilestoneID        *uint64
+	Statuses           []Status
+	Query              string
+	SearchMode         SearchMode
+	Archived           ArchivedFilter
+	IncludeDeleted     bool
+	PriorityMin        *uint8
+	PriorityMax        *uint8
+	EstimateMinutesMin *uint32
+	EstimateMinutesMax *uint32
+	StoryPointsMin     *uint16
+	StoryPointsMax     *uint16
+	DueBefore          *time.Time
+	DueAfter           *time.Time
+	HasDueDate         *bool
+	Overdue            *bool
+	Now                time.Time
+	CreatedAfter       *time.Time
+	UpdatedAfter       *time.Time
+	CustomFields       []CustomFieldFilter
+	Expr               FilterExpr
+	Sort               []Sort
+	After              *Cursor
+	Limit              int
+	Offset             int
+}
+
+// ListSummary aggregates all tasks matching a ListFilter regardless of its
+// limit and offset.
+type ListSummary struct {
+	Count           int64 `json:"count"`
+	EstimateMinutes int64 `json:"estimate_minutes"`
+	StoryPoints     int64 `json:"story_points"`
 }
 
 type CreateParams struct {
-	Title       string
-	Description string
-	Status      Status
-	Priority    uint8
-	DueAt       *time.Time
+	ProjectID       *uint64
+	SprintID        *uint64
+	MilestoneID     *uint64
+	ParentID        *uint64
+	ReporterID      *string
+	AssigneeID      *string
+	Title           string
+	Description     string
+	Status          Status
+	Priority        uint8
+	EstimateMinutes *uint32
+	StoryPoints     *uint16
+	Labels          []string
+	CustomFields    []CustomFieldValue
+	Mentions        []string
+	DueAt           *time.Time
+	RecurrenceRule  string
 }
 
+// UpdateParams.CustomFields replaces the values of the listed fields only;
+// changing or clearing the project removes the values of fields that do not
+// belong to the new project and, unless SprintID is given, a sprint of another
+// project. Mentions are the users mentioned in the new description and ActorID
+// is recorded as the author of those mentions. WIPLimit is enforced only if
+// the status actually changes. ExpectedVersion is compared with the version
+// of the locked task.
 type UpdateParams struct {
-	Title       *string
-	Description *string
-	Status      *Status
-	Priority    *uint8
-	DueAt       *time.Time
-	ClearDueAt  bool
+	ActorID              *string
+	ProjectID            *uint64
+	ClearProjectID       bool
+	SprintID             *uint64
+	ClearSprintID        bool
+	MilestoneID          *uint64
+	ClearMilestoneID     bool
+	AssigneeID           *string
+	ClearAssigneeID      bool
+	Title                *string
+	Description          *string
+	Status               *Status
+	Priority             *uint8
+	EstimateMinutes      *uint32
+	ClearEstimateMinutes bool
+	StoryPoints          *uint16
+	ClearStoryPoints     bool
+	Labels               *[]string
+	CustomFields         []CustomFieldValue
+	Mentions             []string
+	DueAt                *time.Time
+	ClearDueAt           bool
+	RecurrenceRule       *string
+	ClearRecurrenceRule  bool
+	WIPLimit             *WIPLimitCheck
+	ExpectedVersion      *uint64
 }
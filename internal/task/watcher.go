@@ -0,0 +1,232 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/notification"
+)
+
+// Watcher is a user who receives notifications about changes of a task. The
+// reporter, the assignee and every commenter watch a task automatically.
+type Watcher struct {
+	TaskID    uint64    `json:"task_id"`
+	UserID    string    `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type WatcherRepository interface {
+	ListWatchers(ctx context.Context, taskID uint64) ([]Watcher, error)
+	AddWatcher(ctx context.Context, taskID uint64, userID string) error
+	RemoveWatcher(ctx context.Context, taskID uint64, userID string) error
+}
+
+type watcherLister interface {
+	ListWatchers(ctx context.Context, taskID uint64) ([]Watcher, error)
+}
+
+type WatcherService interface {
+	List(ctx context.Context, taskID uint64) ([]Watcher, error)
+	Watch(ctx context.Context, taskID uint64, userID string) ([]Watcher, error)
+	Unwatch(ctx context.Context, taskID uint64, userID string) error
+}
+
+type watcherService struct {
+	repo WatcherRepository
+}
+
+func NewWatcherService(repo WatcherRepository) WatcherService {
+	return &watcherService{repo: repo}
+}
+
+func (s *watcherService) List(ctx context.Context, taskID uint64) ([]Watcher, error) {
+	if err := validateTaskID(taskID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListWatchers(ctx, taskID)
+}
+
+// Watch is idempotent: watching a task twice keeps a single watcher.
+func (s *watcherService) Watch(ctx context.Context, taskID uint64, userID string) ([]Watcher, error) {
+	if err := validateTaskID(taskID); err != nil {
+		return nil, err
+	}
+	userID, err := normalizeUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.AddWatcher(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListWatchers(ctx, taskID)
+}
+
+func (s *watcherService) Unwatch(ctx context.Context, taskID uint64, userID string) error {
+	if err := validateTaskID(taskID); err != nil {
+		return err
+	}
+	userID, err := normalizeUserID(userID)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.RemoveWatcher(ctx, taskID, userID)
+}
+
+// notifyWatchers sends a notification to the watchers of a task except the
+// user who caused it. Delivery is best effort: the change is already stored
+// and a failed notification must not fail the request.
+func notifyWatchers(ctx context.Context, repo watcherLister, notifier notification.Notifier, actorID string, n notification.Notification) {
+	if notifier == nil {
+		return
+	}
+
+	watchers, err := repo.ListWatchers(ctx, n.TaskID)
+	if err != nil {
+		return
+	}
+
+	for _, watcher := range watchers {
+		if watcher.UserID != actorID {
+			n.Recipients = append(n.Recipients, watcher.UserID)
+		}
+	}
+	if len(n.Recipients) == 0 {
+		return
+	}
+
+	n.CreatedAt = time.Now().UTC()
+	notify(ctx, notifier, n)
+}
+
+type pendingNotificationsKey struct{}
+
+// pendingNotifications holds back the notifications of changes made in a
+// transaction until it is committed.
+type pendingNotifications struct {
+	notifiers     []notification.Notifier
+	notifications []notification.Notification
+}
+
+// withPendingNotifications makes notify collect the notifications sent with
+// the returned context in pending instead of sending them.
+func withPendingNotifications(ctx context.Context, pending *pendingNotifications) context.Context {
+	return context.WithValue(ctx, pendingNotificationsKey{}, pending)
+}
+
+func (p *pendingNotifications) send(ctx context.Context) {
+	for i, n := range p.notifications {
+		_ = p.notifiers[i].Notify(ctx, n)
+	}
+}
+
+func notify(ctx context.Context, notifier notification.Notifier, n notification.Notification) {
+	if pending, ok := ctx.Value(pendingNotificationsKey{}).(*pendingNotifications); ok {
+		pending.notifiers = append(pending.notifiers, notifier)
+		pending.notifications = append(pending.notifications, n)
+		return
+	}
+	_ = notifier.Notify(ctx, n)
+}
+
+// describeChanges lists the user visible differences between two versions of
+// a task, e.g. `status: "new" -> "in_progress"`.
+func describeChanges(before, after Task) []string {
+	var changes []string
+	add := func(field string, from, to any) {
+		changes = append(changes, fmt.Sprintf("%s: %s -> %s", field, formatChangeValue(from), formatChangeValue(to)))
+	}
+
+	if before.Title != after.Title {
+		add("title", before.Title, after.Title)
+	}
+	if before.Description != after.Description {
+		changes = append(changes, "description changed")
+	}
+	if before.Status != after.Status {
+		add("status", before.Status, after.Status)
+	}
+	if before.Priority != after.Priority {
+		add("priority", before.Priority, after.Priority)
+	}
+	if !equalPtr(before.AssigneeID, after.AssigneeID) {
+		add("assignee_id", before.AssigneeID, after.AssigneeID)
+	}
+	if !equalPtr(before.ProjectID, after.ProjectID) {
+		add("project_id", before.ProjectID, after.ProjectID)
+	}
+	if !equalPtr(before.EstimateMinutes, after.EstimateMinutes) {
+		add("estimate_minutes", before.EstimateMinutes, after.EstimateMinutes)
+	}
+	if !equalPtr(before.StoryPoints, after.StoryPoints) {
+		add("story_points", before.StoryPoints, after.StoryPoints)
+	}
+	if !equalTimePtr(before.DueAt, after.DueAt) {
+		add("due_at", before.DueAt, after.DueAt)
+	}
+	if !slices.Equal(before.Labels, after.Labels) {
+		add("labels", strings.Join(before.Labels, ", "), strings.Join(after.Labels, ", "))
+	}
+	if before.RecurrenceRule != after.RecurrenceRule {
+		add("recurrence_rule", before.RecurrenceRule, after.RecurrenceRule)
+	}
+
+	return changes
+}
+
+func formatChangeValue(value any) string {
+	switch v := value.(type) {
+	case *string:
+		if v == nil {
+			return "none"
+		}
+		return fmt.Sprintf("%q", *v)
+	case *uint64:
+		if v == nil {
+			return "none"
+		}
+		return fmt.Sprint(*v)
+	case *uint32:
+		if v == nil {
+			return "none"
+		}
+		return fmt.Sprint(*v)
+	case *uint16:
+		if v == nil {
+			return "none"
+		}
+		return fmt.Sprint(*v)
+	case *time.Time:
+		if v == nil {
+			return "none"
+		}
+		return v.UTC().Format(time.RFC3339)
+	case string:
+		if v == "" {
+			return "none"
+		}
+		return fmt.Sprintf("%q", v)
+	case Status:
+		return fmt.Sprintf("%q", string(v))
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func equalPtr[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func equalTimePtr(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
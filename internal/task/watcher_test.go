@@ -0,0 +1,216 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/PavelFesenkoFirst/task_tracker/internal/notification"
+)
+
+const (
+	testReporterID = "7d3f9a52-7c1e-4a59-9b7e-0f6f3c2a1b10"
+	testAssigneeID = "0c9b8a27-54d1-4e1f-9a0e-3c7f2d5b6e41"
+)
+
+type mockWatcherRepository struct {
+	added    string
+	removed  string
+	watchers []Watcher
+}
+
+func (m *mockWatcherRepository) ListWatchers(_ context.Context, _ uint64) ([]Watcher, error) {
+	return m.watchers, nil
+}
+
+func (m *mockWatcherRepository) AddWatcher(_ context.Context, taskID uint64, userID string) error {
+	m.added = userID
+	m.watchers = append(m.watchers, Watcher{TaskID: taskID, UserID: userID})
+	return nil
+}
+
+func (m *mockWatcherRepository) RemoveWatcher(_ context.Context, _ uint64, userID string) error {
+	m.removed = userID
+	return nil
+}
+
+type mockCommentRepository struct {
+	params   CommentParams
+	watchers []Watcher
+	users    []User
+}
+
+func (m *mockCommentRepository) CreateComment(_ context.Context, params CommentParams) (Comment, error) {
+	m.params = params
+	return Comment{ID: 1, TaskID: params.TaskID, AuthorID: params.AuthorID, Body: params.Body}, nil
+}
+
+func (m *mockCommentRepository) ListComments(_ context.Context, _ uint64) ([]Comment, error) {
+	return []Comment{}, nil
+}
+
+func (m *mockCommentRepository) ListWatchers(_ context.Context, _ uint64) ([]Watcher, error) {
+	return m.watchers, nil
+}
+
+func (m *mockCommentRepository) FindUsersByUsername(_ context.Context, _ []string) ([]User, error) {
+	return m.users, nil
+}
+
+func TestServiceCreate_ReporterAndAssignee(t *testing.T) {
+	repo := &mockRepository{}
+	svc := NewService(repo)
+
+	assigneeID := strings.ToUpper(testAssigneeID)
+	_, err := svc.Create(context.Background(), CreateTaskInput{
+		Title:      "Task",
+		ReporterID: testReporterID,
+		AssigneeID: &assigneeID,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if repo.createParams.ReporterID == nil || *repo.createParams.ReporterID != testReporterID {
+		t.Fatalf("unexpected reporter: %v", repo.createParams.ReporterID)
+	}
+	if repo.createParams.AssigneeID == nil || *repo.createParams.AssigneeID != testAssigneeID {
+		t.Fatalf("expected normalized assignee, got %v", repo.createParams.AssigneeID)
+	}
+
+	invalid := "bob"
+	_, err = svc.Create(context.Background(), CreateTaskInput{Title: "Task", AssigneeID: &invalid})
+	var validationErr ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Field != "assignee_id" {
+		t.Fatalf("expected assignee_id validation error, got %v", err)
+	}
+}
+
+func TestServiceUpdate_NotifiesWatchersExceptActor(t *testing.T) {
+	repo := &mockRepository{
+		getResult:    Task{ID: 7, Title: "Deploy", Status: StatusNew, Priority: 3},
+		updateResult: Task{ID: 7, Title: "Deploy", Status: StatusInProgress, Priority: 3},
+		watchers: []Watcher{
+			{TaskID: 7, UserID: testReporterID},
+			{TaskID: 7, UserID: testAssigneeID},
+		},
+	}
+	notifier := &mockNotifier{}
+	svc := NewService(repo, WithNotifier(notifier))
+
+	status := string(StatusInProgress)
+	_, err := svc.Update(context.Background(), 7, UpdateTaskInput{ActorID: testReporterID, Status: &status})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(notifier.notifications) != 1 {
+		t.Fatalf("expected one notification, got %d", len(notifier.notifications))
+	}
+	sent := notifier.notifications[0]
+	if sent.Type != notification.TypeTaskUpdated || sent.TaskID != 7 {
+		t.Fatalf("unexpected notification: %+v", sent)
+	}
+	if len(sent.Recipients) != 1 || sent.Recipients[0] != testAssigneeID {
+		t.Fatalf("expected only the assignee as recipient, got %v", sent.Recipients)
+	}
+	if sent.Message != `status: "new" -> "in_progress"` {
+		t.Fatalf("unexpected message: %q", sent.Message)
+	}
+}
+
+func TestServiceUpdate_NoNotificationWithoutChanges(t *testing.T) {
+	unchanged := Task{ID: 7, Title: "Deploy", Status: StatusNew, Priority: 3}
+	repo := &mockRepository{
+		getResult:    unchanged,
+		updateResult: unchanged,
+		watchers:     []Watcher{{TaskID: 7, UserID: testAssigneeID}},
+	}
+	notifier := &mockNotifier{}
+	svc := NewService(repo, WithNotifier(notifier))
+
+	title := "Deploy"
+	if _, err := svc.Update(context.Background(), 7, UpdateTaskInput{Title: &title}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(notifier.notifications) != 0 {
+		t.Fatalf("expected no notifications, got %+v", notifier.notifications)
+	}
+}
+
+func TestServiceUpdate_ClearAssigneeConflict(t *testing.T) {
+	assigneeID := testAssigneeID
+	_, err := NewService(&mockRepository{}).Update(context.Background(), 1, UpdateTaskInput{
+		AssigneeID:      &assigneeID,
+		ClearAssigneeID: true,
+	})
+	var validationErr ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Field != "assignee_id" {
+		t.Fatalf("expected assignee_id validation error, got %v", err)
+	}
+}
+
+func TestWatcherServiceWatch(t *testing.T) {
+	repo := &mockWatcherRepository{}
+	svc := NewWatcherService(repo)
+
+	watchers, err := svc.Watch(context.Background(), 3, " "+strings.ToUpper(testReporterID)+" ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.added != testReporterID || len(watchers) != 1 {
+		t.Fatalf("unexpected watch: added=%q watchers=%v", repo.added, watchers)
+	}
+
+	if err := svc.Unwatch(context.Background(), 3, "nobody"); err == nil {
+		t.Fatal("expected validation error for invalid user id")
+	}
+}
+
+func TestCommentServiceAdd_NotifiesOtherWatchers(t *testing.T) {
+	repo := &mockCommentRepository{watchers: []Watcher{
+		{TaskID: 4, UserID: testReporterID},
+		{TaskID: 4, UserID: testAssigneeID},
+	}}
+	notifier := &mockNotifier{}
+	svc := NewCommentService(repo, notifier)
+
+	comment, err := svc.Add(context.Background(), 4, AddCommentInput{AuthorID: testAssigneeID, Body: "  Done on staging  "})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if comment.Body != "Done on staging" || repo.params.AuthorID != testAssigneeID {
+		t.Fatalf("unexpected comment: %+v", comment)
+	}
+
+	if len(notifier.notifications) != 1 {
+		t.Fatalf("expected one notification, got %d", len(notifier.notifications))
+	}
+	sent := notifier.notifications[0]
+	if sent.Type != notification.TypeTaskCommented || len(sent.Recipients) != 1 || sent.Recipients[0] != testReporterID {
+		t.Fatalf("unexpected notification: %+v", sent)
+	}
+
+	_, err = svc.Add(context.Background(), 4, AddCommentInput{AuthorID: testAssigneeID, Body: "   "})
+	var validationErr ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Field != "body" {
+		t.Fatalf("expected body validation error, got %v", err)
+	}
+}
+
+func TestDescribeChanges(t *testing.T) {
+	assigneeID := testAssigneeID
+	before := Task{Title: "A", Status: StatusNew, Priority: 3, Labels: []string{"bug"}}
+	after := Task{Title: "B", Status: StatusNew, Priority: 3, Labels: []string{"bug", "ops"}, AssigneeID: &assigneeID}
+
+	changes := describeChanges(before, after)
+	want := []string{
+		`title: "A" -> "B"`,
+		`assignee_id: none -> "` + testAssigneeID + `"`,
+		`labels: "bug" -> "bug, ops"`,
+	}
+	if strings.Join(changes, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected changes:\n%s", strings.Join(changes, "\n"))
+	}
+}
@@ -9,7 +9,7 @@ import (
 
 type User struct {
 	ID           uuid.UUID      `gorm:"type:char(36);primaryKey" json:"id"`
-	Username     string         `gorm:"size:255" json:"username"`
+	Username     string         `gorm:"size:255;unique;not null" json:"username"`
 	Email        string         `gorm:"unique;not null" json:"email"`
 	PasswordHash string         `gorm:"not null" json:"-"`
 	CreatedAt    time.Time      `json:"created_at"`
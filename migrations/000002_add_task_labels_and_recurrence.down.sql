@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS task_labels;
+
+ALTER TABLE tasks
+    DROP FOREIGN KEY fk_tasks_next_occurrence,
+    DROP COLUMN next_occurrence_id,
+    DROP COLUMN recurrence_rule;
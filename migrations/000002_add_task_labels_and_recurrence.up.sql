@@ -0,0 +1,12 @@
+ALTER TABLE tasks
+    ADD COLUMN recurrence_rule VARCHAR(255) NULL AFTER due_at,
+    ADD COLUMN next_occurrence_id BIGINT UNSIGNED NULL AFTER recurrence_rule,
+    ADD CONSTRAINT fk_tasks_next_occurrence FOREIGN KEY (next_occurrence_id) REFERENCES tasks (id) ON DELETE SET NULL;
+
+CREATE TABLE IF NOT EXISTS task_labels (
+    task_id BIGINT UNSIGNED NOT NULL,
+    label VARCHAR(64) NOT NULL,
+    PRIMARY KEY (task_id, label),
+    INDEX idx_task_labels_label (label),
+    CONSTRAINT fk_task_labels_task FOREIGN KEY (task_id) REFERENCES tasks (id) ON DELETE CASCADE
+);
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS task_reminders (
+    task_id BIGINT UNSIGNED NOT NULL,
+    threshold VARCHAR(32) NOT NULL,
+    due_at DATETIME NOT NULL,
+    sent_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (task_id, threshold, due_at),
+    CONSTRAINT fk_task_reminders_task FOREIGN KEY (task_id) REFERENCES tasks (id) ON DELETE CASCADE
+);
@@ -0,0 +1,3 @@
+ALTER TABLE tasks
+    DROP INDEX idx_tasks_deleted_at,
+    DROP COLUMN deleted_at;
@@ -0,0 +1,3 @@
+ALTER TABLE tasks
+    ADD COLUMN deleted_at DATETIME NULL AFTER updated_at,
+    ADD INDEX idx_tasks_deleted_at (deleted_at);
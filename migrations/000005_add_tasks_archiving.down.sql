@@ -0,0 +1,5 @@
+ALTER TABLE tasks
+    DROP INDEX idx_tasks_archived_at,
+    DROP INDEX idx_tasks_completed_at,
+    DROP COLUMN archived_at,
+    DROP COLUMN completed_at;
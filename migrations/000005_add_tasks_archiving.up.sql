@@ -0,0 +1,7 @@
+ALTER TABLE tasks
+    ADD COLUMN completed_at DATETIME NULL AFTER updated_at,
+    ADD COLUMN archived_at DATETIME NULL AFTER completed_at,
+    ADD INDEX idx_tasks_completed_at (completed_at),
+    ADD INDEX idx_tasks_archived_at (archived_at);
+
+UPDATE tasks SET completed_at = updated_at WHERE status = 'done';
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS time_entries (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    task_id BIGINT UNSIGNED NOT NULL,
+    user_id CHAR(36) NOT NULL,
+    started_at DATETIME NOT NULL,
+    ended_at DATETIME NULL,
+    duration_seconds INT UNSIGNED NULL,
+    note VARCHAR(1000) NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    running_user_id CHAR(36) GENERATED ALWAYS AS (IF(ended_at IS NULL, user_id, NULL)) STORED,
+    PRIMARY KEY (id),
+    UNIQUE KEY uq_time_entries_running_user (running_user_id),
+    INDEX idx_time_entries_task (task_id, started_at),
+    INDEX idx_time_entries_user (user_id, started_at),
+    CONSTRAINT fk_time_entries_task FOREIGN KEY (task_id) REFERENCES tasks (id) ON DELETE CASCADE
+);
@@ -0,0 +1,5 @@
+ALTER TABLE tasks
+    DROP INDEX idx_tasks_story_points,
+    DROP INDEX idx_tasks_estimate_minutes,
+    DROP COLUMN story_points,
+    DROP COLUMN estimate_minutes;
@@ -0,0 +1,5 @@
+ALTER TABLE tasks
+    ADD COLUMN estimate_minutes INT UNSIGNED NULL AFTER priority,
+    ADD COLUMN story_points SMALLINT UNSIGNED NULL AFTER estimate_minutes,
+    ADD INDEX idx_tasks_estimate_minutes (estimate_minutes),
+    ADD INDEX idx_tasks_story_points (story_points);
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS task_custom_field_values;
+DROP TABLE IF EXISTS project_custom_fields;
+
+ALTER TABLE tasks
+    DROP FOREIGN KEY fk_tasks_project,
+    DROP INDEX idx_tasks_project_id,
+    DROP COLUMN project_id;
+
+DROP TABLE IF EXISTS projects;
@@ -0,0 +1,39 @@
+CREATE TABLE IF NOT EXISTS projects (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    name VARCHAR(255) NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    UNIQUE KEY uq_projects_name (name)
+);
+
+ALTER TABLE tasks
+    ADD COLUMN project_id BIGINT UNSIGNED NULL AFTER id,
+    ADD INDEX idx_tasks_project_id (project_id),
+    ADD CONSTRAINT fk_tasks_project FOREIGN KEY (project_id) REFERENCES projects (id) ON DELETE SET NULL;
+
+CREATE TABLE IF NOT EXISTS project_custom_fields (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    project_id BIGINT UNSIGNED NOT NULL,
+    name VARCHAR(64) NOT NULL,
+    type ENUM('text', 'number', 'date', 'single_select', 'multi_select', 'user') NOT NULL,
+    required BOOLEAN NOT NULL DEFAULT FALSE,
+    options JSON NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    UNIQUE KEY uq_project_custom_fields_name (project_id, name),
+    INDEX idx_project_custom_fields_name (name),
+    CONSTRAINT fk_project_custom_fields_project FOREIGN KEY (project_id) REFERENCES projects (id) ON DELETE CASCADE
+);
+
+-- Values are stored in their canonical string form, one row per value so that
+-- multi-select fields can be filtered with the same index.
+CREATE TABLE IF NOT EXISTS task_custom_field_values (
+    task_id BIGINT UNSIGNED NOT NULL,
+    field_id BIGINT UNSIGNED NOT NULL,
+    value VARCHAR(255) NOT NULL,
+    PRIMARY KEY (task_id, field_id, value),
+    INDEX idx_task_custom_field_values_value (field_id, value),
+    CONSTRAINT fk_task_custom_field_values_task FOREIGN KEY (task_id) REFERENCES tasks (id) ON DELETE CASCADE,
+    CONSTRAINT fk_task_custom_field_values_field FOREIGN KEY (field_id) REFERENCES project_custom_fields (id) ON DELETE CASCADE
+);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS task_checklist_items (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    task_id BIGINT UNSIGNED NOT NULL,
+    position INT UNSIGNED NOT NULL,
+    text VARCHAR(500) NOT NULL,
+    checked_at DATETIME NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    INDEX idx_task_checklist_items_task (task_id, position),
+    CONSTRAINT fk_task_checklist_items_task FOREIGN KEY (task_id) REFERENCES tasks (id) ON DELETE CASCADE
+);
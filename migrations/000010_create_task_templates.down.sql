@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS task_templates;
+
+ALTER TABLE tasks
+    DROP FOREIGN KEY fk_tasks_parent,
+    DROP INDEX idx_tasks_parent_id,
+    DROP COLUMN parent_id;
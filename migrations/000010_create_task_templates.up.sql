@@ -0,0 +1,14 @@
+ALTER TABLE tasks
+    ADD COLUMN parent_id BIGINT UNSIGNED NULL AFTER project_id,
+    ADD INDEX idx_tasks_parent_id (parent_id),
+    ADD CONSTRAINT fk_tasks_parent FOREIGN KEY (parent_id) REFERENCES tasks (id) ON DELETE SET NULL;
+
+CREATE TABLE IF NOT EXISTS task_templates (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    name VARCHAR(255) NOT NULL,
+    definition JSON NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    UNIQUE KEY uq_task_templates_name (name)
+);
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS task_comments;
+DROP TABLE IF EXISTS task_watchers;
+
+ALTER TABLE tasks
+    DROP INDEX idx_tasks_assignee_id,
+    DROP COLUMN assignee_id,
+    DROP COLUMN reporter_id;
@@ -0,0 +1,24 @@
+ALTER TABLE tasks
+    ADD COLUMN reporter_id CHAR(36) NULL AFTER parent_id,
+    ADD COLUMN assignee_id CHAR(36) NULL AFTER reporter_id,
+    ADD INDEX idx_tasks_assignee_id (assignee_id);
+
+CREATE TABLE IF NOT EXISTS task_watchers (
+    task_id BIGINT UNSIGNED NOT NULL,
+    user_id CHAR(36) NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (task_id, user_id),
+    INDEX idx_task_watchers_user (user_id),
+    CONSTRAINT fk_task_watchers_task FOREIGN KEY (task_id) REFERENCES tasks (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS task_comments (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    task_id BIGINT UNSIGNED NOT NULL,
+    author_id CHAR(36) NOT NULL,
+    body TEXT NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    INDEX idx_task_comments_task (task_id, created_at),
+    CONSTRAINT fk_task_comments_task FOREIGN KEY (task_id) REFERENCES tasks (id) ON DELETE CASCADE
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS task_mentions;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS users (
+    id CHAR(36) NOT NULL,
+    username VARCHAR(255) NOT NULL,
+    deleted_at DATETIME NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY uq_users_username (username),
+    INDEX idx_users_deleted_at (deleted_at)
+);
+
+CREATE TABLE IF NOT EXISTS task_mentions (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    task_id BIGINT UNSIGNED NOT NULL,
+    comment_id BIGINT UNSIGNED NULL,
+    user_id CHAR(36) NOT NULL,
+    author_id CHAR(36) NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    comment_key BIGINT UNSIGNED GENERATED ALWAYS AS (COALESCE(comment_id, 0)) STORED,
+    PRIMARY KEY (id),
+    UNIQUE KEY uq_task_mentions_source_user (task_id, comment_key, user_id),
+    INDEX idx_task_mentions_user (user_id, created_at),
+    CONSTRAINT fk_task_mentions_task FOREIGN KEY (task_id) REFERENCES tasks (id) ON DELETE CASCADE,
+    CONSTRAINT fk_task_mentions_comment FOREIGN KEY (comment_id) REFERENCES task_comments (id) ON DELETE CASCADE
+);
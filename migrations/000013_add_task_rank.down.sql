@@ -0,0 +1,3 @@
+ALTER TABLE tasks
+    DROP INDEX idx_tasks_status_board_rank,
+    DROP COLUMN board_rank;
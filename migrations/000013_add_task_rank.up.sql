@@ -0,0 +1,12 @@
+ALTER TABLE tasks
+    ADD COLUMN board_rank VARCHAR(128) CHARACTER SET ascii COLLATE ascii_bin NOT NULL DEFAULT '' AFTER priority;
+
+UPDATE tasks
+JOIN (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY status ORDER BY created_at, id) AS position
+    FROM tasks
+) ranked ON ranked.id = tasks.id
+SET tasks.board_rank = CONCAT(LPAD(LOWER(CONV(ranked.position, 10, 36)), 8, '0'), 'i');
+
+ALTER TABLE tasks
+    ADD INDEX idx_tasks_status_board_rank (status, board_rank);
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS project_wip_limits (
+    project_id BIGINT UNSIGNED NOT NULL,
+    status ENUM('new', 'in_progress', 'done') NOT NULL,
+    wip_limit SMALLINT UNSIGNED NOT NULL,
+    PRIMARY KEY (project_id, status),
+    CONSTRAINT fk_project_wip_limits_project FOREIGN KEY (project_id) REFERENCES projects (id) ON DELETE CASCADE
+);
@@ -0,0 +1,35 @@
+-- Committed totals are recorded when a sprint starts, completed and carried
+-- over totals when it closes; active_project_id allows a single active sprint
+-- per project.
+CREATE TABLE IF NOT EXISTS sprints (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    project_id BIGINT UNSIGNED NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    goal TEXT NULL,
+    state ENUM('planned', 'active', 'closed') NOT NULL DEFAULT 'planned',
+    starts_at DATETIME NOT NULL,
+    ends_at DATETIME NOT NULL,
+    committed_tasks INT UNSIGNED NULL,
+    committed_points INT UNSIGNED NULL,
+    completed_tasks INT UNSIGNED NULL,
+    completed_points INT UNSIGNED NULL,
+    carried_over_tasks INT UNSIGNED NULL,
+    carried_over_points INT UNSIGNED NULL,
+    next_sprint_id BIGINT UNSIGNED NULL,
+    active_project_id BIGINT UNSIGNED AS (IF(state = 'active', project_id, NULL)) STORED,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    started_at DATETIME NULL,
+    closed_at DATETIME NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY uq_sprints_name (project_id, name),
+    UNIQUE KEY uq_sprints_active_project (active_project_id),
+    INDEX idx_sprints_project_state (project_id, state, starts_at),
+    CONSTRAINT fk_sprints_project FOREIGN KEY (project_id) REFERENCES projects (id) ON DELETE CASCADE,
+    CONSTRAINT fk_sprints_next_sprint FOREIGN KEY (next_sprint_id) REFERENCES sprints (id) ON DELETE SET NULL
+);
+
+ALTER TABLE tasks
+    ADD COLUMN sprint_id BIGINT UNSIGNED NULL AFTER project_id,
+    ADD INDEX idx_tasks_sprint_id (sprint_id),
+    ADD CONSTRAINT fk_tasks_sprint FOREIGN KEY (sprint_id) REFERENCES sprints (id) ON DELETE SET NULL;
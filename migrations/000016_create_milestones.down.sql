@@ -0,0 +1,6 @@
+ALTER TABLE tasks
+    DROP FOREIGN KEY fk_tasks_milestone,
+    DROP INDEX idx_tasks_milestone_id,
+    DROP COLUMN milestone_id;
+
+DROP TABLE IF EXISTS milestones;
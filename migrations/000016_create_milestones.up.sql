@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS milestones (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NULL,
+    target_date DATE NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    UNIQUE KEY uq_milestones_name (name),
+    INDEX idx_milestones_target_date (target_date)
+);
+
+ALTER TABLE tasks
+    ADD COLUMN milestone_id BIGINT UNSIGNED NULL AFTER sprint_id,
+    ADD INDEX idx_tasks_milestone_id (milestone_id),
+    ADD CONSTRAINT fk_tasks_milestone FOREIGN KEY (milestone_id) REFERENCES milestones (id) ON DELETE SET NULL;
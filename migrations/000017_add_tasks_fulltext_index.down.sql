@@ -0,0 +1,2 @@
+ALTER TABLE tasks
+    DROP INDEX ft_tasks_title_description;
@@ -0,0 +1,2 @@
+ALTER TABLE tasks
+    ADD FULLTEXT INDEX ft_tasks_title_description (title, description);
@@ -0,0 +1,2 @@
+ALTER TABLE tasks
+    ADD COLUMN version INT UNSIGNED NOT NULL DEFAULT 1 AFTER deleted_at;
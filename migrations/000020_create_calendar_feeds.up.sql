@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS calendar_feeds (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    owner_id CHAR(36) NOT NULL,
+    project_id BIGINT UNSIGNED NULL,
+    token_hash CHAR(64) NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    UNIQUE KEY uq_calendar_feeds_token_hash (token_hash),
+    INDEX idx_calendar_feeds_owner (owner_id, created_at),
+    CONSTRAINT fk_calendar_feeds_project FOREIGN KEY (project_id) REFERENCES projects (id) ON DELETE CASCADE
+);